@@ -16,7 +16,7 @@ package v1alpha1
 
 type (
 	// OpenTelemetryTargetAllocatorAllocationStrategy represent which strategy to distribute target to each collector
-	// +kubebuilder:validation:Enum=least-weighted;consistent-hashing;per-node
+	// +kubebuilder:validation:Enum=least-weighted;consistent-hashing;per-node;cost-weighted;zone-aware
 	OpenTelemetryTargetAllocatorAllocationStrategy string
 )
 
@@ -29,4 +29,10 @@ const (
 
 	// OpenTelemetryTargetAllocatorAllocationStrategyPerNode targets will be assigned to the collector on the node they reside on (use only with daemon set).
 	OpenTelemetryTargetAllocatorAllocationStrategyPerNode OpenTelemetryTargetAllocatorAllocationStrategy = "per-node"
+
+	// OpenTelemetryTargetAllocatorAllocationStrategyCostWeighted targets will be distributed to collectors by the cost reported for them, such as their series count.
+	OpenTelemetryTargetAllocatorAllocationStrategyCostWeighted OpenTelemetryTargetAllocatorAllocationStrategy = "cost-weighted"
+
+	// OpenTelemetryTargetAllocatorAllocationStrategyZoneAware targets will be assigned to a collector in the zone of the node they reside on, falling back to consistent hashing across zones.
+	OpenTelemetryTargetAllocatorAllocationStrategyZoneAware OpenTelemetryTargetAllocatorAllocationStrategy = "zone-aware"
 )
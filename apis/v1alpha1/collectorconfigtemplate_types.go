@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1beta1"
+)
+
+func init() {
+	SchemeBuilder.Register(&CollectorConfigTemplate{}, &CollectorConfigTemplateList{})
+	SchemeBuilder.Register(&ClusterCollectorConfigTemplate{}, &ClusterCollectorConfigTemplateList{})
+}
+
+// CollectorConfigTemplateSpec defines a fragment of collector configuration.
+type CollectorConfigTemplateSpec struct {
+	// Config is the fragment of collector configuration merged with the configuration of the collectors referencing
+	// the template in spec.config.templates. It has the same structure as the collector configuration, and can contain
+	// any of its sections, for instance only processors and exporters.
+	// +required
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	Config v1beta1.AnyConfig `json:"config"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +operator-sdk:csv:customresourcedefinitions:displayName="OpenTelemetry Collector Config Template"
+
+// CollectorConfigTemplate is the Schema for the collectorconfigtemplates API. It holds a fragment of collector
+// configuration shared by the collectors of its namespace.
+type CollectorConfigTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CollectorConfigTemplateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CollectorConfigTemplateList contains a list of CollectorConfigTemplate.
+type CollectorConfigTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CollectorConfigTemplate `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +operator-sdk:csv:customresourcedefinitions:displayName="OpenTelemetry Cluster Collector Config Template"
+
+// ClusterCollectorConfigTemplate is the Schema for the clustercollectorconfigtemplates API. It holds a fragment of
+// collector configuration shared by the collectors of all namespaces.
+type ClusterCollectorConfigTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CollectorConfigTemplateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterCollectorConfigTemplateList contains a list of ClusterCollectorConfigTemplate.
+type ClusterCollectorConfigTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterCollectorConfigTemplate `json:"items"`
+}
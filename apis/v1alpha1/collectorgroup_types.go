@@ -0,0 +1,122 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1beta1"
+)
+
+func init() {
+	SchemeBuilder.Register(&CollectorGroup{}, &CollectorGroupList{})
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Gateway Endpoint",type="string",JSONPath=".status.gatewayEndpoint"
+// +kubebuilder:printcolumn:name="Management",type="string",JSONPath=".spec.managementState",description="Management State"
+// +operator-sdk:csv:customresourcedefinitions:displayName="OpenTelemetry Collector Group"
+// +operator-sdk:csv:customresourcedefinitions:resources={{OpenTelemetryCollector,v1beta1}}
+
+// CollectorGroup is the Schema for the collectorgroups API. It composes an agent collector, running on every node,
+// with a gateway collector receiving the telemetry of the agents.
+type CollectorGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CollectorGroupSpec   `json:"spec,omitempty"`
+	Status CollectorGroupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CollectorGroupList contains a list of CollectorGroup.
+type CollectorGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CollectorGroup `json:"items"`
+}
+
+// CollectorGroupSpec defines the desired state of CollectorGroup.
+type CollectorGroupSpec struct {
+	// Agent is the spec of the collector deployed as a DaemonSet on every node. Its mode is always daemonset.
+	// The exporter forwarding to the gateway is added to its configuration and to its pipelines.
+	// +required
+	Agent v1beta1.OpenTelemetryCollectorSpec `json:"agent"`
+	// Gateway is the spec of the collector deployed as a StatefulSet, receiving the telemetry of the agents. Its mode
+	// is always statefulset. Its configuration must enable the gRPC protocol of an otlp receiver.
+	// +required
+	Gateway v1beta1.OpenTelemetryCollectorSpec `json:"gateway"`
+	// Forwarding configures how the agents export to the gateway.
+	// +optional
+	Forwarding CollectorGroupForwarding `json:"forwarding,omitempty"`
+	// ManagementState defines if the CollectorGroup is managed by the operator or not.
+	// +optional
+	// +kubebuilder:default:=managed
+	ManagementState v1beta1.ManagementStateType `json:"managementState,omitempty"`
+}
+
+// CollectorGroupForwarding configures how the agents export to the gateway.
+type CollectorGroupForwarding struct {
+	// Pipelines are the pipelines of the agent which export to the gateway. All the pipelines of the agent are used
+	// when empty.
+	// +optional
+	// +listType=set
+	Pipelines []string `json:"pipelines,omitempty"`
+	// LoadBalancing, when set, makes the agents export with the load-balancing exporter, which resolves the gateway
+	// pods through the gateway's headless Service, instead of the otlp exporter sending to the gateway's Service.
+	// +optional
+	LoadBalancing *CollectorGroupLoadBalancing `json:"loadBalancing,omitempty"`
+}
+
+// CollectorGroupRoutingKey is the key the load-balancing exporter routes the telemetry on.
+// +kubebuilder:validation:Enum=traceID;service;resource;metric;streamID
+type CollectorGroupRoutingKey string
+
+const (
+	// CollectorGroupRoutingKeyTraceID routes the spans of a trace to the same gateway pod.
+	CollectorGroupRoutingKeyTraceID CollectorGroupRoutingKey = "traceID"
+	// CollectorGroupRoutingKeyService routes the telemetry of a service to the same gateway pod.
+	CollectorGroupRoutingKeyService CollectorGroupRoutingKey = "service"
+	// CollectorGroupRoutingKeyResource routes the telemetry of a resource to the same gateway pod.
+	CollectorGroupRoutingKeyResource CollectorGroupRoutingKey = "resource"
+	// CollectorGroupRoutingKeyMetric routes the data points of a metric to the same gateway pod.
+	CollectorGroupRoutingKeyMetric CollectorGroupRoutingKey = "metric"
+	// CollectorGroupRoutingKeyStreamID routes the data points of a stream to the same gateway pod.
+	CollectorGroupRoutingKeyStreamID CollectorGroupRoutingKey = "streamID"
+)
+
+// CollectorGroupLoadBalancing configures the load-balancing exporter of the agents.
+type CollectorGroupLoadBalancing struct {
+	// RoutingKey is the key the telemetry is routed on. The default is traceID for traces and service for metrics.
+	// Traces can only be routed on traceID and service, and metrics on service, resource, metric and streamID.
+	// +optional
+	RoutingKey CollectorGroupRoutingKey `json:"routingKey,omitempty"`
+}
+
+// CollectorGroupStatus defines the observed state of CollectorGroup.
+type CollectorGroupStatus struct {
+	// Agent is the name of the OpenTelemetryCollector of the agents.
+	// +optional
+	Agent string `json:"agent,omitempty"`
+	// Gateway is the name of the OpenTelemetryCollector of the gateway.
+	// +optional
+	Gateway string `json:"gateway,omitempty"`
+	// GatewayEndpoint is the endpoint the agents export to.
+	// +optional
+	GatewayEndpoint string `json:"gatewayEndpoint,omitempty"`
+}
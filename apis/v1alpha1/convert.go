@@ -485,6 +485,10 @@ func tov1alpha1TAAllocationStrategy(strategy v1beta1.TargetAllocatorAllocationSt
 		return OpenTelemetryTargetAllocatorAllocationStrategyPerNode
 	case v1beta1.TargetAllocatorAllocationStrategyLeastWeighted:
 		return OpenTelemetryTargetAllocatorAllocationStrategyLeastWeighted
+	case v1beta1.TargetAllocatorAllocationStrategyCostWeighted:
+		return OpenTelemetryTargetAllocatorAllocationStrategyCostWeighted
+	case v1beta1.TargetAllocatorAllocationStrategyZoneAware:
+		return OpenTelemetryTargetAllocatorAllocationStrategyZoneAware
 	}
 	return ""
 }
@@ -504,6 +508,10 @@ func tov1beta1TAAllocationStrategy(strategy OpenTelemetryTargetAllocatorAllocati
 		return v1beta1.TargetAllocatorAllocationStrategyConsistentHashing
 	case OpenTelemetryTargetAllocatorAllocationStrategyLeastWeighted:
 		return v1beta1.TargetAllocatorAllocationStrategyLeastWeighted
+	case OpenTelemetryTargetAllocatorAllocationStrategyCostWeighted:
+		return v1beta1.TargetAllocatorAllocationStrategyCostWeighted
+	case OpenTelemetryTargetAllocatorAllocationStrategyZoneAware:
+		return v1beta1.TargetAllocatorAllocationStrategyZoneAware
 	}
 	return ""
 }
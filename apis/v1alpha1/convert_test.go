@@ -99,6 +99,29 @@ func Test_tov1alpha1_config(t *testing.T) {
 	assert.YAMLEq(t, collectorCfg, alpha1Col.Spec.Config)
 }
 
+func Test_tov1alpha1AndBack_config_templates(t *testing.T) {
+	cfg := v1beta1.Config{}
+	err := yaml.Unmarshal([]byte(collectorCfg), &cfg)
+	require.NoError(t, err)
+	cfg.Templates = []v1beta1.ConfigTemplateReference{
+		{Name: "base"},
+		{Kind: v1beta1.ConfigTemplateKindCluster, Name: "cluster-base"},
+	}
+
+	beta1Col := v1beta1.OpenTelemetryCollector{
+		Spec: v1beta1.OpenTelemetryCollectorSpec{
+			Config: cfg,
+		},
+	}
+	alpha1Col, err := tov1alpha1(beta1Col)
+	require.NoError(t, err)
+	assert.Contains(t, alpha1Col.Spec.Config, "templates:")
+
+	beta1ColBack, err := tov1beta1(*alpha1Col)
+	require.NoError(t, err)
+	assert.Equal(t, cfg, beta1ColBack.Spec.Config)
+}
+
 func Test_tov1beta1AndBack(t *testing.T) {
 	one := int32(1)
 	two := int64(2)
@@ -0,0 +1,41 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+type (
+	// ExporterProtocol represents the transport protocol of the OTLP exporter.
+	// +kubebuilder:validation:Enum=grpc;http/protobuf;http/json
+	ExporterProtocol string
+
+	// ExporterCompression represents the compression of the OTLP exporter.
+	// +kubebuilder:validation:Enum=gzip;none
+	ExporterCompression string
+)
+
+const (
+	// ExporterProtocolGRPC represents OTLP over gRPC.
+	ExporterProtocolGRPC ExporterProtocol = "grpc"
+	// ExporterProtocolHTTPProtobuf represents OTLP over HTTP with protobuf payloads.
+	ExporterProtocolHTTPProtobuf ExporterProtocol = "http/protobuf"
+	// ExporterProtocolHTTPJSON represents OTLP over HTTP with JSON payloads.
+	ExporterProtocolHTTPJSON ExporterProtocol = "http/json"
+)
+
+const (
+	// ExporterCompressionGzip represents gzip compression.
+	ExporterCompressionGzip ExporterCompression = "gzip"
+	// ExporterCompressionNone disables compression.
+	ExporterCompressionNone ExporterCompression = "none"
+)
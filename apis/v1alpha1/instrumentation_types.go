@@ -43,6 +43,12 @@ type InstrumentationSpec struct {
 	// Defaults defines default values for the instrumentation.
 	Defaults Defaults `json:"defaults,omitempty"`
 
+	// ContainerOverrides overrides the exporter and sampler for specific containers of the instrumented pods, e.g. to
+	// sample a high-volume proxy container at a lower rate than the application container.
+	// Env vars already defined by the container or by spec.env still take precedence.
+	// +optional
+	ContainerOverrides []ContainerOverride `json:"containerOverrides,omitempty"`
+
 	// Env defines common env vars. There are four layers for env vars' definitions and
 	// the precedence order is: `original container env vars` > `language specific env vars` > `common env vars` > `instrument spec configs' vars`.
 	// If the former var had been defined, then the other vars would be ignored.
@@ -79,6 +85,100 @@ type InstrumentationSpec struct {
 	// Nginx defines configuration for Nginx auto-instrumentation.
 	// +optional
 	Nginx Nginx `json:"nginx,omitempty"`
+
+	// Ruby defines configuration for Ruby auto-instrumentation.
+	// +optional
+	Ruby Ruby `json:"ruby,omitempty"`
+
+	// PHP defines configuration for PHP auto-instrumentation.
+	// The auto-instrumentation image must be built for the PHP version of the application.
+	// +optional
+	PHP PHP `json:"php,omitempty"`
+
+	// Selector defines which pods are instrumented without requiring the instrumentation.opentelemetry.io/inject-*
+	// annotations. An inject annotation set on the pod or its namespace, including "false", always takes precedence
+	// over a selector.
+	// +optional
+	Selector *InstrumentationSelector `json:"selector,omitempty"`
+
+	// LanguageDetection configures how the language of each container is detected for pods annotated with
+	// instrumentation.opentelemetry.io/inject-auto.
+	// +optional
+	LanguageDetection LanguageDetection `json:"languageDetection,omitempty"`
+
+	// RolloutPolicy configures restarting the workloads of already instrumented pods when this spec changes, so
+	// that they pick up e.g. a new auto-instrumentation image or exporter endpoint.
+	// +optional
+	RolloutPolicy *RolloutPolicy `json:"rolloutPolicy,omitempty"`
+}
+
+// RolloutPolicy defines how the workloads of pods instrumented with an outdated spec are restarted.
+type RolloutPolicy struct {
+	// RolloutOnChange enables rolling restarts of the Deployments, StatefulSets and DaemonSets owning pods that were
+	// instrumented with a previous version of the spec. Only pods instrumented while it is enabled are tracked.
+	// +optional
+	RolloutOnChange bool `json:"rolloutOnChange,omitempty"`
+
+	// MaxConcurrent is the maximum number of workloads being restarted at the same time. Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrent *int32 `json:"maxConcurrent,omitempty"`
+
+	// Namespaces restricts the restarts to the workloads of these namespaces. All namespaces are allowed when empty.
+	// +optional
+	// +listType=set
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// LanguageDetection defines the rules used to detect the language of a container.
+type LanguageDetection struct {
+	// Rules are evaluated in order before the default rules. The first rule matching a container selects its language.
+	// +optional
+	Rules []LanguageDetectionRule `json:"rules,omitempty"`
+
+	// DisableDefaultRules disables the built-in rules, which detect Java, NodeJS, Python, .NET, Ruby and PHP from well-known
+	// environment variables, commands and images.
+	// +optional
+	DisableDefaultRules bool `json:"disableDefaultRules,omitempty"`
+}
+
+// LanguageDetectionRule selects a language for the containers matching all of its fields that are set.
+// At least one of image, command or env must be set.
+type LanguageDetectionRule struct {
+	// Language is the auto-instrumentation injected into matching containers.
+	// Only java, nodejs, python, dotnet, ruby and php are supported.
+	// +required
+	Language Language `json:"language"`
+
+	// Image is a regular expression matched against the container image.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Command is a regular expression matched against the container command and arguments, joined by spaces.
+	// +optional
+	Command string `json:"command,omitempty"`
+
+	// Env is the name of an environment variable the container must define.
+	// +optional
+	Env string `json:"env,omitempty"`
+}
+
+// InstrumentationSelector selects pods to instrument by their labels and the labels of their namespace.
+type InstrumentationSelector struct {
+	// PodSelector selects the pods to instrument.
+	// +required
+	PodSelector *metav1.LabelSelector `json:"podSelector"`
+
+	// NamespaceSelector selects the namespaces in which pods are instrumented.
+	// If omitted, only pods in the namespace of the Instrumentation are selected.
+	// An empty selector selects all namespaces.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Languages lists the auto-instrumentations injected into the selected pods.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	Languages []Language `json:"languages"`
 }
 
 // Resource defines the configuration for the resource attributes, as defined by the OpenTelemetry specification.
@@ -104,6 +204,84 @@ type Exporter struct {
 	// TLS defines certificates for TLS.
 	// TLS needs to be enabled by specifying https:// scheme in the Endpoint.
 	TLS *TLS `json:"tls,omitempty"`
+
+	// Protocol defines the transport protocol of the exporter.
+	// The value will be set in the OTEL_EXPORTER_OTLP_PROTOCOL env var.
+	// Python auto-instrumentation only supports http/protobuf, the Apache HTTPD and Nginx modules only support grpc.
+	// +optional
+	Protocol ExporterProtocol `json:"protocol,omitempty"`
+
+	// Compression defines the compression of the exported data.
+	// The value will be set in the OTEL_EXPORTER_OTLP_COMPRESSION env var.
+	// +optional
+	Compression ExporterCompression `json:"compression,omitempty"`
+
+	// Timeout defines the maximum time the exporter waits for each batch export.
+	// The value will be set in milliseconds in the OTEL_EXPORTER_OTLP_TIMEOUT env var.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Headers defines the headers sent with every export request, e.g. for authentication.
+	// The headers will be set in the OTEL_EXPORTER_OTLP_HEADERS env var.
+	// Values are used verbatim, so commas have to be percent-encoded.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Headers []ExporterHeader `json:"headers,omitempty"`
+
+	// Traces configures the exporter of traces, its fields take precedence over the ones of the exporter.
+	// +optional
+	Traces *SignalExporter `json:"traces,omitempty"`
+
+	// Metrics configures the exporter of metrics, its fields take precedence over the ones of the exporter.
+	// +optional
+	Metrics *SignalExporter `json:"metrics,omitempty"`
+
+	// Logs configures the exporter of logs, its fields take precedence over the ones of the exporter.
+	// +optional
+	Logs *SignalExporter `json:"logs,omitempty"`
+}
+
+// SignalExporter defines the OTLP exporter of a single signal.
+type SignalExporter struct {
+	// Enabled defines whether the signal is exported. When false, the OTEL_{TRACES,METRICS,LOGS}_EXPORTER env var of
+	// the signal is set to none and no other field can be set.
+	// Defaults to true.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Endpoint is address of the collector with OTLP endpoint receiving the signal.
+	// The value will be set in the OTEL_EXPORTER_OTLP_{TRACES,METRICS,LOGS}_ENDPOINT env var. Unlike the endpoint of
+	// the exporter it is used as is, so it has to include the path of the signal, e.g. /v1/traces, for the HTTP protocols.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Protocol defines the transport protocol of the exporter of the signal.
+	// The value will be set in the OTEL_EXPORTER_OTLP_{TRACES,METRICS,LOGS}_PROTOCOL env var.
+	// +optional
+	Protocol ExporterProtocol `json:"protocol,omitempty"`
+
+	// TLS defines certificates for TLS.
+	// TLS needs to be enabled by specifying https:// scheme in the Endpoint.
+	// +optional
+	TLS *TLS `json:"tls,omitempty"`
+}
+
+// ExporterHeader defines a header sent by the exporter.
+type ExporterHeader struct {
+	// Name is the name of the header.
+	// +required
+	Name string `json:"name"`
+
+	// Value is the value of the header.
+	// Exactly one of value and secretKeyRef must be set.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// SecretKeyRef selects a key of a secret holding the value of the header.
+	// It is user responsibility to create the secret in the namespace of the workload.
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
 }
 
 // TLS defines TLS configuration for exporter.
@@ -146,6 +324,23 @@ type Sampler struct {
 	Argument string `json:"argument,omitempty"`
 }
 
+// ContainerOverride defines the exporter and sampler of the containers it names.
+type ContainerOverride struct {
+	// ContainerNames lists the names of the containers the override applies to.
+	// A container can be named by a single override only.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	ContainerNames []string `json:"containerNames"`
+
+	// Exporter replaces spec.exporter for the named containers.
+	// +optional
+	Exporter *Exporter `json:"exporter,omitempty"`
+
+	// Sampler replaces spec.sampler for the named containers.
+	// +optional
+	Sampler *Sampler `json:"sampler,omitempty"`
+}
+
 // Defaults defines default values for the instrumentation.
 type Defaults struct {
 	// UseLabelsForResourceAttributes defines whether to use common labels for resource attributes:
@@ -367,8 +562,128 @@ type Nginx struct {
 	Resources corev1.ResourceRequirements `json:"resourceRequirements,omitempty"`
 }
 
+// Ruby defines Ruby SDK and instrumentation configuration.
+type Ruby struct {
+	// Image is a container image with Ruby SDK and auto-instrumentation.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// VolumeClaimTemplate defines a ephemeral volume used for auto-instrumentation.
+	// If omitted, an emptyDir is used with size limit VolumeSizeLimit
+	VolumeClaimTemplate corev1.PersistentVolumeClaimTemplate `json:"volumeClaimTemplate,omitempty"`
+
+	// VolumeSizeLimit defines size limit for volume used for auto-instrumentation.
+	// The default size is 200Mi.
+	VolumeSizeLimit *resource.Quantity `json:"volumeLimitSize,omitempty"`
+
+	// Env defines ruby specific env vars. There are four layers for env vars' definitions and
+	// the precedence order is: `original container env vars` > `language specific env vars` > `common env vars` > `instrument spec configs' vars`.
+	// If the former var had been defined, then the other vars would be ignored.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Resources describes the compute resource requirements.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resourceRequirements,omitempty"`
+}
+
+// PHP defines PHP SDK and instrumentation configuration.
+type PHP struct {
+	// Image is a container image with PHP SDK and auto-instrumentation.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// VolumeClaimTemplate defines a ephemeral volume used for auto-instrumentation.
+	// If omitted, an emptyDir is used with size limit VolumeSizeLimit
+	VolumeClaimTemplate corev1.PersistentVolumeClaimTemplate `json:"volumeClaimTemplate,omitempty"`
+
+	// VolumeSizeLimit defines size limit for volume used for auto-instrumentation.
+	// The default size is 200Mi.
+	VolumeSizeLimit *resource.Quantity `json:"volumeLimitSize,omitempty"`
+
+	// Env defines php specific env vars. There are four layers for env vars' definitions and
+	// the precedence order is: `original container env vars` > `language specific env vars` > `common env vars` > `instrument spec configs' vars`.
+	// If the former var had been defined, then the other vars would be ignored.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Resources describes the compute resource requirements.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resourceRequirements,omitempty"`
+}
+
+const (
+	// InstrumentationConditionValid indicates whether the exporter configuration of the Instrumentation is usable
+	// by the workloads that reference it.
+	InstrumentationConditionValid = "Valid"
+
+	// InstrumentationConditionInUse indicates whether at least one running pod is instrumented by the Instrumentation.
+	InstrumentationConditionInUse = "InUse"
+
+	// InstrumentationConditionUpToDate indicates whether all the tracked pods are instrumented with the current spec
+	// of the Instrumentation. It is only set when the rollout policy enables rolloutOnChange.
+	InstrumentationConditionUpToDate = "UpToDate"
+)
+
 // InstrumentationStatus defines status of the instrumentation.
 type InstrumentationStatus struct {
+	// ObservedGeneration is the most recent generation of the Instrumentation observed by the operator.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Namespaces lists the namespaces with pods that reference this Instrumentation.
+	// +optional
+	// +listType=set
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Workloads lists the workloads with pods that reference this Instrumentation.
+	// +optional
+	// +listType=atomic
+	Workloads []InstrumentationWorkload `json:"workloads,omitempty"`
+
+	// InjectedPods is the number of running pods currently instrumented by this Instrumentation, per language.
+	// +optional
+	InjectedPods map[string]int32 `json:"injectedPods,omitempty"`
+
+	// Problems lists the issues found with the exporter configuration, e.g. TLS secrets missing from a namespace
+	// that references this Instrumentation.
+	// +optional
+	// +listType=atomic
+	Problems []string `json:"problems,omitempty"`
+
+	// Conditions represent the latest available observations of the Instrumentation's state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// InstrumentationWorkload describes a workload with pods that reference an Instrumentation.
+type InstrumentationWorkload struct {
+	// Namespace of the workload.
+	Namespace string `json:"namespace"`
+
+	// Kind of the workload, e.g. Deployment, StatefulSet, DaemonSet, Job or Pod for pods without a controller.
+	Kind string `json:"kind"`
+
+	// Name of the workload.
+	Name string `json:"name"`
+
+	// Languages lists the auto-instrumentations requested by the workload's pods.
+	// +optional
+	// +listType=set
+	Languages []Language `json:"languages,omitempty"`
+
+	// Pods is the number of running pods of the workload that reference the Instrumentation.
+	Pods int32 `json:"pods"`
+
+	// InjectedPods is the number of running pods of the workload that were instrumented.
+	InjectedPods int32 `json:"injectedPods"`
+
+	// OutdatedPods is the number of running pods of the workload that were instrumented with a previous version of
+	// the spec. Pods are only tracked while the rollout policy enables rolloutOnChange.
+	// +optional
+	OutdatedPods int32 `json:"outdatedPods,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -378,6 +693,9 @@ type InstrumentationStatus struct {
 // +kubebuilder:printcolumn:name="Endpoint",type="string",JSONPath=".spec.exporter.endpoint"
 // +kubebuilder:printcolumn:name="Sampler",type="string",JSONPath=".spec.sampler.type"
 // +kubebuilder:printcolumn:name="Sampler Arg",type="string",JSONPath=".spec.sampler.argument"
+// +kubebuilder:printcolumn:name="Valid",type="string",JSONPath=".status.conditions[?(@.type==\"Valid\")].status",priority=1
+// +kubebuilder:printcolumn:name="In Use",type="string",JSONPath=".status.conditions[?(@.type==\"InUse\")].status",priority=1
+// +kubebuilder:printcolumn:name="Up To Date",type="string",JSONPath=".status.conditions[?(@.type==\"UpToDate\")].status",priority=1
 // +operator-sdk:csv:customresourcedefinitions:displayName="OpenTelemetry Instrumentation"
 // +operator-sdk:csv:customresourcedefinitions:resources={{Pod,v1}}
 
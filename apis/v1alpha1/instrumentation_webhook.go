@@ -18,12 +18,14 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -194,6 +196,36 @@ func (w InstrumentationWebhook) defaulter(r *Instrumentation) error {
 	if r.Spec.Nginx.ConfigFile == "" {
 		r.Spec.Nginx.ConfigFile = "/etc/nginx/nginx.conf"
 	}
+	if r.Spec.Ruby.Image == "" {
+		r.Spec.Ruby.Image = w.cfg.AutoInstrumentationRubyImage()
+	}
+	if r.Spec.Ruby.Resources.Limits == nil {
+		r.Spec.Ruby.Resources.Limits = corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+			corev1.ResourceMemory: resource.MustParse("64Mi"),
+		}
+	}
+	if r.Spec.Ruby.Resources.Requests == nil {
+		r.Spec.Ruby.Resources.Requests = corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("50m"),
+			corev1.ResourceMemory: resource.MustParse("64Mi"),
+		}
+	}
+	if r.Spec.PHP.Image == "" {
+		r.Spec.PHP.Image = w.cfg.AutoInstrumentationPHPImage()
+	}
+	if r.Spec.PHP.Resources.Limits == nil {
+		r.Spec.PHP.Resources.Limits = corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+			corev1.ResourceMemory: resource.MustParse("64Mi"),
+		}
+	}
+	if r.Spec.PHP.Resources.Requests == nil {
+		r.Spec.PHP.Resources.Requests = corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("50m"),
+			corev1.ResourceMemory: resource.MustParse("64Mi"),
+		}
+	}
 	// Set the defaulting annotations
 	if r.Annotations == nil {
 		r.Annotations = map[string]string{}
@@ -205,40 +237,21 @@ func (w InstrumentationWebhook) defaulter(r *Instrumentation) error {
 	r.Annotations[constants.AnnotationDefaultAutoInstrumentationGo] = w.cfg.AutoInstrumentationGoImage()
 	r.Annotations[constants.AnnotationDefaultAutoInstrumentationApacheHttpd] = w.cfg.AutoInstrumentationApacheHttpdImage()
 	r.Annotations[constants.AnnotationDefaultAutoInstrumentationNginx] = w.cfg.AutoInstrumentationNginxImage()
+	r.Annotations[constants.AnnotationDefaultAutoInstrumentationRuby] = w.cfg.AutoInstrumentationRubyImage()
+	r.Annotations[constants.AnnotationDefaultAutoInstrumentationPHP] = w.cfg.AutoInstrumentationPHPImage()
 	return nil
 }
 
 func (w InstrumentationWebhook) validate(r *Instrumentation) (admission.Warnings, error) {
 	var warnings []string
-	switch r.Spec.Sampler.Type {
-	case "":
+	if r.Spec.Sampler.Type == "" {
 		warnings = append(warnings, "sampler type not set")
-	case TraceIDRatio, ParentBasedTraceIDRatio:
-		if r.Spec.Sampler.Argument != "" {
-			rate, err := strconv.ParseFloat(r.Spec.Sampler.Argument, 64)
-			if err != nil {
-				return warnings, fmt.Errorf("spec.sampler.argument is not a number: %s", r.Spec.Sampler.Argument)
-			}
-			if rate < 0 || rate > 1 {
-				return warnings, fmt.Errorf("spec.sampler.argument should be in rage [0..1]: %s", r.Spec.Sampler.Argument)
-			}
-		}
-	case JaegerRemote, ParentBasedJaegerRemote:
-		// value is a comma separated list of endpoint, pollingIntervalMs, initialSamplingRate
-		// Example: `endpoint=http://localhost:14250,pollingIntervalMs=5000,initialSamplingRate=0.25`
-		if r.Spec.Sampler.Argument != "" {
-			err := validateJaegerRemoteSamplerArgument(r.Spec.Sampler.Argument)
-
-			if err != nil {
-				return warnings, fmt.Errorf("spec.sampler.argument is not a valid argument for sampler %s: %w", r.Spec.Sampler.Type, err)
-			}
-		}
-	case AlwaysOn, AlwaysOff, ParentBasedAlwaysOn, ParentBasedAlwaysOff, XRaySampler:
-	default:
-		return warnings, fmt.Errorf("spec.sampler.type is not valid: %s", r.Spec.Sampler.Type)
+	}
+	err := validateSampler("spec.sampler", r.Spec.Sampler)
+	if err != nil {
+		return warnings, err
 	}
 
-	var err error
 	err = validateInstrVolume(r.Spec.ApacheHttpd.VolumeClaimTemplate, r.Spec.ApacheHttpd.VolumeSizeLimit)
 	if err != nil {
 		return warnings, fmt.Errorf("spec.apachehttpd.volumeClaimTemplate and spec.apachehttpd.volumeSizeLimit cannot both be defined: %w", err)
@@ -267,12 +280,171 @@ func (w InstrumentationWebhook) validate(r *Instrumentation) (admission.Warnings
 	if err != nil {
 		return warnings, fmt.Errorf("spec.python.volumeClaimTemplate and spec.python.volumeSizeLimit cannot both be defined: %w", err)
 	}
+	err = validateInstrVolume(r.Spec.Ruby.VolumeClaimTemplate, r.Spec.Ruby.VolumeSizeLimit)
+	if err != nil {
+		return warnings, fmt.Errorf("spec.ruby.volumeClaimTemplate and spec.ruby.volumeSizeLimit cannot both be defined: %w", err)
+	}
+	err = validateInstrVolume(r.Spec.PHP.VolumeClaimTemplate, r.Spec.PHP.VolumeSizeLimit)
+	if err != nil {
+		return warnings, fmt.Errorf("spec.php.volumeClaimTemplate and spec.php.volumeSizeLimit cannot both be defined: %w", err)
+	}
 
+	if err = validateSelector(r.Spec.Selector); err != nil {
+		return warnings, err
+	}
+	if err = validateLanguageDetection(r.Spec.LanguageDetection); err != nil {
+		return warnings, err
+	}
+
+	if err = validateExporterConfig("spec.exporter", r.Spec.Exporter); err != nil {
+		return warnings, err
+	}
 	warnings = append(warnings, validateExporter(r.Spec.Exporter)...)
 
+	warnings = append(warnings, w.validateExporterLanguages("spec.exporter", r.Spec.Exporter)...)
+
+	overrideWarnings, err := validateContainerOverrides(r.Spec.ContainerOverrides)
+	warnings = append(warnings, overrideWarnings...)
+	if err != nil {
+		return warnings, err
+	}
+	for i, override := range r.Spec.ContainerOverrides {
+		if override.Exporter != nil {
+			warnings = append(warnings, w.validateExporterLanguages(fmt.Sprintf("spec.containerOverrides[%d].exporter", i), *override.Exporter)...)
+		}
+	}
+
+	rolloutWarnings, err := validateRolloutPolicy(r.Spec.RolloutPolicy)
+	warnings = append(warnings, rolloutWarnings...)
+	if err != nil {
+		return warnings, err
+	}
+
 	return warnings, nil
 }
 
+func validateRolloutPolicy(policy *RolloutPolicy) ([]string, error) {
+	if policy == nil {
+		return nil, nil
+	}
+	if policy.MaxConcurrent != nil && *policy.MaxConcurrent < 1 {
+		return nil, fmt.Errorf("spec.rolloutPolicy.maxConcurrent should be greater than or equal to 1: %d", *policy.MaxConcurrent)
+	}
+	if !policy.RolloutOnChange && (policy.MaxConcurrent != nil || len(policy.Namespaces) > 0) {
+		return []string{"spec.rolloutPolicy is configured but rolloutOnChange is not enabled"}, nil
+	}
+	return nil, nil
+}
+
+func validateSampler(path string, sampler Sampler) error {
+	switch sampler.Type {
+	case "":
+	case TraceIDRatio, ParentBasedTraceIDRatio:
+		if sampler.Argument != "" {
+			rate, err := strconv.ParseFloat(sampler.Argument, 64)
+			if err != nil {
+				return fmt.Errorf("%s.argument is not a number: %s", path, sampler.Argument)
+			}
+			if rate < 0 || rate > 1 {
+				return fmt.Errorf("%s.argument should be in rage [0..1]: %s", path, sampler.Argument)
+			}
+		}
+	case JaegerRemote, ParentBasedJaegerRemote:
+		// value is a comma separated list of endpoint, pollingIntervalMs, initialSamplingRate
+		// Example: `endpoint=http://localhost:14250,pollingIntervalMs=5000,initialSamplingRate=0.25`
+		if sampler.Argument != "" {
+			err := validateJaegerRemoteSamplerArgument(sampler.Argument)
+
+			if err != nil {
+				return fmt.Errorf("%s.argument is not a valid argument for sampler %s: %w", path, sampler.Type, err)
+			}
+		}
+	case AlwaysOn, AlwaysOff, ParentBasedAlwaysOn, ParentBasedAlwaysOff, XRaySampler:
+	default:
+		return fmt.Errorf("%s.type is not valid: %s", path, sampler.Type)
+	}
+	return nil
+}
+
+func validateContainerOverrides(overrides []ContainerOverride) ([]string, error) {
+	var warnings []string
+	overridden := map[string]int{}
+	for i, override := range overrides {
+		if len(override.ContainerNames) == 0 {
+			return warnings, fmt.Errorf("spec.containerOverrides[%d].containerNames must list at least one container", i)
+		}
+		for _, name := range override.ContainerNames {
+			if j, ok := overridden[name]; ok && j != i {
+				return warnings, fmt.Errorf("spec.containerOverrides[%d] and spec.containerOverrides[%d] both override container %s", j, i, name)
+			}
+			overridden[name] = i
+		}
+		if override.Exporter == nil && override.Sampler == nil {
+			warnings = append(warnings, fmt.Sprintf("spec.containerOverrides[%d] does not override the exporter nor the sampler", i))
+		}
+		if override.Exporter != nil {
+			if err := validateExporterConfig(fmt.Sprintf("spec.containerOverrides[%d].exporter", i), *override.Exporter); err != nil {
+				return warnings, err
+			}
+			warnings = append(warnings, validateExporter(*override.Exporter)...)
+		}
+		if override.Sampler != nil {
+			if err := validateSampler(fmt.Sprintf("spec.containerOverrides[%d].sampler", i), *override.Sampler); err != nil {
+				return warnings, err
+			}
+		}
+	}
+	return warnings, nil
+}
+
+func validateSelector(selector *InstrumentationSelector) error {
+	if selector == nil {
+		return nil
+	}
+	if selector.PodSelector == nil {
+		return fmt.Errorf("spec.selector.podSelector must be set")
+	}
+	if _, err := metav1.LabelSelectorAsSelector(selector.PodSelector); err != nil {
+		return fmt.Errorf("spec.selector.podSelector is not valid: %w", err)
+	}
+	if selector.NamespaceSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(selector.NamespaceSelector); err != nil {
+			return fmt.Errorf("spec.selector.namespaceSelector is not valid: %w", err)
+		}
+	}
+	if len(selector.Languages) == 0 {
+		return fmt.Errorf("spec.selector.languages must list at least one language")
+	}
+	for _, language := range selector.Languages {
+		switch language {
+		case LanguageJava, LanguageNodeJS, LanguagePython, LanguageDotNet, LanguageGo, LanguageApacheHttpd, LanguageNginx, LanguageRuby, LanguagePHP, LanguageSdk:
+		default:
+			return fmt.Errorf("spec.selector.languages contains an unknown language: %s", language)
+		}
+	}
+	return nil
+}
+
+func validateLanguageDetection(detection LanguageDetection) error {
+	for i, rule := range detection.Rules {
+		switch rule.Language {
+		case LanguageJava, LanguageNodeJS, LanguagePython, LanguageDotNet, LanguageRuby, LanguagePHP:
+		default:
+			return fmt.Errorf("spec.languageDetection.rules[%d].language is not supported for language detection: %s", i, rule.Language)
+		}
+		if rule.Image == "" && rule.Command == "" && rule.Env == "" {
+			return fmt.Errorf("spec.languageDetection.rules[%d] must set at least one of image, command or env", i)
+		}
+		if _, err := regexp.Compile(rule.Image); err != nil {
+			return fmt.Errorf("spec.languageDetection.rules[%d].image is not a valid regular expression: %w", i, err)
+		}
+		if _, err := regexp.Compile(rule.Command); err != nil {
+			return fmt.Errorf("spec.languageDetection.rules[%d].command is not a valid regular expression: %w", i, err)
+		}
+	}
+	return nil
+}
+
 func validateExporter(exporter Exporter) []string {
 	var warnings []string
 	if exporter.TLS != nil {
@@ -289,9 +461,139 @@ func validateExporter(exporter Exporter) []string {
 		warnings = append(warnings, "exporter is using https:// but exporter.tls is unset")
 	}
 
+	disabled := 0
+	for _, signal := range signalExporters(exporter) {
+		if signal.exporter == nil {
+			continue
+		}
+		if signal.exporter.Enabled != nil && !*signal.exporter.Enabled {
+			disabled++
+			continue
+		}
+		endpoint := signal.exporter.Endpoint
+		if endpoint == "" {
+			endpoint = exporter.Endpoint
+		}
+		if signal.exporter.TLS != nil && !strings.HasPrefix(endpoint, "https://") {
+			warnings = append(warnings, fmt.Sprintf("exporter.%s.tls is configured but the %s endpoint is not enabling TLS with https://", signal.name, signal.name))
+		}
+	}
+	if disabled == 3 {
+		warnings = append(warnings, "exporter disables traces, metrics and logs")
+	}
+
 	return warnings
 }
 
+// validateExporterLanguages warns about the exporter settings that the enabled auto-instrumentations ignore: the
+// Python auto-instrumentation only exports with http/protobuf, while the Apache HTTPD and Nginx modules only export
+// traces with grpc and without compression.
+func (w InstrumentationWebhook) validateExporterLanguages(path string, exporter Exporter) []string {
+	var warnings []string
+	if w.cfg.EnablePythonAutoInstrumentation() {
+		if exporter.Protocol != "" && exporter.Protocol != ExporterProtocolHTTPProtobuf {
+			warnings = append(warnings, fmt.Sprintf("%s.protocol %s is not supported by the Python auto-instrumentation, which only exports with %s", path, exporter.Protocol, ExporterProtocolHTTPProtobuf))
+		}
+		for _, signal := range signalExporters(exporter) {
+			if signal.exporter != nil && signal.exporter.Protocol != "" && signal.exporter.Protocol != ExporterProtocolHTTPProtobuf {
+				warnings = append(warnings, fmt.Sprintf("%s.%s.protocol %s is not supported by the Python auto-instrumentation, which only exports with %s", path, signal.name, signal.exporter.Protocol, ExporterProtocolHTTPProtobuf))
+			}
+		}
+	}
+
+	var modules []string
+	if w.cfg.EnableApacheHttpdAutoInstrumentation() {
+		modules = append(modules, "Apache HTTPD")
+	}
+	if w.cfg.EnableNginxAutoInstrumentation() {
+		modules = append(modules, "Nginx")
+	}
+	if len(modules) > 0 {
+		names := strings.Join(modules, " and ")
+		// the modules only export traces
+		protocol, protocolPath := exporter.Protocol, path
+		if exporter.Traces != nil && exporter.Traces.Protocol != "" {
+			protocol, protocolPath = exporter.Traces.Protocol, path+".traces"
+		}
+		if protocol != "" && protocol != ExporterProtocolGRPC {
+			warnings = append(warnings, fmt.Sprintf("%s.protocol %s is ignored by the %s auto-instrumentation, which only exports with %s", protocolPath, protocol, names, ExporterProtocolGRPC))
+		}
+		if exporter.Compression != "" && exporter.Compression != ExporterCompressionNone {
+			warnings = append(warnings, fmt.Sprintf("%s.compression %s is ignored by the %s auto-instrumentation, which exports without compression", path, exporter.Compression, names))
+		}
+	}
+	return warnings
+}
+
+var exporterHeaderNameRegex = regexp.MustCompile(`^[A-Za-z0-9]+(-[A-Za-z0-9]+)*$`)
+
+func validateExporterConfig(path string, exporter Exporter) error {
+	switch exporter.Protocol {
+	case "", ExporterProtocolGRPC, ExporterProtocolHTTPProtobuf, ExporterProtocolHTTPJSON:
+	default:
+		return fmt.Errorf("%s.protocol is not valid: %s", path, exporter.Protocol)
+	}
+	switch exporter.Compression {
+	case "", ExporterCompressionGzip, ExporterCompressionNone:
+	default:
+		return fmt.Errorf("%s.compression is not valid: %s", path, exporter.Compression)
+	}
+	if exporter.Timeout != nil && exporter.Timeout.Duration <= 0 {
+		return fmt.Errorf("%s.timeout must be positive: %s", path, exporter.Timeout.Duration)
+	}
+	names := map[string]bool{}
+	for i, header := range exporter.Headers {
+		if !exporterHeaderNameRegex.MatchString(header.Name) {
+			return fmt.Errorf("%s.headers[%d].name is not a valid header name: %q", path, i, header.Name)
+		}
+		// header names are case-insensitive
+		name := strings.ToLower(header.Name)
+		if names[name] {
+			return fmt.Errorf("%s.headers[%d].name is duplicated: %s", path, i, header.Name)
+		}
+		names[name] = true
+		if (header.Value == "") == (header.SecretKeyRef == nil) {
+			return fmt.Errorf("%s.headers[%d] must set exactly one of value and secretKeyRef", path, i)
+		}
+		if header.SecretKeyRef != nil && (header.SecretKeyRef.Name == "" || header.SecretKeyRef.Key == "") {
+			return fmt.Errorf("%s.headers[%d].secretKeyRef must set the name and key of the secret", path, i)
+		}
+		if strings.Contains(header.Value, ",") {
+			return fmt.Errorf("%s.headers[%d].value must not contain commas, they have to be percent-encoded", path, i)
+		}
+	}
+	for _, signal := range signalExporters(exporter) {
+		if signal.exporter == nil {
+			continue
+		}
+		if signal.exporter.Enabled != nil && !*signal.exporter.Enabled {
+			if signal.exporter.Endpoint != "" || signal.exporter.Protocol != "" || signal.exporter.TLS != nil {
+				return fmt.Errorf("%s.%s is disabled but sets endpoint, protocol or tls", path, signal.name)
+			}
+			continue
+		}
+		switch signal.exporter.Protocol {
+		case "", ExporterProtocolGRPC, ExporterProtocolHTTPProtobuf, ExporterProtocolHTTPJSON:
+		default:
+			return fmt.Errorf("%s.%s.protocol is not valid: %s", path, signal.name, signal.exporter.Protocol)
+		}
+	}
+	return nil
+}
+
+type namedSignalExporter struct {
+	name     string
+	exporter *SignalExporter
+}
+
+func signalExporters(exporter Exporter) []namedSignalExporter {
+	return []namedSignalExporter{
+		{"traces", exporter.Traces},
+		{"metrics", exporter.Metrics},
+		{"logs", exporter.Logs},
+	}
+}
+
 func validateJaegerRemoteSamplerArgument(argument string) error {
 	parts := strings.Split(argument, ",")
 
@@ -16,11 +16,15 @@ package v1alpha1
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/open-telemetry/opentelemetry-operator/internal/config"
@@ -38,6 +42,8 @@ func TestInstrumentationDefaultingWebhook(t *testing.T) {
 			config.WithAutoInstrumentationDotNetImage("dotnet-img:1"),
 			config.WithAutoInstrumentationApacheHttpdImage("apache-httpd-img:1"),
 			config.WithAutoInstrumentationNginxImage("nginx-img:1"),
+			config.WithAutoInstrumentationRubyImage("ruby-img:1"),
+			config.WithAutoInstrumentationPHPImage("php-img:1"),
 		),
 	}.Default(context.Background(), inst)
 	assert.NoError(t, err)
@@ -47,6 +53,8 @@ func TestInstrumentationDefaultingWebhook(t *testing.T) {
 	assert.Equal(t, "dotnet-img:1", inst.Spec.DotNet.Image)
 	assert.Equal(t, "apache-httpd-img:1", inst.Spec.ApacheHttpd.Image)
 	assert.Equal(t, "nginx-img:1", inst.Spec.Nginx.Image)
+	assert.Equal(t, "ruby-img:1", inst.Spec.Ruby.Image)
+	assert.Equal(t, "php-img:1", inst.Spec.PHP.Image)
 }
 
 func TestInstrumentationValidatingWebhook(t *testing.T) {
@@ -222,6 +230,386 @@ func TestInstrumentationValidatingWebhook(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "selector without podSelector",
+			err:  "spec.selector.podSelector must be set",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					Selector: &InstrumentationSelector{
+						Languages: []Language{LanguageJava},
+					},
+				},
+			},
+		},
+		{
+			name: "selector without languages",
+			err:  "spec.selector.languages must list at least one language",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					Selector: &InstrumentationSelector{
+						PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "my-app"}},
+					},
+				},
+			},
+		},
+		{
+			name: "selector with unknown language",
+			err:  "spec.selector.languages contains an unknown language: cobol",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					Selector: &InstrumentationSelector{
+						PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "my-app"}},
+						Languages:   []Language{"cobol"},
+					},
+				},
+			},
+		},
+		{
+			name: "selector with invalid namespaceSelector",
+			err:  "spec.selector.namespaceSelector is not valid",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					Selector: &InstrumentationSelector{
+						PodSelector: &metav1.LabelSelector{},
+						NamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+							{Key: "team", Operator: "Near"},
+						}},
+						Languages: []Language{LanguageJava},
+					},
+				},
+			},
+		},
+		{
+			name: "language detection rule with unsupported language",
+			err:  "spec.languageDetection.rules[0].language is not supported for language detection: go",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					LanguageDetection: LanguageDetection{
+						Rules: []LanguageDetectionRule{{Language: LanguageGo, Image: "my-go-app"}},
+					},
+				},
+			},
+		},
+		{
+			name: "language detection rule without criteria",
+			err:  "spec.languageDetection.rules[1] must set at least one of image, command or env",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					LanguageDetection: LanguageDetection{
+						Rules: []LanguageDetectionRule{
+							{Language: LanguageJava, Env: "JDK_JAVA_OPTIONS"},
+							{Language: LanguagePython},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "language detection rule with invalid command",
+			err:  "spec.languageDetection.rules[0].command is not a valid regular expression",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					LanguageDetection: LanguageDetection{
+						Rules: []LanguageDetectionRule{{Language: LanguageNodeJS, Command: "(node"}},
+					},
+				},
+			},
+		},
+		{
+			name: "exporter with invalid protocol",
+			err:  "spec.exporter.protocol is not valid: http",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler:  Sampler{Type: AlwaysOn},
+					Exporter: Exporter{Endpoint: "http://collector:4318", Protocol: "http"},
+				},
+			},
+		},
+		{
+			name: "exporter with invalid compression",
+			err:  "spec.exporter.compression is not valid: zstd",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler:  Sampler{Type: AlwaysOn},
+					Exporter: Exporter{Endpoint: "http://collector:4318", Compression: "zstd"},
+				},
+			},
+		},
+		{
+			name: "exporter with negative timeout",
+			err:  "spec.exporter.timeout must be positive",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler:  Sampler{Type: AlwaysOn},
+					Exporter: Exporter{Endpoint: "http://collector:4318", Timeout: &metav1.Duration{Duration: -time.Second}},
+				},
+			},
+		},
+		{
+			name: "exporter header with invalid name",
+			err:  `spec.exporter.headers[0].name is not a valid header name: "api key"`,
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler:  Sampler{Type: AlwaysOn},
+					Exporter: Exporter{Endpoint: "http://collector:4318", Headers: []ExporterHeader{{Name: "api key", Value: "abc"}}},
+				},
+			},
+		},
+		{
+			name: "exporter header duplicated",
+			err:  "spec.exporter.headers[1].name is duplicated: Api-Key",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					Exporter: Exporter{Endpoint: "http://collector:4318", Headers: []ExporterHeader{
+						{Name: "api-key", Value: "abc"},
+						{Name: "Api-Key", Value: "def"},
+					}},
+				},
+			},
+		},
+		{
+			name: "exporter header with value and secret",
+			err:  "spec.exporter.headers[0] must set exactly one of value and secretKeyRef",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					Exporter: Exporter{Endpoint: "http://collector:4318", Headers: []ExporterHeader{{
+						Name:         "api-key",
+						Value:        "abc",
+						SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "auth"}, Key: "api-key"},
+					}}},
+				},
+			},
+		},
+		{
+			name: "exporter header with comma",
+			err:  "spec.exporter.headers[0].value must not contain commas",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler:  Sampler{Type: AlwaysOn},
+					Exporter: Exporter{Endpoint: "http://collector:4318", Headers: []ExporterHeader{{Name: "x-tags", Value: "a,b"}}},
+				},
+			},
+		},
+		{
+			name: "container override exporter with invalid protocol",
+			err:  "spec.containerOverrides[0].exporter.protocol is not valid: udp",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					ContainerOverrides: []ContainerOverride{
+						{ContainerNames: []string{"proxy"}, Exporter: &Exporter{Endpoint: "http://collector:4317", Protocol: "udp"}},
+					},
+				},
+			},
+		},
+		{
+			name: "valid exporter configuration",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					Exporter: Exporter{
+						Endpoint:    "http://collector:4318",
+						Protocol:    ExporterProtocolHTTPProtobuf,
+						Compression: ExporterCompressionGzip,
+						Timeout:     &metav1.Duration{Duration: 10 * time.Second},
+						Headers: []ExporterHeader{
+							{Name: "authorization", Value: "Basic dXNlcjpwYXNz=="},
+							{Name: "x-api-key", SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "auth"}, Key: "api-key"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "disabled signal with endpoint",
+			err:  "spec.exporter.metrics is disabled but sets endpoint, protocol or tls",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					Exporter: Exporter{
+						Endpoint: "http://collector:4318",
+						Metrics:  &SignalExporter{Enabled: ptr.To(false), Endpoint: "http://metrics-gateway:4318/v1/metrics"},
+					},
+				},
+			},
+		},
+		{
+			name: "signal with invalid protocol",
+			err:  "spec.exporter.traces.protocol is not valid: thrift",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					Exporter: Exporter{
+						Traces: &SignalExporter{Endpoint: "http://jaeger:14268", Protocol: "thrift"},
+					},
+				},
+			},
+		},
+		{
+			name: "signal tls without https",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					Exporter: Exporter{
+						Endpoint: "http://collector:4318",
+						Logs:     &SignalExporter{TLS: &TLS{SecretName: "certs", CA: "ca.crt"}},
+					},
+				},
+			},
+			warnings: []string{"exporter.logs.tls is configured but the logs endpoint is not enabling TLS with https://"},
+		},
+		{
+			name: "all signals disabled",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					Exporter: Exporter{
+						Traces:  &SignalExporter{Enabled: ptr.To(false)},
+						Metrics: &SignalExporter{Enabled: ptr.To(false)},
+						Logs:    &SignalExporter{Enabled: ptr.To(false)},
+					},
+				},
+			},
+			warnings: []string{"exporter disables traces, metrics and logs"},
+		},
+		{
+			name: "valid per-signal exporters",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					Exporter: Exporter{
+						Endpoint: "http://collector:4318",
+						Traces:   &SignalExporter{Endpoint: "http://traces-gateway:4317", Protocol: ExporterProtocolGRPC},
+						Metrics:  &SignalExporter{Endpoint: "https://metrics-gateway:4318/v1/metrics", TLS: &TLS{ConfigMapName: "ca", CA: "ca.crt"}},
+						Logs:     &SignalExporter{Enabled: ptr.To(false)},
+					},
+				},
+			},
+		},
+		{
+			name: "container override without containers",
+			err:  "spec.containerOverrides[0].containerNames must list at least one container",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					ContainerOverrides: []ContainerOverride{
+						{Sampler: &Sampler{Type: AlwaysOff}},
+					},
+				},
+			},
+		},
+		{
+			name: "container overridden twice",
+			err:  "spec.containerOverrides[0] and spec.containerOverrides[1] both override container proxy",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					ContainerOverrides: []ContainerOverride{
+						{ContainerNames: []string{"proxy"}, Sampler: &Sampler{Type: AlwaysOff}},
+						{ContainerNames: []string{"app", "proxy"}, Sampler: &Sampler{Type: AlwaysOn}},
+					},
+				},
+			},
+		},
+		{
+			name: "container override with invalid sampler argument",
+			err:  "spec.containerOverrides[0].sampler.argument should be in rage [0..1]",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					ContainerOverrides: []ContainerOverride{
+						{ContainerNames: []string{"proxy"}, Sampler: &Sampler{Type: TraceIDRatio, Argument: "10"}},
+					},
+				},
+			},
+		},
+		{
+			name: "container override without exporter nor sampler",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					ContainerOverrides: []ContainerOverride{
+						{ContainerNames: []string{"proxy"}},
+					},
+				},
+			},
+			warnings: []string{"spec.containerOverrides[0] does not override the exporter nor the sampler"},
+		},
+		{
+			name: "container override with exporter using https:// without tls",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					ContainerOverrides: []ContainerOverride{
+						{ContainerNames: []string{"proxy"}, Exporter: &Exporter{Endpoint: "https://collector:4317"}},
+					},
+				},
+			},
+			warnings: []string{"exporter is using https:// but exporter.tls is unset"},
+		},
+		{
+			name: "valid container overrides",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: ParentBasedTraceIDRatio, Argument: "0.1"},
+					ContainerOverrides: []ContainerOverride{
+						{ContainerNames: []string{"proxy"}, Sampler: &Sampler{Type: ParentBasedTraceIDRatio, Argument: "0.01"}},
+						{ContainerNames: []string{"batch"}, Exporter: &Exporter{Endpoint: "http://batch-collector:4317"}},
+					},
+				},
+			},
+		},
+		{
+			name: "valid selector",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					Selector: &InstrumentationSelector{
+						PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "my-app"}},
+						NamespaceSelector: &metav1.LabelSelector{},
+						Languages:         []Language{LanguageJava, LanguagePython},
+					},
+				},
+			},
+		},
+		{
+			name: "rollout policy with invalid maxConcurrent",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler:       Sampler{Type: AlwaysOn},
+					RolloutPolicy: &RolloutPolicy{RolloutOnChange: true, MaxConcurrent: ptr.To(int32(0))},
+				},
+			},
+			err: "spec.rolloutPolicy.maxConcurrent should be greater than or equal to 1: 0",
+		},
+		{
+			name: "rollout policy without rolloutOnChange",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler:       Sampler{Type: AlwaysOn},
+					RolloutPolicy: &RolloutPolicy{Namespaces: []string{"shop"}},
+				},
+			},
+			warnings: []string{"spec.rolloutPolicy is configured but rolloutOnChange is not enabled"},
+		},
+		{
+			name: "valid rollout policy",
+			inst: Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler:       Sampler{Type: AlwaysOn},
+					RolloutPolicy: &RolloutPolicy{RolloutOnChange: true, MaxConcurrent: ptr.To(int32(2)), Namespaces: []string{"shop"}},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -247,6 +635,78 @@ func TestInstrumentationValidatingWebhook(t *testing.T) {
 	}
 }
 
+func TestInstrumentationValidatingWebhookExporterLanguages(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      config.Config
+		exporter Exporter
+		warnings admission.Warnings
+	}{
+		{
+			name:     "grpc with python enabled",
+			cfg:      config.New(config.WithEnablePythonInstrumentation(true)),
+			exporter: Exporter{Endpoint: "http://collector:4317", Protocol: ExporterProtocolGRPC},
+			warnings: []string{"spec.exporter.protocol grpc is not supported by the Python auto-instrumentation, which only exports with http/protobuf"},
+		},
+		{
+			name:     "grpc traces with python enabled",
+			cfg:      config.New(config.WithEnablePythonInstrumentation(true)),
+			exporter: Exporter{Endpoint: "http://collector:4318", Traces: &SignalExporter{Protocol: ExporterProtocolGRPC}},
+			warnings: []string{"spec.exporter.traces.protocol grpc is not supported by the Python auto-instrumentation, which only exports with http/protobuf"},
+		},
+		{
+			name:     "http/protobuf and gzip with nginx and apache httpd enabled",
+			cfg:      config.New(config.WithEnableNginxInstrumentation(true), config.WithEnableApacheHttpdInstrumentation(true)),
+			exporter: Exporter{Endpoint: "http://collector:4318", Protocol: ExporterProtocolHTTPProtobuf, Compression: ExporterCompressionGzip},
+			warnings: []string{
+				"spec.exporter.protocol http/protobuf is ignored by the Apache HTTPD and Nginx auto-instrumentation, which only exports with grpc",
+				"spec.exporter.compression gzip is ignored by the Apache HTTPD and Nginx auto-instrumentation, which exports without compression",
+			},
+		},
+		{
+			name:     "grpc traces with nginx enabled",
+			cfg:      config.New(config.WithEnableNginxInstrumentation(true)),
+			exporter: Exporter{Endpoint: "http://collector:4318", Protocol: ExporterProtocolHTTPProtobuf, Traces: &SignalExporter{Protocol: ExporterProtocolGRPC}},
+		},
+		{
+			name:     "languages disabled",
+			cfg:      config.New(config.WithEnablePythonInstrumentation(false)),
+			exporter: Exporter{Endpoint: "http://collector:4317", Protocol: ExporterProtocolGRPC, Compression: ExporterCompressionGzip},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			inst := &Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler:  Sampler{Type: AlwaysOn},
+					Exporter: test.exporter,
+				},
+			}
+			warnings, err := InstrumentationWebhook{cfg: test.cfg}.ValidateCreate(context.Background(), inst)
+			assert.NoError(t, err)
+			assert.Equal(t, test.warnings, warnings)
+
+			// the exporters of the container overrides are checked the same way
+			overridden := &Instrumentation{
+				Spec: InstrumentationSpec{
+					Sampler: Sampler{Type: AlwaysOn},
+					ContainerOverrides: []ContainerOverride{
+						{ContainerNames: []string{"app"}, Exporter: &test.exporter},
+					},
+				},
+			}
+			warnings, err = InstrumentationWebhook{cfg: test.cfg}.ValidateCreate(context.Background(), overridden)
+			assert.NoError(t, err)
+			var expected admission.Warnings
+			for _, warning := range test.warnings {
+				expected = append(expected, strings.Replace(warning, "spec.exporter", "spec.containerOverrides[0].exporter", 1))
+			}
+			assert.Equal(t, expected, warnings)
+		})
+	}
+}
+
 func TestInstrumentationJaegerRemote(t *testing.T) {
 	tests := []struct {
 		name string
@@ -0,0 +1,44 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+type (
+	// Language represents an auto-instrumentation the operator can inject.
+	// +kubebuilder:validation:Enum=java;nodejs;python;dotnet;go;apache-httpd;nginx;ruby;php;sdk
+	Language string
+)
+
+const (
+	// LanguageJava represents the Java agent.
+	LanguageJava Language = "java"
+	// LanguageNodeJS represents the NodeJS SDK.
+	LanguageNodeJS Language = "nodejs"
+	// LanguagePython represents the Python SDK.
+	LanguagePython Language = "python"
+	// LanguageDotNet represents the .NET SDK.
+	LanguageDotNet Language = "dotnet"
+	// LanguageGo represents the Go eBPF instrumentation.
+	LanguageGo Language = "go"
+	// LanguageApacheHttpd represents the Apache HTTPD module.
+	LanguageApacheHttpd Language = "apache-httpd"
+	// LanguageNginx represents the Nginx module.
+	LanguageNginx Language = "nginx"
+	// LanguageRuby represents the Ruby SDK.
+	LanguageRuby Language = "ruby"
+	// LanguagePHP represents the PHP extension and SDK.
+	LanguagePHP Language = "php"
+	// LanguageSdk represents the SDK configuration only, without any auto-instrumentation.
+	LanguageSdk Language = "sdk"
+)
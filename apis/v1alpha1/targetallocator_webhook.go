@@ -28,6 +28,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-operator/internal/config"
 	"github.com/open-telemetry/opentelemetry-operator/internal/naming"
 	"github.com/open-telemetry/opentelemetry-operator/internal/rbac"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/featuregate"
 )
 
 var (
@@ -117,16 +118,25 @@ func (w TargetAllocatorWebhook) validate(ctx context.Context, ta *TargetAllocato
 		return warnings, err
 	}
 
+	saname := ta.Spec.ServiceAccount
+	if len(ta.Spec.ServiceAccount) == 0 {
+		saname = naming.TargetAllocatorServiceAccount(ta.Name)
+	}
 	// if the prometheusCR is enabled, it needs a suite of permissions to function
 	if ta.Spec.PrometheusCR.Enabled {
-		saname := ta.Spec.ServiceAccount
-		if len(ta.Spec.ServiceAccount) == 0 {
-			saname = naming.TargetAllocatorServiceAccount(ta.Name)
+		crWarnings, err := v1beta1.CheckTargetAllocatorPrometheusCRPolicyRules(ctx, w.reviewer, ta.GetNamespace(), saname)
+		if err != nil {
+			return crWarnings, err
 		}
-		warnings, err := v1beta1.CheckTargetAllocatorPrometheusCRPolicyRules(ctx, w.reviewer, ta.GetNamespace(), saname)
-		if err != nil || len(warnings) > 0 {
-			return warnings, err
+		warnings = append(warnings, crWarnings...)
+	}
+	// the replicas elect a leader through a lease
+	if featuregate.EnableTargetAllocatorLeaderElection.IsEnabled() && ta.Spec.Replicas != nil && *ta.Spec.Replicas > 1 {
+		leaseWarnings, err := v1beta1.CheckTargetAllocatorLeaderElectionPolicyRules(ctx, w.reviewer, ta.GetNamespace(), saname)
+		if err != nil {
+			return leaseWarnings, err
 		}
+		warnings = append(warnings, leaseWarnings...)
 	}
 
 	return warnings, nil
@@ -22,6 +22,8 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	colfeaturegate "go.opentelemetry.io/collector/featuregate"
 	authv1 "k8s.io/api/authorization/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -33,6 +35,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-operator/apis/v1beta1"
 	"github.com/open-telemetry/opentelemetry-operator/internal/config"
 	"github.com/open-telemetry/opentelemetry-operator/internal/rbac"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/featuregate"
 )
 
 func TestTargetAllocatorDefaultingWebhook(t *testing.T) {
@@ -339,6 +342,51 @@ func TestTargetAllocatorValidatingWebhook(t *testing.T) {
 	}
 }
 
+func TestTargetAllocatorValidatingWebhookLeaderElection(t *testing.T) {
+	require.NoError(t, colfeaturegate.GlobalRegistry().Set(featuregate.EnableTargetAllocatorLeaderElection.ID(), true))
+	t.Cleanup(func() {
+		require.NoError(t, colfeaturegate.GlobalRegistry().Set(featuregate.EnableTargetAllocatorLeaderElection.ID(), false))
+	})
+
+	newTargetAllocator := func(replicas int32) *TargetAllocator {
+		return &TargetAllocator{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-ta", Namespace: "test-ns"},
+			Spec: TargetAllocatorSpec{
+				OpenTelemetryCommonFields: v1beta1.OpenTelemetryCommonFields{Replicas: &replicas},
+			},
+		}
+	}
+	cvw := &TargetAllocatorWebhook{
+		logger:   logr.Discard(),
+		scheme:   testScheme,
+		cfg:      config.New(),
+		reviewer: getReviewer(true),
+	}
+
+	warnings, err := cvw.ValidateCreate(context.Background(), newTargetAllocator(2))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"missing the following rules for system:serviceaccount:test-ns:test-ta-targetallocator - coordination.k8s.io/leases: [get,create,update]",
+	}, []string(warnings))
+
+	// a single replica doesn't elect a leader
+	warnings, err = cvw.ValidateCreate(context.Background(), newTargetAllocator(1))
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+
+	// the lease is checked in the namespace of the target allocator
+	c := fake.NewSimpleClientset()
+	c.PrependReactor("create", "subjectaccessreviews", func(action kubeTesting.Action) (handled bool, ret runtime.Object, err error) {
+		sar := action.(kubeTesting.CreateAction).GetObject().DeepCopyObject().(*authv1.SubjectAccessReview)
+		sar.Status = authv1.SubjectAccessReviewStatus{Allowed: sar.Spec.ResourceAttributes.Namespace == "test-ns"}
+		return true, sar, nil
+	})
+	cvw.reviewer = rbac.NewReviewer(c)
+	warnings, err = cvw.ValidateCreate(context.Background(), newTargetAllocator(2))
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+}
+
 func getReviewer(shouldFailSAR bool) *rbac.Reviewer {
 	c := fake.NewSimpleClientset()
 	c.PrependReactor("create", "subjectaccessreviews", func(action kubeTesting.Action) (handled bool, ret runtime.Object, err error) {
@@ -17,13 +17,20 @@ package v1beta1
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/go-logr/logr"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/open-telemetry/opentelemetry-operator/internal/config"
@@ -52,6 +59,7 @@ type CollectorWebhook struct {
 	metrics  *Metrics
 	bv       BuildValidator
 	fips     fips.FIPSCheck
+	reader   client.Reader
 }
 
 func (c CollectorWebhook) Default(_ context.Context, obj runtime.Object) error {
@@ -118,6 +126,16 @@ func (c CollectorWebhook) ValidateCreate(ctx context.Context, obj runtime.Object
 	if err != nil {
 		return warnings, err
 	}
+	pipelineWarnings, err := c.validatePipelines(otelcol, nil)
+	warnings = append(warnings, pipelineWarnings...)
+	if err != nil {
+		return warnings, err
+	}
+	secretWarnings, err := c.validateSecretReferences(ctx, otelcol, nil)
+	warnings = append(warnings, secretWarnings...)
+	if err != nil {
+		return warnings, err
+	}
 	if c.metrics != nil {
 		c.metrics.create(ctx, otelcol)
 	}
@@ -146,6 +164,16 @@ func (c CollectorWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj run
 	if err != nil {
 		return warnings, err
 	}
+	pipelineWarnings, err := c.validatePipelines(otelcol, otelcolOld)
+	warnings = append(warnings, pipelineWarnings...)
+	if err != nil {
+		return warnings, err
+	}
+	secretWarnings, err := c.validateSecretReferences(ctx, otelcol, otelcolOld)
+	warnings = append(warnings, secretWarnings...)
+	if err != nil {
+		return warnings, err
+	}
 
 	if c.metrics != nil {
 		c.metrics.update(ctx, otelcolOld, otelcol)
@@ -184,6 +212,10 @@ func (c CollectorWebhook) Validate(ctx context.Context, r *OpenTelemetryCollecto
 		warnings = append(warnings, fmt.Sprintf("Collector config spec.config has null objects: %s. For compatibility with other tooling, such as kustomize and kubectl edit, it is recommended to use empty objects e.g. batch: {}.", strings.Join(nullObjects, ", ")))
 	}
 
+	if len(r.Spec.Config.Templates) > 0 && !featuregate.EnableCollectorConfigTemplates.IsEnabled() {
+		return warnings, fmt.Errorf("the OpenTelemetry Collector configuration references templates, which requires the %s feature gate", featuregate.EnableCollectorConfigTemplates.ID())
+	}
+
 	// validate volumeClaimTemplates
 	if r.Spec.Mode != ModeStatefulSet && len(r.Spec.VolumeClaimTemplates) > 0 {
 		return warnings, fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'volumeClaimTemplates'", r.Spec.Mode)
@@ -302,7 +334,7 @@ func (c CollectorWebhook) Validate(ctx context.Context, r *OpenTelemetryCollecto
 
 	if c.fips != nil {
 		components := r.Spec.Config.GetEnabledComponents()
-		if notAllowedComponents := c.fips.DisabledComponents(components[KindReceiver], components[KindExporter], components[KindProcessor], components[KindExtension]); notAllowedComponents != nil {
+		if notAllowedComponents := c.fips.DisabledComponents(components[KindReceiver], components[KindExporter], components[KindProcessor], components[KindExtension], components[KindConnector]); notAllowedComponents != nil {
 			return nil, fmt.Errorf("the collector configuration contains not FIPS compliant components: %s. Please remove it from the config", notAllowedComponents)
 		}
 	}
@@ -340,20 +372,124 @@ func (c CollectorWebhook) validateTargetAllocatorConfig(ctx context.Context, r *
 	if err != nil {
 		return nil, fmt.Errorf("the OpenTelemetry Spec Prometheus configuration is incorrect, %w", err)
 	}
+	saname := r.Spec.TargetAllocator.ServiceAccount
+	if len(r.Spec.TargetAllocator.ServiceAccount) == 0 {
+		saname = naming.TargetAllocatorServiceAccount(r.Name)
+	}
+	var warnings admission.Warnings
 	// if the prometheusCR is enabled, it needs a suite of permissions to function
 	if r.Spec.TargetAllocator.PrometheusCR.Enabled {
-		saname := r.Spec.TargetAllocator.ServiceAccount
-		if len(r.Spec.TargetAllocator.ServiceAccount) == 0 {
-			saname = naming.TargetAllocatorServiceAccount(r.Name)
+		crWarnings, err := CheckTargetAllocatorPrometheusCRPolicyRules(
+			ctx, c.reviewer, r.GetNamespace(), saname)
+		if err != nil {
+			return crWarnings, err
 		}
-		warnings, err := CheckTargetAllocatorPrometheusCRPolicyRules(
+		warnings = append(warnings, crWarnings...)
+	}
+	// the replicas elect a leader through a lease
+	if featuregate.EnableTargetAllocatorLeaderElection.IsEnabled() && r.Spec.TargetAllocator.Replicas != nil && *r.Spec.TargetAllocator.Replicas > 1 {
+		leaseWarnings, err := CheckTargetAllocatorLeaderElectionPolicyRules(
 			ctx, c.reviewer, r.GetNamespace(), saname)
-		if err != nil || len(warnings) > 0 {
-			return warnings, err
+		if err != nil {
+			return leaseWarnings, err
+		}
+		warnings = append(warnings, leaseWarnings...)
+	}
+
+	return warnings, nil
+}
+
+// validatePipelines checks that the pipelines reference the components of the config. A collector being deleted isn't
+// checked, so that removing its finalizer isn't denied. On updates, only the errors which aren't in the old config deny
+// the update, the others being reported as warnings, so that collectors created before this validation can still be
+// updated.
+func (c CollectorWebhook) validatePipelines(r *OpenTelemetryCollector, old *OpenTelemetryCollector) (admission.Warnings, error) {
+	// the components of the pipelines can be defined in the templates, the pipelines are validated when the templates
+	// are merged during the reconciliation
+	if r.GetDeletionTimestamp() != nil || len(r.Spec.Config.Templates) > 0 {
+		return nil, nil
+	}
+	path := field.NewPath("spec", "config")
+	warnings, errs := r.Spec.Config.ValidatePipelines(path)
+	if len(errs) > 0 && old != nil && len(old.Spec.Config.Templates) == 0 {
+		_, oldErrs := old.Spec.Config.ValidatePipelines(path)
+		existing := map[string]struct{}{}
+		for _, err := range oldErrs {
+			existing[err.Error()] = struct{}{}
+		}
+		var newErrs field.ErrorList
+		for _, err := range errs {
+			if _, ok := existing[err.Error()]; ok {
+				warnings = append(warnings, fmt.Sprintf("the OpenTelemetry Collector configuration is incorrect: %s", err.Error()))
+				continue
+			}
+			newErrs = append(newErrs, err)
+		}
+		errs = newErrs
+	}
+	if len(errs) > 0 {
+		return warnings, fmt.Errorf("the OpenTelemetry Collector configuration is incorrect: %w", errs.ToAggregate())
+	}
+	return warnings, nil
+}
+
+// validateSecretReferences checks that the Secrets referenced by the config exist and have the referenced keys, and
+// warns when the service account of the collector can't read them. On updates, only the references which aren't in the
+// old config are checked, so that updating a collector whose Secret has been removed, for instance to remove its
+// finalizer, isn't denied.
+func (c CollectorWebhook) validateSecretReferences(ctx context.Context, r *OpenTelemetryCollector, old *OpenTelemetryCollector) (admission.Warnings, error) {
+	refs, err := r.Spec.Config.SecretReferences()
+	if err != nil {
+		return nil, fmt.Errorf("the OpenTelemetry Collector configuration is incorrect: %w", err)
+	}
+	if old != nil {
+		oldRefs, oldErr := old.Spec.Config.SecretReferences()
+		if oldErr == nil {
+			refs = slices.DeleteFunc(refs, func(ref SecretReference) bool {
+				return slices.Contains(oldRefs, ref)
+			})
 		}
 	}
 
-	return nil, nil
+	var warnings admission.Warnings
+	reviewed := map[string]bool{}
+	for _, ref := range refs {
+		if c.reader != nil {
+			secret := corev1.Secret{}
+			if err = c.reader.Get(ctx, types.NamespacedName{Namespace: r.Namespace, Name: ref.Name}, &secret); err != nil {
+				if apierrors.IsNotFound(err) {
+					return warnings, fmt.Errorf("the Secret %s referenced by %s doesn't exist", ref.Name, ref)
+				}
+				return warnings, fmt.Errorf("unable to get the Secret %s referenced by %s: %w", ref.Name, ref, err)
+			}
+			if _, ok := secret.Data[ref.Key]; !ok {
+				return warnings, fmt.Errorf("the Secret %s referenced by %s has no key %s", ref.Name, ref, ref.Key)
+			}
+		}
+
+		// sidecars run with the service account of the pod they are injected in
+		if c.reviewer == nil || r.Spec.Mode == ModeSidecar || reviewed[ref.Name] {
+			continue
+		}
+		reviewed[ref.Name] = true
+		saName := r.Spec.ServiceAccount
+		if len(saName) == 0 {
+			saName = naming.ServiceAccount(r.Name)
+		}
+		review, err := c.reviewer.CanAccess(ctx, saName, r.Namespace, &authorizationv1.ResourceAttributes{
+			Namespace: r.Namespace,
+			Verb:      "get",
+			Resource:  "secrets",
+			Name:      ref.Name,
+		}, nil)
+		if err != nil {
+			return warnings, fmt.Errorf("unable to check rbac rules %w", err)
+		}
+		if allowed, _ := rbac.AllSubjectAccessReviewsAllowed([]*authorizationv1.SubjectAccessReview{review}); !allowed {
+			warnings = append(warnings, fmt.Sprintf("the service account %s can't read the Secret %s referenced by spec.config", saName, ref.Name))
+		}
+	}
+	return warnings, nil
 }
 
 func ValidateProbe(probeName string, probe *Probe) error {
@@ -445,6 +581,7 @@ func NewCollectorWebhook(
 	metrics *Metrics,
 	bv BuildValidator,
 	fips fips.FIPSCheck,
+	reader client.Reader,
 ) *CollectorWebhook {
 	return &CollectorWebhook{
 		logger:   logger,
@@ -454,11 +591,12 @@ func NewCollectorWebhook(
 		metrics:  metrics,
 		bv:       bv,
 		fips:     fips,
+		reader:   reader,
 	}
 }
 
 func SetupCollectorWebhook(mgr ctrl.Manager, cfg config.Config, reviewer *rbac.Reviewer, metrics *Metrics, bv BuildValidator, fipsCheck fips.FIPSCheck) error {
-	cvw := NewCollectorWebhook(mgr.GetLogger().WithValues("handler", "CollectorWebhook", "version", "v1beta1"), mgr.GetScheme(), cfg, reviewer, metrics, bv, fipsCheck)
+	cvw := NewCollectorWebhook(mgr.GetLogger().WithValues("handler", "CollectorWebhook", "version", "v1beta1"), mgr.GetScheme(), cfg, reviewer, metrics, bv, fipsCheck, mgr.GetAPIReader())
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&OpenTelemetryCollector{}).
 		WithValidator(cvw).
@@ -24,6 +24,7 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	colfeaturegate "go.opentelemetry.io/collector/featuregate"
 	"gopkg.in/yaml.v3"
 	appsv1 "k8s.io/api/apps/v1"
 	authv1 "k8s.io/api/authorization/v1"
@@ -36,6 +37,7 @@ import (
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/scheme"
 	kubeTesting "k8s.io/client-go/testing"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/open-telemetry/opentelemetry-operator/apis/v1beta1"
@@ -43,6 +45,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-operator/internal/manifests"
 	collectorManifests "github.com/open-telemetry/opentelemetry-operator/internal/manifests/collector"
 	"github.com/open-telemetry/opentelemetry-operator/internal/rbac"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/featuregate"
 )
 
 var (
@@ -115,6 +118,7 @@ func TestValidate(t *testing.T) {
 			nil,
 			bv,
 			nil,
+			nil,
 		)
 		t.Run(tt.name, func(t *testing.T) {
 			tt := tt
@@ -551,6 +555,7 @@ func TestCollectorDefaultingWebhook(t *testing.T) {
 				nil,
 				bv,
 				nil,
+				nil,
 			)
 			ctx := context.Background()
 			err := cvw.Default(ctx, &test.otelcol)
@@ -579,6 +584,9 @@ var cfgYaml = `receivers:
        endpoint: 0.0.0.0:15268
 `
 
+// cfgYamlUnusedWarning is the warning about the receivers of cfgYaml, which no pipeline uses.
+const cfgYamlUnusedWarning = "Collector config spec.config has unused components: receivers.examplereceiver, receivers.examplereceiver/settings, receivers.jaeger/custom, receivers.prometheus. They are not used by any pipeline or, for extensions, not enabled in the service."
+
 func TestOTELColValidatingWebhook(t *testing.T) {
 	minusOne := int32(-1)
 	zero := int32(0)
@@ -656,6 +664,7 @@ func TestOTELColValidatingWebhook(t *testing.T) {
 					Config: cfg,
 				},
 			},
+			expectedWarnings: []string{cfgYamlUnusedWarning},
 		},
 		{
 			name:          "prom CR admissions warning",
@@ -707,6 +716,7 @@ func TestOTELColValidatingWebhook(t *testing.T) {
 				},
 			},
 			expectedWarnings: []string{
+				cfgYamlUnusedWarning,
 				"missing the following rules for system:serviceaccount:test-ns:adm-warning-targetallocator - monitoring.coreos.com/servicemonitors: [*]",
 				"missing the following rules for system:serviceaccount:test-ns:adm-warning-targetallocator - monitoring.coreos.com/podmonitors: [*]",
 				"missing the following rules for system:serviceaccount:test-ns:adm-warning-targetallocator - nodes/metrics: [get,list,watch]",
@@ -764,6 +774,38 @@ func TestOTELColValidatingWebhook(t *testing.T) {
 					Config: cfg,
 				},
 			},
+			expectedWarnings: []string{cfgYamlUnusedWarning},
+		},
+		{
+			name: "pipeline referencing an undefined receiver",
+			otelcol: v1beta1.OpenTelemetryCollector{
+				Spec: v1beta1.OpenTelemetryCollectorSpec{
+					Config: v1beta1.Config{
+						Receivers: v1beta1.AnyConfig{Object: map[string]interface{}{"otlp": map[string]interface{}{}}},
+						Exporters: v1beta1.AnyConfig{Object: map[string]interface{}{"debug": map[string]interface{}{}}},
+						Service: v1beta1.Service{
+							Pipelines: map[string]*v1beta1.Pipeline{
+								"traces": {Receivers: []string{"otlp/missing"}, Exporters: []string{"debug"}},
+							},
+						},
+					},
+				},
+			},
+			expectedErr: `spec.config.service.pipelines[traces].receivers[0]: Invalid value: "otlp/missing": receiver is not defined in receivers or connectors`,
+			expectedWarnings: []string{
+				"Collector config spec.config has unused components: receivers.otlp. They are not used by any pipeline or, for extensions, not enabled in the service.",
+			},
+		},
+		{
+			name: "config templates without the feature gate",
+			otelcol: v1beta1.OpenTelemetryCollector{
+				Spec: v1beta1.OpenTelemetryCollectorSpec{
+					Config: v1beta1.Config{
+						Templates: []v1beta1.ConfigTemplateReference{{Name: "exporters"}},
+					},
+				},
+			},
+			expectedErr: "requires the operator.collector.configtemplates feature gate",
 		},
 		{
 			name: "invalid mode with volume claim templates",
@@ -1408,6 +1450,7 @@ func TestOTELColValidatingWebhook(t *testing.T) {
 				nil,
 				bv,
 				nil,
+				nil,
 			)
 			ctx := context.Background()
 			warnings, err := cvw.ValidateCreate(ctx, &test.otelcol)
@@ -1422,6 +1465,234 @@ func TestOTELColValidatingWebhook(t *testing.T) {
 	}
 }
 
+func TestOTELColValidatingWebhookInvalidPipelines(t *testing.T) {
+	// the collector was created before the pipelines were validated, its traces pipeline references a missing receiver
+	otelcol := v1beta1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{Name: "simplest", Namespace: "observability"},
+		Spec: v1beta1.OpenTelemetryCollectorSpec{
+			Mode: v1beta1.ModeDeployment,
+			Config: v1beta1.Config{
+				Receivers: v1beta1.AnyConfig{Object: map[string]interface{}{"otlp": map[string]interface{}{}}},
+				Exporters: v1beta1.AnyConfig{Object: map[string]interface{}{"debug": map[string]interface{}{}}},
+				Service: v1beta1.Service{
+					Pipelines: map[string]*v1beta1.Pipeline{
+						"traces": {Receivers: []string{"otlp", "otlp/missing"}, Exporters: []string{"debug"}},
+					},
+				},
+			},
+		},
+	}
+	bv := func(_ context.Context, _ v1beta1.OpenTelemetryCollector) admission.Warnings {
+		return nil
+	}
+	cvw := v1beta1.NewCollectorWebhook(
+		logr.Discard(),
+		testScheme,
+		config.New(
+			config.WithCollectorImage("collector:v0.0.0"),
+			config.WithTargetAllocatorImage("ta:v0.0.0"),
+		),
+		getReviewer(false),
+		nil,
+		bv,
+		nil,
+		nil,
+	)
+	ctx := context.Background()
+	const existingErr = `spec.config.service.pipelines[traces].receivers[1]: Invalid value: "otlp/missing": receiver is not defined in receivers or connectors`
+
+	_, err := cvw.ValidateCreate(ctx, &otelcol)
+	assert.ErrorContains(t, err, existingErr)
+
+	t.Run("delete", func(t *testing.T) {
+		_, err := cvw.ValidateDelete(ctx, &otelcol)
+		assert.NoError(t, err)
+	})
+
+	t.Run("finalizer removal", func(t *testing.T) {
+		deleted := otelcol.DeepCopy()
+		now := metav1.Now()
+		deleted.DeletionTimestamp = &now
+		updated := deleted.DeepCopy()
+		updated.Finalizers = nil
+		_, err := cvw.ValidateUpdate(ctx, deleted, updated)
+		assert.NoError(t, err)
+	})
+
+	t.Run("update keeping the existing errors", func(t *testing.T) {
+		updated := otelcol.DeepCopy()
+		two := int32(2)
+		updated.Spec.Replicas = &two
+		warnings, err := cvw.ValidateUpdate(ctx, &otelcol, updated)
+		assert.NoError(t, err)
+		assert.Contains(t, warnings, "the OpenTelemetry Collector configuration is incorrect: "+existingErr)
+	})
+
+	t.Run("update adding an error", func(t *testing.T) {
+		updated := otelcol.DeepCopy()
+		updated.Spec.Config.Service.Pipelines["traces"].Exporters = []string{"debug", "otlp/missing"}
+		_, err := cvw.ValidateUpdate(ctx, &otelcol, updated)
+		assert.ErrorContains(t, err, `spec.config.service.pipelines[traces].exporters[1]: Invalid value: "otlp/missing": exporter is not defined in exporters or connectors`)
+		assert.NotContains(t, err.Error(), existingErr)
+	})
+}
+
+func TestOTELColValidatingWebhookConfigTemplates(t *testing.T) {
+	require.NoError(t, colfeaturegate.GlobalRegistry().Set(featuregate.EnableCollectorConfigTemplates.ID(), true))
+	t.Cleanup(func() {
+		require.NoError(t, colfeaturegate.GlobalRegistry().Set(featuregate.EnableCollectorConfigTemplates.ID(), false))
+	})
+
+	// the exporter of the pipeline is defined in a template, the pipelines are validated once the templates are merged
+	otelcol := v1beta1.OpenTelemetryCollector{
+		Spec: v1beta1.OpenTelemetryCollectorSpec{
+			Mode: v1beta1.ModeDeployment,
+			Config: v1beta1.Config{
+				Receivers: v1beta1.AnyConfig{Object: map[string]interface{}{"otlp": map[string]interface{}{}}},
+				Service: v1beta1.Service{
+					Pipelines: map[string]*v1beta1.Pipeline{
+						"traces": {Receivers: []string{"otlp"}, Exporters: []string{"otlp/backend"}},
+					},
+				},
+				Templates: []v1beta1.ConfigTemplateReference{
+					{Kind: v1beta1.ConfigTemplateKindCluster, Name: "backend"},
+				},
+			},
+		},
+	}
+	bv := func(_ context.Context, _ v1beta1.OpenTelemetryCollector) admission.Warnings {
+		return nil
+	}
+	cvw := v1beta1.NewCollectorWebhook(
+		logr.Discard(),
+		testScheme,
+		config.New(
+			config.WithCollectorImage("collector:v0.0.0"),
+			config.WithTargetAllocatorImage("ta:v0.0.0"),
+		),
+		getReviewer(false),
+		nil,
+		bv,
+		nil,
+		nil,
+	)
+
+	warnings, err := cvw.ValidateCreate(context.Background(), &otelcol)
+	assert.NoError(t, err)
+	assert.Empty(t, warnings)
+}
+
+func TestOTELColValidatingWebhookSecretReferences(t *testing.T) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend", Namespace: "observability"},
+		Data:       map[string][]byte{"api-key": []byte("secret")},
+	}
+	newCollector := func(headers map[string]interface{}) v1beta1.OpenTelemetryCollector {
+		return v1beta1.OpenTelemetryCollector{
+			ObjectMeta: metav1.ObjectMeta{Name: "simplest", Namespace: "observability"},
+			Spec: v1beta1.OpenTelemetryCollectorSpec{
+				Mode: v1beta1.ModeDeployment,
+				Config: v1beta1.Config{
+					Receivers: v1beta1.AnyConfig{Object: map[string]interface{}{"otlp": map[string]interface{}{}}},
+					Exporters: v1beta1.AnyConfig{Object: map[string]interface{}{
+						"otlp": map[string]interface{}{"endpoint": "backend:4317", "headers": headers},
+					}},
+					Service: v1beta1.Service{
+						Pipelines: map[string]*v1beta1.Pipeline{
+							"traces": {Receivers: []string{"otlp"}, Exporters: []string{"otlp"}},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct { //nolint:govet
+		name             string
+		otelcolOld       *v1beta1.OpenTelemetryCollector
+		otelcol          v1beta1.OpenTelemetryCollector
+		shouldFailSar    bool
+		expectedErr      string
+		expectedWarnings []string
+	}{
+		{
+			name:    "existing secret and key",
+			otelcol: newCollector(map[string]interface{}{"api-key": "${secret:backend/api-key}"}),
+		},
+		{
+			name:        "invalid reference",
+			otelcol:     newCollector(map[string]interface{}{"api-key": "${secret:backend}"}),
+			expectedErr: "invalid secret reference ${secret:backend}",
+		},
+		{
+			name:        "missing secret",
+			otelcol:     newCollector(map[string]interface{}{"api-key": "${secret:other/api-key}"}),
+			expectedErr: "the Secret other referenced by ${secret:other/api-key} doesn't exist",
+		},
+		{
+			name:        "missing key",
+			otelcol:     newCollector(map[string]interface{}{"api-key": "${secret:backend/token}"}),
+			expectedErr: "the Secret backend referenced by ${secret:backend/token} has no key token",
+		},
+		{
+			name:          "service account can't read the secret",
+			otelcol:       newCollector(map[string]interface{}{"api-key": "${secret:backend/api-key}"}),
+			shouldFailSar: true,
+			expectedWarnings: []string{
+				"the service account simplest-collector can't read the Secret backend referenced by spec.config",
+			},
+		},
+		{
+			name: "unchanged reference on update",
+			otelcolOld: func() *v1beta1.OpenTelemetryCollector {
+				otelcol := newCollector(map[string]interface{}{"api-key": "${secret:other/api-key}"})
+				return &otelcol
+			}(),
+			otelcol: newCollector(map[string]interface{}{"api-key": "${secret:other/api-key}", "tenant": "a"}),
+		},
+		{
+			name: "new reference on update",
+			otelcolOld: func() *v1beta1.OpenTelemetryCollector {
+				otelcol := newCollector(map[string]interface{}{"api-key": "${secret:backend/api-key}"})
+				return &otelcol
+			}(),
+			otelcol:     newCollector(map[string]interface{}{"api-key": "${secret:backend/api-key}", "tenant": "${secret:tenant/id}"}),
+			expectedErr: "the Secret tenant referenced by ${secret:tenant/id} doesn't exist",
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			cvw := v1beta1.NewCollectorWebhook(
+				logr.Discard(),
+				testScheme,
+				config.New(
+					config.WithCollectorImage("collector:v0.0.0"),
+					config.WithTargetAllocatorImage("ta:v0.0.0"),
+				),
+				getReviewer(test.shouldFailSar),
+				nil,
+				nil,
+				nil,
+				ctrlfake.NewClientBuilder().WithObjects(secret).Build(),
+			)
+			var warnings admission.Warnings
+			var err error
+			if test.otelcolOld == nil {
+				warnings, err = cvw.ValidateCreate(context.Background(), &test.otelcol)
+			} else {
+				warnings, err = cvw.ValidateUpdate(context.Background(), test.otelcolOld, &test.otelcol)
+			}
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expectedErr)
+			}
+			assert.ElementsMatch(t, test.expectedWarnings, warnings)
+		})
+	}
+}
+
 func TestOTELColValidateUpdateWebhook(t *testing.T) {
 	tests := []struct { //nolint:govet
 		name             string
@@ -1476,6 +1747,7 @@ func TestOTELColValidateUpdateWebhook(t *testing.T) {
 				nil,
 				bv,
 				nil,
+				nil,
 			)
 			ctx := context.Background()
 			warnings, err := cvw.ValidateUpdate(ctx, &test.otelcolOld, &test.otelcolNew)
@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+const (
+	// ConditionTypeReady indicates that the collector workload has been rolled out and all of its pods are ready.
+	ConditionTypeReady = "Ready"
+
+	// ConditionTypeReconciled indicates whether the last reconciliation of the OpenTelemetryCollector succeeded.
+	ConditionTypeReconciled = "Reconciled"
+
+	// ConditionTypeConfigValid indicates whether the collector configuration could be turned into manifests.
+	ConditionTypeConfigValid = "ConfigValid"
+
+	// ConditionTypeDegraded indicates that the collector is not operating as desired, either because the last
+	// reconciliation failed or because the workload failed to roll out.
+	ConditionTypeDegraded = "Degraded"
+)
+
+const (
+	// ReasonReconcileSucceeded is used when all the manifests for the collector were applied.
+	ReasonReconcileSucceeded = "ReconcileSucceeded"
+
+	// ReasonReconcileFailed is used when the operator could not apply the manifests for the collector.
+	ReasonReconcileFailed = "ReconcileFailed"
+
+	// ReasonConfigValid is used when the collector configuration was accepted.
+	ReasonConfigValid = "ConfigValid"
+
+	// ReasonInvalidConfig is used when the collector configuration could not be turned into manifests.
+	ReasonInvalidConfig = "InvalidConfig"
+
+	// ReasonWorkloadReady is used when all the pods of the collector workload are updated and ready.
+	ReasonWorkloadReady = "WorkloadReady"
+
+	// ReasonWorkloadProgressing is used while the collector workload is still rolling out.
+	ReasonWorkloadProgressing = "WorkloadProgressing"
+
+	// ReasonWorkloadFailed is used when the collector workload reports that it cannot make progress.
+	ReasonWorkloadFailed = "WorkloadFailed"
+
+	// ReasonWorkloadNotFound is used when the collector workload does not exist (yet).
+	ReasonWorkloadNotFound = "WorkloadNotFound"
+
+	// ReasonSidecarMode is used for sidecar collectors, which have no workload of their own.
+	ReasonSidecarMode = "SidecarMode"
+
+	// ReasonAsExpected is used for conditions in their healthy state when no other reason applies.
+	ReasonAsExpected = "AsExpected"
+)
@@ -32,6 +32,7 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 
 	"github.com/open-telemetry/opentelemetry-operator/internal/components"
+	"github.com/open-telemetry/opentelemetry-operator/internal/components/connectors"
 	"github.com/open-telemetry/opentelemetry-operator/internal/components/exporters"
 	"github.com/open-telemetry/opentelemetry-operator/internal/components/extensions"
 	"github.com/open-telemetry/opentelemetry-operator/internal/components/processors"
@@ -45,10 +46,11 @@ const (
 	KindExporter
 	KindProcessor
 	KindExtension
+	KindConnector
 )
 
 func (c ComponentKind) String() string {
-	return [...]string{"receiver", "exporter", "processor", "extension"}[c]
+	return [...]string{"receiver", "exporter", "processor", "extension", "connector"}[c]
 }
 
 // AnyConfig represent parts of the config.
@@ -106,13 +108,15 @@ type Pipeline struct {
 	Receivers  []string `json:"receivers" yaml:"receivers"`
 }
 
-// GetEnabledComponents constructs a list of enabled components by component type.
+// GetEnabledComponents constructs a list of enabled components by component type. The connectors of the pipelines,
+// which are used as both receivers and exporters, are listed as connectors.
 func (c *Config) GetEnabledComponents() map[ComponentKind]map[string]interface{} {
 	toReturn := map[ComponentKind]map[string]interface{}{
 		KindReceiver:  {},
 		KindProcessor: {},
 		KindExporter:  {},
 		KindExtension: {},
+		KindConnector: {},
 	}
 	for _, extension := range c.Service.Extensions {
 		toReturn[KindExtension][extension] = struct{}{}
@@ -123,10 +127,10 @@ func (c *Config) GetEnabledComponents() map[ComponentKind]map[string]interface{}
 			continue
 		}
 		for _, componentId := range pipeline.Receivers {
-			toReturn[KindReceiver][componentId] = struct{}{}
+			toReturn[c.receiverOrConnector(componentId)][componentId] = struct{}{}
 		}
 		for _, componentId := range pipeline.Exporters {
-			toReturn[KindExporter][componentId] = struct{}{}
+			toReturn[c.exporterOrConnector(componentId)][componentId] = struct{}{}
 		}
 		for _, componentId := range pipeline.Processors {
 			toReturn[KindProcessor][componentId] = struct{}{}
@@ -138,6 +142,30 @@ func (c *Config) GetEnabledComponents() map[ComponentKind]map[string]interface{}
 	return toReturn
 }
 
+// receiverOrConnector returns whether the receiver of a pipeline is a connector, if it's only defined as a connector.
+func (c *Config) receiverOrConnector(componentId string) ComponentKind {
+	if _, ok := c.Receivers.Object[componentId]; !ok && c.isConnector(componentId) {
+		return KindConnector
+	}
+	return KindReceiver
+}
+
+// exporterOrConnector returns whether the exporter of a pipeline is a connector, if it's only defined as a connector.
+func (c *Config) exporterOrConnector(componentId string) ComponentKind {
+	if _, ok := c.Exporters.Object[componentId]; !ok && c.isConnector(componentId) {
+		return KindConnector
+	}
+	return KindExporter
+}
+
+func (c *Config) isConnector(componentId string) bool {
+	if c.Connectors == nil {
+		return false
+	}
+	_, ok := c.Connectors.Object[componentId]
+	return ok
+}
+
 // Config encapsulates collector config.
 type Config struct {
 	// +kubebuilder:pruning:PreserveUnknownFields
@@ -151,6 +179,11 @@ type Config struct {
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Extensions *AnyConfig `json:"extensions,omitempty" yaml:"extensions,omitempty"`
 	Service    Service    `json:"service" yaml:"service"`
+	// Templates are the CollectorConfigTemplates and ClusterCollectorConfigTemplates merged with this config, in order.
+	// A template takes precedence over the templates listed before it, and this config takes precedence over all of them.
+	// +optional
+	// +listType=atomic
+	Templates []ConfigTemplateReference `json:"templates,omitempty" yaml:"templates,omitempty"`
 }
 
 // getRbacRulesForComponentKinds gets the RBAC Rules for the given ComponentKind(s).
@@ -176,6 +209,13 @@ func (c *Config) getRbacRulesForComponentKinds(logger logr.Logger, componentKind
 			}
 		case KindExtension:
 			continue
+		case KindConnector:
+			retriever = connectors.ConnectorFor
+			if c.Connectors == nil {
+				cfg = AnyConfig{}
+			} else {
+				cfg = *c.Connectors
+			}
 		}
 		for componentName := range enabledComponents[componentKind] {
 			// TODO: Clean up the naming here and make it simpler to use a retriever.
@@ -213,6 +253,13 @@ func (c *Config) getPortsForComponentKinds(logger logr.Logger, componentKinds ..
 			} else {
 				cfg = *c.Extensions
 			}
+		case KindConnector:
+			retriever = connectors.ConnectorFor
+			if c.Connectors == nil {
+				cfg = AnyConfig{}
+			} else {
+				cfg = *c.Connectors
+			}
 		}
 		for componentName := range enabledComponents[componentKind] {
 			// TODO: Clean up the naming here and make it simpler to use a retriever.
@@ -250,6 +297,13 @@ func (c *Config) getEnvironmentVariablesForComponentKinds(logger logr.Logger, co
 			continue
 		case KindExtension:
 			continue
+		case KindConnector:
+			retriever = connectors.ConnectorFor
+			if c.Connectors == nil {
+				cfg = AnyConfig{}
+			} else {
+				cfg = *c.Connectors
+			}
 		}
 		for componentName := range enabledComponents[componentKind] {
 			parser := retriever(componentName)
@@ -287,6 +341,8 @@ func (c *Config) applyDefaultForComponentKinds(logger logr.Logger, componentKind
 			continue
 		case KindExtension:
 			continue
+		case KindConnector:
+			continue
 		}
 		for componentName := range enabledComponents[componentKind] {
 			parser := retriever(componentName)
@@ -333,15 +389,15 @@ func (c *Config) GetReceiverAndExporterPorts(logger logr.Logger) ([]corev1.Servi
 }
 
 func (c *Config) GetAllPorts(logger logr.Logger) ([]corev1.ServicePort, error) {
-	return c.getPortsForComponentKinds(logger, KindReceiver, KindExporter, KindExtension)
+	return c.getPortsForComponentKinds(logger, KindReceiver, KindExporter, KindExtension, KindConnector)
 }
 
 func (c *Config) GetEnvironmentVariables(logger logr.Logger) ([]corev1.EnvVar, error) {
-	return c.getEnvironmentVariablesForComponentKinds(logger, KindReceiver)
+	return c.getEnvironmentVariablesForComponentKinds(logger, KindReceiver, KindConnector)
 }
 
 func (c *Config) GetAllRbacRules(logger logr.Logger) ([]rbacv1.PolicyRule, error) {
-	return c.getRbacRulesForComponentKinds(logger, KindReceiver, KindExporter, KindProcessor)
+	return c.getRbacRulesForComponentKinds(logger, KindReceiver, KindExporter, KindProcessor, KindConnector)
 }
 
 func (c *Config) ApplyDefaults(logger logr.Logger) error {
@@ -0,0 +1,156 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// secretReferenceRegexp matches the references to a key of a Secret in the config, written ${secret:name/key}. The
+	// dollar signs before a reference are matched to tell the escaped references, written $${secret:name/key}, apart.
+	secretReferenceRegexp = regexp.MustCompile(`(\$+)\{secret:([^}]*)\}`)
+	// secretReferenceValueRegexp matches the name of the Secret and the key of a reference.
+	secretReferenceValueRegexp = regexp.MustCompile(`^([a-z0-9]([-a-z0-9.]*[a-z0-9])?)/([-._a-zA-Z0-9]+)$`)
+	envVarNameReplacer         = regexp.MustCompile(`[^A-Z0-9_]`)
+)
+
+// SecretReference is a reference to a key of a Secret in the namespace of the collector, written ${secret:name/key} in
+// the config. The collector reads the value of the key from an environment variable.
+type SecretReference struct {
+	// Name of the Secret.
+	Name string
+	// Key of the Secret.
+	Key string
+}
+
+// EnvVarName returns the name of the environment variable holding the value of the referenced key.
+func (r SecretReference) EnvVarName() string {
+	return envVarNameReplacer.ReplaceAllString(strings.ToUpper(fmt.Sprintf("OTEL_SECRET_%s_%s", r.Name, r.Key)), "_")
+}
+
+func (r SecretReference) String() string {
+	return fmt.Sprintf("${secret:%s/%s}", r.Name, r.Key)
+}
+
+// SecretReferences returns the Secret references of the components of the config, sorted by Secret and key.
+func (c *Config) SecretReferences() ([]SecretReference, error) {
+	refs := map[SecretReference]struct{}{}
+	if _, err := c.replaceSecretReferences(func(ref SecretReference) string {
+		refs[ref] = struct{}{}
+		return ref.String()
+	}); err != nil {
+		return nil, err
+	}
+
+	sorted := make([]SecretReference, 0, len(refs))
+	for ref := range refs {
+		sorted = append(sorted, ref)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Name != sorted[j].Name {
+			return sorted[i].Name < sorted[j].Name
+		}
+		return sorted[i].Key < sorted[j].Key
+	})
+	envVars := map[string]SecretReference{}
+	for _, ref := range sorted {
+		if other, ok := envVars[ref.EnvVarName()]; ok {
+			return nil, fmt.Errorf("the secret references %s and %s use the same environment variable %s", other, ref, ref.EnvVarName())
+		}
+		envVars[ref.EnvVarName()] = ref
+	}
+	return sorted, nil
+}
+
+// WithSecretReferencesAsEnv returns a copy of the config where the Secret references are replaced by references to the
+// environment variables holding their values, written ${env:NAME}.
+func (c *Config) WithSecretReferencesAsEnv() (*Config, error) {
+	return c.replaceSecretReferences(func(ref SecretReference) string {
+		return fmt.Sprintf("${env:%s}", ref.EnvVarName())
+	})
+}
+
+// replaceSecretReferences returns a copy of the config where the Secret references in the configuration of the
+// components are replaced by the result of replace.
+func (c *Config) replaceSecretReferences(replace func(ref SecretReference) string) (*Config, error) {
+	var errs []string
+	replaceString := func(s string) string {
+		return secretReferenceRegexp.ReplaceAllStringFunc(s, func(match string) string {
+			groups := secretReferenceRegexp.FindStringSubmatch(match)
+			dollars, value := groups[1], groups[2]
+			if len(dollars)%2 == 0 {
+				// the reference is escaped
+				return match
+			}
+			parts := secretReferenceValueRegexp.FindStringSubmatch(value)
+			if parts == nil {
+				errs = append(errs, fmt.Sprintf("invalid secret reference %s, it must be written ${secret:<secret name>/<key>}", match[len(dollars)-1:]))
+				return match
+			}
+			return dollars[:len(dollars)-1] + replace(SecretReference{Name: parts[1], Key: parts[3]})
+		})
+	}
+	replaceAnyConfig := func(in *AnyConfig) *AnyConfig {
+		if in == nil {
+			return nil
+		}
+		return &AnyConfig{Object: replaceStrings(in.Object, replaceString).(map[string]interface{})}
+	}
+
+	out := c.DeepCopy()
+	out.Receivers = *replaceAnyConfig(&c.Receivers)
+	out.Exporters = *replaceAnyConfig(&c.Exporters)
+	out.Processors = replaceAnyConfig(c.Processors)
+	out.Connectors = replaceAnyConfig(c.Connectors)
+	out.Extensions = replaceAnyConfig(c.Extensions)
+	out.Service.Telemetry = replaceAnyConfig(c.Service.Telemetry)
+	if len(errs) > 0 {
+		return nil, errors.New(strings.Join(errs, ", "))
+	}
+	return out, nil
+}
+
+// replaceStrings returns a copy of value where the strings are replaced by the result of replace.
+func replaceStrings(value interface{}, replace func(string) string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return replace(v)
+	case map[string]interface{}:
+		if v == nil {
+			return v
+		}
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[key] = replaceStrings(item, replace)
+		}
+		return out
+	case []interface{}:
+		if v == nil {
+			return v
+		}
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = replaceStrings(item, replace)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+func TestConfig_SecretReferences(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      string
+		expected    []SecretReference
+		expectedCfg string
+		expectedErr string
+	}{
+		{
+			name: "no references",
+			config: `
+receivers:
+  otlp: {}
+exporters:
+  otlp:
+    endpoint: ${env:ENDPOINT}
+service:
+  pipelines: {}
+`,
+			expected: []SecretReference{},
+			expectedCfg: `
+receivers:
+  otlp: {}
+exporters:
+  otlp:
+    endpoint: ${env:ENDPOINT}
+service:
+  pipelines: {}
+`,
+		},
+		{
+			name: "references in components and telemetry",
+			config: `
+receivers:
+  otlp: {}
+exporters:
+  otlphttp:
+    headers:
+      api-key: ${secret:backend/api-key}
+      authorization: Bearer ${secret:backend/token} ${secret:backend/api-key}
+  kafka:
+    brokers: [kafka:9092]
+    auth:
+      plain_text:
+        password: ${secret:kafka.credentials/password}
+extensions:
+  basicauth:
+    client_auth:
+      password: ${secret:backend/api-key}
+service:
+  pipelines: {}
+  telemetry:
+    logs:
+      initial_fields:
+        cluster: ${secret:cluster-info/name}
+`,
+			expected: []SecretReference{
+				{Name: "backend", Key: "api-key"},
+				{Name: "backend", Key: "token"},
+				{Name: "cluster-info", Key: "name"},
+				{Name: "kafka.credentials", Key: "password"},
+			},
+			expectedCfg: `
+receivers:
+  otlp: {}
+exporters:
+  otlphttp:
+    headers:
+      api-key: ${env:OTEL_SECRET_BACKEND_API_KEY}
+      authorization: Bearer ${env:OTEL_SECRET_BACKEND_TOKEN} ${env:OTEL_SECRET_BACKEND_API_KEY}
+  kafka:
+    brokers: [kafka:9092]
+    auth:
+      plain_text:
+        password: ${env:OTEL_SECRET_KAFKA_CREDENTIALS_PASSWORD}
+extensions:
+  basicauth:
+    client_auth:
+      password: ${env:OTEL_SECRET_BACKEND_API_KEY}
+service:
+  pipelines: {}
+  telemetry:
+    logs:
+      initial_fields:
+        cluster: ${env:OTEL_SECRET_CLUSTER_INFO_NAME}
+`,
+		},
+		{
+			name: "escaped references",
+			config: `
+receivers:
+  otlp: {}
+exporters:
+  otlp:
+    headers:
+      literal: $${secret:backend/api-key}
+      dollar: $$${secret:backend/token}
+service:
+  pipelines: {}
+`,
+			expected: []SecretReference{
+				{Name: "backend", Key: "token"},
+			},
+			expectedCfg: `
+receivers:
+  otlp: {}
+exporters:
+  otlp:
+    headers:
+      literal: $${secret:backend/api-key}
+      dollar: $$${env:OTEL_SECRET_BACKEND_TOKEN}
+service:
+  pipelines: {}
+`,
+		},
+		{
+			name: "invalid reference",
+			config: `
+receivers:
+  otlp: {}
+exporters:
+  otlp:
+    headers:
+      api-key: ${secret:api-key}
+service:
+  pipelines: {}
+`,
+			expectedErr: "invalid secret reference ${secret:api-key}, it must be written ${secret:<secret name>/<key>}",
+		},
+		{
+			name: "references using the same environment variable",
+			config: `
+receivers:
+  otlp: {}
+exporters:
+  otlp:
+    headers:
+      a: ${secret:my-secret/key}
+      b: ${secret:my.secret/key}
+service:
+  pipelines: {}
+`,
+			expectedErr: "the secret references ${secret:my-secret/key} and ${secret:my.secret/key} use the same environment variable OTEL_SECRET_MY_SECRET_KEY",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{}
+			require.NoError(t, yaml.Unmarshal([]byte(tt.config), cfg))
+			original := cfg.DeepCopy()
+
+			refs, err := cfg.SecretReferences()
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, refs)
+
+			replaced, err := cfg.WithSecretReferencesAsEnv()
+			require.NoError(t, err)
+			expectedCfg := &Config{}
+			require.NoError(t, yaml.Unmarshal([]byte(tt.expectedCfg), expectedCfg))
+			assert.Equal(t, expectedCfg, replaced)
+			assert.Equal(t, original, cfg)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"encoding/json"
+)
+
+// ConfigTemplateKind is the kind of a config template referenced by a collector.
+// +kubebuilder:validation:Enum=CollectorConfigTemplate;ClusterCollectorConfigTemplate
+type ConfigTemplateKind string
+
+const (
+	// ConfigTemplateKindNamespaced is a CollectorConfigTemplate in the namespace of the collector.
+	ConfigTemplateKindNamespaced ConfigTemplateKind = "CollectorConfigTemplate"
+	// ConfigTemplateKindCluster is a cluster-scoped ClusterCollectorConfigTemplate.
+	ConfigTemplateKindCluster ConfigTemplateKind = "ClusterCollectorConfigTemplate"
+)
+
+// ConfigTemplateReference references a config template merged with the config of a collector.
+type ConfigTemplateReference struct {
+	// Kind of the template. The default is CollectorConfigTemplate, which is looked up in the namespace of the
+	// collector.
+	// +optional
+	// +kubebuilder:default:=CollectorConfigTemplate
+	Kind ConfigTemplateKind `json:"kind,omitempty" yaml:"kind,omitempty"`
+	// Name of the template.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name" yaml:"name"`
+}
+
+// MergeTemplates returns the config resulting from merging the given template configs, in order, and then the config
+// itself. Objects are merged key by key, and any other value, including the component lists of the pipelines, replaces
+// the value merged before it, unless it's null. The returned config doesn't reference any template.
+func (c *Config) MergeTemplates(templates ...AnyConfig) (*Config, error) {
+	own, err := configObject(c)
+	if err != nil {
+		return nil, err
+	}
+	delete(own, "templates")
+
+	merged := map[string]interface{}{}
+	for _, template := range templates {
+		merged = mergeObjects(merged, template.Object)
+	}
+	merged = mergeObjects(merged, own)
+
+	b, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	result := &Config{}
+	if err = json.Unmarshal(b, result); err != nil {
+		return nil, err
+	}
+	result.Templates = nil
+	return result, nil
+}
+
+// configObject returns the generic JSON representation of the config.
+func configObject(c *Config) (map[string]interface{}, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	object := map[string]interface{}{}
+	if err = json.Unmarshal(b, &object); err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// mergeObjects merges src into dst, the values of src taking precedence, and returns dst. Objects are merged
+// recursively, any other value of src replaces the value of dst. Null values, which unset fields of the config marshal
+// to, don't replace anything.
+func mergeObjects(dst, src map[string]interface{}) map[string]interface{} {
+	if dst == nil {
+		dst = map[string]interface{}{}
+	}
+	for key, srcValue := range src {
+		if srcValue == nil {
+			if _, ok := dst[key]; !ok {
+				dst[key] = nil
+			}
+			continue
+		}
+		srcObject, srcIsObject := srcValue.(map[string]interface{})
+		dstObject, dstIsObject := dst[key].(map[string]interface{})
+		if srcIsObject && dstIsObject {
+			dst[key] = mergeObjects(dstObject, srcObject)
+			continue
+		}
+		if srcIsObject {
+			// copy the object, so that merging other objects into it doesn't modify the source
+			srcValue = mergeObjects(nil, srcObject)
+		}
+		dst[key] = srcValue
+	}
+	return dst
+}
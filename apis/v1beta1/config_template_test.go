@@ -0,0 +1,243 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+func TestConfig_MergeTemplates(t *testing.T) {
+	parseConfig := func(t *testing.T, in string) *Config {
+		t.Helper()
+		c := &Config{}
+		require.NoError(t, yaml.Unmarshal([]byte(in), c))
+		return c
+	}
+	parseTemplate := func(t *testing.T, in string) AnyConfig {
+		t.Helper()
+		object := map[string]interface{}{}
+		require.NoError(t, yaml.Unmarshal([]byte(in), &object))
+		return AnyConfig{Object: object}
+	}
+
+	tests := []struct {
+		name      string
+		config    string
+		templates []string
+		expected  string
+	}{
+		{
+			name: "no templates",
+			config: `
+receivers:
+  otlp: {}
+exporters:
+  debug: {}
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [debug]
+`,
+			expected: `
+receivers:
+  otlp: {}
+exporters:
+  debug: {}
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [debug]
+`,
+		},
+		{
+			name: "components of the templates are added",
+			config: `
+templates:
+- name: processors
+- name: exporters
+receivers:
+  otlp: {}
+exporters: {}
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      processors: [batch]
+      exporters: [otlp/backend]
+`,
+			templates: []string{`
+processors:
+  batch:
+    send_batch_size: 100
+`, `
+exporters:
+  otlp/backend:
+    endpoint: backend:4317
+`},
+			expected: `
+receivers:
+  otlp: {}
+processors:
+  batch:
+    send_batch_size: 100
+exporters:
+  otlp/backend:
+    endpoint: backend:4317
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      processors: [batch]
+      exporters: [otlp/backend]
+`,
+		},
+		{
+			name: "later templates and the config take precedence",
+			config: `
+receivers: {}
+exporters:
+  otlp/backend:
+    endpoint: override:4317
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [otlp/backend]
+`,
+			templates: []string{`
+receivers:
+  otlp:
+    protocols:
+      grpc: {}
+exporters:
+  otlp/backend:
+    endpoint: first:4317
+    tls:
+      insecure: true
+`, `
+receivers:
+  otlp:
+    protocols:
+      http: {}
+exporters:
+  otlp/backend:
+    endpoint: second:4317
+`},
+			expected: `
+receivers:
+  otlp:
+    protocols:
+      grpc: {}
+      http: {}
+exporters:
+  otlp/backend:
+    endpoint: override:4317
+    tls:
+      insecure: true
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [otlp/backend]
+`,
+		},
+		{
+			name: "lists are replaced and unset values are kept",
+			config: `
+receivers: {}
+exporters: {}
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      processors: [memory_limiter]
+`,
+			templates: []string{`
+receivers:
+  otlp: {}
+exporters:
+  debug: {}
+processors:
+  batch: {}
+  memory_limiter: {}
+service:
+  pipelines:
+    traces:
+      receivers: [otlp, otlp/other]
+      processors: [batch]
+      exporters: [debug]
+`},
+			expected: `
+receivers:
+  otlp: {}
+exporters:
+  debug: {}
+processors:
+  batch: {}
+  memory_limiter: {}
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      processors: [memory_limiter]
+      exporters: [debug]
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := parseConfig(t, tt.config)
+			var templates []AnyConfig
+			for _, template := range tt.templates {
+				templates = append(templates, parseTemplate(t, template))
+			}
+			original := config.DeepCopy()
+
+			merged, err := config.MergeTemplates(templates...)
+			require.NoError(t, err)
+			assert.Equal(t, parseConfig(t, tt.expected), merged)
+			assert.Equal(t, original, config)
+		})
+	}
+
+	t.Run("templates are not modified", func(t *testing.T) {
+		template := parseTemplate(t, `
+exporters:
+  debug:
+    verbosity: basic
+`)
+		config := parseConfig(t, `
+receivers: {}
+exporters:
+  debug:
+    verbosity: detailed
+service:
+  pipelines: {}
+`)
+		_, err := config.MergeTemplates(template)
+		require.NoError(t, err)
+		assert.Equal(t, parseTemplate(t, `
+exporters:
+  debug:
+    verbosity: basic
+`), template)
+	})
+}
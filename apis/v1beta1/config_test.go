@@ -26,9 +26,13 @@ import (
 	"github.com/stretchr/testify/require"
 	go_yaml "gopkg.in/yaml.v3"
 	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/yaml"
+
+	"github.com/open-telemetry/opentelemetry-operator/internal/components"
+	"github.com/open-telemetry/opentelemetry-operator/internal/components/connectors"
 )
 
 func TestConfigFiles(t *testing.T) {
@@ -431,15 +435,16 @@ func TestConfig_GetEnabledComponents(t *testing.T) {
 			file: "testdata/otelcol-connectors.yaml",
 			want: map[ComponentKind]map[string]interface{}{
 				KindReceiver: {
-					"foo":   struct{}{},
-					"count": struct{}{},
+					"foo": struct{}{},
 				},
 				KindProcessor: {},
 				KindExporter: {
-					"bar":   struct{}{},
-					"count": struct{}{},
+					"bar": struct{}{},
 				},
 				KindExtension: {},
+				KindConnector: {
+					"count": struct{}{},
+				},
 			},
 		},
 		{
@@ -458,6 +463,7 @@ func TestConfig_GetEnabledComponents(t *testing.T) {
 					"prometheus": struct{}{},
 				},
 				KindExtension: {},
+				KindConnector: {},
 			},
 		},
 		{
@@ -481,6 +487,7 @@ func TestConfig_GetEnabledComponents(t *testing.T) {
 					"pprof":        struct{}{},
 					"zpages":       struct{}{},
 				},
+				KindConnector: {},
 			},
 		},
 		{
@@ -497,6 +504,7 @@ func TestConfig_GetEnabledComponents(t *testing.T) {
 				KindExtension: {
 					"oauth2client": struct{}{},
 				},
+				KindConnector: {},
 			},
 		},
 		{
@@ -511,6 +519,7 @@ func TestConfig_GetEnabledComponents(t *testing.T) {
 					"debug": struct{}{},
 				},
 				KindExtension: {},
+				KindConnector: {},
 			},
 		},
 		{
@@ -521,6 +530,7 @@ func TestConfig_GetEnabledComponents(t *testing.T) {
 				KindProcessor: {},
 				KindExporter:  {},
 				KindExtension: {},
+				KindConnector: {},
 			},
 		},
 	}
@@ -597,6 +607,45 @@ func TestConfig_getEnvironmentVariablesForComponentKinds(t *testing.T) {
 	}
 }
 
+func TestConfig_Connectors(t *testing.T) {
+	podsRule := rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "watch", "list"}}
+	connectors.Register("k8sconnector", components.NewBuilder[any]().WithName("k8sconnector").WithRbacGen(func(_ logr.Logger, _ any) ([]rbacv1.PolicyRule, error) {
+		return []rbacv1.PolicyRule{podsRule}, nil
+	}).MustBuild())
+
+	cfg := &Config{
+		Receivers: AnyConfig{Object: map[string]interface{}{"otlp": map[string]interface{}{}}},
+		Exporters: AnyConfig{Object: map[string]interface{}{"debug": map[string]interface{}{}}},
+		Connectors: &AnyConfig{Object: map[string]interface{}{
+			"k8sconnector": map[string]interface{}{},
+			// connectors don't listen, even though the generic receiver parser would take this for a port
+			"forward": map[string]interface{}{"endpoint": "0.0.0.0:1234"},
+		}},
+		Service: Service{
+			Pipelines: map[string]*Pipeline{
+				"traces":  {Receivers: []string{"otlp"}, Exporters: []string{"k8sconnector", "forward"}},
+				"metrics": {Receivers: []string{"k8sconnector"}, Exporters: []string{"debug"}},
+				"logs":    {Receivers: []string{"forward"}, Exporters: []string{"debug"}},
+			},
+		},
+	}
+
+	enabled := cfg.GetEnabledComponents()
+	assert.Equal(t, map[string]interface{}{"k8sconnector": struct{}{}, "forward": struct{}{}}, enabled[KindConnector])
+	assert.Equal(t, map[string]interface{}{"otlp": struct{}{}}, enabled[KindReceiver])
+	assert.Equal(t, map[string]interface{}{"debug": struct{}{}}, enabled[KindExporter])
+
+	rules, err := cfg.GetAllRbacRules(logr.Discard())
+	require.NoError(t, err)
+	assert.Equal(t, []rbacv1.PolicyRule{podsRule}, rules)
+
+	ports, err := cfg.GetAllPorts(logr.Discard())
+	require.NoError(t, err)
+	for _, port := range ports {
+		assert.NotEqual(t, int32(1234), port.Port)
+	}
+}
+
 func TestConfig_GetReceiverPorts(t *testing.T) {
 	tests := []struct {
 		name    string
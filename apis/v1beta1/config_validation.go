@@ -0,0 +1,252 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidatePipelines checks the graph of the service pipelines against the components of the config, which is found at
+// the given path. It returns an error for every reference to a component that isn't defined, every connector that isn't
+// used as both an exporter and a receiver, and every cycle between pipelines through connectors. The components which
+// aren't used are returned as warnings, since the collector ignores them.
+func (c *Config) ValidatePipelines(path *field.Path) ([]string, field.ErrorList) {
+	var (
+		errs          field.ErrorList
+		receivers     = c.Receivers.Object
+		exporters     = c.Exporters.Object
+		processors    = anyConfigObject(c.Processors)
+		connectors    = anyConfigObject(c.Connectors)
+		extensions    = anyConfigObject(c.Extensions)
+		used          = map[string]map[string]struct{}{}
+		pipelinesPath = path.Child("service", "pipelines")
+		// the paths of the first pipeline referencing a connector as an exporter and as a receiver
+		connectorExporters = map[string]*field.Path{}
+		connectorReceivers = map[string]*field.Path{}
+	)
+	use := func(kind, id string) {
+		if used[kind] == nil {
+			used[kind] = map[string]struct{}{}
+		}
+		used[kind][id] = struct{}{}
+	}
+
+	for _, id := range sortedKeys(connectors) {
+		connectorPath := path.Child("connectors").Key(id)
+		if _, ok := receivers[id]; ok {
+			errs = append(errs, field.Invalid(connectorPath, id, "ambiguous ID, a receiver has the same ID"))
+		}
+		if _, ok := exporters[id]; ok {
+			errs = append(errs, field.Invalid(connectorPath, id, "ambiguous ID, an exporter has the same ID"))
+		}
+	}
+
+	for i, id := range c.Service.Extensions {
+		if _, ok := extensions[id]; !ok {
+			errs = append(errs, field.Invalid(path.Child("service", "extensions").Index(i), id, "extension is not defined in extensions"))
+			continue
+		}
+		use("extensions", id)
+	}
+
+	pipelineNames := make([]string, 0, len(c.Service.Pipelines))
+	for name := range c.Service.Pipelines {
+		pipelineNames = append(pipelineNames, name)
+	}
+	sort.Strings(pipelineNames)
+	for _, name := range pipelineNames {
+		pipeline := c.Service.Pipelines[name]
+		pipelinePath := pipelinesPath.Key(name)
+		if pipeline == nil {
+			errs = append(errs, field.Required(pipelinePath, "a pipeline must have receivers and exporters"))
+			continue
+		}
+		if len(pipeline.Receivers) == 0 {
+			errs = append(errs, field.Required(pipelinePath.Child("receivers"), "a pipeline must have at least one receiver"))
+		}
+		if len(pipeline.Exporters) == 0 {
+			errs = append(errs, field.Required(pipelinePath.Child("exporters"), "a pipeline must have at least one exporter"))
+		}
+
+		errs = append(errs, checkDuplicates(pipelinePath.Child("receivers"), pipeline.Receivers)...)
+		for i, id := range pipeline.Receivers {
+			idPath := pipelinePath.Child("receivers").Index(i)
+			if _, ok := receivers[id]; ok {
+				use("receivers", id)
+			} else if _, ok := connectors[id]; ok {
+				use("connectors", id)
+				if _, ok := connectorReceivers[id]; !ok {
+					connectorReceivers[id] = idPath
+				}
+			} else {
+				errs = append(errs, field.Invalid(idPath, id, "receiver is not defined in receivers or connectors"))
+			}
+		}
+		errs = append(errs, checkDuplicates(pipelinePath.Child("processors"), pipeline.Processors)...)
+		for i, id := range pipeline.Processors {
+			if _, ok := processors[id]; !ok {
+				errs = append(errs, field.Invalid(pipelinePath.Child("processors").Index(i), id, "processor is not defined in processors"))
+				continue
+			}
+			use("processors", id)
+		}
+		errs = append(errs, checkDuplicates(pipelinePath.Child("exporters"), pipeline.Exporters)...)
+		for i, id := range pipeline.Exporters {
+			idPath := pipelinePath.Child("exporters").Index(i)
+			if _, ok := exporters[id]; ok {
+				use("exporters", id)
+			} else if _, ok := connectors[id]; ok {
+				use("connectors", id)
+				if _, ok := connectorExporters[id]; !ok {
+					connectorExporters[id] = idPath
+				}
+			} else {
+				errs = append(errs, field.Invalid(idPath, id, "exporter is not defined in exporters or connectors"))
+			}
+		}
+	}
+
+	for _, id := range sortedKeys(connectors) {
+		exporterPath, exported := connectorExporters[id]
+		receiverPath, received := connectorReceivers[id]
+		switch {
+		case exported && !received:
+			errs = append(errs, field.Invalid(exporterPath, id, "connector is used as an exporter but not as a receiver in any pipeline"))
+		case received && !exported:
+			errs = append(errs, field.Invalid(receiverPath, id, "connector is used as a receiver but not as an exporter in any pipeline"))
+		}
+	}
+	if cycle := c.Service.pipelineCycle(connectors); len(cycle) > 0 {
+		errs = append(errs, field.Forbidden(pipelinesPath.Key(cycle[0]), fmt.Sprintf("pipelines form a cycle through connectors: %s", strings.Join(cycle, " -> "))))
+	}
+
+	var unused []string
+	for kind, components := range map[string]map[string]interface{}{
+		"receivers":  receivers,
+		"exporters":  exporters,
+		"processors": processors,
+		"connectors": connectors,
+		"extensions": extensions,
+	} {
+		for id := range components {
+			if _, ok := used[kind][id]; !ok {
+				unused = append(unused, fmt.Sprintf("%s.%s", kind, id))
+			}
+		}
+	}
+	var warnings []string
+	if len(unused) > 0 {
+		sort.Strings(unused)
+		warnings = append(warnings, fmt.Sprintf("Collector config %s has unused components: %s. They are not used by any pipeline or, for extensions, not enabled in the service.", path, strings.Join(unused, ", ")))
+	}
+	return warnings, errs
+}
+
+// pipelineCycle returns the pipelines of a cycle, where a pipeline exports to a connector which another pipeline
+// receives from, starting and ending with the same pipeline. It returns nil if there is no cycle.
+func (s *Service) pipelineCycle(connectors map[string]interface{}) []string {
+	// the pipelines receiving from each connector
+	receiving := map[string][]string{}
+	names := make([]string, 0, len(s.Pipelines))
+	for name, pipeline := range s.Pipelines {
+		names = append(names, name)
+		if pipeline == nil {
+			continue
+		}
+		for _, id := range pipeline.Receivers {
+			if _, ok := connectors[id]; ok {
+				receiving[id] = append(receiving[id], name)
+			}
+		}
+	}
+	sort.Strings(names)
+	for _, pipelines := range receiving {
+		sort.Strings(pipelines)
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[string]int{}
+	var stack []string
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		state[name] = visiting
+		stack = append(stack, name)
+		if pipeline := s.Pipelines[name]; pipeline != nil {
+			for _, id := range pipeline.Exporters {
+				for _, next := range receiving[id] {
+					switch state[next] {
+					case visiting:
+						for i, n := range stack {
+							if n == next {
+								return append(append([]string{}, stack[i:]...), next)
+							}
+						}
+					case unvisited:
+						if cycle := visit(next); cycle != nil {
+							return cycle
+						}
+					}
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = visited
+		return nil
+	}
+	for _, name := range names {
+		if state[name] == unvisited {
+			if cycle := visit(name); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+func checkDuplicates(path *field.Path, ids []string) field.ErrorList {
+	var errs field.ErrorList
+	seen := map[string]struct{}{}
+	for i, id := range ids {
+		if _, ok := seen[id]; ok {
+			errs = append(errs, field.Duplicate(path.Index(i), id))
+		}
+		seen[id] = struct{}{}
+	}
+	return errs
+}
+
+func anyConfigObject(c *AnyConfig) map[string]interface{} {
+	if c == nil {
+		return nil
+	}
+	return c.Object
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
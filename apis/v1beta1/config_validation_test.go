@@ -0,0 +1,184 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestConfig_ValidatePipelines(t *testing.T) {
+	components := func(ids ...string) AnyConfig {
+		object := map[string]interface{}{}
+		for _, id := range ids {
+			object[id] = map[string]interface{}{}
+		}
+		return AnyConfig{Object: object}
+	}
+	optionalComponents := func(ids ...string) *AnyConfig {
+		c := components(ids...)
+		return &c
+	}
+
+	tests := []struct {
+		name     string
+		config   Config
+		warnings []string
+		errs     []string
+	}{
+		{
+			name: "valid pipelines",
+			config: Config{
+				Receivers:  components("otlp"),
+				Processors: optionalComponents("batch"),
+				Exporters:  components("debug", "otlp/backend"),
+				Connectors: optionalComponents("spanmetrics"),
+				Extensions: optionalComponents("health_check"),
+				Service: Service{
+					Extensions: []string{"health_check"},
+					Pipelines: map[string]*Pipeline{
+						"traces":  {Receivers: []string{"otlp"}, Processors: []string{"batch"}, Exporters: []string{"otlp/backend", "spanmetrics"}},
+						"metrics": {Receivers: []string{"otlp", "spanmetrics"}, Exporters: []string{"debug"}},
+					},
+				},
+			},
+		},
+		{
+			name: "no pipelines",
+		},
+		{
+			name: "undefined components",
+			config: Config{
+				Receivers: components("otlp"),
+				Exporters: components("debug"),
+				Service: Service{
+					Extensions: []string{"health_check"},
+					Pipelines: map[string]*Pipeline{
+						"traces": {Receivers: []string{"otlp", "jaeger"}, Processors: []string{"batch"}, Exporters: []string{"otlp/backend", "debug"}},
+					},
+				},
+			},
+			errs: []string{
+				`spec.config.service.extensions[0]: Invalid value: "health_check": extension is not defined in extensions`,
+				`spec.config.service.pipelines[traces].receivers[1]: Invalid value: "jaeger": receiver is not defined in receivers or connectors`,
+				`spec.config.service.pipelines[traces].processors[0]: Invalid value: "batch": processor is not defined in processors`,
+				`spec.config.service.pipelines[traces].exporters[0]: Invalid value: "otlp/backend": exporter is not defined in exporters or connectors`,
+			},
+		},
+		{
+			name: "empty and duplicate references",
+			config: Config{
+				Receivers: components("otlp"),
+				Exporters: components("debug"),
+				Service: Service{
+					Pipelines: map[string]*Pipeline{
+						"logs":    nil,
+						"metrics": {Receivers: []string{"otlp"}},
+						"traces":  {Receivers: []string{"otlp", "otlp"}, Exporters: []string{"debug"}},
+					},
+				},
+			},
+			errs: []string{
+				"spec.config.service.pipelines[logs]: Required value: a pipeline must have receivers and exporters",
+				"spec.config.service.pipelines[metrics].exporters: Required value: a pipeline must have at least one exporter",
+				`spec.config.service.pipelines[traces].receivers[1]: Duplicate value: "otlp"`,
+			},
+		},
+		{
+			name: "connectors used on one side only",
+			config: Config{
+				Receivers:  components("otlp"),
+				Exporters:  components("debug"),
+				Connectors: optionalComponents("count", "forward"),
+				Service: Service{
+					Pipelines: map[string]*Pipeline{
+						"logs":   {Receivers: []string{"forward"}, Exporters: []string{"debug"}},
+						"traces": {Receivers: []string{"otlp"}, Exporters: []string{"debug", "count"}},
+					},
+				},
+			},
+			errs: []string{
+				`spec.config.service.pipelines[traces].exporters[1]: Invalid value: "count": connector is used as an exporter but not as a receiver in any pipeline`,
+				`spec.config.service.pipelines[logs].receivers[0]: Invalid value: "forward": connector is used as a receiver but not as an exporter in any pipeline`,
+			},
+		},
+		{
+			name: "ambiguous connector",
+			config: Config{
+				Receivers:  components("otlp"),
+				Exporters:  components("debug"),
+				Connectors: optionalComponents("otlp"),
+				Service: Service{
+					Pipelines: map[string]*Pipeline{
+						"traces": {Receivers: []string{"otlp"}, Exporters: []string{"debug"}},
+					},
+				},
+			},
+			errs: []string{
+				`spec.config.connectors[otlp]: Invalid value: "otlp": ambiguous ID, a receiver has the same ID`,
+			},
+			warnings: []string{
+				"Collector config spec.config has unused components: connectors.otlp. They are not used by any pipeline or, for extensions, not enabled in the service.",
+			},
+		},
+		{
+			name: "cycle through connectors",
+			config: Config{
+				Receivers:  components("otlp"),
+				Exporters:  components("debug"),
+				Connectors: optionalComponents("forward/a", "forward/b"),
+				Service: Service{
+					Pipelines: map[string]*Pipeline{
+						"traces/a": {Receivers: []string{"otlp", "forward/b"}, Exporters: []string{"forward/a"}},
+						"traces/b": {Receivers: []string{"forward/a"}, Exporters: []string{"debug", "forward/b"}},
+					},
+				},
+			},
+			errs: []string{
+				"spec.config.service.pipelines[traces/a]: Forbidden: pipelines form a cycle through connectors: traces/a -> traces/b -> traces/a",
+			},
+		},
+		{
+			name: "unused components",
+			config: Config{
+				Receivers:  components("otlp", "jaeger"),
+				Processors: optionalComponents("batch"),
+				Exporters:  components("debug"),
+				Extensions: optionalComponents("health_check"),
+				Service: Service{
+					Pipelines: map[string]*Pipeline{
+						"traces": {Receivers: []string{"otlp"}, Exporters: []string{"debug"}},
+					},
+				},
+			},
+			warnings: []string{
+				"Collector config spec.config has unused components: extensions.health_check, processors.batch, receivers.jaeger. They are not used by any pipeline or, for extensions, not enabled in the service.",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, errs := tt.config.ValidatePipelines(field.NewPath("spec", "config"))
+			var errStrings []string
+			for _, err := range errs {
+				errStrings = append(errStrings, err.Error())
+			}
+			assert.Equal(t, tt.errs, errStrings)
+			assert.Equal(t, tt.warnings, warnings)
+		})
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 
+	authv1 "k8s.io/api/authorization/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 
 	"github.com/open-telemetry/opentelemetry-operator/internal/rbac"
@@ -52,6 +53,16 @@ var (
 			Verbs:           []string{"get"},
 		},
 	}
+
+	// targetAllocatorLeaderElectionPolicyRules are the policy rules required in the namespace of the target allocator
+	// for its replicas to elect a leader.
+	targetAllocatorLeaderElectionPolicyRules = []*rbacv1.PolicyRule{
+		{
+			APIGroups: []string{"coordination.k8s.io"},
+			Resources: []string{"leases"},
+			Verbs:     []string{"get", "create", "update"},
+		},
+	}
 )
 
 func CheckTargetAllocatorPrometheusCRPolicyRules(
@@ -73,3 +84,35 @@ func CheckTargetAllocatorPrometheusCRPolicyRules(
 	}
 	return []string{}, nil
 }
+
+// CheckTargetAllocatorLeaderElectionPolicyRules checks that the service account of the target allocator can manage
+// its lease in the namespace of the target allocator, and returns a warning per missing rule.
+func CheckTargetAllocatorLeaderElectionPolicyRules(
+	ctx context.Context,
+	reviewer *rbac.Reviewer,
+	namespace string,
+	serviceAccountName string) (warnings []string, err error) {
+	var subjectAccessReviews []*authv1.SubjectAccessReview
+	for _, rule := range targetAllocatorLeaderElectionPolicyRules {
+		for _, group := range rule.APIGroups {
+			for _, resource := range rule.Resources {
+				for _, verb := range rule.Verbs {
+					sar, err := reviewer.CanAccess(ctx, serviceAccountName, namespace, &authv1.ResourceAttributes{
+						Namespace: namespace,
+						Verb:      verb,
+						Group:     group,
+						Resource:  resource,
+					}, nil)
+					if err != nil {
+						return []string{}, fmt.Errorf("unable to check rbac rules %w", err)
+					}
+					subjectAccessReviews = append(subjectAccessReviews, sar)
+				}
+			}
+		}
+	}
+	if allowed, deniedReviews := rbac.AllSubjectAccessReviewsAllowed(subjectAccessReviews); !allowed {
+		return rbac.WarningsGroupedByResource(deniedReviews), nil
+	}
+	return []string{}, nil
+}
@@ -58,7 +58,7 @@ type TargetAllocatorPrometheusCR struct {
 
 type (
 	// TargetAllocatorAllocationStrategy represent a strategy Target Allocator uses to distribute targets to each collector
-	// +kubebuilder:validation:Enum=least-weighted;consistent-hashing;per-node
+	// +kubebuilder:validation:Enum=least-weighted;consistent-hashing;per-node;cost-weighted;zone-aware
 	TargetAllocatorAllocationStrategy string
 	// TargetAllocatorFilterStrategy represent a filtering strategy for targets before they are assigned to collectors
 	// +kubebuilder:validation:Enum="";relabel-config
@@ -75,6 +75,12 @@ const (
 	// TargetAllocatorAllocationStrategyPerNode targets will be assigned to the collector on the node they reside on (use only with daemon set).
 	TargetAllocatorAllocationStrategyPerNode TargetAllocatorAllocationStrategy = "per-node"
 
+	// TargetAllocatorAllocationStrategyCostWeighted targets will be distributed to collectors by the cost reported for them, such as their series count.
+	TargetAllocatorAllocationStrategyCostWeighted TargetAllocatorAllocationStrategy = "cost-weighted"
+
+	// TargetAllocatorAllocationStrategyZoneAware targets will be assigned to a collector in the zone of the node they reside on, falling back to consistent hashing across zones.
+	TargetAllocatorAllocationStrategyZoneAware TargetAllocatorAllocationStrategy = "zone-aware"
+
 	// TargetAllocatorFilterStrategyRelabelConfig targets will be consistently drops targets based on the relabel_config.
 	TargetAllocatorFilterStrategyRelabelConfig TargetAllocatorFilterStrategy = "relabel-config"
 )
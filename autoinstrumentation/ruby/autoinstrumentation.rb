@@ -0,0 +1,39 @@
+# frozen_string_literal: true
+
+# Loaded through RUBYOPT when the OpenTelemetry Operator injects the Ruby auto-instrumentation.
+# The gems copied next to this file are added to the load path, so that they are available to applications
+# using Bundler too. Applications without Bundler are instrumented right away. For applications using Bundler, the
+# SDK is configured once Bundler.require loaded the application gems, as instrumentations are only installed for the
+# libraries that are present at that time.
+Dir.glob(File.join(__dir__, 'gems', 'gems', '*', 'lib')).each { |lib| $LOAD_PATH.push(lib) }
+
+module OpenTelemetryAutoInstrumentation
+  def self.configure
+    return if @configured
+
+    @configured = true
+    require 'opentelemetry/sdk'
+    require 'opentelemetry/exporter/otlp'
+    require 'opentelemetry/instrumentation/all'
+    OpenTelemetry::SDK.configure(&:use_all)
+  rescue LoadError, StandardError => e
+    warn "OpenTelemetry auto-instrumentation could not be configured: #{e.message}"
+  end
+
+  module BundlerRuntime
+    def require(*groups)
+      super.tap { OpenTelemetryAutoInstrumentation.configure }
+    end
+  end
+end
+
+if defined?(Bundler) || File.exist?(ENV.fetch('BUNDLE_GEMFILE', 'Gemfile'))
+  begin
+    require 'bundler'
+    Bundler::Runtime.prepend(OpenTelemetryAutoInstrumentation::BundlerRuntime)
+  rescue LoadError
+    OpenTelemetryAutoInstrumentation.configure
+  end
+else
+  OpenTelemetryAutoInstrumentation.configure
+end
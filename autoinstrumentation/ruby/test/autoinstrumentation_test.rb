@@ -0,0 +1,53 @@
+# frozen_string_literal: true
+
+require 'fileutils'
+require 'minitest/autorun'
+require 'open3'
+require 'rbconfig'
+require 'tmpdir'
+
+# Runs applications with the auto-instrumentation loaded through RUBYOPT, as injected by the operator. The
+# OpenTelemetry gems are replaced by stubs recording whether the SDK was configured.
+class AutoInstrumentationTest < Minitest::Test
+  SCRIPT = File.expand_path('../autoinstrumentation.rb', __dir__)
+
+  def setup
+    @dir = Dir.mktmpdir
+    FileUtils.cp(SCRIPT, @dir)
+    stub_gem('opentelemetry-sdk', 'opentelemetry/sdk.rb', <<~RUBY)
+      module OpenTelemetry
+        module SDK
+          def self.configure
+            yield(Class.new { def use_all; end }.new)
+            $opentelemetry_configured = true
+          end
+        end
+      end
+    RUBY
+    stub_gem('opentelemetry-exporter-otlp', 'opentelemetry/exporter/otlp.rb', '')
+    stub_gem('opentelemetry-instrumentation-all', 'opentelemetry/instrumentation/all.rb', '')
+  end
+
+  def teardown
+    FileUtils.remove_entry(@dir)
+  end
+
+  def test_configures_applications_without_bundler_while_they_run
+    app = File.join(@dir, 'app.rb')
+    File.write(app, "puts $opentelemetry_configured.inspect\n")
+
+    env = { 'RUBYOPT' => "-r#{File.join(@dir, 'autoinstrumentation.rb')}", 'BUNDLE_GEMFILE' => nil }
+    out, err, status = Open3.capture3(env, RbConfig.ruby, app, chdir: @dir)
+
+    assert status.success?, err
+    assert_equal "true\n", out
+  end
+
+  private
+
+  def stub_gem(name, path, content)
+    file = File.join(@dir, 'gems', 'gems', "#{name}-0.0.0", 'lib', path)
+    FileUtils.mkdir_p(File.dirname(file))
+    File.write(file, content)
+  end
+end
@@ -40,6 +40,8 @@ func newAllocator(log logr.Logger, strategy Strategy, opts ...Option) Allocator
 		collectors:                    make(map[string]*Collector),
 		targetItems:                   make(map[string]*target.Item),
 		targetItemsPerJobPerCollector: make(map[string]map[string]map[string]bool),
+		targetCosts:                   make(map[string]float64),
+		lastDecisions:                 make(map[string]Decision),
 		log:                           log,
 	}
 	for _, opt := range opts {
@@ -63,12 +65,43 @@ type allocator struct {
 	// collectorKey -> job -> target item hash -> true
 	targetItemsPerJobPerCollector map[string]map[string]map[string]bool
 
-	// m protects collectors, targetItems and targetItemsPerJobPerCollector for concurrent use.
+	// targetCosts is a map from a target item's hash to its reported cost
+	// targetItem hash -> cost
+	targetCosts map[string]float64
+	// costSum is the sum of targetCosts, used to estimate the cost of targets without a reported one
+	costSum float64
+
+	// nodeTopology is a map from a Node's name to its topology
+	nodeTopology map[string]Topology
+
+	// targetsOverCapacity is how many targets exceed the total capacity of the collectors
+	targetsOverCapacity int
+
+	// changesHandler is called with the changes of the assignments, which are collected in pendingChanges while
+	// handling an update
+	changesHandler func(CollectorChanges)
+	pendingChanges CollectorChanges
+
+	// cause is why targets are (re)assigned during the current update, and moves the changes of their collector
+	// during it, which are recorded as decisions once it's done
+	cause DecisionCause
+	moves map[string]*move
+	// decisions is a ring buffer of the latest decisions, starting at nextDecision once full
+	decisions    []Decision
+	nextDecision int
+	// lastDecisions is a map from a target item's hash to the decision which assigned its current collector
+	lastDecisions map[string]Decision
+
+	// m protects collectors, targetItems, targetItemsPerJobPerCollector, targetCosts, nodeTopology,
+	// targetsOverCapacity, pendingChanges, moves and decisions for concurrent use.
 	m sync.RWMutex
 
 	log logr.Logger
 
 	filter Filter
+
+	// pool is the name of the pool of collectors the allocator allocates to, which labels its metrics
+	pool string
 }
 
 // SetFilter sets the filtering hook to use.
@@ -76,25 +109,46 @@ func (a *allocator) SetFilter(filter Filter) {
 	a.filter = filter
 }
 
+// SetPool sets the name of the pool of collectors, which labels the metrics.
+func (a *allocator) SetPool(pool string) {
+	a.pool = pool
+}
+
 // SetFallbackStrategy sets the fallback strategy to use.
 func (a *allocator) SetFallbackStrategy(strategy Strategy) {
 	a.strategy.SetFallbackStrategy(strategy)
 }
 
+// SetTargetChangesHandler sets the function called with the changes of the assignments.
+func (a *allocator) SetTargetChangesHandler(handler func(changes CollectorChanges)) {
+	a.m.Lock()
+	defer a.m.Unlock()
+	a.changesHandler = handler
+}
+
+// TargetsOverCapacity returns how many targets exceed the total capacity of the collectors.
+func (a *allocator) TargetsOverCapacity() int {
+	a.m.RLock()
+	defer a.m.RUnlock()
+	return a.targetsOverCapacity
+}
+
 // SetTargets accepts a list of targets that will be used to make
 // load balancing decisions. This method should be called when there are
 // new targets discovered or existing targets are shutdown.
 func (a *allocator) SetTargets(targets map[string]*target.Item) {
-	timer := prometheus.NewTimer(TimeToAssign.WithLabelValues("SetTargets", a.strategy.GetName()))
+	timer := prometheus.NewTimer(TimeToAssign.WithLabelValues("SetTargets", a.strategy.GetName(), a.pool))
 	defer timer.ObserveDuration()
 
 	if a.filter != nil {
 		targets = a.filter.Apply(targets)
 	}
-	RecordTargetsKept(targets)
+	RecordTargetsKept(a.pool, targets)
 
 	a.m.Lock()
 	defer a.m.Unlock()
+	defer a.publishChanges()
+	defer a.recordDecisions()
 
 	// Check for target changes
 	targetsDiff := diff.Maps(a.targetItems, targets)
@@ -107,24 +161,80 @@ func (a *allocator) SetTargets(targets map[string]*target.Item) {
 // SetCollectors sets the set of collectors with key=collectorName, value=Collector object.
 // This method is called when Collectors are added or removed.
 func (a *allocator) SetCollectors(collectors map[string]*Collector) {
-	timer := prometheus.NewTimer(TimeToAssign.WithLabelValues("SetCollectors", a.strategy.GetName()))
+	timer := prometheus.NewTimer(TimeToAssign.WithLabelValues("SetCollectors", a.strategy.GetName(), a.pool))
 	defer timer.ObserveDuration()
 
-	CollectorsAllocatable.WithLabelValues(a.strategy.GetName()).Set(float64(len(collectors)))
+	CollectorsAllocatable.WithLabelValues(a.strategy.GetName(), a.pool).Set(float64(len(collectors)))
 	if len(collectors) == 0 {
 		a.log.Info("No collector instances present")
 	}
 
 	a.m.Lock()
 	defer a.m.Unlock()
+	defer a.publishChanges()
+	defer a.recordDecisions()
 
 	// Check for collector changes
 	collectorsDiff := diff.Maps(a.collectors, collectors)
-	if len(collectorsDiff.Additions()) != 0 || len(collectorsDiff.Removals()) != 0 {
+	resized := a.resizeCollectors(collectors)
+	if len(collectorsDiff.Additions()) != 0 || len(collectorsDiff.Removals()) != 0 || resized {
 		a.handleCollectors(collectorsDiff)
 	}
 }
 
+// SetTargetCosts records the reported cost of the targets, matched by job name and target URL, and gives the
+// strategy a chance to move targets to even out the load of the collectors.
+func (a *allocator) SetTargetCosts(costs []TargetCost) {
+	timer := prometheus.NewTimer(TimeToAssign.WithLabelValues("SetTargetCosts", a.strategy.GetName(), a.pool))
+	defer timer.ObserveDuration()
+
+	a.m.Lock()
+	defer a.m.Unlock()
+	defer a.publishChanges()
+	defer a.recordDecisions()
+
+	type targetKey struct{ job, url string }
+	reported := make(map[targetKey]float64, len(costs))
+	for _, c := range costs {
+		reported[targetKey{c.JobName, c.TargetURL}] = c.Cost
+	}
+	for hash, item := range a.targetItems {
+		cost, ok := reported[targetKey{item.JobName, item.TargetURL}]
+		if !ok {
+			continue
+		}
+		a.costSum += cost - a.targetCosts[hash]
+		a.targetCosts[hash] = cost
+	}
+
+	a.updateCollectorCosts()
+	a.rebalance()
+}
+
+// SetTopology sets the topology of the Nodes, which the collectors are enriched with. Strategies taking the topology
+// into account get a chance to reallocate all targets.
+func (a *allocator) SetTopology(nodes map[string]Topology) {
+	timer := prometheus.NewTimer(TimeToAssign.WithLabelValues("SetTopology", a.strategy.GetName(), a.pool))
+	defer timer.ObserveDuration()
+
+	a.m.Lock()
+	defer a.m.Unlock()
+	defer a.publishChanges()
+	defer a.recordDecisions()
+
+	a.nodeTopology = nodes
+	for _, c := range a.collectors {
+		a.setCollectorTopology(c)
+	}
+	if t, ok := a.strategy.(topologyAware); ok {
+		t.SetTopology(nodes)
+		a.strategy.SetCollectors(a.collectors)
+		a.cause = CauseTopologyChanged
+		a.reallocateTargets()
+	}
+	a.updateCrossZoneTargets()
+}
+
 func (a *allocator) GetTargetsForCollectorAndJob(collector string, job string) []*target.Item {
 	a.m.RLock()
 	defer a.m.RUnlock()
@@ -143,13 +253,40 @@ func (a *allocator) GetTargetsForCollectorAndJob(collector string, job string) [
 	return targetItemsCopy
 }
 
-// TargetItems returns a shallow copy of the targetItems map.
+// TargetItems returns a copy of the targetItems map and of its items, whose collector changes as they're allocated.
 func (a *allocator) TargetItems() map[string]*target.Item {
 	a.m.RLock()
 	defer a.m.RUnlock()
-	targetItemsCopy := make(map[string]*target.Item)
+	targetItemsCopy := make(map[string]*target.Item, len(a.targetItems))
 	for k, v := range a.targetItems {
-		targetItemsCopy[k] = v
+		itemCopy := *v
+		targetItemsCopy[k] = &itemCopy
+	}
+	return targetItemsCopy
+}
+
+// TargetItem returns a copy of the target item, along with its current collector, if it's known.
+func (a *allocator) TargetItem(targetHash string) (*target.Item, bool) {
+	a.m.RLock()
+	defer a.m.RUnlock()
+	item, ok := a.targetItems[targetHash]
+	if !ok {
+		return nil, false
+	}
+	itemCopy := *item
+	return &itemCopy, true
+}
+
+// TargetItemsForCollector returns a copy of the target items assigned to the collector, keyed by target hash.
+func (a *allocator) TargetItemsForCollector(collector string) map[string]*target.Item {
+	a.m.RLock()
+	defer a.m.RUnlock()
+	targetItemsCopy := make(map[string]*target.Item)
+	for _, targetHashes := range a.targetItemsPerJobPerCollector[collector] {
+		for targetHash := range targetHashes {
+			itemCopy := *a.targetItems[targetHash]
+			targetItemsCopy[targetHash] = &itemCopy
+		}
 	}
 	return targetItemsCopy
 }
@@ -170,6 +307,7 @@ func (a *allocator) Collectors() map[string]*Collector {
 // Any net-new additions are assigned to the collector on the same node as the target.
 func (a *allocator) handleTargets(diff diff.Changes[*target.Item]) {
 	// Check for removals
+	a.cause = CauseTargetRemoved
 	for k, item := range a.targetItems {
 		// if the current item is in the removals list
 		if _, ok := diff.Removals()[k]; ok {
@@ -177,8 +315,22 @@ func (a *allocator) handleTargets(diff diff.Changes[*target.Item]) {
 		}
 	}
 
-	// Check for additions
+	// Check for targets which didn't fit in the collectors, but may fit now
 	var assignmentErrors []error
+	if len(diff.Removals()) > 0 && a.targetsOverCapacity > 0 {
+		a.cause = CauseCapacityFreed
+		for _, item := range a.targetItems {
+			if item.CollectorName != "" {
+				continue
+			}
+			if err := a.addTargetToTargetItems(item); err != nil {
+				assignmentErrors = append(assignmentErrors, err)
+			}
+		}
+	}
+
+	// Check for additions
+	a.cause = CauseTargetAdded
 	for k, item := range diff.Additions() {
 		// Do nothing if the item is already there
 		if _, ok := a.targetItems[k]; ok {
@@ -199,8 +351,13 @@ func (a *allocator) handleTargets(diff diff.Changes[*target.Item]) {
 	if unassignedTargets > 0 {
 		err := errors.Join(assignmentErrors...)
 		a.log.Info("Could not assign targets for some jobs", "targets", unassignedTargets, "error", err)
-		TargetsUnassigned.Set(float64(unassignedTargets))
+		TargetsUnassigned.WithLabelValues(a.pool).Set(float64(unassignedTargets))
 	}
+
+	a.updateCollectorCosts()
+	a.rebalance()
+	a.updateCrossZoneTargets()
+	a.updateTargetsOverCapacity()
 }
 
 func (a *allocator) addTargetToTargetItems(tg *target.Item) error {
@@ -221,14 +378,23 @@ func (a *allocator) addTargetToTargetItems(tg *target.Item) error {
 		a.unassignTargetItem(tg)
 	}
 
-	tg.CollectorName = colOwner.Name
-	a.addCollectorTargetItemMapping(tg)
-	a.collectors[colOwner.Name].NumTargets++
-	TargetsPerCollector.WithLabelValues(colOwner.String(), a.strategy.GetName()).Set(float64(a.collectors[colOwner.String()].NumTargets))
+	a.assignTargetItem(tg, colOwner.Name)
+	a.recordFallback(tg)
 
 	return nil
 }
 
+// assignTargetItem assigns the unassigned target item to the collector.
+func (a *allocator) assignTargetItem(tg *target.Item, collectorName string) {
+	tg.CollectorName = collectorName
+	a.addCollectorTargetItemMapping(tg)
+	a.recordChange(collectorName, tg, true)
+	c := a.collectors[collectorName]
+	c.NumTargets++
+	c.Cost += a.targetCost(tg)
+	TargetsPerCollector.WithLabelValues(collectorName, a.strategy.GetName(), a.pool).Set(float64(c.NumTargets))
+}
+
 // unassignTargetItem unassigns the target item from its Collector. The target item is still tracked.
 func (a *allocator) unassignTargetItem(item *target.Item) {
 	collectorName := item.CollectorName
@@ -240,7 +406,9 @@ func (a *allocator) unassignTargetItem(item *target.Item) {
 		return
 	}
 	c.NumTargets--
-	TargetsPerCollector.WithLabelValues(item.CollectorName, a.strategy.GetName()).Set(float64(c.NumTargets))
+	c.Cost -= a.targetCost(item)
+	a.recordChange(collectorName, item, false)
+	TargetsPerCollector.WithLabelValues(item.CollectorName, a.strategy.GetName(), a.pool).Set(float64(c.NumTargets))
 	delete(a.targetItemsPerJobPerCollector[item.CollectorName][item.JobName], item.Hash())
 	if len(a.targetItemsPerJobPerCollector[item.CollectorName][item.JobName]) == 0 {
 		delete(a.targetItemsPerJobPerCollector[item.CollectorName], item.JobName)
@@ -252,6 +420,8 @@ func (a *allocator) unassignTargetItem(item *target.Item) {
 func (a *allocator) removeTargetItem(item *target.Item) {
 	a.unassignTargetItem(item)
 	delete(a.targetItems, item.Hash())
+	a.costSum -= a.targetCosts[item.Hash()]
+	delete(a.targetCosts, item.Hash())
 }
 
 // removeCollector removes a Collector from the allocator.
@@ -260,11 +430,13 @@ func (a *allocator) removeCollector(collector *Collector) {
 	// Remove the collector from any target item records
 	for _, targetItems := range a.targetItemsPerJobPerCollector[collector.Name] {
 		for targetHash := range targetItems {
+			a.recordChange(collector.Name, a.targetItems[targetHash], false)
 			a.targetItems[targetHash].CollectorName = ""
 		}
 	}
 	delete(a.targetItemsPerJobPerCollector, collector.Name)
-	TargetsPerCollector.WithLabelValues(collector.Name, a.strategy.GetName()).Set(0)
+	TargetsPerCollector.WithLabelValues(collector.Name, a.strategy.GetName(), a.pool).Set(0)
+	CostPerCollector.WithLabelValues(collector.Name, a.strategy.GetName(), a.pool).Set(0)
 }
 
 // addCollectorTargetItemMapping keeps track of which collector has which jobs and targets
@@ -285,18 +457,38 @@ func (a *allocator) addCollectorTargetItemMapping(tg *target.Item) {
 // Finally, update all targets' collector assignments.
 func (a *allocator) handleCollectors(diff diff.Changes[*Collector]) {
 	// Clear removed collectors
+	a.cause = CauseCollectorRemoved
 	for _, k := range diff.Removals() {
 		a.removeCollector(k)
 	}
 	// Insert the new collectors
 	for _, i := range diff.Additions() {
 		a.collectors[i.Name] = NewCollector(i.Name, i.NodeName)
+		a.collectors[i.Name].Weight = i.Weight
+		a.collectors[i.Name].Capacity = i.Capacity
+		a.setCollectorTopology(a.collectors[i.Name])
 	}
 
 	// Set collectors on the strategy
 	a.strategy.SetCollectors(a.collectors)
 
-	// Re-Allocate all targets
+	switch {
+	case len(diff.Additions()) > 0:
+		a.cause = CauseCollectorAdded
+	case len(diff.Removals()) > 0:
+		a.cause = CauseCollectorRemoved
+	default:
+		a.cause = CauseCollectorResized
+	}
+	a.reallocateTargets()
+	a.updateCollectorCosts()
+	a.rebalance()
+	a.updateCrossZoneTargets()
+	a.updateTargetsOverCapacity()
+}
+
+// reallocateTargets asks the strategy for the collector of every target.
+func (a *allocator) reallocateTargets() {
 	var assignmentErrors []error
 	for _, item := range a.targetItems {
 		err := a.addTargetToTargetItems(item)
@@ -310,6 +502,168 @@ func (a *allocator) handleCollectors(diff diff.Changes[*Collector]) {
 	if unassignedTargets > 0 {
 		err := errors.Join(assignmentErrors...)
 		a.log.Info("Could not assign targets for some jobs", "targets", unassignedTargets, "error", err)
-		TargetsUnassigned.Set(float64(unassignedTargets))
+		TargetsUnassigned.WithLabelValues(a.pool).Set(float64(unassignedTargets))
+	}
+}
+
+// resizeCollectors updates the weight and capacity of the existing collectors, reporting whether any changed.
+func (a *allocator) resizeCollectors(collectors map[string]*Collector) bool {
+	resized := false
+	for name, c := range collectors {
+		if existing, ok := a.collectors[name]; ok && (existing.Weight != c.Weight || existing.Capacity != c.Capacity) {
+			existing.Weight = c.Weight
+			existing.Capacity = c.Capacity
+			resized = true
+		}
+	}
+	return resized
+}
+
+// setCollectorTopology fills in the zone and region of the collector from the topology of its Node.
+func (a *allocator) setCollectorTopology(c *Collector) {
+	topology := a.nodeTopology[c.NodeName]
+	c.Zone = topology.Zone
+	c.Region = topology.Region
+}
+
+// updateCrossZoneTargets counts the targets assigned to a collector outside the zone of their Node.
+func (a *allocator) updateCrossZoneTargets() {
+	if len(a.nodeTopology) == 0 {
+		return
+	}
+	crossZone := 0
+	for _, item := range a.targetItems {
+		c, ok := a.collectors[item.CollectorName]
+		if !ok {
+			continue
+		}
+		if zone := a.nodeTopology[item.GetNodeName()].Zone; zone != "" && zone != c.Zone {
+			crossZone++
+		}
+	}
+	TargetsCrossZone.WithLabelValues(a.strategy.GetName(), a.pool).Set(float64(crossZone))
+}
+
+// targetCost returns the reported cost of the target item. Targets without a reported cost are assumed to cost as
+// much as the average reported target, or 1 if no cost has been reported at all.
+func (a *allocator) targetCost(item *target.Item) float64 {
+	if cost, ok := a.targetCosts[item.Hash()]; ok {
+		return cost
+	}
+	if len(a.targetCosts) == 0 {
+		return 1
+	}
+	return a.costSum / float64(len(a.targetCosts))
+}
+
+// updateCollectorCosts recomputes the total cost of every collector from the targets assigned to it.
+func (a *allocator) updateCollectorCosts() {
+	for _, c := range a.collectors {
+		c.Cost = 0
+	}
+	for _, item := range a.targetItems {
+		if c, ok := a.collectors[item.CollectorName]; ok {
+			c.Cost += a.targetCost(item)
+		}
+	}
+	for _, c := range a.collectors {
+		CostPerCollector.WithLabelValues(c.Name, a.strategy.GetName(), a.pool).Set(c.Cost)
+	}
+}
+
+// rebalance moves targets between collectors if the strategy asks for it.
+func (a *allocator) rebalance() {
+	r, ok := a.strategy.(rebalancer)
+	if !ok {
+		return
+	}
+	a.cause = CauseRebalanced
+	moved := 0
+	for hash, collectorName := range r.Rebalance(a.collectors, a.targetItems, a.targetCost) {
+		item, ok := a.targetItems[hash]
+		if !ok || item.CollectorName == collectorName {
+			continue
+		}
+		if _, ok := a.collectors[collectorName]; !ok {
+			continue
+		}
+		a.unassignTargetItem(item)
+		a.assignTargetItem(item, collectorName)
+		moved++
+	}
+	if moved > 0 {
+		a.log.Info("Moved targets to even out the cost of the collectors", "targets", moved)
+		TargetsRebalanced.WithLabelValues(a.strategy.GetName(), a.pool).Add(float64(moved))
+		for _, c := range a.collectors {
+			CostPerCollector.WithLabelValues(c.Name, a.strategy.GetName(), a.pool).Set(c.Cost)
+		}
+	}
+}
+
+// updateTargetsOverCapacity records how many targets exceed the total capacity of the collectors, which is only
+// limited if every collector has a capacity.
+func (a *allocator) updateTargetsOverCapacity() {
+	capacity := 0
+	for _, c := range a.collectors {
+		if c.Capacity <= 0 {
+			capacity = -1
+			break
+		}
+		capacity += c.Capacity
+	}
+	a.targetsOverCapacity = 0
+	if capacity >= 0 && len(a.collectors) > 0 && len(a.targetItems) > capacity {
+		a.targetsOverCapacity = len(a.targetItems) - capacity
+		a.log.Info("The targets exceed the total capacity of the collectors", "targets", len(a.targetItems), "capacity", capacity)
+	}
+	TargetsOverCapacity.WithLabelValues(a.strategy.GetName(), a.pool).Set(float64(a.targetsOverCapacity))
+}
+
+// recordChange records that the target item was assigned to or unassigned from the collector. Unassigning and assigning
+// it back, as happens on reallocation, cancels out.
+func (a *allocator) recordChange(collectorName string, item *target.Item, assigned bool) {
+	a.recordMove(collectorName, item, assigned)
+	if a.changesHandler == nil {
+		return
+	}
+	if a.pendingChanges == nil {
+		a.pendingChanges = make(CollectorChanges)
+	}
+	changes, ok := a.pendingChanges[collectorName]
+	if !ok {
+		changes = diff.NewChanges(map[string]*target.Item{}, map[string]*target.Item{})
+		a.pendingChanges[collectorName] = changes
+	}
+	added, removed := changes.Additions(), changes.Removals()
+	if assigned {
+		if _, ok := removed[item.Hash()]; ok {
+			delete(removed, item.Hash())
+		} else {
+			added[item.Hash()] = item
+		}
+	} else {
+		if _, ok := added[item.Hash()]; ok {
+			delete(added, item.Hash())
+		} else {
+			removed[item.Hash()] = item
+		}
+	}
+}
+
+// publishChanges calls the changes handler with the changes recorded while handling an update. The caller of this
+// method has to hold the lock, so that the handler sees the changes in the order of the updates.
+func (a *allocator) publishChanges() {
+	if a.changesHandler == nil || len(a.pendingChanges) == 0 {
+		return
+	}
+	changes := make(CollectorChanges, len(a.pendingChanges))
+	for collectorName, c := range a.pendingChanges {
+		if len(c.Additions()) > 0 || len(c.Removals()) > 0 {
+			changes[collectorName] = c
+		}
+	}
+	a.pendingChanges = nil
+	if len(changes) > 0 {
+		a.changesHandler(changes)
 	}
 }
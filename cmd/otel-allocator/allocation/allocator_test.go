@@ -17,8 +17,10 @@ package allocation
 import (
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/target"
 )
@@ -49,6 +51,60 @@ func TestSetTargets(t *testing.T) {
 	})
 }
 
+func TestTargetItemsAreCopies(t *testing.T) {
+	RunForAllStrategies(t, func(t *testing.T, allocator Allocator) {
+		allocator.SetCollectors(MakeNCollectors(3, 0))
+		allocator.SetTargets(MakeNNewTargetsWithEmptyCollectors(3, 0))
+
+		for _, item := range allocator.TargetItems() {
+			item.CollectorName = ""
+		}
+		// the collectors of the allocated targets are left as they are
+		for _, item := range allocator.TargetItems() {
+			assert.NotEmpty(t, item.CollectorName)
+		}
+	})
+}
+
+func TestTargetItem(t *testing.T) {
+	RunForAllStrategies(t, func(t *testing.T, allocator Allocator) {
+		allocator.SetCollectors(MakeNCollectors(3, 0))
+		allocator.SetTargets(MakeNNewTargetsWithEmptyCollectors(3, 0))
+
+		for hash, expected := range allocator.TargetItems() {
+			item, ok := allocator.TargetItem(hash)
+			require.True(t, ok)
+			assert.Equal(t, expected, item)
+			// the item is a copy
+			item.CollectorName = "other"
+			item, _ = allocator.TargetItem(hash)
+			assert.Equal(t, expected.CollectorName, item.CollectorName)
+		}
+		_, ok := allocator.TargetItem("unknown")
+		assert.False(t, ok)
+	})
+}
+
+func TestTargetItemsForCollector(t *testing.T) {
+	RunForAllStrategies(t, func(t *testing.T, allocator Allocator) {
+		cols := MakeNCollectors(3, 0)
+		allocator.SetCollectors(cols)
+		allocator.SetTargets(MakeNNewTargetsWithEmptyCollectors(9, 0))
+
+		total := 0
+		for name := range cols {
+			items := allocator.TargetItemsForCollector(name)
+			for hash, item := range items {
+				assert.Equal(t, name, item.CollectorName)
+				assert.Equal(t, hash, item.Hash())
+			}
+			total += len(items)
+		}
+		assert.Equal(t, len(allocator.TargetItems()), total)
+		assert.Empty(t, allocator.TargetItemsForCollector("unknown"))
+	})
+}
+
 func TestCanSetSingleTarget(t *testing.T) {
 	RunForAllStrategies(t, func(t *testing.T, allocator Allocator) {
 		cols := MakeNCollectors(3, 0)
@@ -205,3 +261,115 @@ func TestAllocationCollision(t *testing.T) {
 		}
 	})
 }
+
+func TestTargetChangesHandler(t *testing.T) {
+	RunForAllStrategies(t, func(t *testing.T, allocator Allocator) {
+		// the changes are applied to a copy of the assignments, which has to match the allocator's after every update
+		assigned := map[string]map[string]bool{}
+		updates := 0
+		allocator.SetTargetChangesHandler(func(changes CollectorChanges) {
+			updates++
+			for collectorName, c := range changes {
+				assert.NotEmpty(t, len(c.Additions())+len(c.Removals()))
+				if assigned[collectorName] == nil {
+					assigned[collectorName] = map[string]bool{}
+				}
+				for hash := range c.Removals() {
+					assert.Contains(t, assigned[collectorName], hash)
+					delete(assigned[collectorName], hash)
+				}
+				for hash := range c.Additions() {
+					assert.NotContains(t, assigned[collectorName], hash)
+					assigned[collectorName][hash] = true
+				}
+			}
+		})
+		assertAssigned := func() {
+			t.Helper()
+			expected := map[string]map[string]bool{}
+			for hash, item := range allocator.TargetItems() {
+				if item.CollectorName == "" {
+					continue
+				}
+				if expected[item.CollectorName] == nil {
+					expected[item.CollectorName] = map[string]bool{}
+				}
+				expected[item.CollectorName][hash] = true
+			}
+			for collectorName, hashes := range assigned {
+				if len(hashes) == 0 {
+					delete(assigned, collectorName)
+				}
+			}
+			assert.Equal(t, expected, assigned)
+		}
+
+		allocator.SetCollectors(MakeNCollectors(3, 0))
+		allocator.SetTargets(MakeNNewTargets(30, 3, 0))
+		assertAssigned()
+
+		allocator.SetCollectors(MakeNCollectors(4, 0))
+		assertAssigned()
+
+		allocator.SetCollectors(MakeNCollectors(2, 0))
+		assertAssigned()
+
+		allocator.SetTargets(MakeNNewTargets(10, 3, 0))
+		assertAssigned()
+
+		// an update which doesn't change the assignments isn't published
+		published := updates
+		allocator.SetTargets(MakeNNewTargets(10, 3, 0))
+		assert.Equal(t, published, updates)
+	})
+}
+
+func TestAllocatorsDontShareStrategies(t *testing.T) {
+	for _, name := range GetRegisteredAllocatorNames() {
+		t.Run(name, func(t *testing.T) {
+			first, err := New(name, logger)
+			require.NoError(t, err)
+			second, err := New(name, logger)
+			require.NoError(t, err)
+
+			first.SetCollectors(MakeNCollectors(3, 0))
+			second.SetCollectors(MakeNCollectors(3, 3))
+			first.SetTargets(MakeNNewTargets(20, 3, 0))
+			second.SetTargets(MakeNNewTargets(20, 3, 0))
+
+			for allocator, collectors := range map[Allocator]map[string]*Collector{first: first.Collectors(), second: second.Collectors()} {
+				assigned := 0
+				for _, item := range allocator.TargetItems() {
+					if item.CollectorName == "" {
+						continue
+					}
+					assigned++
+					assert.Contains(t, collectors, item.CollectorName)
+				}
+				if name != perNodeStrategyName {
+					assert.Positive(t, assigned)
+				}
+			}
+		})
+	}
+}
+
+func TestPoolMetrics(t *testing.T) {
+	teamA, err := New(leastWeightedStrategyName, logger, WithPool("team-a"))
+	require.NoError(t, err)
+	teamB, err := New(leastWeightedStrategyName, logger, WithPool("team-b"))
+	require.NoError(t, err)
+
+	teamA.SetCollectors(MakeNCollectors(3, 0))
+	teamA.SetTargets(MakeNNewTargets(20, 3, 0))
+	teamB.SetCollectors(MakeNCollectors(2, 0))
+	teamB.SetTargets(MakeNNewTargets(5, 2, 0))
+
+	// the collectors of the pools have the same names, their metrics are kept apart by pool
+	assert.Equal(t, 3.0, testutil.ToFloat64(CollectorsAllocatable.WithLabelValues(leastWeightedStrategyName, "team-a")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(CollectorsAllocatable.WithLabelValues(leastWeightedStrategyName, "team-b")))
+	assert.Equal(t, 20.0, testutil.ToFloat64(TargetsRemaining.WithLabelValues("team-a")))
+	assert.Equal(t, 5.0, testutil.ToFloat64(TargetsRemaining.WithLabelValues("team-b")))
+	assert.Equal(t, float64(teamA.Collectors()["collector-0"].NumTargets), testutil.ToFloat64(TargetsPerCollector.WithLabelValues("collector-0", leastWeightedStrategyName, "team-a")))
+	assert.Equal(t, float64(teamB.Collectors()["collector-0"].NumTargets), testutil.ToFloat64(TargetsPerCollector.WithLabelValues("collector-0", leastWeightedStrategyName, "team-b")))
+}
@@ -36,6 +36,26 @@ var _ Strategy = &consistentHashingStrategy{}
 type consistentHashingStrategy struct {
 	config           consistent.Config
 	consistentHasher *consistent.Consistent
+	// members is the number of members of the hasher, a collector being added once per unit of weight
+	members int
+}
+
+// weightedMember is an additional member of the hasher for a collector with a weight above 1.
+type weightedMember struct {
+	collector string
+	index     int
+}
+
+func (m weightedMember) String() string {
+	return fmt.Sprintf("%s/%d", m.collector, m.index)
+}
+
+// collectorOf returns the name of the collector a member of the hasher stands for.
+func collectorOf(member consistent.Member) string {
+	if m, ok := member.(weightedMember); ok {
+		return m.collector
+	}
+	return member.String()
 }
 
 func newConsistentHashingStrategy() Strategy {
@@ -60,12 +80,30 @@ func (s *consistentHashingStrategy) GetName() string {
 func (s *consistentHashingStrategy) GetCollectorForTarget(collectors map[string]*Collector, item *target.Item) (*Collector, error) {
 	hashKey := item.TargetURL
 	member := s.consistentHasher.LocateKey([]byte(hashKey))
-	collectorName := member.String()
+	collectorName := collectorOf(member)
 	collector, ok := collectors[collectorName]
 	if !ok {
 		return nil, fmt.Errorf("unknown collector %s", collectorName)
 	}
-	return collector, nil
+	if collector.hasRoomFor(item) {
+		return collector, nil
+	}
+
+	// the collector is at capacity, move on to the next ones on the ring
+	closest, err := s.consistentHasher.GetClosestN([]byte(hashKey), s.members)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range closest {
+		if c, ok := collectors[collectorOf(m)]; ok && c.hasRoomFor(item) {
+			return c, nil
+		}
+	}
+	if current, ok := collectors[item.CollectorName]; ok && item.CollectorName != "" {
+		// there is no room anywhere else
+		return current, nil
+	}
+	return nil, errCollectorsAtCapacity
 }
 
 func (s *consistentHashingStrategy) SetCollectors(collectors map[string]*Collector) {
@@ -77,10 +115,15 @@ func (s *consistentHashingStrategy) SetCollectors(collectors map[string]*Collect
 		members = make([]consistent.Member, 0, len(collectors))
 		for _, collector := range collectors {
 			members = append(members, collector)
+			// the collector itself stays a member, so that the targets of collectors without a weight don't move
+			for i := 1; i < collector.weight(); i++ {
+				members = append(members, weightedMember{collector: collector.Name, index: i})
+			}
 		}
 	}
 
 	s.consistentHasher = consistent.New(members, s.config)
+	s.members = len(members)
 
 }
 
@@ -130,3 +130,42 @@ func TestTargetsWithNoCollectorsConsistentHashing(t *testing.T) {
 		assert.InDelta(t, col.NumTargets, expectedPerCollector, expectedDelta)
 	}
 }
+
+func TestWeightedCollectorsConsistentHashing(t *testing.T) {
+	cols := MakeNCollectors(3, 0)
+	cols["collector-2"].Weight = 2
+	c, _ := New("consistent-hashing", logger)
+	c.SetCollectors(cols)
+	c.SetTargets(MakeNNewTargets(10000, 0, 0))
+
+	actualCollectors := c.Collectors()
+	expectedPerWeight := 10000.0 / 4
+	assert.InDelta(t, expectedPerWeight, actualCollectors["collector-0"].NumTargets, expectedPerWeight*0.5)
+	assert.InDelta(t, expectedPerWeight, actualCollectors["collector-1"].NumTargets, expectedPerWeight*0.5)
+	assert.InDelta(t, 2*expectedPerWeight, actualCollectors["collector-2"].NumTargets, 2*expectedPerWeight*0.5)
+}
+
+func TestCollectorCapacityConsistentHashing(t *testing.T) {
+	cols := MakeNCollectors(3, 0)
+	for _, col := range cols {
+		col.Capacity = 300
+	}
+	c, _ := New("consistent-hashing", logger)
+	c.SetCollectors(cols)
+	c.SetTargets(MakeNNewTargets(1000, 0, 0))
+
+	assigned := 0
+	for _, col := range c.Collectors() {
+		assert.Equal(t, 300, col.NumTargets)
+		assigned += col.NumTargets
+	}
+	assert.Equal(t, 900, assigned)
+	assert.Equal(t, 100, c.TargetsOverCapacity())
+
+	// without a capacity, every target is assigned again
+	c.SetCollectors(MakeNCollectors(3, 0))
+	assert.Equal(t, 0, c.TargetsOverCapacity())
+	for _, item := range c.TargetItems() {
+		assert.NotEmpty(t, item.CollectorName)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package allocation
+
+import (
+	"math"
+	"sort"
+
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/target"
+)
+
+const (
+	costWeightedStrategyName = "cost-weighted"
+
+	// costImbalanceThreshold is how far above the average cost the most loaded collector has to be before targets
+	// are moved. costBalancedThreshold is how close to the average it has to get before moving stops. The gap between
+	// the two keeps the allocator from shuffling targets back and forth on every cost report.
+	costImbalanceThreshold = 0.2
+	costBalancedThreshold  = 0.1
+)
+
+var _ Strategy = &costWeightedStrategy{}
+var _ rebalancer = &costWeightedStrategy{}
+
+// costWeightedStrategy assigns targets to the collector with the lowest total cost, where the cost of a target is
+// reported by the collectors, e.g. the number of series it exposes. Unlike least-weighted, already assigned targets
+// are moved when the cost of the collectors drifts apart.
+type costWeightedStrategy struct{}
+
+func newCostWeightedStrategy() Strategy {
+	return &costWeightedStrategy{}
+}
+
+func (s *costWeightedStrategy) GetName() string {
+	return costWeightedStrategyName
+}
+
+func (s *costWeightedStrategy) GetCollectorForTarget(collectors map[string]*Collector, item *target.Item) (*Collector, error) {
+	// if a collector is already assigned, do nothing, rebalancing takes care of moving it
+	if item.CollectorName != "" {
+		if col, ok := collectors[item.CollectorName]; ok {
+			return col, nil
+		}
+	}
+
+	var col *Collector
+	for _, v := range collectors {
+		if col == nil || lessLoaded(v, col) {
+			col = v
+		}
+	}
+	return col, nil
+}
+
+func (s *costWeightedStrategy) SetCollectors(_ map[string]*Collector) {}
+
+func (s *costWeightedStrategy) SetFallbackStrategy(_ Strategy) {}
+
+// Rebalance moves targets from the most to the least loaded collector once the most loaded one exceeds the average
+// cost by costImbalanceThreshold, until it is within costBalancedThreshold of it or no move reduces the gap anymore.
+func (s *costWeightedStrategy) Rebalance(collectors map[string]*Collector, targets map[string]*target.Item, cost func(*target.Item) float64) map[string]string {
+	if len(collectors) < 2 {
+		return nil
+	}
+
+	load := map[string]float64{}
+	total := 0.0
+	for name := range collectors {
+		load[name] = 0
+	}
+	assigned := map[string][]*target.Item{}
+	for _, item := range targets {
+		if _, ok := load[item.CollectorName]; !ok {
+			continue
+		}
+		load[item.CollectorName] += cost(item)
+		total += cost(item)
+		assigned[item.CollectorName] = append(assigned[item.CollectorName], item)
+	}
+	average := total / float64(len(collectors))
+	if average == 0 {
+		return nil
+	}
+	for _, items := range assigned {
+		sort.Slice(items, func(i, j int) bool { return items[i].Hash() < items[j].Hash() })
+	}
+
+	names := make([]string, 0, len(load))
+	for name := range load {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	mostAndLeastLoaded := func() (string, string) {
+		most, least := names[0], names[0]
+		for _, name := range names[1:] {
+			if load[name] > load[most] {
+				most = name
+			}
+			if load[name] < load[least] {
+				least = name
+			}
+		}
+		return most, least
+	}
+
+	most, _ := mostAndLeastLoaded()
+	if load[most] <= average*(1+costImbalanceThreshold) {
+		return nil
+	}
+
+	moves := map[string]string{}
+	for range targets {
+		most, least := mostAndLeastLoaded()
+		if load[most] <= average*(1+costBalancedThreshold) {
+			break
+		}
+		// the target whose cost is closest to half the gap evens out the pair the most, anything at or above the
+		// gap would only swap which collector is overloaded
+		gap := load[most] - load[least]
+		candidate := -1
+		for i, item := range assigned[most] {
+			c := cost(item)
+			if c <= 0 || c >= gap {
+				continue
+			}
+			if candidate == -1 || math.Abs(c-gap/2) < math.Abs(cost(assigned[most][candidate])-gap/2) {
+				candidate = i
+			}
+		}
+		if candidate == -1 {
+			break
+		}
+		item := assigned[most][candidate]
+		assigned[most] = append(assigned[most][:candidate], assigned[most][candidate+1:]...)
+		assigned[least] = append(assigned[least], item)
+		load[most] -= cost(item)
+		load[least] += cost(item)
+		moves[item.Hash()] = least
+	}
+	return moves
+}
+
+// lessLoaded orders collectors by cost, then by number of targets and finally by name so that the choice is stable
+// while no cost has been reported yet.
+func lessLoaded(a, b *Collector) bool {
+	if a.Cost != b.Cost {
+		return a.Cost < b.Cost
+	}
+	if a.NumTargets != b.NumTargets {
+		return a.NumTargets < b.NumTargets
+	}
+	return a.Name < b.Name
+}
@@ -0,0 +1,189 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package allocation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/target"
+)
+
+func makeCostTargets(costs ...float64) (map[string]*target.Item, []TargetCost) {
+	targets := map[string]*target.Item{}
+	var reported []TargetCost
+	for i, cost := range costs {
+		item := target.NewItem("test-job", fmt.Sprintf("test-url-%d", i), labels.Labels{{Name: "i", Value: fmt.Sprint(i)}}, "")
+		targets[item.Hash()] = item
+		reported = append(reported, TargetCost{JobName: item.JobName, TargetURL: item.TargetURL, Cost: cost})
+	}
+	return targets, reported
+}
+
+func collectorCosts(a Allocator) map[string]float64 {
+	costs := map[string]float64{}
+	for name, col := range a.Collectors() {
+		costs[name] = col.Cost
+	}
+	return costs
+}
+
+func TestCostWeightedWithoutCostsBalancesTargetCount(t *testing.T) {
+	s, err := New(costWeightedStrategyName, logger)
+	require.NoError(t, err)
+
+	s.SetCollectors(MakeNCollectors(3, 0))
+	s.SetTargets(MakeNNewTargets(9, 3, 0))
+
+	for _, col := range s.Collectors() {
+		assert.Equal(t, 3, col.NumTargets)
+		assert.Equal(t, float64(3), col.Cost)
+	}
+}
+
+func TestCostWeightedAssignsToCheapestCollector(t *testing.T) {
+	s, err := New(costWeightedStrategyName, logger)
+	require.NoError(t, err)
+	s.SetCollectors(MakeNCollectors(2, 0))
+
+	targets, costs := makeCostTargets(100, 10, 10)
+	s.SetTargets(targets)
+	s.SetTargetCosts(costs)
+
+	// the expensive target ends up alone on one collector and the two cheap ones on the other
+	expensive := targets[findTarget(t, targets, "test-url-0")].CollectorName
+	for _, item := range s.TargetItems() {
+		if item.TargetURL != "test-url-0" {
+			assert.NotEqual(t, expensive, item.CollectorName, item.TargetURL)
+		}
+	}
+
+	// a new target without a reported cost goes to the cheaper collector
+	newTarget := target.NewItem("test-job", "test-url-new", labels.Labels{}, "")
+	targets[newTarget.Hash()] = newTarget
+	s.SetTargets(targets)
+	assert.NotEqual(t, expensive, s.TargetItems()[newTarget.Hash()].CollectorName)
+}
+
+func TestCostWeightedRebalancesWithHysteresis(t *testing.T) {
+	s, err := New(costWeightedStrategyName, logger)
+	require.NoError(t, err)
+	s.SetCollectors(MakeNCollectors(2, 0))
+
+	targets, costs := makeCostTargets(1, 1, 1, 1)
+	s.SetTargets(targets)
+	assert.Equal(t, map[string]float64{"collector-0": 2, "collector-1": 2}, collectorCosts(s))
+
+	// both targets of one collector turn out to be expensive
+	for i, c := range costs {
+		if targets[findTarget(t, targets, c.TargetURL)].CollectorName == "collector-0" {
+			costs[i].Cost = 100
+		} else {
+			costs[i].Cost = 10
+		}
+	}
+	s.SetTargetCosts(costs)
+
+	// one expensive target is moved, which is the best the allocator can do
+	assert.Equal(t, map[string]float64{"collector-0": 100, "collector-1": 120}, collectorCosts(s))
+	assignments := map[string]string{}
+	for hash, item := range s.TargetItems() {
+		assignments[hash] = item.CollectorName
+	}
+
+	// a change within the threshold doesn't move anything
+	for i := range costs {
+		if costs[i].Cost == 10 {
+			costs[i].Cost = 12
+			break
+		}
+	}
+	s.SetTargetCosts(costs)
+	assert.Equal(t, map[string]float64{"collector-0": 100, "collector-1": 122}, collectorCosts(s))
+	for hash, item := range s.TargetItems() {
+		assert.Equal(t, assignments[hash], item.CollectorName)
+	}
+}
+
+func TestCostWeightedRebalance(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		numCollectors int
+		costs         []float64
+		collectors    []string
+		expectedMoves int
+	}{
+		{
+			name:          "balanced",
+			numCollectors: 2,
+			costs:         []float64{10, 11},
+			collectors:    []string{"collector-0", "collector-1"},
+			expectedMoves: 0,
+		},
+		{
+			name:          "single expensive target can't be split",
+			numCollectors: 2,
+			costs:         []float64{1000, 10},
+			collectors:    []string{"collector-0", "collector-1"},
+			expectedMoves: 0,
+		},
+		{
+			name:          "all targets on one collector",
+			numCollectors: 2,
+			costs:         []float64{10, 10, 10, 10},
+			collectors:    []string{"collector-0", "collector-0", "collector-0", "collector-0"},
+			expectedMoves: 2,
+		},
+		{
+			name:          "single collector",
+			numCollectors: 1,
+			costs:         []float64{10, 10},
+			collectors:    []string{"collector-0", "collector-0"},
+			expectedMoves: 0,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			collectors := MakeNCollectors(tc.numCollectors, 0)
+			targets := map[string]*target.Item{}
+			costs := map[string]float64{}
+			for i, cost := range tc.costs {
+				item := target.NewItem("test-job", fmt.Sprintf("test-url-%d", i), labels.Labels{}, tc.collectors[i])
+				targets[item.Hash()] = item
+				costs[item.Hash()] = cost
+			}
+			moves := newCostWeightedStrategy().(rebalancer).Rebalance(collectors, targets, func(item *target.Item) float64 {
+				return costs[item.Hash()]
+			})
+			assert.Len(t, moves, tc.expectedMoves)
+			for hash, col := range moves {
+				assert.NotEqual(t, targets[hash].CollectorName, col)
+			}
+		})
+	}
+}
+
+func findTarget(t *testing.T, targets map[string]*target.Item, url string) string {
+	for hash, item := range targets {
+		if item.TargetURL == url {
+			return hash
+		}
+	}
+	t.Fatalf("no target with url %s", url)
+	return ""
+}
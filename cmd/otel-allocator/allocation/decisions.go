@@ -0,0 +1,219 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package allocation
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/target"
+)
+
+// maxDecisions is how many of the latest allocation decisions are kept.
+const maxDecisions = 1000
+
+// DecisionCause is why the collector of a target changed.
+type DecisionCause string
+
+const (
+	CauseTargetAdded      DecisionCause = "target_added"
+	CauseTargetRemoved    DecisionCause = "target_removed"
+	CauseTargetRelabeled  DecisionCause = "target_relabeled"
+	CauseCollectorAdded   DecisionCause = "collector_added"
+	CauseCollectorRemoved DecisionCause = "collector_removed"
+	CauseCollectorResized DecisionCause = "collector_resized"
+	CauseCapacityFreed    DecisionCause = "capacity_freed"
+	CauseRebalanced       DecisionCause = "rebalanced"
+	CauseTopologyChanged  DecisionCause = "topology_changed"
+	CauseFallback         DecisionCause = "fallback"
+)
+
+var causeDescriptions = map[DecisionCause]string{
+	CauseTargetAdded:      "the target was discovered",
+	CauseTargetRemoved:    "the target is gone",
+	CauseTargetRelabeled:  "the labels of the target changed",
+	CauseCollectorAdded:   "a collector was added",
+	CauseCollectorRemoved: "a collector was removed",
+	CauseCollectorResized: "the weight or capacity of a collector changed",
+	CauseCapacityFreed:    "other targets were removed, leaving room for it",
+	CauseRebalanced:       "the load of the collectors was evened out",
+	CauseTopologyChanged:  "the topology of the Nodes changed",
+	CauseFallback:         "the strategy fell back to another way of assigning it",
+}
+
+var (
+	// TargetsReassigned records how many targets moved from one collector to another, by cause.
+	TargetsReassigned = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "opentelemetry_allocator_targets_reassigned",
+		Help: "Number of targets moved from one collector to another.",
+	}, []string{"cause", "strategy", "pool"})
+)
+
+// Decision is a change of the collector a target is assigned to, an empty collector meaning that the target isn't
+// assigned to any.
+type Decision struct {
+	Time       time.Time     `json:"time"`
+	TargetHash string        `json:"target_hash"`
+	JobName    string        `json:"job_name"`
+	TargetURL  string        `json:"target"`
+	From       string        `json:"from,omitempty"`
+	To         string        `json:"to,omitempty"`
+	Cause      DecisionCause `json:"cause"`
+}
+
+// Explain describes the decision in a sentence.
+func (d Decision) Explain() string {
+	switch {
+	case d.To == "":
+		return fmt.Sprintf("Unassigned from %s at %s, because %s.", d.From, d.Time.Format(time.RFC3339), causeDescriptions[d.Cause])
+	case d.From == "" || d.From == d.To:
+		return fmt.Sprintf("Assigned to %s at %s, because %s.", d.To, d.Time.Format(time.RFC3339), causeDescriptions[d.Cause])
+	default:
+		return fmt.Sprintf("Moved from %s to %s at %s, because %s.", d.From, d.To, d.Time.Format(time.RFC3339), causeDescriptions[d.Cause])
+	}
+}
+
+// fallbackReporter is implemented by strategies which fall back to another way of assigning some targets.
+type fallbackReporter interface {
+	// UsesFallback reports whether the target is assigned by the fallback.
+	UsesFallback(item *target.Item) bool
+}
+
+// move is the change of the collector of a target during an update of the allocator.
+type move struct {
+	item     *target.Item
+	from     string
+	to       string
+	cause    DecisionCause
+	fallback bool
+}
+
+// Decisions returns the latest allocation decisions, oldest first.
+func (a *allocator) Decisions() []Decision {
+	a.m.RLock()
+	defer a.m.RUnlock()
+	decisions := make([]Decision, 0, len(a.decisions))
+	decisions = append(decisions, a.decisions[a.nextDecision:]...)
+	return append(decisions, a.decisions[:a.nextDecision]...)
+}
+
+// LastDecision returns the decision which assigned the target to its current collector, if any.
+func (a *allocator) LastDecision(targetHash string) (Decision, bool) {
+	a.m.RLock()
+	defer a.m.RUnlock()
+	d, ok := a.lastDecisions[targetHash]
+	return d, ok
+}
+
+// recordMove records that the target item was assigned to or unassigned from the collector, for the cause of the
+// current update unless the target already moved during it.
+func (a *allocator) recordMove(collectorName string, item *target.Item, assigned bool) {
+	if a.moves == nil {
+		a.moves = make(map[string]*move)
+	}
+	m, ok := a.moves[item.Hash()]
+	if !ok {
+		m = &move{item: item, cause: a.cause}
+		if !assigned {
+			m.from = collectorName
+		}
+		a.moves[item.Hash()] = m
+	}
+	m.to = ""
+	if assigned {
+		m.to = collectorName
+	}
+}
+
+// recordFallback records that the strategy assigned the target item by its fallback, if it has one.
+func (a *allocator) recordFallback(item *target.Item) {
+	if f, ok := a.strategy.(fallbackReporter); ok && f.UsesFallback(item) {
+		if m, ok := a.moves[item.Hash()]; ok {
+			m.fallback = true
+		}
+	}
+}
+
+// recordDecisions turns the moves of the targets during an update into decisions. The caller of this method has to
+// hold the lock.
+func (a *allocator) recordDecisions() {
+	if len(a.moves) == 0 {
+		return
+	}
+	now := time.Now()
+	decision := func(hash string, m *move) Decision {
+		return Decision{Time: now, TargetHash: hash, JobName: m.item.JobName, TargetURL: m.item.TargetURL, From: m.from, To: m.to, Cause: m.cause}
+	}
+
+	// a relabeled target is removed and added back with another hash, which makes a single decision
+	type targetKey struct{ job, url string }
+	removed := map[targetKey]string{}
+	for hash, m := range a.moves {
+		if _, ok := a.targetItems[hash]; !ok && m.from != "" {
+			removed[targetKey{m.item.JobName, m.item.TargetURL}] = hash
+		}
+	}
+
+	var decisions []Decision
+	for hash, m := range a.moves {
+		if _, ok := a.targetItems[hash]; !ok {
+			continue
+		}
+		d := decision(hash, m)
+		key := targetKey{m.item.JobName, m.item.TargetURL}
+		if removedHash, ok := removed[key]; ok && m.from == "" && m.cause == CauseTargetAdded {
+			d.From, d.Cause = a.moves[removedHash].from, CauseTargetRelabeled
+			delete(removed, key)
+		}
+		if m.fallback && d.To != "" {
+			d.Cause = CauseFallback
+		}
+		// a relabeled target is a new one for collectors, even if it stays on the same collector
+		if d.From != d.To || d.Cause == CauseTargetRelabeled {
+			decisions = append(decisions, d)
+		}
+	}
+	for _, hash := range removed {
+		d := decision(hash, a.moves[hash])
+		d.To, d.Cause = "", CauseTargetRemoved
+		decisions = append(decisions, d)
+	}
+	for hash := range a.moves {
+		if _, ok := a.targetItems[hash]; !ok {
+			delete(a.lastDecisions, hash)
+		}
+	}
+	a.moves = nil
+
+	sort.Slice(decisions, func(i, j int) bool { return decisions[i].TargetHash < decisions[j].TargetHash })
+	for _, d := range decisions {
+		if d.From != "" && d.To != "" && d.From != d.To {
+			TargetsReassigned.WithLabelValues(string(d.Cause), a.strategy.GetName(), a.pool).Inc()
+		}
+		if d.Cause != CauseTargetRemoved {
+			a.lastDecisions[d.TargetHash] = d
+		}
+		if len(a.decisions) < maxDecisions {
+			a.decisions = append(a.decisions, d)
+			continue
+		}
+		a.decisions[a.nextDecision] = d
+		a.nextDecision = (a.nextDecision + 1) % maxDecisions
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package allocation
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/target"
+)
+
+func TestDecisions(t *testing.T) {
+	s, _ := New("least-weighted", logger)
+	s.SetCollectors(MakeNCollectors(2, 0))
+	targets := MakeNNewTargets(4, 0, 0)
+	s.SetTargets(targets)
+
+	decisions := s.Decisions()
+	require.Len(t, decisions, 4)
+	for _, d := range decisions {
+		assert.Equal(t, CauseTargetAdded, d.Cause)
+		assert.Empty(t, d.From)
+		assert.Equal(t, s.TargetItems()[d.TargetHash].CollectorName, d.To)
+		last, ok := s.LastDecision(d.TargetHash)
+		require.True(t, ok)
+		assert.Equal(t, d, last)
+	}
+
+	// the targets of a removed collector move to the remaining one
+	reassigned := testutil.ToFloat64(TargetsReassigned.WithLabelValues(string(CauseCollectorRemoved), "least-weighted", ""))
+	s.SetCollectors(MakeNCollectors(1, 0))
+	decisions = s.Decisions()[4:]
+	require.Len(t, decisions, 2)
+	for _, d := range decisions {
+		assert.Equal(t, CauseCollectorRemoved, d.Cause)
+		assert.Equal(t, "collector-1", d.From)
+		assert.Equal(t, "collector-0", d.To)
+	}
+	assert.Equal(t, reassigned+2, testutil.ToFloat64(TargetsReassigned.WithLabelValues(string(CauseCollectorRemoved), "least-weighted", "")))
+
+	// a relabeled target makes a single decision
+	var relabeled, removed *target.Item
+	for _, item := range targets {
+		if relabeled == nil {
+			relabeled = item
+		} else if removed == nil {
+			removed = item
+		}
+	}
+	delete(targets, relabeled.Hash())
+	delete(targets, removed.Hash())
+	newItem := target.NewItem(relabeled.JobName, relabeled.TargetURL, labels.Labels{{Name: "relabeled", Value: "true"}}, "")
+	targets[newItem.Hash()] = newItem
+	s.SetTargets(targets)
+	decisions = s.Decisions()[6:]
+	require.Len(t, decisions, 2)
+	for _, d := range decisions {
+		switch d.TargetHash {
+		case newItem.Hash():
+			assert.Equal(t, CauseTargetRelabeled, d.Cause)
+			assert.Equal(t, "collector-0", d.From)
+			assert.Equal(t, "collector-0", d.To)
+		case removed.Hash():
+			assert.Equal(t, CauseTargetRemoved, d.Cause)
+			assert.Equal(t, "collector-0", d.From)
+			assert.Empty(t, d.To)
+		default:
+			t.Errorf("unexpected decision %v", d)
+		}
+	}
+	_, ok := s.LastDecision(relabeled.Hash())
+	assert.False(t, ok)
+	_, ok = s.LastDecision(removed.Hash())
+	assert.False(t, ok)
+	_, ok = s.LastDecision(newItem.Hash())
+	assert.True(t, ok)
+}
+
+func TestDecisionsAreBounded(t *testing.T) {
+	s, _ := New("least-weighted", logger)
+	s.SetCollectors(MakeNCollectors(1, 0))
+	targets := MakeNNewTargets(maxDecisions, 0, 0)
+	s.SetTargets(targets)
+	for hash, item := range MakeNNewTargets(10, 0, maxDecisions) {
+		targets[hash] = item
+	}
+	s.SetTargets(targets)
+
+	decisions := s.Decisions()
+	assert.Len(t, decisions, maxDecisions)
+	// the oldest decisions are dropped first
+	for _, d := range decisions[len(decisions)-10:] {
+		assert.Equal(t, CauseTargetAdded, d.Cause)
+		assert.Contains(t, MakeNNewTargets(10, 0, maxDecisions), d.TargetHash)
+	}
+}
+
+func TestDecisionsWithFallback(t *testing.T) {
+	s, _ := New("per-node", logger, WithFallbackStrategy("consistent-hashing"))
+	s.SetCollectors(MakeNCollectors(2, 0))
+	s.SetTargets(MakeNNewTargets(2, 0, 0))
+
+	decisions := s.Decisions()
+	require.Len(t, decisions, 2)
+	for _, d := range decisions {
+		assert.Equal(t, CauseFallback, d.Cause)
+		assert.Contains(t, d.Explain(), "fell back")
+	}
+}
@@ -33,23 +33,33 @@ func (s *leastWeightedStrategy) GetName() string {
 }
 
 func (s *leastWeightedStrategy) GetCollectorForTarget(collectors map[string]*Collector, item *target.Item) (*Collector, error) {
-	// if a collector is already assigned, do nothing
+	// if a collector is already assigned, do nothing, unless it's over capacity
 	// TODO: track this in a separate map
-	if item.CollectorName != "" {
-		if col, ok := collectors[item.CollectorName]; ok {
-			return col, nil
-		}
+	current, assigned := collectors[item.CollectorName]
+	if assigned && item.CollectorName != "" && current.hasRoomFor(item) {
+		return current, nil
 	}
 
 	var col *Collector
 	for _, v := range collectors {
+		if !v.hasRoomFor(item) {
+			continue
+		}
 		// If the initial collector is empty, set the initial collector to the first element of map
 		if col == nil {
 			col = v
-		} else if v.NumTargets < col.NumTargets {
+		} else if v.NumTargets*col.weight() < col.NumTargets*v.weight() {
+			// v has fewer targets relative to its weight
 			col = v
 		}
 	}
+	if col == nil {
+		if assigned && item.CollectorName != "" {
+			// there is no room anywhere else
+			return current, nil
+		}
+		return nil, errCollectorsAtCapacity
+	}
 	return col, nil
 }
 
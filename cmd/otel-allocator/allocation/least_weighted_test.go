@@ -218,3 +218,52 @@ func TestTargetsWithNoCollectorsLeastWeighted(t *testing.T) {
 		assert.InDelta(t, i.NumTargets, count, math.Round(percent))
 	}
 }
+
+func TestWeightedCollectorsLeastWeighted(t *testing.T) {
+	s, _ := New("least-weighted", logger)
+
+	cols := MakeNCollectors(2, 0)
+	cols["collector-1"].Weight = 3
+	s.SetCollectors(cols)
+	s.SetTargets(MakeNNewTargets(40, 0, 0))
+
+	assert.Equal(t, 10, s.Collectors()["collector-0"].NumTargets)
+	assert.Equal(t, 30, s.Collectors()["collector-1"].NumTargets)
+}
+
+func TestCollectorCapacityLeastWeighted(t *testing.T) {
+	s, _ := New("least-weighted", logger)
+
+	cols := MakeNCollectors(2, 0)
+	for _, col := range cols {
+		col.Capacity = 5
+	}
+	s.SetCollectors(cols)
+	targets := MakeNNewTargets(12, 0, 0)
+	s.SetTargets(targets)
+
+	assert.Equal(t, 5, s.Collectors()["collector-0"].NumTargets)
+	assert.Equal(t, 5, s.Collectors()["collector-1"].NumTargets)
+	assert.Equal(t, 2, s.TargetsOverCapacity())
+
+	// the targets which didn't fit take the room left by removed ones
+	removed := 0
+	for hash, item := range s.TargetItems() {
+		if item.CollectorName != "" && removed < 3 {
+			delete(targets, hash)
+			removed++
+		}
+	}
+	s.SetTargets(targets)
+	assert.Equal(t, 0, s.TargetsOverCapacity())
+	for _, item := range s.TargetItems() {
+		assert.NotEmpty(t, item.CollectorName)
+	}
+
+	// lowering the capacity moves targets to collectors with room left
+	cols = MakeNCollectors(3, 0)
+	cols["collector-0"].Capacity = 2
+	s.SetCollectors(cols)
+	assert.Equal(t, 2, s.Collectors()["collector-0"].NumTargets)
+	assert.Equal(t, 9, s.Collectors()["collector-0"].NumTargets+s.Collectors()["collector-1"].NumTargets+s.Collectors()["collector-2"].NumTargets)
+}
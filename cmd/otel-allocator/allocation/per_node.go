@@ -23,6 +23,7 @@ import (
 const perNodeStrategyName = "per-node"
 
 var _ Strategy = &perNodeStrategy{}
+var _ fallbackReporter = &perNodeStrategy{}
 
 type perNodeStrategy struct {
 	collectorByNode  map[string]*Collector
@@ -57,6 +58,11 @@ func (s *perNodeStrategy) GetCollectorForTarget(collectors map[string]*Collector
 	return collectors[collector.Name], nil
 }
 
+// UsesFallback reports whether the target is assigned by the fallback strategy, as it isn't on a Node.
+func (s *perNodeStrategy) UsesFallback(item *target.Item) bool {
+	return s.fallbackStrategy != nil && item.GetNodeName() == ""
+}
+
 func (s *perNodeStrategy) SetCollectors(collectors map[string]*Collector) {
 	clear(s.collectorByNode)
 	for _, collector := range collectors {
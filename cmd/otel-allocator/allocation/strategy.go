@@ -15,6 +15,7 @@
 package allocation
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/buraksezer/consistent"
@@ -22,16 +23,21 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/diff"
 	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/target"
 )
 
 type AllocatorProvider func(log logr.Logger, opts ...Option) Allocator
 
 var (
-	strategies = map[string]Strategy{
-		leastWeightedStrategyName:     newleastWeightedStrategy(),
-		consistentHashingStrategyName: newConsistentHashingStrategy(),
-		perNodeStrategyName:           newPerNodeStrategy(),
+	// strategies holds the constructor of each strategy, as every allocator needs its own strategy to keep its
+	// state separate from the other allocators.
+	strategies = map[string]func() Strategy{
+		leastWeightedStrategyName:     newleastWeightedStrategy,
+		consistentHashingStrategyName: newConsistentHashingStrategy,
+		perNodeStrategyName:           newPerNodeStrategy,
+		costWeightedStrategyName:      newCostWeightedStrategy,
+		zoneAwareStrategyName:         newZoneAwareStrategy,
 	}
 
 	// TargetsPerCollector records how many targets have been assigned to each collector.
@@ -39,25 +45,46 @@ var (
 	TargetsPerCollector = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "opentelemetry_allocator_targets_per_collector",
 		Help: "The number of targets for each collector.",
-	}, []string{"collector_name", "strategy"})
+	}, []string{"collector_name", "strategy", "pool"})
+	// CostPerCollector records the total cost of the targets assigned to each collector, as reported by the collectors.
+	CostPerCollector = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "opentelemetry_allocator_cost_per_collector",
+		Help: "The total cost of the targets for each collector.",
+	}, []string{"collector_name", "strategy", "pool"})
+	TargetsRebalanced = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "opentelemetry_allocator_targets_rebalanced",
+		Help: "Number of targets moved to another collector to even out the load.",
+	}, []string{"strategy", "pool"})
+	// TargetsCrossZone records how many targets are assigned to a collector outside the zone of their Node.
+	TargetsCrossZone = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "opentelemetry_allocator_targets_cross_zone",
+		Help: "Number of targets assigned to a collector in another zone than their node.",
+	}, []string{"strategy", "pool"})
 	CollectorsAllocatable = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "opentelemetry_allocator_collectors_allocatable",
 		Help: "Number of collectors the allocator is able to allocate to.",
-	}, []string{"strategy"})
+	}, []string{"strategy", "pool"})
 	TimeToAssign = promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Name: "opentelemetry_allocator_time_to_allocate",
 		Help: "The time it takes to allocate",
-	}, []string{"method", "strategy"})
-	TargetsRemaining = promauto.NewGauge(prometheus.GaugeOpts{
+	}, []string{"method", "strategy", "pool"})
+	TargetsRemaining = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "opentelemetry_allocator_targets_remaining",
 		Help: "Number of targets kept after filtering.",
-	})
-	TargetsUnassigned = promauto.NewGauge(prometheus.GaugeOpts{
+	}, []string{"pool"})
+	TargetsUnassigned = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "opentelemetry_allocator_targets_unassigned",
 		Help: "Number of targets that could not be assigned due to missing node label.",
-	})
+	}, []string{"pool"})
+	// TargetsOverCapacity records how many targets exceed the total capacity of the collectors.
+	TargetsOverCapacity = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "opentelemetry_allocator_targets_over_capacity",
+		Help: "Number of targets exceeding the total capacity of the collectors.",
+	}, []string{"strategy", "pool"})
 )
 
+var errCollectorsAtCapacity = errors.New("all collectors are at capacity")
+
 type Option func(Allocator)
 
 type Filter interface {
@@ -71,26 +98,48 @@ func WithFilter(filter Filter) Option {
 }
 
 func WithFallbackStrategy(fallbackStrategy string) Option {
-	var strategy, ok = strategies[fallbackStrategy]
+	var newStrategy, ok = strategies[fallbackStrategy]
 	if fallbackStrategy != "" && !ok {
 		panic(fmt.Errorf("unregistered strategy used as fallback: %s", fallbackStrategy))
 	}
 	return func(allocator Allocator) {
-		allocator.SetFallbackStrategy(strategy)
+		if newStrategy == nil {
+			allocator.SetFallbackStrategy(nil)
+			return
+		}
+		allocator.SetFallbackStrategy(newStrategy())
+	}
+}
+
+// WithPool sets the name of the pool of collectors the allocator allocates to, which labels its metrics. Allocators
+// without a pool are labelled with an empty pool.
+func WithPool(pool string) Option {
+	return func(allocator Allocator) {
+		allocator.SetPool(pool)
 	}
 }
 
-func RecordTargetsKept(targets map[string]*target.Item) {
-	TargetsRemaining.Set(float64(len(targets)))
+func RecordTargetsKept(pool string, targets map[string]*target.Item) {
+	TargetsRemaining.WithLabelValues(pool).Set(float64(len(targets)))
 }
 
 func New(name string, log logr.Logger, opts ...Option) (Allocator, error) {
-	if strategy, ok := strategies[name]; ok {
-		return newAllocator(log.WithValues("allocator", name), strategy, opts...), nil
+	if newStrategy, ok := strategies[name]; ok {
+		return newAllocator(log.WithValues("allocator", name), newStrategy(), opts...), nil
 	}
 	return nil, fmt.Errorf("unregistered strategy: %s", name)
 }
 
+// UsesTopology reports whether the named strategy needs the topology of the Nodes to allocate targets.
+func UsesTopology(name string) bool {
+	newStrategy, ok := strategies[name]
+	if !ok {
+		return false
+	}
+	_, ok = newStrategy().(topologyAware)
+	return ok
+}
+
 func GetRegisteredAllocatorNames() []string {
 	var names []string
 	for s := range strategies {
@@ -103,12 +152,37 @@ type Allocator interface {
 	SetCollectors(collectors map[string]*Collector)
 	SetTargets(targets map[string]*target.Item)
 	TargetItems() map[string]*target.Item
+	// TargetItemsForCollector returns the target items assigned to the collector, keyed by target hash.
+	TargetItemsForCollector(collector string) map[string]*target.Item
 	Collectors() map[string]*Collector
 	GetTargetsForCollectorAndJob(collector string, job string) []*target.Item
 	SetFilter(filter Filter)
 	SetFallbackStrategy(strategy Strategy)
+	// SetPool sets the name of the pool of collectors the allocator allocates to, which labels its metrics.
+	SetPool(pool string)
+	// SetTargetCosts records the observed cost of targets. Costs of targets the allocator doesn't know are ignored.
+	SetTargetCosts(costs []TargetCost)
+	// SetTopology sets the topology of the Nodes, keyed by Node name.
+	SetTopology(nodes map[string]Topology)
+	// SetTargetChangesHandler sets the function called with the targets assigned to and unassigned from collectors
+	// whenever an update of the allocator changes them. It is called in the order of the updates. Nil changes mean that
+	// the assignments were replaced altogether, e.g. by those of another allocator, so that they have to be fetched again.
+	SetTargetChangesHandler(handler func(changes CollectorChanges))
+	// TargetsOverCapacity returns how many targets exceed the total capacity of the collectors, which is only limited
+	// if every collector has a capacity.
+	TargetsOverCapacity() int
+	// Decisions returns the latest allocation decisions, oldest first.
+	Decisions() []Decision
+	// LastDecision returns the decision which assigned the target to its current collector, if any.
+	LastDecision(targetHash string) (Decision, bool)
+	// TargetItem returns the target item, along with its current collector, if it's known.
+	TargetItem(targetHash string) (*target.Item, bool)
 }
 
+// CollectorChanges are the targets assigned to, as additions, and unassigned from, as removals, each collector by an
+// update of the allocator, keyed by collector name and then target hash.
+type CollectorChanges map[string]diff.Changes[*target.Item]
+
 type Strategy interface {
 	GetCollectorForTarget(map[string]*Collector, *target.Item) (*Collector, error)
 	// SetCollectors exists for strategies where changing the collector set is potentially an expensive operation.
@@ -120,6 +194,33 @@ type Strategy interface {
 	SetFallbackStrategy(Strategy)
 }
 
+// rebalancer is implemented by strategies which move already assigned targets to even out the load of the
+// collectors. The allocator calls it whenever the targets, the collectors or the target costs change.
+type rebalancer interface {
+	// Rebalance returns the targets to move as a map from target hash to the name of their new collector.
+	Rebalance(collectors map[string]*Collector, targets map[string]*target.Item, cost func(*target.Item) float64) map[string]string
+}
+
+// topologyAware is implemented by strategies which take the topology of the Nodes into account. The allocator passes
+// the topology before the collectors, whose zone and region it fills in.
+type topologyAware interface {
+	SetTopology(nodes map[string]Topology)
+}
+
+// Topology is the location of a Node, read from its well-known topology labels.
+type Topology struct {
+	Zone   string
+	Region string
+}
+
+// TargetCost is the cost of scraping a target as reported by the collector scraping it, such as the number of
+// series it exposes. All targets of the job with the given URL get the cost.
+type TargetCost struct {
+	JobName   string  `json:"job_name"`
+	TargetURL string  `json:"target"`
+	Cost      float64 `json:"cost"`
+}
+
 var _ consistent.Member = Collector{}
 
 // Collector Creates a struct that holds Collector information.
@@ -129,6 +230,15 @@ type Collector struct {
 	Name       string
 	NodeName   string
 	NumTargets int
+	Cost       float64
+	Zone       string
+	Region     string
+	// Weight is the share of the targets the collector takes relative to the others, 1 if unset. Only some strategies
+	// take it into account.
+	Weight int
+	// Capacity is the maximum number of targets the collector takes, unlimited if unset. Only some strategies take it
+	// into account.
+	Capacity int
 }
 
 func (c Collector) Hash() string {
@@ -139,6 +249,26 @@ func (c Collector) String() string {
 	return c.Name
 }
 
+// weight returns the weight of the collector, which defaults to 1.
+func (c Collector) weight() int {
+	if c.Weight < 1 {
+		return 1
+	}
+	return c.Weight
+}
+
+// hasRoomFor reports whether the collector can take the target without exceeding its capacity, the target counting
+// already if it's assigned to the collector.
+func (c Collector) hasRoomFor(item *target.Item) bool {
+	if c.Capacity <= 0 {
+		return true
+	}
+	if item.CollectorName == c.Name {
+		return c.NumTargets <= c.Capacity
+	}
+	return c.NumTargets < c.Capacity
+}
+
 func NewCollector(name, node string) *Collector {
 	return &Collector{Name: name, NodeName: node}
 }
@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package allocation
+
+import (
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/target"
+)
+
+const zoneAwareStrategyName = "zone-aware"
+
+var _ Strategy = &zoneAwareStrategy{}
+var _ topologyAware = &zoneAwareStrategy{}
+var _ fallbackReporter = &zoneAwareStrategy{}
+
+// zoneAwareStrategy assigns targets to a collector in the zone of the Node they reside on, using consistent hashing
+// between the collectors of that zone. Targets without a Node, on a Node of unknown zone or in a zone without any
+// collector are consistently hashed across all collectors.
+type zoneAwareStrategy struct {
+	nodeZones map[string]string
+	zones     map[string]Strategy
+	allZones  Strategy
+}
+
+func newZoneAwareStrategy() Strategy {
+	return &zoneAwareStrategy{
+		nodeZones: make(map[string]string),
+		zones:     make(map[string]Strategy),
+		allZones:  newConsistentHashingStrategy(),
+	}
+}
+
+func (s *zoneAwareStrategy) GetName() string {
+	return zoneAwareStrategyName
+}
+
+func (s *zoneAwareStrategy) GetCollectorForTarget(collectors map[string]*Collector, item *target.Item) (*Collector, error) {
+	if zone, ok := s.zones[s.nodeZones[item.GetNodeName()]]; ok {
+		return zone.GetCollectorForTarget(collectors, item)
+	}
+	return s.allZones.GetCollectorForTarget(collectors, item)
+}
+
+// UsesFallback reports whether the target is hashed across all collectors, not having any in its zone.
+func (s *zoneAwareStrategy) UsesFallback(item *target.Item) bool {
+	_, ok := s.zones[s.nodeZones[item.GetNodeName()]]
+	return !ok
+}
+
+func (s *zoneAwareStrategy) SetCollectors(collectors map[string]*Collector) {
+	collectorsByZone := map[string]map[string]*Collector{}
+	for name, collector := range collectors {
+		if collector.Zone == "" {
+			continue
+		}
+		if collectorsByZone[collector.Zone] == nil {
+			collectorsByZone[collector.Zone] = map[string]*Collector{}
+		}
+		collectorsByZone[collector.Zone][name] = collector
+	}
+
+	clear(s.zones)
+	for zone, zoneCollectors := range collectorsByZone {
+		s.zones[zone] = newConsistentHashingStrategy()
+		s.zones[zone].SetCollectors(zoneCollectors)
+	}
+	s.allZones.SetCollectors(collectors)
+}
+
+func (s *zoneAwareStrategy) SetTopology(nodes map[string]Topology) {
+	clear(s.nodeZones)
+	for node, topology := range nodes {
+		if topology.Zone != "" {
+			s.nodeZones[node] = topology.Zone
+		}
+	}
+}
+
+func (s *zoneAwareStrategy) SetFallbackStrategy(_ Strategy) {}
@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package allocation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/target"
+)
+
+var testTopology = map[string]Topology{
+	"node-0": {Zone: "zone-a", Region: "region-1"},
+	"node-1": {Zone: "zone-a", Region: "region-1"},
+	"node-2": {Zone: "zone-b", Region: "region-1"},
+	"node-3": {Zone: "zone-c", Region: "region-1"},
+}
+
+func makeTargetsOnNode(n int, node string) map[string]*target.Item {
+	targets := map[string]*target.Item{}
+	for i := 0; i < n; i++ {
+		lbls := labels.Labels{{Name: "__meta_kubernetes_pod_node_name", Value: node}}
+		if node == "" {
+			lbls = labels.Labels{{Name: "i", Value: fmt.Sprint(i)}}
+		}
+		item := target.NewItem("test-job", fmt.Sprintf("%s-url-%d", node, i), lbls, "")
+		targets[item.Hash()] = item
+	}
+	return targets
+}
+
+func TestZoneAwareAllocation(t *testing.T) {
+	s, err := New(zoneAwareStrategyName, logger)
+	require.NoError(t, err)
+
+	// collectors run on node-0 and node-1 in zone-a and node-2 in zone-b, none in zone-c
+	s.SetCollectors(MakeNCollectors(3, 0))
+	s.SetTopology(testTopology)
+
+	for _, col := range s.Collectors() {
+		assert.Equal(t, testTopology[col.NodeName].Zone, col.Zone)
+		assert.Equal(t, "region-1", col.Region)
+	}
+
+	zoneA := makeTargetsOnNode(10, "node-1")
+	zoneB := makeTargetsOnNode(10, "node-2")
+	zoneC := makeTargetsOnNode(10, "node-3")
+	withoutNode := makeTargetsOnNode(10, "")
+	targets := map[string]*target.Item{}
+	for _, group := range []map[string]*target.Item{zoneA, zoneB, zoneC, withoutNode} {
+		for hash, item := range group {
+			targets[hash] = item
+		}
+	}
+	s.SetTargets(targets)
+
+	collectors := s.Collectors()
+	items := s.TargetItems()
+	for hash := range zoneA {
+		assert.Equal(t, "zone-a", collectors[items[hash].CollectorName].Zone)
+	}
+	for hash := range zoneB {
+		assert.Equal(t, "collector-2", items[hash].CollectorName)
+	}
+	for hash := range zoneC {
+		assert.Contains(t, collectors, items[hash].CollectorName)
+	}
+	for hash := range withoutNode {
+		assert.Contains(t, collectors, items[hash].CollectorName)
+	}
+	assert.Equal(t, float64(len(zoneC)), testutil.ToFloat64(TargetsCrossZone.WithLabelValues(zoneAwareStrategyName, "")))
+}
+
+func TestZoneAwareAllocationTopologyChange(t *testing.T) {
+	s, err := New(zoneAwareStrategyName, logger)
+	require.NoError(t, err)
+
+	s.SetCollectors(MakeNCollectors(3, 0))
+	targets := makeTargetsOnNode(10, "node-2")
+	s.SetTargets(targets)
+
+	// once the topology is known, the targets move to the collector of their zone
+	s.SetTopology(testTopology)
+	for _, item := range s.TargetItems() {
+		assert.Equal(t, "collector-2", item.CollectorName)
+	}
+	assert.Equal(t, float64(0), testutil.ToFloat64(TargetsCrossZone.WithLabelValues(zoneAwareStrategyName, "")))
+
+	// the only collector of zone-b goes away
+	cols := MakeNCollectors(2, 0)
+	s.SetCollectors(cols)
+	for _, item := range s.TargetItems() {
+		assert.Contains(t, cols, item.CollectorName)
+	}
+	assert.Equal(t, float64(len(targets)), testutil.ToFloat64(TargetsCrossZone.WithLabelValues(zoneAwareStrategyName, "")))
+}
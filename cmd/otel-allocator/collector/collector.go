@@ -15,7 +15,9 @@
 package collector
 
 import (
+	"maps"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -33,14 +35,19 @@ import (
 
 const (
 	defaultMinUpdateInterval = time.Second * 5
+
+	// WeightAnnotation sets the weight of a collector Pod, the share of the targets it takes relative to the others.
+	WeightAnnotation = "targetallocator.opentelemetry.io/weight"
+	// CapacityAnnotation sets the maximum number of targets a collector Pod takes.
+	CapacityAnnotation = "targetallocator.opentelemetry.io/capacity"
 )
 
 var (
 	ns                   = os.Getenv("OTELCOL_NAMESPACE")
-	collectorsDiscovered = promauto.NewGauge(prometheus.GaugeOpts{
+	collectorsDiscovered = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "opentelemetry_allocator_collectors_discovered",
 		Help: "Number of collectors discovered.",
-	})
+	}, []string{"pool"})
 )
 
 type Watcher struct {
@@ -48,9 +55,11 @@ type Watcher struct {
 	k8sClient         kubernetes.Interface
 	close             chan struct{}
 	minUpdateInterval time.Duration
+	// pool is the name of the pool of the watched collectors, which labels the metrics
+	pool string
 }
 
-func NewCollectorWatcher(logger logr.Logger, kubeConfig *rest.Config) (*Watcher, error) {
+func NewCollectorWatcher(logger logr.Logger, kubeConfig *rest.Config, pool string) (*Watcher, error) {
 	clientset, err := kubernetes.NewForConfig(kubeConfig)
 	if err != nil {
 		return &Watcher{}, err
@@ -61,6 +70,7 @@ func NewCollectorWatcher(logger logr.Logger, kubeConfig *rest.Config) (*Watcher,
 		k8sClient:         clientset,
 		close:             make(chan struct{}),
 		minUpdateInterval: defaultMinUpdateInterval,
+		pool:              pool,
 	}, nil
 }
 
@@ -83,30 +93,84 @@ func (k *Watcher) Watch(labelSelector *metav1.LabelSelector, fn func(collectors
 	notify := make(chan struct{}, 1)
 	go k.rateLimitedCollectorHandler(notify, informer.GetStore(), fn)
 
+	_, err = informer.AddEventHandler(notifyingHandler(notify))
+	if err != nil {
+		return err
+	}
+
+	informer.Run(k.close)
+	return nil
+}
+
+// WatchTopology watches the Nodes of the cluster and runs fn with the topology of every Node whenever it changes.
+func (k *Watcher) WatchTopology(fn func(nodes map[string]allocation.Topology)) error {
+	informerFactory := informers.NewSharedInformerFactory(k.k8sClient, time.Second*30)
+	informer := informerFactory.Core().V1().Nodes().Informer()
+	// only the labels are needed, there is no point in keeping the status of every Node in memory
+	err := informer.SetTransform(func(obj interface{}) (interface{}, error) {
+		node, ok := obj.(*v1.Node)
+		if !ok {
+			return obj, nil
+		}
+		return &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: node.Name, Labels: node.Labels, ResourceVersion: node.ResourceVersion}}, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	notify := make(chan struct{}, 1)
+	go k.rateLimitedTopologyHandler(notify, informer.GetStore(), fn)
+
+	_, err = informer.AddEventHandler(notifyingHandler(notify))
+	if err != nil {
+		return err
+	}
+
+	informer.Run(k.close)
+	return nil
+}
+
+// notifyingHandler sends a notification on the notify channel for every event, unless one is already pending.
+func notifyingHandler(notify chan struct{}) cache.ResourceEventHandlerFuncs {
 	notifyFunc := func(_ interface{}) {
 		select {
 		case notify <- struct{}{}:
 		default:
 		}
 	}
-	_, err = informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+	return cache.ResourceEventHandlerFuncs{
 		AddFunc: notifyFunc,
 		UpdateFunc: func(oldObj, newObj interface{}) {
 			notifyFunc(newObj)
 		},
 		DeleteFunc: notifyFunc,
-	})
-	if err != nil {
-		return err
 	}
-
-	informer.Run(k.close)
-	return nil
 }
 
 // rateLimitedCollectorHandler runs fn on collectors present in the store whenever it gets a notification on the notify channel,
 // but not more frequently than once per k.eventPeriod.
 func (k *Watcher) rateLimitedCollectorHandler(notify chan struct{}, store cache.Store, fn func(collectors map[string]*allocation.Collector)) {
+	k.rateLimitedHandler(notify, func() {
+		k.runOnCollectors(store, fn)
+	})
+}
+
+// rateLimitedTopologyHandler runs fn on the topology of the Nodes present in the store whenever it gets a notification
+// on the notify channel and the topology changed, but not more frequently than once per k.eventPeriod.
+func (k *Watcher) rateLimitedTopologyHandler(notify chan struct{}, store cache.Store, fn func(nodes map[string]allocation.Topology)) {
+	var current map[string]allocation.Topology
+	k.rateLimitedHandler(notify, func() {
+		nodes := topologyOf(store)
+		// Node objects change all the time, their topology labels almost never do
+		if current != nil && maps.Equal(current, nodes) {
+			return
+		}
+		current = nodes
+		fn(nodes)
+	})
+}
+
+func (k *Watcher) rateLimitedHandler(notify chan struct{}, run func()) {
 	ticker := time.NewTicker(k.minUpdateInterval)
 	defer ticker.Stop()
 
@@ -117,7 +181,7 @@ func (k *Watcher) rateLimitedCollectorHandler(notify chan struct{}, store cache.
 		case <-ticker.C: // throttle events to avoid excessive updates
 			select {
 			case <-notify:
-				k.runOnCollectors(store, fn)
+				run()
 			default:
 			}
 		}
@@ -133,12 +197,43 @@ func (k *Watcher) runOnCollectors(store cache.Store, fn func(collectors map[stri
 		if pod.Spec.NodeName == "" {
 			continue
 		}
-		collectorMap[pod.Name] = allocation.NewCollector(pod.Name, pod.Spec.NodeName)
+		collector := allocation.NewCollector(pod.Name, pod.Spec.NodeName)
+		collector.Weight = k.annotationValue(pod, WeightAnnotation)
+		collector.Capacity = k.annotationValue(pod, CapacityAnnotation)
+		collectorMap[pod.Name] = collector
 	}
-	collectorsDiscovered.Set(float64(len(collectorMap)))
+	collectorsDiscovered.WithLabelValues(k.pool).Set(float64(len(collectorMap)))
 	fn(collectorMap)
 }
 
+// annotationValue returns the positive integer value of the annotation of the Pod, or 0 if it isn't set or invalid.
+func (k *Watcher) annotationValue(pod *v1.Pod, annotation string) int {
+	value, ok := pod.Annotations[annotation]
+	if !ok {
+		return 0
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		k.log.Info("Ignoring invalid annotation of collector, it should be a positive integer", "pod", pod.Name, "annotation", annotation, "value", value)
+		return 0
+	}
+	return n
+}
+
+// topologyOf returns the topology of the Nodes from the Store.
+func topologyOf(store cache.Store) map[string]allocation.Topology {
+	objects := store.List()
+	nodes := make(map[string]allocation.Topology, len(objects))
+	for _, obj := range objects {
+		node := obj.(*v1.Node)
+		nodes[node.Name] = allocation.Topology{
+			Zone:   node.Labels[v1.LabelTopologyZone],
+			Region: node.Labels[v1.LabelTopologyRegion],
+		}
+	}
+	return nodes
+}
+
 func (k *Watcher) Close() {
 	close(k.close)
 }
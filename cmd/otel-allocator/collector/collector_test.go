@@ -131,6 +131,36 @@ func Test_runWatch(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "pod annotations",
+			args: args{
+				kubeFn: func(t *testing.T, podWatcher Watcher) {
+					annotations := map[string]map[string]string{
+						"test-pod1": {WeightAnnotation: "3", CapacityAnnotation: "100"},
+						"test-pod2": {WeightAnnotation: "-1", CapacityAnnotation: "lots"},
+					}
+					for k, a := range annotations {
+						p := pod(k)
+						p.Annotations = a
+						_, err := podWatcher.k8sClient.CoreV1().Pods("test-ns").Create(context.Background(), p, metav1.CreateOptions{})
+						assert.NoError(t, err)
+					}
+				},
+				collectorMap: map[string]*allocation.Collector{},
+			},
+			want: map[string]*allocation.Collector{
+				"test-pod1": {
+					Name:     "test-pod1",
+					NodeName: "test-node",
+					Weight:   3,
+					Capacity: 100,
+				},
+				"test-pod2": {
+					Name:     "test-pod2",
+					NodeName: "test-node",
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -161,7 +191,7 @@ func Test_runWatch(t *testing.T) {
 				defer mapMutex.Unlock()
 				assert.Len(collect, actual, len(tt.want))
 				assert.Equal(collect, actual, tt.want)
-				assert.Equal(collect, testutil.ToFloat64(collectorsDiscovered), float64(len(actual)))
+				assert.Equal(collect, testutil.ToFloat64(collectorsDiscovered.WithLabelValues("")), float64(len(actual)))
 			}, time.Second*3, time.Millisecond)
 		})
 	}
@@ -183,3 +213,66 @@ func Test_closeChannel(t *testing.T) {
 	podWatcher.Close()
 	wg.Wait()
 }
+
+func Test_watchTopology(t *testing.T) {
+	nodeWatcher := getTestPodWatcher()
+	defer close(nodeWatcher.close)
+
+	for name, zone := range map[string]string{"node-a": "zone-a", "node-b": "zone-b"} {
+		node := &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					v1.LabelTopologyZone:   zone,
+					v1.LabelTopologyRegion: "region-1",
+				},
+			},
+		}
+		_, err := nodeWatcher.k8sClient.CoreV1().Nodes().Create(context.Background(), node, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	var actual map[string]allocation.Topology
+	calls := 0
+	mapMutex := sync.Mutex{}
+	go func(nodeWatcher Watcher) {
+		err := nodeWatcher.WatchTopology(func(nodes map[string]allocation.Topology) {
+			mapMutex.Lock()
+			defer mapMutex.Unlock()
+			actual = nodes
+			calls++
+		})
+		require.NoError(t, err)
+	}(nodeWatcher)
+
+	want := map[string]allocation.Topology{
+		"node-a": {Zone: "zone-a", Region: "region-1"},
+		"node-b": {Zone: "zone-b", Region: "region-1"},
+	}
+	assert.EventuallyWithT(t, func(collect *assert.CollectT) {
+		mapMutex.Lock()
+		defer mapMutex.Unlock()
+		assert.Equal(collect, want, actual)
+	}, time.Second*3, time.Millisecond)
+
+	// updates that don't touch the topology labels don't trigger fn
+	mapMutex.Lock()
+	callsBefore := calls
+	mapMutex.Unlock()
+	node, err := nodeWatcher.k8sClient.CoreV1().Nodes().Get(context.Background(), "node-a", metav1.GetOptions{})
+	require.NoError(t, err)
+	node.Labels["other"] = "label"
+	_, err = nodeWatcher.k8sClient.CoreV1().Nodes().Update(context.Background(), node, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	node.Labels[v1.LabelTopologyZone] = "zone-c"
+	want["node-a"] = allocation.Topology{Zone: "zone-c", Region: "region-1"}
+	_, err = nodeWatcher.k8sClient.CoreV1().Nodes().Update(context.Background(), node, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	assert.EventuallyWithT(t, func(collect *assert.CollectT) {
+		mapMutex.Lock()
+		defer mapMutex.Unlock()
+		assert.Equal(collect, want, actual)
+		assert.Equal(collect, callsBefore+1, calls)
+	}, time.Second*3, time.Millisecond)
+}
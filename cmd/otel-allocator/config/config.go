@@ -20,10 +20,13 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"net/url"
 	"os"
+	"regexp"
 	"time"
 
 	"github.com/go-logr/logr"
+	promcommconfig "github.com/prometheus/common/config"
 	"github.com/prometheus/common/model"
 	promconfig "github.com/prometheus/prometheus/config"
 	_ "github.com/prometheus/prometheus/discovery/install"
@@ -43,6 +46,11 @@ const (
 	DefaultCRScrapeInterval   model.Duration = model.Duration(time.Second * 30)
 	DefaultAllocationStrategy                = "consistent-hashing"
 	DefaultFilterStrategy                    = "relabel-config"
+	DefaultLeaseDuration                     = 15 * time.Second
+	DefaultRenewDeadline                     = 10 * time.Second
+	DefaultRetryPeriod                       = 2 * time.Second
+	DefaultSyncInterval                      = 5 * time.Second
+	DefaultRefreshInterval                   = time.Minute
 )
 
 type Config struct {
@@ -55,10 +63,28 @@ type Config struct {
 	AllocationStrategy         string                `yaml:"allocation_strategy,omitempty"`
 	AllocationFallbackStrategy string                `yaml:"allocation_fallback_strategy,omitempty"`
 	FilterStrategy             string                `yaml:"filter_strategy,omitempty"`
+	Filters                    FilterConfig          `yaml:"filters,omitempty"`
 	PrometheusCR               PrometheusCRConfig    `yaml:"prometheus_cr,omitempty"`
+	HTTPScrapeConfigs          HTTPScrapeConfigs     `yaml:"http_scrape_configs,omitempty"`
 	HTTPS                      HTTPSServerConfig     `yaml:"https,omitempty"`
+	LeaderElection             LeaderElectionConfig  `yaml:"leader_election,omitempty"`
+	// Pools split the collectors into isolated pools, each allocating its own scrape configs with its own strategy.
+	// When set, scrape configs are only defined per pool.
+	Pools []PoolConfig `yaml:"pools,omitempty"`
 }
 
+// PoolConfig configures a pool of collectors and the scrape configs allocated to them.
+type PoolConfig struct {
+	Name                       string                `yaml:"name"`
+	CollectorSelector          *metav1.LabelSelector `yaml:"collector_selector,omitempty"`
+	PromConfig                 *promconfig.Config    `yaml:"config,omitempty"`
+	AllocationStrategy         string                `yaml:"allocation_strategy,omitempty"`
+	AllocationFallbackStrategy string                `yaml:"allocation_fallback_strategy,omitempty"`
+	PrometheusCR               PrometheusCRConfig    `yaml:"prometheus_cr,omitempty"`
+}
+
+var poolNameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
 type PrometheusCRConfig struct {
 	Enabled                         bool                  `yaml:"enabled,omitempty"`
 	PodMonitorSelector              *metav1.LabelSelector `yaml:"pod_monitor_selector,omitempty"`
@@ -72,6 +98,25 @@ type PrometheusCRConfig struct {
 	ScrapeInterval                  model.Duration        `yaml:"scrape_interval,omitempty"`
 }
 
+// HTTPScrapeConfigs configures fetching scrape configs from an HTTP endpoint, which serves them in the format of
+// Prometheus scrape config files.
+type HTTPScrapeConfigs struct {
+	URL             string        `yaml:"url,omitempty"`
+	RefreshInterval time.Duration `yaml:"refresh_interval,omitempty"`
+	// HTTPClientConfig configures the authentication and TLS of the requests.
+	HTTPClientConfig promcommconfig.HTTPClientConfig `yaml:",inline"`
+}
+
+// FilterConfig configures the filters chained by the filter strategy.
+type FilterConfig struct {
+	// MaxTargetsPerJob is the number of targets of each job kept by the target-limit filter.
+	MaxTargetsPerJob int `yaml:"max_targets_per_job,omitempty"`
+	// AllowedNamespaces are the only namespaces whose targets the namespace filter keeps, if any.
+	AllowedNamespaces []string `yaml:"allowed_namespaces,omitempty"`
+	// DeniedNamespaces are the namespaces whose targets the namespace filter drops.
+	DeniedNamespaces []string `yaml:"denied_namespaces,omitempty"`
+}
+
 type HTTPSServerConfig struct {
 	Enabled         bool   `yaml:"enabled,omitempty"`
 	ListenAddr      string `yaml:"listen_addr,omitempty"`
@@ -80,6 +125,22 @@ type HTTPSServerConfig struct {
 	TLSKeyFilePath  string `yaml:"tls_key_file_path,omitempty"`
 }
 
+// LeaderElectionConfig configures running several replicas of the target allocator, where the replica holding the
+// Lease allocates targets and the others serve its assignments.
+type LeaderElectionConfig struct {
+	Enabled        bool   `yaml:"enabled,omitempty"`
+	LeaseName      string `yaml:"lease_name,omitempty"`
+	LeaseNamespace string `yaml:"lease_namespace,omitempty"`
+	// AdvertiseAddr is the address the other replicas reach this one at, defaults to the Pod IP and the port of
+	// ListenAddr.
+	AdvertiseAddr string        `yaml:"advertise_addr,omitempty"`
+	LeaseDuration time.Duration `yaml:"lease_duration,omitempty"`
+	RenewDeadline time.Duration `yaml:"renew_deadline,omitempty"`
+	RetryPeriod   time.Duration `yaml:"retry_period,omitempty"`
+	// SyncInterval is how often followers fetch the assignments of the leader.
+	SyncInterval time.Duration `yaml:"sync_interval,omitempty"`
+}
+
 func LoadFromFile(file string, target *Config) error {
 	return unmarshal(target, file)
 }
@@ -172,6 +233,16 @@ func CreateDefaultConfig() Config {
 		PrometheusCR: PrometheusCRConfig{
 			ScrapeInterval: DefaultCRScrapeInterval,
 		},
+		HTTPScrapeConfigs: HTTPScrapeConfigs{
+			RefreshInterval:  DefaultRefreshInterval,
+			HTTPClientConfig: promcommconfig.DefaultHTTPClientConfig,
+		},
+		LeaderElection: LeaderElectionConfig{
+			LeaseDuration: DefaultLeaseDuration,
+			RenewDeadline: DefaultRenewDeadline,
+			RetryPeriod:   DefaultRetryPeriod,
+			SyncInterval:  DefaultSyncInterval,
+		},
 	}
 }
 
@@ -204,15 +275,95 @@ func Load() (*Config, error) {
 	return &config, nil
 }
 
+// ForPool returns the config of a single pool, taking the settings the pool doesn't define from c.
+func (c Config) ForPool(pool PoolConfig) Config {
+	c.CollectorSelector = pool.CollectorSelector
+	c.PromConfig = pool.PromConfig
+	if pool.AllocationStrategy != "" {
+		c.AllocationStrategy = pool.AllocationStrategy
+		c.AllocationFallbackStrategy = pool.AllocationFallbackStrategy
+	}
+	scrapeInterval := c.PrometheusCR.ScrapeInterval
+	c.PrometheusCR = pool.PrometheusCR
+	if c.PrometheusCR.ScrapeInterval == 0 {
+		c.PrometheusCR.ScrapeInterval = scrapeInterval
+	}
+	c.HTTPScrapeConfigs.URL = ""
+	c.Pools = nil
+	return c
+}
+
 // ValidateConfig validates the cli and file configs together.
 func ValidateConfig(config *Config) error {
-	scrapeConfigsPresent := (config.PromConfig != nil && len(config.PromConfig.ScrapeConfigs) > 0)
+	if len(config.Pools) > 0 {
+		return validatePools(config)
+	}
+	scrapeConfigsPresent := (config.PromConfig != nil && (len(config.PromConfig.ScrapeConfigs) > 0 || len(config.PromConfig.ScrapeConfigFiles) > 0)) ||
+		config.HTTPScrapeConfigs.URL != ""
 	if !(config.PrometheusCR.Enabled || scrapeConfigsPresent) {
 		return fmt.Errorf("at least one scrape config must be defined, or Prometheus CR watching must be enabled")
 	}
+	if config.HTTPScrapeConfigs.URL != "" {
+		if err := config.HTTPScrapeConfigs.validate(); err != nil {
+			return err
+		}
+	}
+	if config.Filters.MaxTargetsPerJob < 0 {
+		return fmt.Errorf("the maximum number of targets per job should not be negative")
+	}
+	if config.LeaderElection.Enabled {
+		if config.LeaderElection.LeaseName == "" {
+			return fmt.Errorf("a lease name must be defined when leader election is enabled")
+		}
+		if config.LeaderElection.RenewDeadline >= config.LeaderElection.LeaseDuration {
+			return fmt.Errorf("the leader election renew deadline must be shorter than the lease duration")
+		}
+	}
 	return nil
 }
 
+func validatePools(config *Config) error {
+	if config.PromConfig != nil && (len(config.PromConfig.ScrapeConfigs) > 0 || len(config.PromConfig.ScrapeConfigFiles) > 0) ||
+		config.HTTPScrapeConfigs.URL != "" || config.PrometheusCR.Enabled {
+		return fmt.Errorf("scrape configs must be defined per pool when pools are used")
+	}
+	if config.LeaderElection.Enabled {
+		return fmt.Errorf("leader election is not supported with pools")
+	}
+	names := map[string]struct{}{}
+	for _, pool := range config.Pools {
+		if !poolNameRegexp.MatchString(pool.Name) {
+			return fmt.Errorf("invalid pool name %q, it must consist of lower case alphanumeric characters or '-'", pool.Name)
+		}
+		if _, ok := names[pool.Name]; ok {
+			return fmt.Errorf("duplicate pool name %q", pool.Name)
+		}
+		names[pool.Name] = struct{}{}
+		if pool.CollectorSelector == nil {
+			return fmt.Errorf("pool %s: a collector selector must be defined", pool.Name)
+		}
+		poolConfig := config.ForPool(pool)
+		if err := ValidateConfig(&poolConfig); err != nil {
+			return fmt.Errorf("pool %s: %w", pool.Name, err)
+		}
+	}
+	return nil
+}
+
+func (c HTTPScrapeConfigs) validate() error {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return fmt.Errorf("invalid scrape configs URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("the scrape configs URL must be an http or https URL")
+	}
+	if c.RefreshInterval <= 0 {
+		return fmt.Errorf("the scrape configs refresh interval must be positive")
+	}
+	return c.HTTPClientConfig.Validate()
+}
+
 func (c HTTPSServerConfig) NewTLSConfig() (*tls.Config, error) {
 	cert, err := tls.LoadX509KeyPair(c.TLSCertFilePath, c.TLSKeyFilePath)
 	if err != nil {
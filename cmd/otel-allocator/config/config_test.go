@@ -25,6 +25,7 @@ import (
 	"github.com/prometheus/prometheus/discovery"
 	"github.com/prometheus/prometheus/discovery/file"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -62,6 +63,10 @@ func TestLoad(t *testing.T) {
 					Enabled:        true,
 					ScrapeInterval: model.Duration(time.Second * 60),
 				},
+				HTTPScrapeConfigs: HTTPScrapeConfigs{
+					RefreshInterval:  DefaultRefreshInterval,
+					HTTPClientConfig: commonconfig.DefaultHTTPClientConfig,
+				},
 				HTTPS: HTTPSServerConfig{
 					Enabled:         true,
 					ListenAddr:      ":8443",
@@ -69,6 +74,12 @@ func TestLoad(t *testing.T) {
 					TLSCertFilePath: "/path/to/cert.pem",
 					TLSKeyFilePath:  "/path/to/key.pem",
 				},
+				LeaderElection: LeaderElectionConfig{
+					LeaseDuration: DefaultLeaseDuration,
+					RenewDeadline: DefaultRenewDeadline,
+					RetryPeriod:   DefaultRetryPeriod,
+					SyncInterval:  DefaultSyncInterval,
+				},
 				PromConfig: &promconfig.Config{
 					GlobalConfig: promconfig.GlobalConfig{
 						ScrapeInterval:     model.Duration(60 * time.Second),
@@ -151,6 +162,16 @@ func TestLoad(t *testing.T) {
 					},
 					ScrapeInterval: DefaultCRScrapeInterval,
 				},
+				HTTPScrapeConfigs: HTTPScrapeConfigs{
+					RefreshInterval:  DefaultRefreshInterval,
+					HTTPClientConfig: commonconfig.DefaultHTTPClientConfig,
+				},
+				LeaderElection: LeaderElectionConfig{
+					LeaseDuration: DefaultLeaseDuration,
+					RenewDeadline: DefaultRenewDeadline,
+					RetryPeriod:   DefaultRetryPeriod,
+					SyncInterval:  DefaultSyncInterval,
+				},
 				PromConfig: &promconfig.Config{
 					GlobalConfig: promconfig.GlobalConfig{
 						ScrapeInterval:     model.Duration(60 * time.Second),
@@ -207,6 +228,72 @@ func TestLoad(t *testing.T) {
 	}
 }
 
+func TestLoadFilters(t *testing.T) {
+	cfg := CreateDefaultConfig()
+	require.NoError(t, LoadFromFile("./testdata/filters_test.yaml", &cfg))
+	require.NoError(t, ValidateConfig(&cfg))
+	assert.Equal(t, "relabel-config,namespace,target-limit", cfg.FilterStrategy)
+	assert.Equal(t, FilterConfig{
+		DeniedNamespaces: []string{"kube-system"},
+		MaxTargetsPerJob: 100,
+	}, cfg.Filters)
+}
+
+func TestLoadHTTPScrapeConfigs(t *testing.T) {
+	cfg := CreateDefaultConfig()
+	require.NoError(t, LoadFromFile("./testdata/http_scrape_configs_test.yaml", &cfg))
+	require.NoError(t, ValidateConfig(&cfg))
+	assert.Equal(t, HTTPScrapeConfigs{
+		URL:             "https://config-service/scrape_configs",
+		RefreshInterval: 30 * time.Second,
+		HTTPClientConfig: commonconfig.HTTPClientConfig{
+			Authorization: &commonconfig.Authorization{
+				Type:            "Bearer",
+				CredentialsFile: "/path/to/token",
+			},
+			FollowRedirects: true,
+			EnableHTTP2:     true,
+		},
+	}, cfg.HTTPScrapeConfigs)
+}
+
+func TestLoadLeaderElection(t *testing.T) {
+	cfg := CreateDefaultConfig()
+	require.NoError(t, LoadFromFile("./testdata/leader_election_test.yaml", &cfg))
+	require.NoError(t, ValidateConfig(&cfg))
+	assert.Equal(t, LeaderElectionConfig{
+		Enabled:       true,
+		LeaseName:     "test-targetallocator",
+		LeaseDuration: 30 * time.Second,
+		RenewDeadline: DefaultRenewDeadline,
+		RetryPeriod:   DefaultRetryPeriod,
+		SyncInterval:  DefaultSyncInterval,
+	}, cfg.LeaderElection)
+}
+
+func TestLoadPools(t *testing.T) {
+	cfg := CreateDefaultConfig()
+	require.NoError(t, LoadFromFile("./testdata/pools_test.yaml", &cfg))
+	require.NoError(t, ValidateConfig(&cfg))
+	require.Len(t, cfg.Pools, 2)
+
+	teamA := cfg.ForPool(cfg.Pools[0])
+	assert.Equal(t, "least-weighted", teamA.AllocationStrategy)
+	assert.Equal(t, map[string]string{"app.kubernetes.io/instance": "team-a.collector"}, teamA.CollectorSelector.MatchLabels)
+	assert.True(t, teamA.PrometheusCR.Enabled)
+	assert.Equal(t, map[string]string{"team": "a"}, teamA.PrometheusCR.ServiceMonitorNamespaceSelector.MatchLabels)
+	assert.Equal(t, model.Duration(time.Minute), teamA.PrometheusCR.ScrapeInterval)
+	assert.Nil(t, teamA.PromConfig)
+	assert.Empty(t, teamA.Pools)
+
+	teamB := cfg.ForPool(cfg.Pools[1])
+	assert.Equal(t, DefaultAllocationStrategy, teamB.AllocationStrategy)
+	assert.False(t, teamB.PrometheusCR.Enabled)
+	require.NotNil(t, teamB.PromConfig)
+	require.Len(t, teamB.PromConfig.ScrapeConfigs, 1)
+	assert.Equal(t, "prometheus", teamB.PromConfig.ScrapeConfigs[0].JobName)
+}
+
 func TestValidateConfig(t *testing.T) {
 	testCases := []struct {
 		name        string
@@ -243,6 +330,116 @@ func TestValidateConfig(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		{
+			name: "promCR disabled, scrape config files present",
+			fileConfig: Config{
+				PromConfig: &promconfig.Config{ScrapeConfigFiles: []string{"/conf/scrape_configs/*.yaml"}},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "promCR disabled, scrape configs URL present",
+			fileConfig: Config{
+				HTTPScrapeConfigs: HTTPScrapeConfigs{URL: "http://config-service/scrape_configs", RefreshInterval: DefaultRefreshInterval},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "scrape configs URL without a scheme",
+			fileConfig: Config{
+				HTTPScrapeConfigs: HTTPScrapeConfigs{URL: "config-service/scrape_configs", RefreshInterval: DefaultRefreshInterval},
+			},
+			expectedErr: fmt.Errorf("the scrape configs URL must be an http or https URL"),
+		},
+		{
+			name: "scrape configs URL without a refresh interval",
+			fileConfig: Config{
+				HTTPScrapeConfigs: HTTPScrapeConfigs{URL: "http://config-service/scrape_configs"},
+			},
+			expectedErr: fmt.Errorf("the scrape configs refresh interval must be positive"),
+		},
+		{
+			name: "leader election without a lease name",
+			fileConfig: Config{
+				PrometheusCR:   PrometheusCRConfig{Enabled: true},
+				LeaderElection: LeaderElectionConfig{Enabled: true, LeaseDuration: DefaultLeaseDuration, RenewDeadline: DefaultRenewDeadline},
+			},
+			expectedErr: fmt.Errorf("a lease name must be defined when leader election is enabled"),
+		},
+		{
+			name: "leader election with a renew deadline longer than the lease",
+			fileConfig: Config{
+				PrometheusCR:   PrometheusCRConfig{Enabled: true},
+				LeaderElection: LeaderElectionConfig{Enabled: true, LeaseName: "ta", LeaseDuration: DefaultRenewDeadline, RenewDeadline: DefaultLeaseDuration},
+			},
+			expectedErr: fmt.Errorf("the leader election renew deadline must be shorter than the lease duration"),
+		},
+		{
+			name: "negative target limit",
+			fileConfig: Config{
+				PrometheusCR: PrometheusCRConfig{Enabled: true},
+				Filters:      FilterConfig{MaxTargetsPerJob: -1},
+			},
+			expectedErr: fmt.Errorf("the maximum number of targets per job should not be negative"),
+		},
+		{
+			name: "pools",
+			fileConfig: Config{
+				Pools: []PoolConfig{
+					{Name: "team-a", CollectorSelector: &metav1.LabelSelector{}, PrometheusCR: PrometheusCRConfig{Enabled: true}},
+					{Name: "team-b", CollectorSelector: &metav1.LabelSelector{}, PrometheusCR: PrometheusCRConfig{Enabled: true}},
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "pools with top-level scrape configs",
+			fileConfig: Config{
+				PrometheusCR: PrometheusCRConfig{Enabled: true},
+				Pools:        []PoolConfig{{Name: "team-a", CollectorSelector: &metav1.LabelSelector{}, PrometheusCR: PrometheusCRConfig{Enabled: true}}},
+			},
+			expectedErr: fmt.Errorf("scrape configs must be defined per pool when pools are used"),
+		},
+		{
+			name: "pools with leader election",
+			fileConfig: Config{
+				LeaderElection: LeaderElectionConfig{Enabled: true, LeaseName: "ta", LeaseDuration: DefaultLeaseDuration, RenewDeadline: DefaultRenewDeadline},
+				Pools:          []PoolConfig{{Name: "team-a", CollectorSelector: &metav1.LabelSelector{}, PrometheusCR: PrometheusCRConfig{Enabled: true}}},
+			},
+			expectedErr: fmt.Errorf("leader election is not supported with pools"),
+		},
+		{
+			name: "pool with an invalid name",
+			fileConfig: Config{
+				Pools: []PoolConfig{{Name: "Team_A", CollectorSelector: &metav1.LabelSelector{}, PrometheusCR: PrometheusCRConfig{Enabled: true}}},
+			},
+			expectedErr: fmt.Errorf("invalid pool name \"Team_A\", it must consist of lower case alphanumeric characters or '-'"),
+		},
+		{
+			name: "pools with the same name",
+			fileConfig: Config{
+				Pools: []PoolConfig{
+					{Name: "team-a", CollectorSelector: &metav1.LabelSelector{}, PrometheusCR: PrometheusCRConfig{Enabled: true}},
+					{Name: "team-a", CollectorSelector: &metav1.LabelSelector{}, PrometheusCR: PrometheusCRConfig{Enabled: true}},
+				},
+			},
+			expectedErr: fmt.Errorf("duplicate pool name \"team-a\""),
+		},
+		{
+			name: "pool without a collector selector",
+			fileConfig: Config{
+				Pools: []PoolConfig{{Name: "team-a", PrometheusCR: PrometheusCRConfig{Enabled: true}}},
+			},
+			expectedErr: fmt.Errorf("pool team-a: a collector selector must be defined"),
+		},
+		{
+			name: "leader election",
+			fileConfig: Config{
+				PrometheusCR:   PrometheusCRConfig{Enabled: true},
+				LeaderElection: LeaderElectionConfig{Enabled: true, LeaseName: "ta", LeaseDuration: DefaultLeaseDuration, RenewDeadline: DefaultRenewDeadline},
+			},
+			expectedErr: nil,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,432 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leader
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/allocation"
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/diff"
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/target"
+)
+
+const (
+	// AssignmentsPath is where the leader serves its assignment table.
+	AssignmentsPath = "/assignments"
+	// targetCostsPath is where the leader accepts target costs, see the server package.
+	targetCostsPath = "/target_costs"
+)
+
+var (
+	syncFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "opentelemetry_allocator_assignment_sync_failures",
+		Help: "Number of times a follower failed to fetch the assignments of the leader.",
+	})
+)
+
+var _ allocation.Allocator = &Allocator{}
+
+// Leadership tells which replica of the target allocator allocates targets.
+type Leadership interface {
+	IsLeader() bool
+	Leader() string
+}
+
+// Table is the assignment table of the leader, which followers serve instead of their own. Versions are only
+// comparable within the same epoch, which changes whenever the allocator restarts.
+type Table struct {
+	Epoch      string           `json:"epoch"`
+	Version    uint64           `json:"version"`
+	Collectors []TableCollector `json:"collectors"`
+	Targets    []TableTarget    `json:"targets"`
+}
+
+type TableCollector struct {
+	Name     string `json:"name"`
+	NodeName string `json:"node_name,omitempty"`
+	Zone     string `json:"zone,omitempty"`
+	Region   string `json:"region,omitempty"`
+}
+
+type TableTarget struct {
+	JobName       string        `json:"job_name"`
+	TargetURL     string        `json:"target"`
+	Labels        labels.Labels `json:"labels"`
+	CollectorName string        `json:"collector"`
+}
+
+// Allocator wraps the allocator of a replica. Every replica keeps discovering and allocating targets so that a
+// follower can take over right away, but while following, the targets are served from the assignments of the leader.
+// This keeps all replicas giving the same answer, whatever the strategy.
+type Allocator struct {
+	allocation.Allocator
+
+	log          logr.Logger
+	leadership   Leadership
+	client       *http.Client
+	syncInterval time.Duration
+
+	// epoch identifies this process, whose version counter starts over on restart
+	epoch string
+	// version changes whenever the local allocation may have changed
+	version atomic.Uint64
+
+	// m protects replica and changesHandler for concurrent use. The changes are published while holding it, so that
+	// those of the local allocator aren't published while the assignments of the leader are served.
+	m sync.RWMutex
+	// replica holds the last assignments fetched from the leader, which are served while it's set
+	replica        *replica
+	changesHandler func(allocation.CollectorChanges)
+}
+
+type replica struct {
+	leader                        string
+	epoch                         string
+	version                       uint64
+	collectors                    map[string]*allocation.Collector
+	targetItems                   map[string]*target.Item
+	targetItemsPerJobPerCollector map[string]map[string][]*target.Item
+}
+
+func NewAllocator(log logr.Logger, local allocation.Allocator, leadership Leadership, syncInterval time.Duration) *Allocator {
+	return &Allocator{
+		Allocator:    local,
+		log:          log.WithValues("component", "replicated-allocator"),
+		epoch:        strconv.FormatInt(time.Now().UnixNano(), 36),
+		leadership:   leadership,
+		client:       &http.Client{Timeout: 30 * time.Second},
+		syncInterval: syncInterval,
+	}
+}
+
+func (a *Allocator) SetCollectors(collectors map[string]*allocation.Collector) {
+	a.Allocator.SetCollectors(collectors)
+	a.version.Add(1)
+}
+
+func (a *Allocator) SetTargets(targets map[string]*target.Item) {
+	a.Allocator.SetTargets(targets)
+	a.version.Add(1)
+}
+
+func (a *Allocator) SetTopology(nodes map[string]allocation.Topology) {
+	a.Allocator.SetTopology(nodes)
+	a.version.Add(1)
+}
+
+// SetTargetCosts records the costs locally and, while following, forwards them to the leader since collectors
+// report them to whichever replica the Service picks.
+func (a *Allocator) SetTargetCosts(costs []allocation.TargetCost) {
+	a.Allocator.SetTargetCosts(costs)
+	a.version.Add(1)
+	if leader := a.leadership.Leader(); !a.leadership.IsLeader() && leader != "" {
+		go a.forwardTargetCosts(leader, costs)
+	}
+}
+
+// SetTargetChangesHandler sets the function called with the changes of the served assignments: those of the local
+// allocator while they're served, and those between the assignments fetched from the leader otherwise.
+func (a *Allocator) SetTargetChangesHandler(handler func(changes allocation.CollectorChanges)) {
+	a.m.Lock()
+	a.changesHandler = handler
+	a.m.Unlock()
+	if handler == nil {
+		a.Allocator.SetTargetChangesHandler(nil)
+		return
+	}
+	a.Allocator.SetTargetChangesHandler(func(changes allocation.CollectorChanges) {
+		a.m.RLock()
+		defer a.m.RUnlock()
+		if a.replica == nil {
+			handler(changes)
+		}
+	})
+}
+
+// Decisions returns the allocation decisions of the local allocator, unless the assignments of the leader are served,
+// which only the leader can explain.
+func (a *Allocator) Decisions() []allocation.Decision {
+	if a.current() != nil {
+		return nil
+	}
+	return a.Allocator.Decisions()
+}
+
+func (a *Allocator) LastDecision(targetHash string) (allocation.Decision, bool) {
+	if a.current() != nil {
+		return allocation.Decision{}, false
+	}
+	return a.Allocator.LastDecision(targetHash)
+}
+
+func (a *Allocator) TargetItems() map[string]*target.Item {
+	r := a.current()
+	if r == nil {
+		return a.Allocator.TargetItems()
+	}
+	targetItemsCopy := make(map[string]*target.Item, len(r.targetItems))
+	for k, v := range r.targetItems {
+		targetItemsCopy[k] = v
+	}
+	return targetItemsCopy
+}
+
+func (a *Allocator) TargetItem(targetHash string) (*target.Item, bool) {
+	r := a.current()
+	if r == nil {
+		return a.Allocator.TargetItem(targetHash)
+	}
+	item, ok := r.targetItems[targetHash]
+	return item, ok
+}
+
+func (a *Allocator) TargetItemsForCollector(collector string) map[string]*target.Item {
+	r := a.current()
+	if r == nil {
+		return a.Allocator.TargetItemsForCollector(collector)
+	}
+	targetItemsCopy := make(map[string]*target.Item)
+	for _, items := range r.targetItemsPerJobPerCollector[collector] {
+		for _, item := range items {
+			targetItemsCopy[item.Hash()] = item
+		}
+	}
+	return targetItemsCopy
+}
+
+func (a *Allocator) Collectors() map[string]*allocation.Collector {
+	r := a.current()
+	if r == nil {
+		return a.Allocator.Collectors()
+	}
+	collectorsCopy := make(map[string]*allocation.Collector, len(r.collectors))
+	for k, v := range r.collectors {
+		collectorsCopy[k] = v
+	}
+	return collectorsCopy
+}
+
+func (a *Allocator) GetTargetsForCollectorAndJob(collector string, job string) []*target.Item {
+	r := a.current()
+	if r == nil {
+		return a.Allocator.GetTargetsForCollectorAndJob(collector, job)
+	}
+	items := r.targetItemsPerJobPerCollector[collector][job]
+	targetItemsCopy := make([]*target.Item, len(items))
+	copy(targetItemsCopy, items)
+	return targetItemsCopy
+}
+
+// AssignmentTable returns the local assignments, unless they haven't changed since the given version of the given
+// epoch. A version of another epoch, such as one fetched from a previous leader process, is always outdated.
+func (a *Allocator) AssignmentTable(sinceEpoch string, sinceVersion uint64) (*Table, bool) {
+	// the version is read first, so that a change made while building the table is fetched again next time
+	version := a.version.Load()
+	if sinceEpoch == a.epoch && sinceVersion != 0 && sinceVersion == version {
+		return nil, false
+	}
+	table := &Table{Epoch: a.epoch, Version: version}
+	for _, col := range a.Allocator.Collectors() {
+		table.Collectors = append(table.Collectors, TableCollector{Name: col.Name, NodeName: col.NodeName, Zone: col.Zone, Region: col.Region})
+	}
+	for _, item := range a.Allocator.TargetItems() {
+		if item.CollectorName == "" {
+			continue
+		}
+		table.Targets = append(table.Targets, TableTarget{
+			JobName:       item.JobName,
+			TargetURL:     item.TargetURL,
+			Labels:        item.Labels,
+			CollectorName: item.CollectorName,
+		})
+	}
+	return table, true
+}
+
+// Run fetches the assignments of the leader every sync interval until the context is done.
+func (a *Allocator) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.syncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := a.sync(ctx); err != nil {
+				syncFailures.Inc()
+				a.log.Error(err, "Failed to fetch the assignments of the leader")
+			}
+		}
+	}
+}
+
+// current returns the assignments to serve instead of the local ones, if any. They only change on sync, so that
+// the served assignments and their published changes agree.
+func (a *Allocator) current() *replica {
+	a.m.RLock()
+	defer a.m.RUnlock()
+	return a.replica
+}
+
+// setReplica replaces the assignments served instead of the local ones and publishes the changes.
+func (a *Allocator) setReplica(r *replica) {
+	a.m.Lock()
+	defer a.m.Unlock()
+	old := a.replica
+	a.replica = r
+	if a.changesHandler == nil || old == r {
+		return
+	}
+	if old != nil && r != nil && old.leader == r.leader {
+		if changes := diffReplicas(old, r); len(changes) > 0 {
+			a.changesHandler(changes)
+		}
+		return
+	}
+	// switching between the assignments of different allocators, which have to be fetched again
+	a.changesHandler(nil)
+}
+
+func (a *Allocator) sync(ctx context.Context) error {
+	leader := a.leadership.Leader()
+	if a.leadership.IsLeader() || leader == "" {
+		a.setReplica(nil)
+		return nil
+	}
+
+	var epoch string
+	var since uint64
+	a.m.RLock()
+	if a.replica != nil && a.replica.leader == leader {
+		epoch, since = a.replica.epoch, a.replica.version
+	}
+	a.m.RUnlock()
+
+	query := url.Values{"epoch": {epoch}, "version": {strconv.FormatUint(since, 10)}}
+	u := url.URL{Scheme: "http", Host: leader, Path: AssignmentsPath, RawQuery: query.Encode()}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return nil
+	case http.StatusOK:
+	default:
+		return fmt.Errorf("unexpected status from the leader %s: %s", leader, resp.Status)
+	}
+
+	var table Table
+	if err := json.NewDecoder(resp.Body).Decode(&table); err != nil {
+		return fmt.Errorf("failed to decode the assignments of the leader %s: %w", leader, err)
+	}
+	a.setReplica(newReplica(leader, table))
+	return nil
+}
+
+func (a *Allocator) forwardTargetCosts(leader string, costs []allocation.TargetCost) {
+	body, err := json.Marshal(costs)
+	if err != nil {
+		a.log.Error(err, "Failed to encode target costs")
+		return
+	}
+	u := url.URL{Scheme: "http", Host: leader, Path: targetCostsPath}
+	resp, err := a.client.Post(u.String(), "application/json", bytes.NewReader(body))
+	if err != nil {
+		a.log.Error(err, "Failed to forward target costs to the leader", "leader", leader)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		a.log.Info("The leader rejected the forwarded target costs", "leader", leader, "status", resp.Status)
+	}
+}
+
+func newReplica(leader string, table Table) *replica {
+	r := &replica{
+		leader:                        leader,
+		epoch:                         table.Epoch,
+		version:                       table.Version,
+		collectors:                    make(map[string]*allocation.Collector, len(table.Collectors)),
+		targetItems:                   make(map[string]*target.Item, len(table.Targets)),
+		targetItemsPerJobPerCollector: make(map[string]map[string][]*target.Item),
+	}
+	for _, col := range table.Collectors {
+		c := allocation.NewCollector(col.Name, col.NodeName)
+		c.Zone = col.Zone
+		c.Region = col.Region
+		r.collectors[col.Name] = c
+	}
+	for _, t := range table.Targets {
+		item := target.NewItem(t.JobName, t.TargetURL, t.Labels, t.CollectorName)
+		r.targetItems[item.Hash()] = item
+		if c, ok := r.collectors[t.CollectorName]; ok {
+			c.NumTargets++
+		}
+		if r.targetItemsPerJobPerCollector[t.CollectorName] == nil {
+			r.targetItemsPerJobPerCollector[t.CollectorName] = make(map[string][]*target.Item)
+		}
+		r.targetItemsPerJobPerCollector[t.CollectorName][t.JobName] = append(r.targetItemsPerJobPerCollector[t.CollectorName][t.JobName], item)
+	}
+	return r
+}
+
+// diffReplicas returns the targets assigned to and unassigned from each collector between two assignment tables.
+func diffReplicas(old, new *replica) allocation.CollectorChanges {
+	oldAssignments, newAssignments := old.assignments(), new.assignments()
+	changes := make(allocation.CollectorChanges)
+	for collectorName, newItems := range newAssignments {
+		if c := diff.Maps(oldAssignments[collectorName], newItems); len(c.Additions()) > 0 || len(c.Removals()) > 0 {
+			changes[collectorName] = c
+		}
+	}
+	for collectorName, oldItems := range oldAssignments {
+		if _, ok := newAssignments[collectorName]; !ok {
+			changes[collectorName] = diff.Maps(oldItems, nil)
+		}
+	}
+	return changes
+}
+
+// assignments returns the target items assigned to each collector, keyed by collector name and then target hash.
+func (r *replica) assignments() map[string]map[string]*target.Item {
+	assignments := make(map[string]map[string]*target.Item)
+	for hash, item := range r.targetItems {
+		if assignments[item.CollectorName] == nil {
+			assignments[item.CollectorName] = make(map[string]*target.Item)
+		}
+		assignments[item.CollectorName][hash] = item
+	}
+	return assignments
+}
@@ -0,0 +1,232 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leader
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/allocation"
+)
+
+var logger = logf.Log.WithName("leader-unit-tests")
+
+type fakeLeadership struct {
+	leading bool
+	leader  string
+}
+
+func (f *fakeLeadership) IsLeader() bool { return f.leading }
+
+func (f *fakeLeadership) Leader() string { return f.leader }
+
+func newTestAllocator(t *testing.T, leadership Leadership) *Allocator {
+	local, err := allocation.New("least-weighted", logger)
+	require.NoError(t, err)
+	return NewAllocator(logger, local, leadership, 0)
+}
+
+// serveAssignments serves the assignment table of the allocator like the server does and counts the full responses.
+func serveAssignments(t *testing.T, a *Allocator, served *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(assignmentsHandler(t, func() *Allocator { return a }, served))
+}
+
+// assignmentsHandler serves the assignment table of the current allocator and counts the full responses.
+func assignmentsHandler(t *testing.T, current func() *Allocator, served *atomic.Int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, AssignmentsPath, r.URL.Path)
+		since, err := strconv.ParseUint(r.URL.Query().Get("version"), 10, 64)
+		require.NoError(t, err)
+		table, modified := current().AssignmentTable(r.URL.Query().Get("epoch"), since)
+		if !modified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		served.Add(1)
+		assert.NoError(t, json.NewEncoder(w).Encode(table))
+	})
+}
+
+func TestFollowerServesLeaderAssignments(t *testing.T) {
+	leaderAllocator := newTestAllocator(t, &fakeLeadership{leading: true})
+	leaderAllocator.SetCollectors(allocation.MakeNCollectors(3, 0))
+	leaderAllocator.SetTargets(allocation.MakeNNewTargets(12, 3, 0))
+
+	var served atomic.Int32
+	srv := serveAssignments(t, leaderAllocator, &served)
+	defer srv.Close()
+
+	followerLeadership := &fakeLeadership{leader: strings.TrimPrefix(srv.URL, "http://")}
+	follower := newTestAllocator(t, followerLeadership)
+	follower.SetCollectors(allocation.MakeNCollectors(1, 5))
+	follower.SetTargets(allocation.MakeNNewTargets(12, 3, 0))
+
+	// before the first sync, the follower serves its own assignments
+	assert.Len(t, follower.Collectors(), 1)
+
+	require.NoError(t, follower.sync(context.Background()))
+	assert.Equal(t, int32(1), served.Load())
+	assertSameAssignments(t, leaderAllocator, follower)
+
+	// nothing changed on the leader, the table isn't sent again
+	require.NoError(t, follower.sync(context.Background()))
+	assert.Equal(t, int32(1), served.Load())
+
+	leaderAllocator.SetTargets(allocation.MakeNNewTargets(6, 3, 0))
+	require.NoError(t, follower.sync(context.Background()))
+	assert.Equal(t, int32(2), served.Load())
+	assertSameAssignments(t, leaderAllocator, follower)
+
+	// once leading, the follower serves its own assignments again
+	followerLeadership.leading = true
+	require.NoError(t, follower.sync(context.Background()))
+	assert.Len(t, follower.Collectors(), 1)
+	assert.Len(t, follower.TargetItems(), 12)
+}
+
+func TestFollowerPublishesServedChanges(t *testing.T) {
+	leaderAllocator := newTestAllocator(t, &fakeLeadership{leading: true})
+	leaderAllocator.SetCollectors(allocation.MakeNCollectors(3, 0))
+	leaderAllocator.SetTargets(allocation.MakeNNewTargets(12, 3, 0))
+
+	var served atomic.Int32
+	srv := serveAssignments(t, leaderAllocator, &served)
+	defer srv.Close()
+
+	followerLeadership := &fakeLeadership{leader: strings.TrimPrefix(srv.URL, "http://")}
+	follower := newTestAllocator(t, followerLeadership)
+	var published []allocation.CollectorChanges
+	follower.SetTargetChangesHandler(func(changes allocation.CollectorChanges) {
+		published = append(published, changes)
+	})
+
+	// the local changes are published while they're served
+	follower.SetCollectors(allocation.MakeNCollectors(1, 5))
+	follower.SetTargets(allocation.MakeNNewTargets(12, 3, 0))
+	require.Len(t, published, 1)
+	assert.Len(t, published[0]["collector-5"].Additions(), 12)
+
+	// switching to the assignments of the leader resets them
+	require.NoError(t, follower.sync(context.Background()))
+	require.Len(t, published, 2)
+	assert.Nil(t, published[1])
+
+	// the local changes aren't published anymore, those of the leader are
+	follower.SetTargets(allocation.MakeNNewTargets(6, 3, 0))
+	leaderAllocator.SetTargets(allocation.MakeNNewTargets(6, 3, 0))
+	require.NoError(t, follower.sync(context.Background()))
+	require.Len(t, published, 3)
+	added, removed := 0, 0
+	for _, changes := range published[2] {
+		added += len(changes.Additions())
+		removed += len(changes.Removals())
+	}
+	assert.Equal(t, 6, added)
+	assert.Equal(t, 12, removed)
+
+	// and switching back to the local assignments resets them again
+	followerLeadership.leading = true
+	require.NoError(t, follower.sync(context.Background()))
+	require.Len(t, published, 4)
+	assert.Nil(t, published[3])
+}
+
+func TestFollowerRefetchesAfterLeaderRestart(t *testing.T) {
+	var current atomic.Pointer[Allocator]
+	var served atomic.Int32
+	srv := httptest.NewServer(assignmentsHandler(t, current.Load, &served))
+	defer srv.Close()
+
+	before := newTestAllocator(t, &fakeLeadership{leading: true})
+	before.SetCollectors(allocation.MakeNCollectors(3, 0))
+	before.SetTargets(allocation.MakeNNewTargets(12, 3, 0))
+	current.Store(before)
+
+	follower := newTestAllocator(t, &fakeLeadership{leader: strings.TrimPrefix(srv.URL, "http://")})
+	require.NoError(t, follower.sync(context.Background()))
+	assert.Equal(t, int32(1), served.Load())
+
+	// the restarted leader reaches the same version with other assignments
+	after := newTestAllocator(t, &fakeLeadership{leading: true})
+	after.SetCollectors(allocation.MakeNCollectors(2, 0))
+	after.SetTargets(allocation.MakeNNewTargets(6, 2, 0))
+	require.Equal(t, before.version.Load(), after.version.Load())
+	current.Store(after)
+
+	require.NoError(t, follower.sync(context.Background()))
+	assert.Equal(t, int32(2), served.Load())
+	assertSameAssignments(t, after, follower)
+}
+
+func TestFollowerWithUnreachableLeader(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	follower := newTestAllocator(t, &fakeLeadership{leader: strings.TrimPrefix(srv.URL, "http://")})
+	follower.SetCollectors(allocation.MakeNCollectors(2, 0))
+	follower.SetTargets(allocation.MakeNNewTargets(4, 2, 0))
+
+	assert.Error(t, follower.sync(context.Background()))
+	// the follower keeps serving its own assignments
+	assert.Len(t, follower.Collectors(), 2)
+	assert.Len(t, follower.TargetItems(), 4)
+}
+
+func TestFollowerForwardsTargetCosts(t *testing.T) {
+	received := make(chan []allocation.TargetCost, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, targetCostsPath, r.URL.Path)
+		var costs []allocation.TargetCost
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&costs))
+		received <- costs
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	follower := newTestAllocator(t, &fakeLeadership{leader: strings.TrimPrefix(srv.URL, "http://")})
+	costs := []allocation.TargetCost{{JobName: "job", TargetURL: "10.0.0.1:8080", Cost: 100}}
+	follower.SetTargetCosts(costs)
+	assert.Equal(t, costs, <-received)
+}
+
+func assertSameAssignments(t *testing.T, expected, actual allocation.Allocator) {
+	t.Helper()
+	assert.Len(t, actual.Collectors(), len(expected.Collectors()))
+	assert.Len(t, actual.TargetItems(), len(expected.TargetItems()))
+	for hash, item := range expected.TargetItems() {
+		require.Contains(t, actual.TargetItems(), hash)
+		assert.Equal(t, item.CollectorName, actual.TargetItems()[hash].CollectorName)
+	}
+	for name, col := range expected.Collectors() {
+		require.Contains(t, actual.Collectors(), name)
+		assert.Equal(t, col.NumTargets, actual.Collectors()[name].NumTargets)
+		assert.Equal(t, expected.TargetItemsForCollector(name), actual.TargetItemsForCollector(name))
+		for _, item := range expected.TargetItems() {
+			assert.ElementsMatch(t, expected.GetTargetsForCollectorAndJob(name, item.JobName), actual.GetTargetsForCollectorAndJob(name, item.JobName))
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leader
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sync/atomic"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/config"
+)
+
+var (
+	isLeader = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "opentelemetry_allocator_leader",
+		Help: "Whether this target allocator replica holds the leader Lease.",
+	})
+)
+
+// Elector elects the replica allocating targets among the target allocator replicas sharing a Lease. The identity of
+// a replica is the address the others reach it at, so that the holder of the Lease is also where to find its
+// assignments.
+type Elector struct {
+	log      logr.Logger
+	identity string
+	elector  *leaderelection.LeaderElector
+	leading  atomic.Bool
+}
+
+func NewElector(log logr.Logger, cfg config.LeaderElectionConfig, client kubernetes.Interface, identity string) (*Elector, error) {
+	namespace := cfg.LeaseNamespace
+	if namespace == "" {
+		namespace = os.Getenv("OTELCOL_NAMESPACE")
+	}
+	if namespace == "" {
+		return nil, fmt.Errorf("the namespace of the Lease is unknown, set lease_namespace")
+	}
+
+	e := &Elector{
+		log:      log.WithValues("component", "leader-elector", "identity", identity),
+		identity: identity,
+	}
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock: &resourcelock.LeaseLock{
+			LeaseMeta:  metav1.ObjectMeta{Name: cfg.LeaseName, Namespace: namespace},
+			Client:     client.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+		},
+		LeaseDuration:   cfg.LeaseDuration,
+		RenewDeadline:   cfg.RenewDeadline,
+		RetryPeriod:     cfg.RetryPeriod,
+		ReleaseOnCancel: true,
+		Name:            cfg.LeaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(_ context.Context) {
+				e.log.Info("Started leading, allocating targets for all replicas")
+				e.leading.Store(true)
+				isLeader.Set(1)
+			},
+			OnStoppedLeading: func() {
+				// called whenever an election round ends, whether this replica was leading or not
+				if e.leading.Swap(false) {
+					e.log.Info("Stopped leading")
+				}
+				isLeader.Set(0)
+			},
+			OnNewLeader: func(identity string) {
+				e.log.Info("New leader elected", "leader", identity)
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	e.elector = elector
+	return e, nil
+}
+
+// Run takes part in the election until the context is done. A replica losing the Lease becomes a follower and
+// competes for it again.
+func (e *Elector) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		e.elector.Run(ctx)
+	}
+}
+
+// IsLeader reports whether this replica holds the Lease.
+func (e *Elector) IsLeader() bool {
+	return e.leading.Load()
+}
+
+// Leader returns the identity of the replica holding the Lease, or an empty string if it isn't known yet.
+func (e *Elector) Leader() string {
+	return e.elector.GetLeader()
+}
+
+// AdvertiseAddr returns the address the other replicas reach this one at. Unless configured, it is the IP the
+// hostname of the Pod resolves to and the port of the listen address.
+func AdvertiseAddr(cfg config.LeaderElectionConfig, listenAddr string) (string, error) {
+	if cfg.AdvertiseAddr != "" {
+		return cfg.AdvertiseAddr, nil
+	}
+	_, port, err := net.SplitHostPort(listenAddr)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the listen address: %w", err)
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", err
+	}
+	ips, err := net.LookupIP(hostname)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the Pod IP, set advertise_addr: %w", err)
+	}
+	for _, ip := range ips {
+		if !ip.IsLoopback() {
+			return net.JoinHostPort(ip.String(), port), nil
+		}
+	}
+	return "", fmt.Errorf("failed to resolve the Pod IP, set advertise_addr")
+}
@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leader
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/config"
+)
+
+func TestElector(t *testing.T) {
+	cfg := config.LeaderElectionConfig{
+		Enabled:        true,
+		LeaseName:      "test-targetallocator",
+		LeaseNamespace: "test-ns",
+		LeaseDuration:  time.Second,
+		RenewDeadline:  500 * time.Millisecond,
+		RetryPeriod:    100 * time.Millisecond,
+	}
+	client := fake.NewSimpleClientset()
+
+	first, err := NewElector(logger, cfg, client, "10.0.0.1:8080")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go first.Run(ctx)
+
+	assert.Eventually(t, first.IsLeader, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "10.0.0.1:8080", first.Leader())
+
+	second, err := NewElector(logger, cfg, client, "10.0.0.2:8080")
+	require.NoError(t, err)
+	secondCtx, secondCancel := context.WithCancel(context.Background())
+	defer secondCancel()
+	go second.Run(secondCtx)
+
+	assert.Eventually(t, func() bool {
+		return second.Leader() == "10.0.0.1:8080"
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.False(t, second.IsLeader())
+
+	// the first replica goes away and releases the Lease
+	cancel()
+	assert.Eventually(t, second.IsLeader, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "10.0.0.2:8080", second.Leader())
+}
+
+func TestElectorWithoutNamespace(t *testing.T) {
+	t.Setenv("OTELCOL_NAMESPACE", "")
+	_, err := NewElector(logger, config.LeaderElectionConfig{LeaseName: "test"}, fake.NewSimpleClientset(), "10.0.0.1:8080")
+	assert.ErrorContains(t, err, "lease_namespace")
+}
+
+func TestAdvertiseAddr(t *testing.T) {
+	addr, err := AdvertiseAddr(config.LeaderElectionConfig{AdvertiseAddr: "10.0.0.1:9090"}, ":8080")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1:9090", addr)
+
+	_, err = AdvertiseAddr(config.LeaderElectionConfig{}, "8080")
+	assert.ErrorContains(t, err, "listen address")
+}
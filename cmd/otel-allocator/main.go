@@ -21,21 +21,18 @@ import (
 	"os/signal"
 	"syscall"
 
-	gokitlog "github.com/go-kit/log"
 	"github.com/oklog/run"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/prometheus/discovery"
+	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/allocation"
-	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/collector"
 	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/config"
-	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/prehook"
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/leader"
 	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/server"
-	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/target"
-	allocatorWatcher "github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/watcher"
 )
 
 var (
@@ -43,26 +40,14 @@ var (
 	eventsMetric = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "opentelemetry_allocator_events",
 		Help: "Number of events in the channel.",
-	}, []string{"source"})
+	}, []string{"source", "pool"})
 )
 
 func main() {
 	var (
-		// allocatorPrehook will be nil if filterStrategy is not set or
-		// unrecognized. No filtering will be used in this case.
-		allocatorPrehook prehook.Hook
-		allocator        allocation.Allocator
-		discoveryManager *discovery.Manager
-		collectorWatcher *collector.Watcher
-		promWatcher      allocatorWatcher.Watcher
-		targetDiscoverer *target.Discoverer
-
-		discoveryCancel context.CancelFunc
 		runGroup        run.Group
-		eventChan       = make(chan allocatorWatcher.Event)
-		eventCloser     = make(chan bool, 1)
 		interrupts      = make(chan os.Signal, 1)
-		errChan         = make(chan error)
+		interruptCloser = make(chan bool, 1)
 	)
 	cfg, err := config.Load()
 	if err != nil {
@@ -80,11 +65,48 @@ func main() {
 	ctx := context.Background()
 	log := ctrl.Log.WithName("allocator")
 
-	allocatorPrehook = prehook.New(cfg.FilterStrategy, log)
-	allocator, err = allocation.New(cfg.AllocationStrategy, log, allocation.WithFilter(allocatorPrehook), allocation.WithFallbackStrategy(cfg.AllocationFallbackStrategy))
-	if err != nil {
-		setupLog.Error(err, "Unable to initialize allocation strategy")
-		os.Exit(1)
+	// without pools, a single pool allocates the targets of all collectors
+	pools := []*pool{}
+	if len(cfg.Pools) == 0 {
+		p, poolErr := newPool("", *cfg, log)
+		if poolErr != nil {
+			setupLog.Error(poolErr, "Unable to initialize allocation strategy")
+			os.Exit(1)
+		}
+		pools = append(pools, p)
+	}
+	for _, poolCfg := range cfg.Pools {
+		p, poolErr := newPool(poolCfg.Name, cfg.ForPool(poolCfg), log)
+		if poolErr != nil {
+			setupLog.Error(poolErr, "Unable to initialize allocation strategy", "pool", poolCfg.Name)
+			os.Exit(1)
+		}
+		pools = append(pools, p)
+	}
+
+	var (
+		elector             *leader.Elector
+		replicatedAllocator *leader.Allocator
+	)
+	if cfg.LeaderElection.Enabled {
+		identity, addrErr := leader.AdvertiseAddr(cfg.LeaderElection, cfg.ListenAddr)
+		if addrErr != nil {
+			setupLog.Error(addrErr, "Unable to determine the address advertised to the other replicas")
+			os.Exit(1)
+		}
+		clientset, clientErr := kubernetes.NewForConfig(cfg.ClusterConfig)
+		if clientErr != nil {
+			setupLog.Error(clientErr, "Unable to create the leader election client")
+			os.Exit(1)
+		}
+		elector, err = leader.NewElector(log, cfg.LeaderElection, clientset, identity)
+		if err != nil {
+			setupLog.Error(err, "Unable to initialize leader election")
+			os.Exit(1)
+		}
+		// leader election is only supported without pools
+		replicatedAllocator = leader.NewAllocator(log, pools[0].allocator, elector, cfg.LeaderElection.SyncInterval)
+		pools[0].allocator = replicatedAllocator
 	}
 
 	httpOptions := []server.Option{}
@@ -96,97 +118,57 @@ func main() {
 		}
 		httpOptions = append(httpOptions, server.WithTLSConfig(tlsConfig, cfg.HTTPS.ListenAddr))
 	}
-	srv := server.NewServer(log, allocator, cfg.ListenAddr, httpOptions...)
+	var srv *server.Server
+	if len(cfg.Pools) == 0 {
+		srv = server.NewServer(log, pools[0].allocator, cfg.ListenAddr, httpOptions...)
+	} else {
+		allocators := make(map[string]allocation.Allocator, len(pools))
+		for _, p := range pools {
+			allocators[p.name] = p.allocator
+		}
+		srv = server.NewPoolsServer(log, allocators, cfg.ListenAddr, httpOptions...)
+	}
 
-	discoveryCtx, discoveryCancel := context.WithCancel(ctx)
 	sdMetrics, err := discovery.CreateAndRegisterSDMetrics(prometheus.DefaultRegisterer)
 	if err != nil {
 		setupLog.Error(err, "Unable to register metrics for Prometheus service discovery")
 		os.Exit(1)
 	}
-	discoveryManager = discovery.NewManager(discoveryCtx, gokitlog.NewNopLogger(), prometheus.DefaultRegisterer, sdMetrics)
-
-	targetDiscoverer = target.NewDiscoverer(log, discoveryManager, allocatorPrehook, srv, allocator.SetTargets)
-	collectorWatcher, collectorWatcherErr := collector.NewCollectorWatcher(log, cfg.ClusterConfig)
-	if collectorWatcherErr != nil {
-		setupLog.Error(collectorWatcherErr, "Unable to initialize collector watcher")
-		os.Exit(1)
-	}
 	signal.Notify(interrupts, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 	defer close(interrupts)
 
-	if cfg.PrometheusCR.Enabled {
-		promWatcher, err = allocatorWatcher.NewPrometheusCRWatcher(ctx, setupLog.WithName("prometheus-cr-watcher"), *cfg)
-		if err != nil {
-			setupLog.Error(err, "Can't start the prometheus watcher")
-			os.Exit(1)
-		}
-		// apply the initial configuration
-		promConfig, loadErr := promWatcher.LoadConfig(ctx)
-		if loadErr != nil {
-			setupLog.Error(err, "Can't load initial Prometheus configuration from Prometheus CRs")
-			os.Exit(1)
+	for _, p := range pools {
+		poolSrv := srv
+		if p.name != "" {
+			poolSrv, _ = srv.Pool(p.name)
 		}
-		loadErr = targetDiscoverer.ApplyConfig(allocatorWatcher.EventSourcePrometheusCR, promConfig.ScrapeConfigs)
-		if loadErr != nil {
-			setupLog.Error(err, "Can't load initial scrape targets from Prometheus CRs")
+		if runErr := p.run(ctx, &runGroup, poolSrv, sdMetrics); runErr != nil {
 			os.Exit(1)
 		}
+	}
+	if cfg.LeaderElection.Enabled {
+		leaderCtx, leaderCancel := context.WithCancel(ctx)
 		runGroup.Add(
 			func() error {
-				promWatcherErr := promWatcher.Watch(eventChan, errChan)
-				setupLog.Info("Prometheus watcher exited")
-				return promWatcherErr
+				elector.Run(leaderCtx)
+				setupLog.Info("Leader elector exited")
+				return nil
 			},
 			func(_ error) {
-				setupLog.Info("Closing prometheus watcher")
-				promWatcherErr := promWatcher.Close()
-				if promWatcherErr != nil {
-					setupLog.Error(promWatcherErr, "prometheus watcher failed to close")
-				}
+				setupLog.Info("Closing leader elector")
+				leaderCancel()
+			})
+		runGroup.Add(
+			func() error {
+				replicatedAllocator.Run(leaderCtx)
+				setupLog.Info("Assignment sync exited")
+				return nil
+			},
+			func(_ error) {
+				setupLog.Info("Closing assignment sync")
+				leaderCancel()
 			})
 	}
-	runGroup.Add(
-		func() error {
-			discoveryManagerErr := discoveryManager.Run()
-			setupLog.Info("Discovery manager exited")
-			return discoveryManagerErr
-		},
-		func(_ error) {
-			setupLog.Info("Closing discovery manager")
-			discoveryCancel()
-		})
-	runGroup.Add(
-		func() error {
-			// Initial loading of the config file's scrape config
-			if cfg.PromConfig != nil && len(cfg.PromConfig.ScrapeConfigs) > 0 {
-				err = targetDiscoverer.ApplyConfig(allocatorWatcher.EventSourceConfigMap, cfg.PromConfig.ScrapeConfigs)
-				if err != nil {
-					setupLog.Error(err, "Unable to apply initial configuration")
-					return err
-				}
-			} else {
-				setupLog.Info("Prometheus config empty, skipping initial discovery configuration")
-			}
-
-			err := targetDiscoverer.Run()
-			setupLog.Info("Target discoverer exited")
-			return err
-		},
-		func(_ error) {
-			setupLog.Info("Closing target discoverer")
-			targetDiscoverer.Close()
-		})
-	runGroup.Add(
-		func() error {
-			err := collectorWatcher.Watch(cfg.CollectorSelector, allocator.SetCollectors)
-			setupLog.Info("Collector watcher exited")
-			return err
-		},
-		func(_ error) {
-			setupLog.Info("Closing collector watcher")
-			collectorWatcher.Close()
-		})
 	runGroup.Add(
 		func() error {
 			err := srv.Start()
@@ -213,33 +195,6 @@ func main() {
 				}
 			})
 	}
-	runGroup.Add(
-		func() error {
-			for {
-				select {
-				case event := <-eventChan:
-					eventsMetric.WithLabelValues(event.Source.String()).Inc()
-					loadConfig, err := event.Watcher.LoadConfig(ctx)
-					if err != nil {
-						setupLog.Error(err, "Unable to load configuration")
-						continue
-					}
-					err = targetDiscoverer.ApplyConfig(event.Source, loadConfig.ScrapeConfigs)
-					if err != nil {
-						setupLog.Error(err, "Unable to apply configuration")
-						continue
-					}
-				case err := <-errChan:
-					setupLog.Error(err, "Watcher error")
-				case <-eventCloser:
-					return nil
-				}
-			}
-		},
-		func(_ error) {
-			setupLog.Info("Closing watcher loop")
-			close(eventCloser)
-		})
 	runGroup.Add(
 		func() error {
 			for {
@@ -247,13 +202,14 @@ func main() {
 				case <-interrupts:
 					setupLog.Info("Received interrupt")
 					return nil
-				case <-eventCloser:
+				case <-interruptCloser:
 					return nil
 				}
 			}
 		},
 		func(_ error) {
 			setupLog.Info("Closing interrupt loop")
+			close(interruptCloser)
 		})
 	if runErr := runGroup.Run(); runErr != nil {
 		setupLog.Error(runErr, "run group exited")
@@ -0,0 +1,241 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+
+	gokitlog "github.com/go-kit/log"
+	"github.com/go-logr/logr"
+	"github.com/oklog/run"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/discovery"
+
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/allocation"
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/collector"
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/config"
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/prehook"
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/server"
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/target"
+	allocatorWatcher "github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/watcher"
+)
+
+// pool discovers the targets of its scrape configs and allocates them to its collectors, independently of the other
+// pools. Without pools in the config, the target allocator runs a single pool with an empty name.
+type pool struct {
+	name     string
+	cfg      config.Config
+	log      logr.Logger
+	setupLog logr.Logger
+	// prehook will be nil if filterStrategy is not set or
+	// unrecognized. No filtering will be used in this case.
+	prehook   prehook.Hook
+	allocator allocation.Allocator
+}
+
+func newPool(name string, cfg config.Config, log logr.Logger) (*pool, error) {
+	p := &pool{name: name, cfg: cfg, log: log, setupLog: setupLog}
+	if name != "" {
+		p.log = log.WithValues("pool", name)
+		p.setupLog = setupLog.WithValues("pool", name)
+	}
+	p.prehook = prehook.New(cfg.FilterStrategy, p.log, cfg.Filters, name)
+	allocator, err := allocation.New(cfg.AllocationStrategy, p.log, allocation.WithFilter(p.prehook), allocation.WithFallbackStrategy(cfg.AllocationFallbackStrategy), allocation.WithPool(name))
+	if err != nil {
+		return nil, err
+	}
+	p.allocator = allocator
+	return p, nil
+}
+
+// run adds the actors discovering the targets of the pool and allocating them to the run group. srv serves the scrape
+// configs and the assignments of the pool.
+func (p *pool) run(ctx context.Context, runGroup *run.Group, srv *server.Server, sdMetrics map[string]discovery.DiscovererMetrics) error {
+	var (
+		setupLog    = p.setupLog
+		cfg         = p.cfg
+		eventChan   = make(chan allocatorWatcher.Event)
+		eventCloser = make(chan bool, 1)
+		errChan     = make(chan error)
+	)
+
+	var managerOptions []func(*discovery.Manager)
+	if p.name != "" {
+		managerOptions = append(managerOptions, discovery.Name(p.name))
+	}
+	discoveryCtx, discoveryCancel := context.WithCancel(ctx)
+	discoveryManager := discovery.NewManager(discoveryCtx, gokitlog.NewNopLogger(), prometheus.DefaultRegisterer, sdMetrics, managerOptions...)
+	runGroup.Add(
+		func() error {
+			discoveryManagerErr := discoveryManager.Run()
+			setupLog.Info("Discovery manager exited")
+			return discoveryManagerErr
+		},
+		func(_ error) {
+			setupLog.Info("Closing discovery manager")
+			discoveryCancel()
+		})
+
+	targetDiscoverer := target.NewDiscoverer(p.log, discoveryManager, p.prehook, srv, p.allocator.SetTargets, p.name)
+	collectorWatcher, err := collector.NewCollectorWatcher(p.log, cfg.ClusterConfig, p.name)
+	if err != nil {
+		setupLog.Error(err, "Unable to initialize collector watcher")
+		return err
+	}
+
+	if cfg.PrometheusCR.Enabled {
+		crWatcher, crWatcherErr := allocatorWatcher.NewPrometheusCRWatcher(ctx, setupLog.WithName("prometheus-cr-watcher"), cfg, p.name)
+		if crWatcherErr != nil {
+			setupLog.Error(crWatcherErr, "Can't start the prometheus watcher")
+			return crWatcherErr
+		}
+		crWatcher.SetProblemsHandler(srv.UpdateScrapeConfigProblems)
+		var promWatcher allocatorWatcher.Watcher = crWatcher
+		// apply the initial configuration
+		promConfig, loadErr := promWatcher.LoadConfig(ctx)
+		if loadErr != nil {
+			setupLog.Error(loadErr, "Can't load initial Prometheus configuration from Prometheus CRs")
+			return loadErr
+		}
+		loadErr = targetDiscoverer.ApplyConfig(allocatorWatcher.EventSourcePrometheusCR, promConfig.ScrapeConfigs)
+		if loadErr != nil {
+			setupLog.Error(loadErr, "Can't load initial scrape targets from Prometheus CRs")
+			return loadErr
+		}
+		runGroup.Add(
+			func() error {
+				promWatcherErr := promWatcher.Watch(eventChan, errChan)
+				setupLog.Info("Prometheus watcher exited")
+				return promWatcherErr
+			},
+			func(_ error) {
+				setupLog.Info("Closing prometheus watcher")
+				promWatcherErr := promWatcher.Close()
+				if promWatcherErr != nil {
+					setupLog.Error(promWatcherErr, "prometheus watcher failed to close")
+				}
+			})
+	}
+	sourceWatchers := make(map[allocatorWatcher.EventSource]allocatorWatcher.Watcher)
+	if cfg.PromConfig != nil && len(cfg.PromConfig.ScrapeConfigFiles) > 0 {
+		fileWatcher, fileWatcherErr := allocatorWatcher.NewFileWatcher(setupLog.WithName("file-watcher"), cfg)
+		if fileWatcherErr != nil {
+			setupLog.Error(fileWatcherErr, "Can't start the scrape config file watcher")
+			return fileWatcherErr
+		}
+		sourceWatchers[allocatorWatcher.EventSourceFile] = fileWatcher
+	}
+	if cfg.HTTPScrapeConfigs.URL != "" {
+		httpWatcher, httpWatcherErr := allocatorWatcher.NewHTTPWatcher(setupLog.WithName("http-watcher"), cfg)
+		if httpWatcherErr != nil {
+			setupLog.Error(httpWatcherErr, "Can't start the scrape config HTTP watcher")
+			return httpWatcherErr
+		}
+		sourceWatchers[allocatorWatcher.EventSourceHTTP] = httpWatcher
+	}
+	for source, sourceWatcher := range sourceWatchers {
+		// apply the initial configuration, if it can be loaded already; otherwise the watcher applies it once it can
+		sourceConfig, loadErr := sourceWatcher.LoadConfig(ctx)
+		if loadErr != nil {
+			setupLog.Error(loadErr, "Can't load initial scrape configs", "source", source.String())
+		} else if loadErr = targetDiscoverer.ApplyConfig(source, sourceConfig.ScrapeConfigs); loadErr != nil {
+			setupLog.Error(loadErr, "Can't load initial scrape targets", "source", source.String())
+			return loadErr
+		}
+		runGroup.Add(
+			func() error {
+				watcherErr := sourceWatcher.Watch(eventChan, errChan)
+				setupLog.Info("Scrape config watcher exited", "source", source.String())
+				return watcherErr
+			},
+			func(_ error) {
+				setupLog.Info("Closing scrape config watcher", "source", source.String())
+				if watcherErr := sourceWatcher.Close(); watcherErr != nil {
+					setupLog.Error(watcherErr, "scrape config watcher failed to close", "source", source.String())
+				}
+			})
+	}
+	runGroup.Add(
+		func() error {
+			// Initial loading of the config file's scrape config
+			if cfg.PromConfig != nil && len(cfg.PromConfig.ScrapeConfigs) > 0 {
+				err := targetDiscoverer.ApplyConfig(allocatorWatcher.EventSourceConfigMap, cfg.PromConfig.ScrapeConfigs)
+				if err != nil {
+					setupLog.Error(err, "Unable to apply initial configuration")
+					return err
+				}
+			} else {
+				setupLog.Info("Prometheus config empty, skipping initial discovery configuration")
+			}
+
+			err := targetDiscoverer.Run()
+			setupLog.Info("Target discoverer exited")
+			return err
+		},
+		func(_ error) {
+			setupLog.Info("Closing target discoverer")
+			targetDiscoverer.Close()
+		})
+	runGroup.Add(
+		func() error {
+			err := collectorWatcher.Watch(cfg.CollectorSelector, p.allocator.SetCollectors)
+			setupLog.Info("Collector watcher exited")
+			return err
+		},
+		func(_ error) {
+			setupLog.Info("Closing collector watcher")
+			collectorWatcher.Close()
+		})
+	if allocation.UsesTopology(cfg.AllocationStrategy) {
+		runGroup.Add(
+			func() error {
+				err := collectorWatcher.WatchTopology(p.allocator.SetTopology)
+				setupLog.Info("Topology watcher exited")
+				return err
+			},
+			func(_ error) {
+				// closing the collector watcher stops the topology watcher too
+				setupLog.Info("Closing topology watcher")
+			})
+	}
+	runGroup.Add(
+		func() error {
+			for {
+				select {
+				case event := <-eventChan:
+					eventsMetric.WithLabelValues(event.Source.String(), p.name).Inc()
+					loadConfig, err := event.Watcher.LoadConfig(ctx)
+					if err != nil {
+						setupLog.Error(err, "Unable to load configuration")
+						continue
+					}
+					err = targetDiscoverer.ApplyConfig(event.Source, loadConfig.ScrapeConfigs)
+					if err != nil {
+						setupLog.Error(err, "Unable to apply configuration")
+						continue
+					}
+				case err := <-errChan:
+					setupLog.Error(err, "Watcher error")
+				case <-eventCloser:
+					return nil
+				}
+			}
+		},
+		func(_ error) {
+			setupLog.Info("Closing watcher loop")
+			close(eventCloser)
+		})
+	return nil
+}
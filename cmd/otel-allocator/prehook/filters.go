@@ -0,0 +1,182 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prehook
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/model/relabel"
+
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/config"
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/target"
+)
+
+const (
+	targetLimitFilterName = "target-limit"
+	namespaceFilterName   = "namespace"
+	dedupFilterName       = "dedup"
+
+	namespaceLabel   = "__meta_kubernetes_namespace"
+	metricsPathLabel = "__metrics_path__"
+	schemeLabel      = "__scheme__"
+)
+
+var (
+	targetsOverLimit = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "opentelemetry_allocator_targets_over_limit",
+		Help: "Number of targets of a job dropped by the target limit.",
+	}, []string{"job_name", "pool"})
+)
+
+// targetLimitFilter keeps at most a fixed number of targets per job. The targets kept are the ones
+// with the lowest hashes, so the same targets are kept for as long as they are discovered.
+type targetLimitFilter struct {
+	log   logr.Logger
+	limit int
+	pool  string
+}
+
+func newTargetLimitFilter(log logr.Logger, cfg config.FilterConfig, pool string) Hook {
+	return &targetLimitFilter{
+		log:   log,
+		limit: cfg.MaxTargetsPerJob,
+		pool:  pool,
+	}
+}
+
+func (tf *targetLimitFilter) Apply(targets map[string]*target.Item) map[string]*target.Item {
+	// only the series of this pool are reset, the other pools filter their own targets
+	targetsOverLimit.DeletePartialMatch(prometheus.Labels{"pool": tf.pool})
+	if tf.limit <= 0 {
+		return targets
+	}
+
+	hashesByJob := make(map[string][]string)
+	for hash, item := range targets {
+		hashesByJob[item.JobName] = append(hashesByJob[item.JobName], hash)
+	}
+	for jobName, hashes := range hashesByJob {
+		if len(hashes) <= tf.limit {
+			continue
+		}
+		slices.Sort(hashes)
+		for _, hash := range hashes[tf.limit:] {
+			delete(targets, hash)
+		}
+		dropped := len(hashes) - tf.limit
+		targetsOverLimit.WithLabelValues(jobName, tf.pool).Set(float64(dropped))
+		tf.log.V(2).Info("Job exceeds the target limit", "job", jobName, "limit", tf.limit, "dropped", dropped)
+	}
+	return targets
+}
+
+func (tf *targetLimitFilter) SetConfig(map[string][]*relabel.Config) {}
+
+func (tf *targetLimitFilter) GetConfig() map[string][]*relabel.Config {
+	return nil
+}
+
+// namespaceFilter drops the targets of denied namespaces and, if any namespaces are allowed, the targets
+// of all other namespaces. Targets without a namespace, such as the ones of static configs, are kept.
+type namespaceFilter struct {
+	log     logr.Logger
+	allowed map[string]bool
+	denied  map[string]bool
+}
+
+func newNamespaceFilter(log logr.Logger, cfg config.FilterConfig, _ string) Hook {
+	tf := &namespaceFilter{
+		log:     log,
+		allowed: make(map[string]bool, len(cfg.AllowedNamespaces)),
+		denied:  make(map[string]bool, len(cfg.DeniedNamespaces)),
+	}
+	for _, namespace := range cfg.AllowedNamespaces {
+		tf.allowed[namespace] = true
+	}
+	for _, namespace := range cfg.DeniedNamespaces {
+		tf.denied[namespace] = true
+	}
+	return tf
+}
+
+func (tf *namespaceFilter) Apply(targets map[string]*target.Item) map[string]*target.Item {
+	numTargets := len(targets)
+	for hash, item := range targets {
+		namespace := item.Labels.Get(namespaceLabel)
+		if namespace == "" {
+			continue
+		}
+		if tf.denied[namespace] || (len(tf.allowed) > 0 && !tf.allowed[namespace]) {
+			delete(targets, hash)
+		}
+	}
+
+	tf.log.V(2).Info("Filtering complete", "seen", numTargets, "kept", len(targets))
+	return targets
+}
+
+func (tf *namespaceFilter) SetConfig(map[string][]*relabel.Config) {}
+
+func (tf *namespaceFilter) GetConfig() map[string][]*relabel.Config {
+	return nil
+}
+
+// dedupFilter keeps a single target out of the targets scraping the same endpoint, which happens when
+// several ServiceMonitors or PodMonitors select the same pods. The target kept is the one of the job
+// whose name sorts first, so that the choice does not depend on the order of discovery.
+type dedupFilter struct {
+	log logr.Logger
+}
+
+func newDedupFilter(log logr.Logger, _ config.FilterConfig, _ string) Hook {
+	return &dedupFilter{log: log}
+}
+
+func (tf *dedupFilter) Apply(targets map[string]*target.Item) map[string]*target.Item {
+	numTargets := len(targets)
+	kept := make(map[string]*target.Item, len(targets))
+	for hash, item := range targets {
+		key := endpointKey(item)
+		other, ok := kept[key]
+		if !ok {
+			kept[key] = item
+			continue
+		}
+		if item.JobName < other.JobName || (item.JobName == other.JobName && hash < other.Hash()) {
+			kept[key] = item
+			delete(targets, other.Hash())
+		} else {
+			delete(targets, hash)
+		}
+	}
+
+	tf.log.V(2).Info("Filtering complete", "seen", numTargets, "kept", len(targets))
+	return targets
+}
+
+func (tf *dedupFilter) SetConfig(map[string][]*relabel.Config) {}
+
+func (tf *dedupFilter) GetConfig() map[string][]*relabel.Config {
+	return nil
+}
+
+// endpointKey identifies the endpoint scraped for a target.
+func endpointKey(item *target.Item) string {
+	return strings.Join([]string{item.Labels.Get(schemeLabel), item.TargetURL, item.Labels.Get(metricsPathLabel)}, "|")
+}
@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prehook
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/config"
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/target"
+)
+
+func makeTargets(items ...*target.Item) map[string]*target.Item {
+	targets := make(map[string]*target.Item, len(items))
+	for _, item := range items {
+		targets[item.Hash()] = item
+	}
+	return targets
+}
+
+func TestTargetLimitFilter(t *testing.T) {
+	var items []*target.Item
+	for i := 0; i < 10; i++ {
+		items = append(items, target.NewItem("limited", fmt.Sprintf("10.0.0.%d:8080", i), labels.EmptyLabels(), ""))
+	}
+	unlimited := target.NewItem("unlimited", "10.0.1.0:8080", labels.EmptyLabels(), "")
+	items = append(items, unlimited)
+
+	filter := newTargetLimitFilter(logger, config.FilterConfig{MaxTargetsPerJob: 3}, "")
+	kept := filter.Apply(makeTargets(items...))
+	assert.Len(t, kept, 4)
+	assert.Contains(t, kept, unlimited.Hash())
+
+	// the same targets are kept regardless of the targets dropped
+	again := filter.Apply(makeTargets(items...))
+	assert.Equal(t, kept, again)
+
+	disabled := newTargetLimitFilter(logger, config.FilterConfig{}, "")
+	assert.Len(t, disabled.Apply(makeTargets(items...)), len(items))
+}
+
+func TestTargetLimitFilterPools(t *testing.T) {
+	var items []*target.Item
+	for i := 0; i < 3; i++ {
+		items = append(items, target.NewItem("pooled", fmt.Sprintf("10.0.0.%d:8080", i), labels.EmptyLabels(), ""))
+	}
+
+	first := newTargetLimitFilter(logger, config.FilterConfig{MaxTargetsPerJob: 1}, "first")
+	second := newTargetLimitFilter(logger, config.FilterConfig{MaxTargetsPerJob: 2}, "second")
+	first.Apply(makeTargets(items...))
+	second.Apply(makeTargets(items...))
+
+	// each pool only resets its own series
+	assert.Equal(t, 2.0, testutil.ToFloat64(targetsOverLimit.WithLabelValues("pooled", "first")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(targetsOverLimit.WithLabelValues("pooled", "second")))
+}
+
+func TestNamespaceFilter(t *testing.T) {
+	inNamespace := func(namespace string) *target.Item {
+		return target.NewItem("job", namespace+":8080", labels.FromStrings(namespaceLabel, namespace), "")
+	}
+	static := target.NewItem("job", "static:8080", labels.EmptyLabels(), "")
+
+	for _, tc := range []struct {
+		name     string
+		cfg      config.FilterConfig
+		expected []string
+	}{
+		{
+			name:     "no namespaces",
+			expected: []string{"default", "monitoring", "kube-system"},
+		},
+		{
+			name:     "allowed namespaces",
+			cfg:      config.FilterConfig{AllowedNamespaces: []string{"default", "monitoring"}},
+			expected: []string{"default", "monitoring"},
+		},
+		{
+			name:     "denied namespaces",
+			cfg:      config.FilterConfig{DeniedNamespaces: []string{"kube-system"}},
+			expected: []string{"default", "monitoring"},
+		},
+		{
+			name: "denied namespaces take precedence",
+			cfg: config.FilterConfig{
+				AllowedNamespaces: []string{"default", "monitoring"},
+				DeniedNamespaces:  []string{"monitoring"},
+			},
+			expected: []string{"default"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			targets := makeTargets(inNamespace("default"), inNamespace("monitoring"), inNamespace("kube-system"), static)
+			expected := makeTargets(static)
+			for _, namespace := range tc.expected {
+				item := inNamespace(namespace)
+				expected[item.Hash()] = item
+			}
+
+			filter := newNamespaceFilter(logger, tc.cfg, "")
+			assert.Equal(t, expected, filter.Apply(targets))
+		})
+	}
+}
+
+func TestDedupFilter(t *testing.T) {
+	podLabels := labels.FromStrings("__meta_kubernetes_pod_name", "app")
+	first := target.NewItem("serviceMonitor/default/a/0", "10.0.0.1:8080", podLabels, "")
+	second := target.NewItem("serviceMonitor/default/b/0", "10.0.0.1:8080", podLabels, "")
+	otherPath := target.NewItem("serviceMonitor/default/b/1", "10.0.0.1:8080", labels.FromStrings(metricsPathLabel, "/federate"), "")
+	otherURL := target.NewItem("serviceMonitor/default/b/0", "10.0.0.2:8080", podLabels, "")
+
+	filter := newDedupFilter(logger, config.FilterConfig{}, "")
+	for i := 0; i < 10; i++ {
+		kept := filter.Apply(makeTargets(first, second, otherPath, otherURL))
+		assert.Equal(t, makeTargets(first, otherPath, otherURL), kept)
+	}
+}
@@ -15,31 +15,96 @@
 package prehook
 
 import (
+	"strings"
+
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/prometheus/model/relabel"
 
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/config"
 	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/target"
 )
 
+var (
+	targetsFiltered = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "opentelemetry_allocator_targets_filtered",
+		Help: "Number of targets dropped by each filter.",
+	}, []string{"filter", "pool"})
+)
+
 type Hook interface {
 	Apply(map[string]*target.Item) map[string]*target.Item
 	SetConfig(map[string][]*relabel.Config)
 	GetConfig() map[string][]*relabel.Config
 }
 
-type HookProvider func(log logr.Logger) Hook
+// HookProvider returns a filter. The pool is the name of the pool of collectors the targets are filtered for, which
+// labels the metrics.
+type HookProvider func(log logr.Logger, cfg config.FilterConfig, pool string) Hook
 
 var (
 	registry = map[string]HookProvider{
 		relabelConfigTargetFilterName: newRelabelConfigTargetFilter,
+		targetLimitFilterName:         newTargetLimitFilter,
+		namespaceFilterName:           newNamespaceFilter,
+		dedupFilterName:               newDedupFilter,
 	}
 )
 
-func New(name string, log logr.Logger) Hook {
-	if p, ok := registry[name]; ok {
-		return p(log.WithName("Prehook").WithName(name))
+// New returns the hook for the given filter strategy, which is a comma-separated list of filters applied in order.
+func New(name string, log logr.Logger, cfg config.FilterConfig, pool string) Hook {
+	var filters chain
+	for _, filterName := range strings.Split(name, ",") {
+		filterName = strings.TrimSpace(filterName)
+		p, ok := registry[filterName]
+		if !ok {
+			log.Info("Unrecognized filter strategy; filter skipped", "filter", filterName)
+			continue
+		}
+		filters = append(filters, namedHook{
+			name: filterName,
+			pool: pool,
+			Hook: p(log.WithName("Prehook").WithName(filterName), cfg, pool),
+		})
 	}
 
-	log.Info("Unrecognized filter strategy; filtering disabled")
+	if len(filters) == 0 {
+		log.Info("Unrecognized filter strategy; filtering disabled")
+		return nil
+	}
+	return filters
+}
+
+type namedHook struct {
+	Hook
+	name string
+	pool string
+}
+
+// chain applies its filters in order, each one to the targets kept by the previous one.
+type chain []namedHook
+
+func (c chain) Apply(targets map[string]*target.Item) map[string]*target.Item {
+	for _, f := range c {
+		numTargets := len(targets)
+		targets = f.Apply(targets)
+		targetsFiltered.WithLabelValues(f.name, f.pool).Set(float64(numTargets - len(targets)))
+	}
+	return targets
+}
+
+func (c chain) SetConfig(cfgs map[string][]*relabel.Config) {
+	for _, f := range c {
+		f.SetConfig(cfgs)
+	}
+}
+
+func (c chain) GetConfig() map[string][]*relabel.Config {
+	for _, f := range c {
+		if cfg := f.GetConfig(); cfg != nil {
+			return cfg
+		}
+	}
 	return nil
 }
@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prehook
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/config"
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/target"
+)
+
+func TestNew(t *testing.T) {
+	assert.Nil(t, New("unknown", logger, config.FilterConfig{}, ""))
+	assert.Nil(t, New("", logger, config.FilterConfig{}, ""))
+
+	hook := New("relabel-config, unknown, namespace", logger, config.FilterConfig{}, "")
+	require.IsType(t, chain{}, hook)
+	filters := hook.(chain)
+	require.Len(t, filters, 2)
+	assert.Equal(t, relabelConfigTargetFilterName, filters[0].name)
+	assert.Equal(t, namespaceFilterName, filters[1].name)
+}
+
+func TestChain(t *testing.T) {
+	cfg := config.FilterConfig{
+		MaxTargetsPerJob: 1,
+		DeniedNamespaces: []string{"kube-system"},
+	}
+	hook := New("namespace,dedup,target-limit", logger, cfg, "")
+	require.NotNil(t, hook)
+
+	denied := target.NewItem("a", "10.0.0.1:8080", labels.FromStrings(namespaceLabel, "kube-system"), "")
+	kept := target.NewItem("a", "10.0.0.2:8080", labels.FromStrings(namespaceLabel, "default"), "")
+	duplicate := target.NewItem("b", "10.0.0.2:8080", labels.FromStrings(namespaceLabel, "default"), "")
+	overLimit := target.NewItem("a", "10.0.0.3:8080", labels.FromStrings(namespaceLabel, "default"), "")
+	require.Less(t, kept.Hash(), overLimit.Hash())
+
+	remaining := hook.Apply(makeTargets(denied, kept, duplicate, overLimit))
+	assert.Equal(t, makeTargets(kept), remaining)
+	assert.Equal(t, 1.0, testutil.ToFloat64(targetsFiltered.WithLabelValues(namespaceFilterName, "")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(targetsFiltered.WithLabelValues(dedupFilterName, "")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(targetsFiltered.WithLabelValues(targetLimitFilterName, "")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(targetsOverLimit.WithLabelValues("a", "")))
+
+	// only the relabel-config filter has a relabel config
+	relabelCfg := map[string][]*relabel.Config{"a": nil}
+	hook.SetConfig(relabelCfg)
+	assert.Nil(t, hook.GetConfig())
+	withRelabel := New("namespace,relabel-config", logger, cfg, "")
+	withRelabel.SetConfig(relabelCfg)
+	assert.Equal(t, relabelCfg, withRelabel.GetConfig())
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/prometheus/prometheus/model/relabel"
 
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/config"
 	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/target"
 )
 
@@ -30,7 +31,7 @@ type relabelConfigTargetFilter struct {
 	relabelCfg map[string][]*relabel.Config
 }
 
-func newRelabelConfigTargetFilter(log logr.Logger) Hook {
+func newRelabelConfigTargetFilter(log logr.Logger, _ config.FilterConfig, _ string) Hook {
 	return &relabelConfigTargetFilter{
 		log:        log,
 		relabelCfg: make(map[string][]*relabel.Config),
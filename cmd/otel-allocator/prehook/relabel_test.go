@@ -27,6 +27,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/config"
 	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/target"
 )
 
@@ -212,7 +213,7 @@ func makeNNewTargets(rCfgs []relabelConfigObj, n int, numCollectors int, startin
 }
 
 func TestApply(t *testing.T) {
-	allocatorPrehook := New("relabel-config", logger)
+	allocatorPrehook := New("relabel-config", logger, config.FilterConfig{}, "")
 	assert.NotNil(t, allocatorPrehook)
 
 	targets, numRemaining, expectedTargetMap, relabelCfg := makeNNewTargets(relabelConfigs, defaultNumTargets, defaultNumCollectors, defaultStartIndex)
@@ -235,7 +236,7 @@ func TestApply(t *testing.T) {
 }
 
 func TestApplyHashmodAction(t *testing.T) {
-	allocatorPrehook := New("relabel-config", logger)
+	allocatorPrehook := New("relabel-config", logger, config.FilterConfig{}, "")
 	assert.NotNil(t, allocatorPrehook)
 
 	hashRelabelConfigs := append(relabelConfigs, HashmodConfig)
@@ -248,7 +249,7 @@ func TestApplyHashmodAction(t *testing.T) {
 
 func TestApplyEmptyRelabelCfg(t *testing.T) {
 
-	allocatorPrehook := New("relabel-config", logger)
+	allocatorPrehook := New("relabel-config", logger, config.FilterConfig{}, "")
 	assert.NotNil(t, allocatorPrehook)
 
 	targets, _, _, _ := makeNNewTargets(relabelConfigs, defaultNumTargets, defaultNumCollectors, defaultStartIndex)
@@ -262,7 +263,7 @@ func TestApplyEmptyRelabelCfg(t *testing.T) {
 }
 
 func TestSetConfig(t *testing.T) {
-	allocatorPrehook := New("relabel-config", logger)
+	allocatorPrehook := New("relabel-config", logger, config.FilterConfig{}, "")
 	assert.NotNil(t, allocatorPrehook)
 
 	_, _, _, relabelCfg := makeNNewTargets(relabelConfigs, defaultNumTargets, defaultNumCollectors, defaultStartIndex)
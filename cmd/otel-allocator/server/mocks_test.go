@@ -22,9 +22,10 @@ import (
 var _ allocation.Allocator = &mockAllocator{}
 
 // mockAllocator implements the Allocator interface, but all funcs other than
-// TargetItems() are a no-op.
+// TargetItems() and TargetsOverCapacity() are a no-op.
 type mockAllocator struct {
-	targetItems map[string]*target.Item
+	targetItems         map[string]*target.Item
+	targetsOverCapacity int
 }
 
 func (m *mockAllocator) SetCollectors(_ map[string]*allocation.Collector)               {}
@@ -33,7 +34,34 @@ func (m *mockAllocator) Collectors() map[string]*allocation.Collector
 func (m *mockAllocator) GetTargetsForCollectorAndJob(_ string, _ string) []*target.Item { return nil }
 func (m *mockAllocator) SetFilter(_ allocation.Filter)                                  {}
 func (m *mockAllocator) SetFallbackStrategy(_ allocation.Strategy)                      {}
+func (m *mockAllocator) SetPool(_ string)                                               {}
+func (m *mockAllocator) SetTargetCosts(_ []allocation.TargetCost)                       {}
+func (m *mockAllocator) SetTopology(_ map[string]allocation.Topology)                   {}
+func (m *mockAllocator) SetTargetChangesHandler(_ func(allocation.CollectorChanges))    {}
+func (m *mockAllocator) Decisions() []allocation.Decision                               { return nil }
+func (m *mockAllocator) LastDecision(_ string) (allocation.Decision, bool) {
+	return allocation.Decision{}, false
+}
 
 func (m *mockAllocator) TargetItems() map[string]*target.Item {
 	return m.targetItems
 }
+
+func (m *mockAllocator) TargetItem(targetHash string) (*target.Item, bool) {
+	item, ok := m.targetItems[targetHash]
+	return item, ok
+}
+
+func (m *mockAllocator) TargetItemsForCollector(collector string) map[string]*target.Item {
+	targetItems := make(map[string]*target.Item)
+	for hash, item := range m.targetItems {
+		if item.CollectorName == collector {
+			targetItems[hash] = item
+		}
+	}
+	return targetItems
+}
+
+func (m *mockAllocator) TargetsOverCapacity() int {
+	return m.targetsOverCapacity
+}
@@ -22,6 +22,8 @@ import (
 	"net/http"
 	"net/http/pprof"
 	"net/url"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -39,7 +41,9 @@ import (
 	"gopkg.in/yaml.v2"
 
 	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/allocation"
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/leader"
 	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/target"
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/watcher"
 )
 
 var (
@@ -62,6 +66,10 @@ type collectorJSON struct {
 	Jobs []*targetJSON `json:"targets"`
 }
 
+type capacityJSON struct {
+	TargetsOverCapacity int `json:"targets_over_capacity"`
+}
+
 type linkJSON struct {
 	Link string `json:"_link"`
 }
@@ -71,12 +79,33 @@ type targetJSON struct {
 	Labels    labels.Labels `json:"labels"`
 }
 
+type targetExplanationJSON struct {
+	TargetHash  string               `json:"target_hash"`
+	JobName     string               `json:"job_name"`
+	TargetURL   string               `json:"target"`
+	Labels      labels.Labels        `json:"labels"`
+	Collector   string               `json:"collector"`
+	Explanation string               `json:"explanation"`
+	Decision    *allocation.Decision `json:"decision,omitempty"`
+}
+
+// assignmentTableSource is implemented by allocators which share their assignments with other replicas.
+type assignmentTableSource interface {
+	AssignmentTable(sinceEpoch string, sinceVersion uint64) (*leader.Table, bool)
+}
+
 type Server struct {
 	logger         logr.Logger
 	allocator      allocation.Allocator
 	server         *http.Server
 	httpsServer    *http.Server
 	jsonMarshaller jsoniter.API
+	targets        *targetBroker
+
+	// pathPrefix is the path the routes of a pool are served under, and prefixes the links it returns.
+	pathPrefix string
+	// pools are the servers of the collector pools, when the target allocator allocates targets per pool.
+	pools map[string]*Server
 
 	// Use RWMutex to protect scrapeConfigResponse, since it
 	// will be predominantly read and only written when config
@@ -84,6 +113,7 @@ type Server struct {
 	mtx                                  sync.RWMutex
 	scrapeConfigResponse                 []byte
 	ScrapeConfigMarshalledSecretResponse []byte
+	scrapeConfigProblems                 map[string][]watcher.Problem
 }
 
 type Option func(*Server)
@@ -105,22 +135,71 @@ func (s *Server) setRouter(router *gin.Engine) {
 	router.UnescapePathValues = false
 	router.Use(s.PrometheusMiddleware)
 
-	router.GET("/scrape_configs", s.ScrapeConfigsHandler)
-	router.GET("/jobs", s.JobHandler)
-	router.GET("/jobs/:job_id/targets", s.TargetsHandler)
+	if s.pools == nil {
+		s.setAllocatorRoutes(router)
+	}
+	for _, pool := range s.pools {
+		pool.setAllocatorRoutes(router.Group(pool.pathPrefix))
+	}
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 	router.GET("/livez", s.LivenessProbeHandler)
 	router.GET("/readyz", s.ReadinessProbeHandler)
 	registerPprof(router.Group("/debug/pprof/"))
 }
 
+func (s *Server) setAllocatorRoutes(routes gin.IRoutes) {
+	routes.GET("/scrape_configs", s.ScrapeConfigsHandler)
+	routes.GET("/scrape_configs/problems", s.ScrapeConfigProblemsHandler)
+	routes.GET("/jobs", s.JobHandler)
+	routes.GET("/jobs/:job_id/targets", s.TargetsHandler)
+	routes.GET("/collectors/:collector_id/targets/watch", s.WatchTargetsHandler)
+	routes.POST("/target_costs", s.TargetCostsHandler)
+	routes.GET("/targets/:target_hash", s.TargetExplanationHandler)
+	routes.GET("/debug/allocations", s.AllocationDecisionsHandler)
+	routes.GET("/capacity", s.CapacityHandler)
+	if _, ok := s.allocator.(assignmentTableSource); ok {
+		routes.GET(leader.AssignmentsPath, s.AssignmentsHandler)
+	}
+}
+
 func NewServer(log logr.Logger, allocator allocation.Allocator, listenAddr string, options ...Option) *Server {
+	s := newServer(log, allocator)
+	return s.listen(listenAddr, options...)
+}
+
+// NewPoolsServer creates a server for several pools of collectors, each with its own allocator. The routes of a pool
+// are served under /pools/<name>, such as /pools/<name>/jobs.
+func NewPoolsServer(log logr.Logger, allocators map[string]allocation.Allocator, listenAddr string, options ...Option) *Server {
+	s := newServer(log, nil)
+	s.pools = make(map[string]*Server, len(allocators))
+	for name, allocator := range allocators {
+		pool := newServer(log.WithValues("pool", name), allocator)
+		pool.pathPrefix = "/pools/" + name
+		s.pools[name] = pool
+	}
+	return s.listen(listenAddr, options...)
+}
+
+// Pool returns the server of a pool created by NewPoolsServer, which holds the scrape configs of the pool.
+func (s *Server) Pool(name string) (*Server, bool) {
+	pool, ok := s.pools[name]
+	return pool, ok
+}
+
+func newServer(log logr.Logger, allocator allocation.Allocator) *Server {
 	s := &Server{
 		logger:         log,
 		allocator:      allocator,
 		jsonMarshaller: jsonConfig,
+		targets:        newTargetBroker(),
 	}
+	if allocator != nil {
+		allocator.SetTargetChangesHandler(s.targets.publish)
+	}
+	return s
+}
 
+func (s *Server) listen(listenAddr string, options ...Option) *Server {
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
 	s.setRouter(router)
@@ -141,6 +220,7 @@ func (s *Server) Start() error {
 
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.Info("Shutting down server...")
+	s.closeTargets()
 	return s.server.Shutdown(ctx)
 }
 
@@ -151,9 +231,17 @@ func (s *Server) StartHTTPS() error {
 
 func (s *Server) ShutdownHTTPS(ctx context.Context) error {
 	s.logger.Info("Shutting down HTTPS server...")
+	s.closeTargets()
 	return s.httpsServer.Shutdown(ctx)
 }
 
+func (s *Server) closeTargets() {
+	s.targets.close()
+	for _, pool := range s.pools {
+		pool.targets.close()
+	}
+}
+
 // RemoveRegexFromRelabelAction is needed specifically for keepequal/dropequal actions because even though the user doesn't specify the
 // regex field for these actions the unmarshalling implementations of prometheus adds back the default regex fields
 // which in turn causes the receiver to error out since the unmarshaling of the json response doesn't expect anything in the regex fields
@@ -198,10 +286,15 @@ func RemoveRegexFromRelabelAction(jsonConfig []byte) ([]byte, error) {
 	return jsonConfigNew, nil
 }
 
+// marshalMtx serializes marshaling scrape configs, since whether secrets are marshaled is a global setting and the
+// servers of pools marshal their scrape configs concurrently.
+var marshalMtx sync.Mutex
+
 func (s *Server) MarshalScrapeConfig(configs map[string]*promconfig.ScrapeConfig, marshalSecretValue bool) error {
+	marshalMtx.Lock()
 	promcommconfig.MarshalSecretValue = marshalSecretValue
-
 	configBytes, err := yaml.Marshal(configs)
+	marshalMtx.Unlock()
 	if err != nil {
 		return err
 	}
@@ -260,22 +353,79 @@ func (s *Server) ScrapeConfigsHandler(c *gin.Context) {
 	}
 }
 
-func (s *Server) ReadinessProbeHandler(c *gin.Context) {
+// UpdateScrapeConfigProblems updates the problems found in the scrape configs generated for Prometheus CRs.
+func (s *Server) UpdateScrapeConfigProblems(problems map[string][]watcher.Problem) {
+	s.mtx.Lock()
+	s.scrapeConfigProblems = problems
+	s.mtx.Unlock()
+}
+
+// ScrapeConfigProblemsHandler returns the problems found in the scrape configs by Prometheus CR.
+func (s *Server) ScrapeConfigProblemsHandler(c *gin.Context) {
 	s.mtx.RLock()
-	result := s.scrapeConfigResponse
+	problems := s.scrapeConfigProblems
 	s.mtx.RUnlock()
 
-	if result != nil {
+	if problems == nil {
+		problems = map[string][]watcher.Problem{}
+	}
+	s.jsonHandler(c.Writer, problems)
+}
+
+func (s *Server) ReadinessProbeHandler(c *gin.Context) {
+	ready, reason := s.ready()
+	if ready {
 		c.Status(http.StatusOK)
-	} else {
+		return
+	}
+	if reason == "" {
 		c.Status(http.StatusServiceUnavailable)
+		return
 	}
+	c.Writer.WriteHeader(http.StatusServiceUnavailable)
+	s.jsonHandler(c.Writer, reason)
+}
+
+// ready returns whether the server is ready to serve assignments and, if known, why it isn't. A server with pools is
+// ready once all of its pools are.
+func (s *Server) ready() (bool, string) {
+	if s.pools != nil {
+		names := make([]string, 0, len(s.pools))
+		for name := range s.pools {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if ready, reason := s.pools[name].ready(); !ready {
+				if reason == "" {
+					reason = "scrape configs not loaded yet"
+				}
+				return false, fmt.Sprintf("pool %s: %s", name, reason)
+			}
+		}
+		return true, ""
+	}
+
+	s.mtx.RLock()
+	result := s.scrapeConfigResponse
+	s.mtx.RUnlock()
+
+	if result == nil {
+		return false, ""
+	}
+	return true, ""
+}
+
+// CapacityHandler returns how many targets exceed the total capacity of the collectors, and are left unassigned
+// until the collectors are scaled up. Unlike readiness, it doesn't tell whether the server can serve assignments.
+func (s *Server) CapacityHandler(c *gin.Context) {
+	s.jsonHandler(c.Writer, capacityJSON{TargetsOverCapacity: s.allocator.TargetsOverCapacity()})
 }
 
 func (s *Server) JobHandler(c *gin.Context) {
 	displayData := make(map[string]linkJSON)
 	for _, v := range s.allocator.TargetItems() {
-		displayData[v.JobName] = linkJSON{Link: fmt.Sprintf("/jobs/%s/targets", url.QueryEscape(v.JobName))}
+		displayData[v.JobName] = linkJSON{Link: fmt.Sprintf("%s/jobs/%s/targets", s.pathPrefix, url.QueryEscape(v.JobName))}
 	}
 	s.jsonHandler(c.Writer, displayData)
 }
@@ -302,7 +452,7 @@ func (s *Server) TargetsHandler(c *gin.Context) {
 	}
 
 	if len(q) == 0 {
-		displayData := GetAllTargetsByJob(s.allocator, jobId)
+		displayData := getAllTargetsByJob(s.allocator, jobId, s.pathPrefix)
 		s.jsonHandler(c.Writer, displayData)
 	} else {
 		targets := GetAllTargetsByCollectorAndJob(s.allocator, q[0], jobId)
@@ -316,6 +466,151 @@ func (s *Server) TargetsHandler(c *gin.Context) {
 
 }
 
+// WatchTargetsHandler streams the changes of the targets assigned to a collector as newline-delimited JSON events,
+// so that the collector doesn't have to poll for them. Given the version of the last event it received, the collector
+// resumes from there if the changes since then are still retained. Otherwise, the stream starts with a snapshot of its
+// targets, which may already include some of the changes that follow; additions and removals apply as set operations.
+func (s *Server) WatchTargetsHandler(c *gin.Context) {
+	collectorName := c.Params.ByName("collector_id")
+	w, resumed, err := s.targets.watch(collectorName, c.Query("version"))
+	if err != nil {
+		s.badRequestHandler(c.Writer, err)
+		return
+	}
+	defer s.targets.unwatch(w)
+
+	c.Writer.Header().Set("Content-Type", "application/x-ndjson")
+	c.Status(http.StatusOK)
+	if !w.resumed {
+		snapshot := s.allocator.TargetItemsForCollector(collectorName)
+		if !s.sendWatchEvent(c, watchEventJSON{Type: watchEventSnapshot, Version: s.targets.formatVersion(w.from), Added: targetsByJob(snapshot)}) {
+			return
+		}
+	}
+	for _, changes := range resumed {
+		if !s.sendChanges(c, collectorName, changes) {
+			return
+		}
+	}
+
+	ticker := time.NewTicker(bookmarkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case changes, ok := <-w.changes:
+			if !ok || !s.sendChanges(c, collectorName, changes) {
+				return
+			}
+		case <-ticker.C:
+			if version, ok := s.targets.bookmark(w); ok && !s.sendWatchEvent(c, watchEventJSON{Type: watchEventBookmark, Version: version}) {
+				return
+			}
+		}
+	}
+}
+
+func (s *Server) sendChanges(c *gin.Context, collectorName string, changes versionedChanges) bool {
+	collectorChanges := changes.changes[collectorName]
+	return s.sendWatchEvent(c, watchEventJSON{
+		Type:    watchEventChanges,
+		Version: s.targets.formatVersion(changes.version),
+		Added:   targetsByJob(collectorChanges.Additions()),
+		Removed: targetsByJob(collectorChanges.Removals()),
+	})
+}
+
+func (s *Server) sendWatchEvent(c *gin.Context, event watchEventJSON) bool {
+	if err := s.jsonMarshaller.NewEncoder(c.Writer).Encode(event); err != nil {
+		s.logger.V(1).Info("Failed to send a watch event", "error", err.Error())
+		return false
+	}
+	c.Writer.Flush()
+	return true
+}
+
+// AllocationDecisionsHandler returns the latest allocation decisions, oldest first, to debug targets moving between
+// collectors.
+func (s *Server) AllocationDecisionsHandler(c *gin.Context) {
+	s.jsonHandler(c.Writer, s.allocator.Decisions())
+}
+
+// TargetExplanationHandler explains why a target, given by its hash as found in allocation decisions, is assigned to
+// its current collector.
+func (s *Server) TargetExplanationHandler(c *gin.Context) {
+	hash, err := url.QueryUnescape(c.Params.ByName("target_hash"))
+	if err != nil {
+		s.errorHandler(c.Writer, err)
+		return
+	}
+	item, ok := s.allocator.TargetItem(hash)
+	if !ok {
+		c.Writer.WriteHeader(http.StatusNotFound)
+		s.jsonHandler(c.Writer, fmt.Sprintf("unknown target %s", hash))
+		return
+	}
+
+	explanation := targetExplanationJSON{
+		TargetHash:  hash,
+		JobName:     item.JobName,
+		TargetURL:   item.TargetURL,
+		Labels:      item.Labels,
+		Collector:   item.CollectorName,
+		Explanation: "No allocation decision is known for this target.",
+	}
+	if decision, ok := s.allocator.LastDecision(hash); ok {
+		explanation.Decision = &decision
+		explanation.Explanation = decision.Explain()
+	}
+	s.jsonHandler(c.Writer, explanation)
+}
+
+// TargetCostsHandler accepts the cost of scraping targets, such as their series count, reported by the collectors.
+// Strategies such as cost-weighted use it to balance the load of the collectors.
+func (s *Server) TargetCostsHandler(c *gin.Context) {
+	var costs []allocation.TargetCost
+	if err := s.jsonMarshaller.NewDecoder(c.Request.Body).Decode(&costs); err != nil {
+		s.badRequestHandler(c.Writer, fmt.Errorf("failed to decode target costs: %w", err))
+		return
+	}
+
+	for i, cost := range costs {
+		if cost.JobName == "" || cost.TargetURL == "" {
+			s.badRequestHandler(c.Writer, fmt.Errorf("target cost %d: job_name and target are required", i))
+			return
+		}
+		if cost.Cost < 0 {
+			s.badRequestHandler(c.Writer, fmt.Errorf("target cost %d: cost should not be negative: %v", i, cost.Cost))
+			return
+		}
+	}
+
+	s.allocator.SetTargetCosts(costs)
+	c.Status(http.StatusNoContent)
+}
+
+// AssignmentsHandler returns the assignment table of this replica to the other replicas, unless it hasn't changed
+// since the version and epoch they already have.
+func (s *Server) AssignmentsHandler(c *gin.Context) {
+	since, err := strconv.ParseUint(c.DefaultQuery("version", "0"), 10, 64)
+	if err != nil {
+		s.badRequestHandler(c.Writer, fmt.Errorf("invalid version: %w", err))
+		return
+	}
+	table, modified := s.allocator.(assignmentTableSource).AssignmentTable(c.Query("epoch"), since)
+	if !modified {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	s.jsonHandler(c.Writer, table)
+}
+
+func (s *Server) badRequestHandler(w http.ResponseWriter, err error) {
+	w.WriteHeader(http.StatusBadRequest)
+	s.jsonHandler(w, err.Error())
+}
+
 func (s *Server) errorHandler(w http.ResponseWriter, err error) {
 	w.WriteHeader(http.StatusInternalServerError)
 	s.jsonHandler(w, err)
@@ -331,11 +626,15 @@ func (s *Server) jsonHandler(w http.ResponseWriter, data interface{}) {
 
 // GetAllTargetsByJob is a relatively expensive call that is usually only used for debugging purposes.
 func GetAllTargetsByJob(allocator allocation.Allocator, job string) map[string]collectorJSON {
+	return getAllTargetsByJob(allocator, job, "")
+}
+
+func getAllTargetsByJob(allocator allocation.Allocator, job string, pathPrefix string) map[string]collectorJSON {
 	displayData := make(map[string]collectorJSON)
 	for _, col := range allocator.Collectors() {
 		targets := GetAllTargetsByCollectorAndJob(allocator, col.Name, job)
 		displayData[col.Name] = collectorJSON{
-			Link: fmt.Sprintf("/jobs/%s/targets?collector_id=%s", url.QueryEscape(job), col.Name),
+			Link: fmt.Sprintf("%s/jobs/%s/targets?collector_id=%s", pathPrefix, url.QueryEscape(job), col.Name),
 			Jobs: targets,
 		}
 	}
@@ -22,6 +22,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -37,7 +38,9 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/allocation"
 	allocatorconfig "github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/config"
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/leader"
 	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/target"
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/watcher"
 )
 
 var (
@@ -617,12 +620,230 @@ func TestServer_JobHandler(t *testing.T) {
 		})
 	}
 }
-func TestServer_Readiness(t *testing.T) {
+func TestServer_TargetCostsHandler(t *testing.T) {
 	tests := []struct {
 		description   string
-		scrapeConfigs map[string]*promconfig.ScrapeConfig
+		body          string
 		expectedCode  int
-		expectedBody  []byte
+		expectedCosts map[string]float64
+	}{
+		{
+			description:   "costs of known targets",
+			body:          `[{"job_name": "test-job", "target": "test-url", "cost": 120}, {"job_name": "test-job", "target": "unknown-url", "cost": 10}]`,
+			expectedCode:  http.StatusNoContent,
+			expectedCosts: map[string]float64{"test-collector": 120, "test-collector2": 120},
+		},
+		{
+			description:   "negative cost",
+			body:          `[{"job_name": "test-job", "target": "test-url", "cost": -1}]`,
+			expectedCode:  http.StatusBadRequest,
+			expectedCosts: map[string]float64{"test-collector": 1, "test-collector2": 1},
+		},
+		{
+			description:   "missing target",
+			body:          `[{"job_name": "test-job", "cost": 1}]`,
+			expectedCode:  http.StatusBadRequest,
+			expectedCosts: map[string]float64{"test-collector": 1, "test-collector2": 1},
+		},
+		{
+			description:   "invalid body",
+			body:          `{"job_name": "test-job"`,
+			expectedCode:  http.StatusBadRequest,
+			expectedCosts: map[string]float64{"test-collector": 1, "test-collector2": 1},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			costWeighted, err := allocation.New("cost-weighted", logger)
+			require.NoError(t, err)
+			costWeighted.SetCollectors(map[string]*allocation.Collector{
+				"test-collector":  {Name: "test-collector"},
+				"test-collector2": {Name: "test-collector2"},
+			})
+			first := target.NewItem("test-job", "test-url", baseLabelSet, "")
+			second := target.NewItem("test-job", "test-url2", testJobLabelSetTwo, "")
+			costWeighted.SetTargets(map[string]*target.Item{first.Hash(): first, second.Hash(): second})
+			s := NewServer(logger, costWeighted, ":8080")
+
+			request := httptest.NewRequest("POST", "/target_costs", strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+
+			s.server.Handler.ServeHTTP(w, request)
+			result := w.Result()
+
+			assert.Equal(t, tc.expectedCode, result.StatusCode)
+			costs := map[string]float64{}
+			for name, col := range costWeighted.Collectors() {
+				costs[name] = col.Cost
+			}
+			assert.Equal(t, tc.expectedCosts, costs)
+		})
+	}
+}
+
+func TestServer_AssignmentsHandler(t *testing.T) {
+	leastWeighted, err := allocation.New("least-weighted", logger)
+	require.NoError(t, err)
+
+	// without leader election, the assignments aren't shared
+	s := NewServer(logger, leastWeighted, ":8080")
+	w := httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(w, httptest.NewRequest("GET", leader.AssignmentsPath, nil))
+	assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+
+	replicated := leader.NewAllocator(logger, leastWeighted, leadership{}, time.Second)
+	replicated.SetCollectors(map[string]*allocation.Collector{"test-collector": {Name: "test-collector"}})
+	replicated.SetTargets(map[string]*target.Item{baseTargetItem.Hash(): baseTargetItem})
+	s = NewServer(logger, replicated, ":8080")
+
+	w = httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(w, httptest.NewRequest("GET", leader.AssignmentsPath, nil))
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	var table leader.Table
+	require.NoError(t, json.NewDecoder(w.Result().Body).Decode(&table))
+	assert.Equal(t, []leader.TableCollector{{Name: "test-collector"}}, table.Collectors)
+	assert.Equal(t, []leader.TableTarget{{JobName: "test-job", TargetURL: "test-url", Labels: baseLabelSet, CollectorName: "test-collector"}}, table.Targets)
+
+	w = httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("%s?epoch=%s&version=%d", leader.AssignmentsPath, table.Epoch, table.Version), nil))
+	assert.Equal(t, http.StatusNotModified, w.Result().StatusCode)
+
+	// the same version of another epoch is outdated
+	w = httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("%s?epoch=other&version=%d", leader.AssignmentsPath, table.Version), nil))
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	w = httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(w, httptest.NewRequest("GET", leader.AssignmentsPath+"?version=abc", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+}
+
+type leadership struct{}
+
+func (leadership) IsLeader() bool { return true }
+
+func (leadership) Leader() string { return "" }
+
+func TestServer_WatchTargetsHandler(t *testing.T) {
+	leastWeighted, err := allocation.New("least-weighted", logger)
+	require.NoError(t, err)
+	s := NewServer(logger, leastWeighted, ":8080")
+	ts := httptest.NewServer(s.server.Handler)
+	defer ts.Close()
+
+	first := target.NewItem("test-job", "test-url", baseLabelSet, "")
+	second := target.NewItem("test-job", "test-url2", testJobLabelSetTwo, "")
+	leastWeighted.SetCollectors(map[string]*allocation.Collector{"test-collector": {Name: "test-collector"}})
+	leastWeighted.SetTargets(map[string]*target.Item{first.Hash(): first})
+
+	watch := func(version string) (*http.Response, *json.Decoder) {
+		resp, err := http.Get(ts.URL + "/collectors/test-collector/targets/watch?version=" + url.QueryEscape(version))
+		require.NoError(t, err)
+		return resp, json.NewDecoder(resp.Body)
+	}
+	next := func(events *json.Decoder) watchEventJSON {
+		var event watchEventJSON
+		require.NoError(t, events.Decode(&event))
+		return event
+	}
+
+	resp, events := watch("")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+	snapshot := next(events)
+	assert.Equal(t, watchEventSnapshot, snapshot.Type)
+	assert.Equal(t, map[string][]*targetJSON{"test-job": {{TargetURL: []string{"test-url"}, Labels: baseLabelSet}}}, snapshot.Added)
+
+	leastWeighted.SetTargets(map[string]*target.Item{second.Hash(): second})
+	changes := next(events)
+	assert.Equal(t, watchEventChanges, changes.Type)
+	assert.Equal(t, map[string][]*targetJSON{"test-job": {{TargetURL: []string{"test-url2"}, Labels: testJobLabelSetTwo}}}, changes.Added)
+	assert.Equal(t, map[string][]*targetJSON{"test-job": {{TargetURL: []string{"test-url"}, Labels: baseLabelSet}}}, changes.Removed)
+	require.NoError(t, resp.Body.Close())
+
+	// resuming from the snapshot replays the changes since then
+	resp, events = watch(snapshot.Version)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, changes, next(events))
+
+	// the stream ends on shutdown
+	s.targets.close()
+	var event watchEventJSON
+	assert.ErrorIs(t, events.Decode(&event), io.EOF)
+	require.NoError(t, resp.Body.Close())
+
+	resp, _ = watch("abc")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+}
+
+func TestServer_AllocationDecisions(t *testing.T) {
+	leastWeighted, err := allocation.New("least-weighted", logger)
+	require.NoError(t, err)
+	s := NewServer(logger, leastWeighted, ":8080")
+
+	item := target.NewItem("serviceMonitor/test/test/0", "test-url", baseLabelSet, "")
+	leastWeighted.SetCollectors(map[string]*allocation.Collector{"test-collector": {Name: "test-collector"}})
+	leastWeighted.SetTargets(map[string]*target.Item{item.Hash(): item})
+
+	w := httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(w, httptest.NewRequest("GET", "/debug/allocations", nil))
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	var decisions []allocation.Decision
+	require.NoError(t, json.NewDecoder(w.Result().Body).Decode(&decisions))
+	require.Len(t, decisions, 1)
+	assert.Equal(t, item.Hash(), decisions[0].TargetHash)
+	assert.Equal(t, "test-collector", decisions[0].To)
+	assert.Equal(t, allocation.CauseTargetAdded, decisions[0].Cause)
+
+	w = httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(w, httptest.NewRequest("GET", "/targets/"+url.QueryEscape(item.Hash()), nil))
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	var explanation targetExplanationJSON
+	require.NoError(t, json.NewDecoder(w.Result().Body).Decode(&explanation))
+	assert.Equal(t, "test-collector", explanation.Collector)
+	assert.Equal(t, "serviceMonitor/test/test/0", explanation.JobName)
+	assert.Equal(t, baseLabelSet, explanation.Labels)
+	assert.Contains(t, explanation.Explanation, "Assigned to test-collector")
+	require.NotNil(t, explanation.Decision)
+	assert.Equal(t, decisions[0].Cause, explanation.Decision.Cause)
+
+	w = httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(w, httptest.NewRequest("GET", "/targets/unknown", nil))
+	assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+}
+
+func TestServer_ScrapeConfigProblems(t *testing.T) {
+	s := NewServer(logger, nil, ":8080")
+
+	w := httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(w, httptest.NewRequest("GET", "/scrape_configs/problems", nil))
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	body, err := io.ReadAll(w.Result().Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, "{}", string(body))
+
+	problems := map[string][]watcher.Problem{
+		"serviceMonitor/test/test": {
+			{JobName: "serviceMonitor/test/test/0", Reason: watcher.ProblemDropsAllTargets, Message: "relabeling 0 drops every target"},
+		},
+	}
+	s.UpdateScrapeConfigProblems(problems)
+	w = httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(w, httptest.NewRequest("GET", "/scrape_configs/problems", nil))
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	var got map[string][]watcher.Problem
+	require.NoError(t, json.NewDecoder(w.Result().Body).Decode(&got))
+	assert.Equal(t, problems, got)
+}
+
+func TestServer_Readiness(t *testing.T) {
+	tests := []struct {
+		description         string
+		scrapeConfigs       map[string]*promconfig.ScrapeConfig
+		targetsOverCapacity int
+		expectedCode        int
+		expectedBody        []byte
 	}{
 		{
 			description:   "nil scrape config",
@@ -661,11 +882,17 @@ func TestServer_Readiness(t *testing.T) {
 			},
 			expectedCode: http.StatusOK,
 		},
+		{
+			description:         "targets over capacity",
+			scrapeConfigs:       map[string]*promconfig.ScrapeConfig{},
+			targetsOverCapacity: 10,
+			expectedCode:        http.StatusOK,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.description, func(t *testing.T) {
 			listenAddr := ":8080"
-			s := NewServer(logger, nil, listenAddr)
+			s := NewServer(logger, &mockAllocator{targetsOverCapacity: tc.targetsOverCapacity}, listenAddr)
 			if tc.scrapeConfigs != nil {
 				assert.NoError(t, s.UpdateScrapeConfigResponse(tc.scrapeConfigs))
 			}
@@ -681,6 +908,21 @@ func TestServer_Readiness(t *testing.T) {
 	}
 }
 
+func TestServer_CapacityHandler(t *testing.T) {
+	allocator := &mockAllocator{}
+	s := NewServer(logger, allocator, ":8080")
+
+	for _, overCapacity := range []int{0, 10} {
+		allocator.targetsOverCapacity = overCapacity
+		w := httptest.NewRecorder()
+		s.server.Handler.ServeHTTP(w, httptest.NewRequest("GET", "/capacity", nil))
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		body, err := io.ReadAll(w.Result().Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, fmt.Sprintf(`{"targets_over_capacity": %d}`, overCapacity), string(body))
+	}
+}
+
 func TestServer_ScrapeConfigRespose(t *testing.T) {
 	tests := []struct {
 		description  string
@@ -741,3 +983,67 @@ func TestServer_ScrapeConfigRespose(t *testing.T) {
 func newLink(jobName string) linkJSON {
 	return linkJSON{Link: fmt.Sprintf("/jobs/%s/targets", url.QueryEscape(jobName))}
 }
+
+func TestServer_Pools(t *testing.T) {
+	teamA, err := allocation.New("least-weighted", logger)
+	require.NoError(t, err)
+	teamB, err := allocation.New("consistent-hashing", logger)
+	require.NoError(t, err)
+	teamA.SetCollectors(map[string]*allocation.Collector{"collector-a": allocation.NewCollector("collector-a", "")})
+	teamB.SetCollectors(map[string]*allocation.Collector{"collector-b": allocation.NewCollector("collector-b", "")})
+	teamA.SetTargets(map[string]*target.Item{baseTargetItem.Hash(): baseTargetItem})
+	teamB.SetTargets(map[string]*target.Item{testJobTargetItemTwo.Hash(): testJobTargetItemTwo})
+
+	s := NewPoolsServer(logger, map[string]allocation.Allocator{"team-a": teamA, "team-b": teamB}, ":8080")
+	get := func(path string) *http.Response {
+		w := httptest.NewRecorder()
+		s.server.Handler.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w.Result()
+	}
+
+	result := get("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, result.StatusCode)
+	body, err := io.ReadAll(result.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `"pool team-a: scrape configs not loaded yet"`, string(body))
+
+	for name, jobName := range map[string]string{"team-a": "job-a", "team-b": "job-b"} {
+		pool, ok := s.Pool(name)
+		require.True(t, ok)
+		require.NoError(t, pool.UpdateScrapeConfigResponse(map[string]*promconfig.ScrapeConfig{jobName: {JobName: jobName}}))
+	}
+	_, ok := s.Pool("team-c")
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusOK, get("/readyz").StatusCode)
+
+	result = get("/pools/team-b/scrape_configs")
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	scrapeConfigs := map[string]*promconfig.ScrapeConfig{}
+	body, err = io.ReadAll(result.Body)
+	require.NoError(t, err)
+	require.NoError(t, yaml.Unmarshal(body, scrapeConfigs))
+	assert.Contains(t, scrapeConfigs, "job-b")
+	assert.NotContains(t, scrapeConfigs, "job-a")
+
+	result = get("/pools/team-a/jobs")
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	jobs := map[string]linkJSON{}
+	require.NoError(t, json.NewDecoder(result.Body).Decode(&jobs))
+	assert.Equal(t, map[string]linkJSON{"test-job": {Link: "/pools/team-a/jobs/test-job/targets"}}, jobs)
+
+	result = get("/pools/team-b/jobs/test-job/targets?collector_id=collector-b")
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	var targets []*targetJSON
+	require.NoError(t, json.NewDecoder(result.Body).Decode(&targets))
+	require.Len(t, targets, 1)
+	assert.Equal(t, []string{"test-url2"}, targets[0].TargetURL)
+
+	result = get("/pools/team-a/jobs/test-job/targets")
+	collectors := map[string]collectorJSON{}
+	require.NoError(t, json.NewDecoder(result.Body).Decode(&collectors))
+	assert.Equal(t, "/pools/team-a/jobs/test-job/targets?collector_id=collector-a", collectors["collector-a"].Link)
+	assert.NotContains(t, collectors, "collector-b")
+
+	assert.Equal(t, http.StatusNotFound, get("/pools/team-c/jobs").StatusCode)
+	assert.Equal(t, http.StatusNotFound, get("/jobs").StatusCode)
+}
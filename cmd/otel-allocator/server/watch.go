@@ -0,0 +1,229 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/allocation"
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/target"
+)
+
+const (
+	// maxRetainedChanges is how many changes of the assignments are kept for collectors resuming their watch.
+	maxRetainedChanges = 1024
+	// watchBufferSize is how many changes a watch may fall behind before it's closed, after which the collector
+	// resumes it.
+	watchBufferSize = 256
+	// bookmarkInterval is how often idle watches are sent the current version, which also keeps the connection alive.
+	bookmarkInterval = 30 * time.Second
+)
+
+const (
+	watchEventSnapshot = "SNAPSHOT"
+	watchEventChanges  = "CHANGES"
+	watchEventBookmark = "BOOKMARK"
+)
+
+var (
+	targetWatches = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "opentelemetry_allocator_target_watches",
+		Help: "Number of collectors watching their targets.",
+	})
+)
+
+var errInvalidVersion = errors.New("invalid version")
+
+// watchEventJSON is an event of a watch of the targets of a collector. A snapshot holds all the targets of the
+// collector as additions, keyed by job name.
+type watchEventJSON struct {
+	Type    string                   `json:"type"`
+	Version string                   `json:"version"`
+	Added   map[string][]*targetJSON `json:"added,omitempty"`
+	Removed map[string][]*targetJSON `json:"removed,omitempty"`
+}
+
+type versionedChanges struct {
+	version uint64
+	changes allocation.CollectorChanges
+}
+
+// targetBroker keeps the recent changes of the assignments and passes them on to the collectors watching them.
+// Versions are only meaningful to the broker that issued them, so they're prefixed with its id.
+type targetBroker struct {
+	id string
+
+	m       sync.Mutex
+	version uint64
+	// oldest is the oldest version watches can resume from, every later change being retained
+	oldest  uint64
+	history []versionedChanges
+	watches map[*targetWatch]struct{}
+	closed  bool
+}
+
+type targetWatch struct {
+	collector string
+	changes   chan versionedChanges
+	// resumed tells whether the watch resumes from the version of the collector, otherwise it starts from a snapshot
+	// taken after the version from
+	resumed bool
+	from    uint64
+}
+
+func newTargetBroker() *targetBroker {
+	return &targetBroker{
+		id:      strconv.FormatInt(time.Now().UnixNano(), 36),
+		watches: make(map[*targetWatch]struct{}),
+	}
+}
+
+// publish retains the changes and passes them on to the watches of the collectors they concern. Nil changes close
+// all watches and drop the retained changes, so that the collectors start over from a snapshot.
+func (b *targetBroker) publish(changes allocation.CollectorChanges) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	b.version++
+	if changes == nil {
+		b.history = nil
+		b.oldest = b.version
+		for w := range b.watches {
+			b.stop(w)
+		}
+		return
+	}
+
+	b.history = append(b.history, versionedChanges{version: b.version, changes: changes})
+	if len(b.history) > maxRetainedChanges {
+		b.oldest = b.history[0].version
+		b.history = b.history[1:]
+	}
+	for w := range b.watches {
+		if _, ok := changes[w.collector]; !ok {
+			continue
+		}
+		select {
+		case w.changes <- versionedChanges{version: b.version, changes: changes}:
+		default:
+			// the watch fell behind, the collector resumes it from the retained changes or a snapshot
+			b.stop(w)
+		}
+	}
+}
+
+// watch starts a watch of the targets of the collector. If the collector can resume from the given version, the
+// retained changes since then are returned.
+func (b *targetBroker) watch(collector, version string) (*targetWatch, []versionedChanges, error) {
+	since, resumable, err := b.parseVersion(version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	b.m.Lock()
+	defer b.m.Unlock()
+	w := &targetWatch{collector: collector, changes: make(chan versionedChanges, watchBufferSize), from: b.version}
+	if b.closed {
+		close(w.changes)
+		return w, nil, nil
+	}
+	b.watches[w] = struct{}{}
+	targetWatches.Inc()
+	if !resumable || since < b.oldest || since > b.version {
+		return w, nil, nil
+	}
+	w.resumed, w.from = true, since
+	var resumed []versionedChanges
+	for _, c := range b.history {
+		if _, ok := c.changes[collector]; ok && c.version > since {
+			resumed = append(resumed, c)
+		}
+	}
+	return w, resumed, nil
+}
+
+// unwatch stops the watch, unless the broker already did.
+func (b *targetBroker) unwatch(w *targetWatch) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	if _, ok := b.watches[w]; ok {
+		b.stop(w)
+	}
+}
+
+// bookmark returns the current version, unless the watch hasn't caught up with it yet.
+func (b *targetBroker) bookmark(w *targetWatch) (string, bool) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	if len(w.changes) > 0 {
+		return "", false
+	}
+	return b.formatVersion(b.version), true
+}
+
+// close stops all watches, which are otherwise never done.
+func (b *targetBroker) close() {
+	b.m.Lock()
+	defer b.m.Unlock()
+	b.closed = true
+	for w := range b.watches {
+		b.stop(w)
+	}
+}
+
+func (b *targetBroker) stop(w *targetWatch) {
+	delete(b.watches, w)
+	close(w.changes)
+	targetWatches.Dec()
+}
+
+func (b *targetBroker) formatVersion(version uint64) string {
+	return fmt.Sprintf("%s.%d", b.id, version)
+}
+
+// parseVersion parses a version issued by a broker, telling whether it was issued by this one.
+func (b *targetBroker) parseVersion(version string) (uint64, bool, error) {
+	if version == "" {
+		return 0, false, nil
+	}
+	id, number, found := strings.Cut(version, ".")
+	if !found {
+		return 0, false, fmt.Errorf("%w: %q", errInvalidVersion, version)
+	}
+	n, err := strconv.ParseUint(number, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("%w: %q", errInvalidVersion, version)
+	}
+	return n, id == b.id, nil
+}
+
+// targetsByJob groups the target items by job name.
+func targetsByJob(items map[string]*target.Item) map[string][]*targetJSON {
+	if len(items) == 0 {
+		return nil
+	}
+	targets := make(map[string][]*targetJSON)
+	for _, item := range items {
+		targets[item.JobName] = append(targets[item.JobName], targetJsonFromTargetItem(item))
+	}
+	return targets
+}
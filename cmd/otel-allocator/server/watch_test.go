@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/allocation"
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/diff"
+	"github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/target"
+)
+
+func changesFor(collectorNames ...string) allocation.CollectorChanges {
+	item := target.NewItem("test-job", "test-url", baseLabelSet, "")
+	changes := make(allocation.CollectorChanges)
+	for _, name := range collectorNames {
+		changes[name] = diff.NewChanges(map[string]*target.Item{item.Hash(): item}, map[string]*target.Item{})
+	}
+	return changes
+}
+
+func Test_targetBroker(t *testing.T) {
+	t.Run("resumes from a retained version", func(t *testing.T) {
+		b := newTargetBroker()
+		b.publish(changesFor("collector-0"))
+		b.publish(changesFor("collector-1"))
+		b.publish(changesFor("collector-0", "collector-1"))
+
+		w, resumed, err := b.watch("collector-0", b.formatVersion(1))
+		require.NoError(t, err)
+		defer b.unwatch(w)
+		assert.True(t, w.resumed)
+		assert.Equal(t, uint64(1), w.from)
+		require.Len(t, resumed, 1)
+		assert.Equal(t, uint64(3), resumed[0].version)
+
+		// only the changes of the collector are passed on
+		b.publish(changesFor("collector-1"))
+		b.publish(changesFor("collector-0"))
+		require.Len(t, w.changes, 1)
+		assert.Equal(t, uint64(5), (<-w.changes).version)
+	})
+
+	t.Run("needs a snapshot otherwise", func(t *testing.T) {
+		b := newTargetBroker()
+		for i := 0; i < maxRetainedChanges+1; i++ {
+			b.publish(changesFor("collector-0"))
+		}
+		for _, version := range []string{"", b.formatVersion(0), "other.1", b.formatVersion(maxRetainedChanges + 2)} {
+			w, resumed, err := b.watch("collector-0", version)
+			require.NoError(t, err)
+			assert.False(t, w.resumed, version)
+			assert.Nil(t, resumed, version)
+			assert.Equal(t, uint64(maxRetainedChanges+1), w.from, version)
+			b.unwatch(w)
+		}
+
+		w, resumed, err := b.watch("collector-0", b.formatVersion(1))
+		require.NoError(t, err)
+		assert.Len(t, resumed, maxRetainedChanges)
+		b.unwatch(w)
+
+		// resuming from the current version replays nothing
+		w, resumed, err = b.watch("collector-0", b.formatVersion(maxRetainedChanges+1))
+		require.NoError(t, err)
+		assert.True(t, w.resumed)
+		assert.Empty(t, resumed)
+		b.unwatch(w)
+
+		_, _, err = b.watch("collector-0", "1")
+		assert.ErrorIs(t, err, errInvalidVersion)
+	})
+
+	t.Run("closes watches falling behind", func(t *testing.T) {
+		b := newTargetBroker()
+		w, _, err := b.watch("collector-0", "")
+		require.NoError(t, err)
+		for i := 0; i < watchBufferSize+1; i++ {
+			b.publish(changesFor("collector-0"))
+		}
+		// the buffered changes are still passed on before the watch ends
+		received := 0
+		for range w.changes {
+			received++
+		}
+		assert.Equal(t, watchBufferSize, received)
+		assert.NotContains(t, b.watches, w)
+	})
+
+	t.Run("closes watches when the assignments are replaced", func(t *testing.T) {
+		b := newTargetBroker()
+		b.publish(changesFor("collector-0"))
+		w, _, err := b.watch("collector-0", "")
+		require.NoError(t, err)
+		b.publish(nil)
+		_, ok := <-w.changes
+		assert.False(t, ok)
+
+		w, _, err = b.watch("collector-0", b.formatVersion(1))
+		require.NoError(t, err)
+		assert.False(t, w.resumed)
+		b.unwatch(w)
+	})
+}
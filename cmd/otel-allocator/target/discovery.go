@@ -39,19 +39,19 @@ var (
 	targetsDiscovered = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "opentelemetry_allocator_targets",
 		Help: "Number of targets discovered.",
-	}, []string{"job_name"})
+	}, []string{"job_name", "pool"})
 
-	processTargetsDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+	processTargetsDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Name:    "opentelemetry_allocator_process_targets_duration_seconds",
 		Help:    "Duration of processing targets.",
 		Buckets: []float64{1, 5, 10, 30, 60, 120},
-	})
+	}, []string{"pool"})
 
 	processTargetGroupsDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Name:    "opentelemetry_allocator_process_target_groups_duration_seconds",
 		Help:    "Duration of processing target groups.",
 		Buckets: []float64{1, 5, 10, 30, 60, 120},
-	}, []string{"job_name"})
+	}, []string{"job_name", "pool"})
 )
 
 type Discoverer struct {
@@ -67,6 +67,8 @@ type Discoverer struct {
 	triggerReload          chan struct{}
 	processTargetsCallBack func(targets map[string]*Item)
 	mtxTargets             sync.Mutex
+	// pool is the name of the pool the targets are discovered for, which labels the metrics
+	pool string
 }
 
 type discoveryHook interface {
@@ -77,7 +79,7 @@ type scrapeConfigsUpdater interface {
 	UpdateScrapeConfigResponse(map[string]*promconfig.ScrapeConfig) error
 }
 
-func NewDiscoverer(log logr.Logger, manager *discovery.Manager, hook discoveryHook, scrapeConfigsUpdater scrapeConfigsUpdater, setTargets func(targets map[string]*Item)) *Discoverer {
+func NewDiscoverer(log logr.Logger, manager *discovery.Manager, hook discoveryHook, scrapeConfigsUpdater scrapeConfigsUpdater, setTargets func(targets map[string]*Item), pool string) *Discoverer {
 	return &Discoverer{
 		log:                    log,
 		manager:                manager,
@@ -88,6 +90,7 @@ func NewDiscoverer(log logr.Logger, manager *discovery.Manager, hook discoveryHo
 		scrapeConfigsHash:      nil, // we want the first update to succeed even if the config is empty
 		scrapeConfigsUpdater:   scrapeConfigsUpdater,
 		processTargetsCallBack: setTargets,
+		pool:                   pool,
 	}
 }
 
@@ -175,7 +178,7 @@ func (m *Discoverer) Reload() {
 	m.mtxScrape.Lock()
 	var wg sync.WaitGroup
 	targets := map[string]*Item{}
-	timer := prometheus.NewTimer(processTargetsDuration)
+	timer := prometheus.NewTimer(processTargetsDuration.WithLabelValues(m.pool))
 	defer timer.ObserveDuration()
 
 	for jobName, groups := range m.targetSets {
@@ -199,7 +202,7 @@ func (m *Discoverer) Reload() {
 // processTargetGroups processes the target groups and returns a map of targets.
 func (m *Discoverer) processTargetGroups(jobName string, groups []*targetgroup.Group) map[string]*Item {
 	builder := labels.NewBuilder(labels.Labels{})
-	timer := prometheus.NewTimer(processTargetGroupsDuration.WithLabelValues(jobName))
+	timer := prometheus.NewTimer(processTargetGroupsDuration.WithLabelValues(jobName, m.pool))
 	targets := map[string]*Item{}
 	defer timer.ObserveDuration()
 	var count float64 = 0
@@ -219,7 +222,7 @@ func (m *Discoverer) processTargetGroups(jobName string, groups []*targetgroup.G
 			targets[item.Hash()] = item
 		}
 	}
-	targetsDiscovered.WithLabelValues(jobName).Set(count)
+	targetsDiscovered.WithLabelValues(jobName, m.pool).Set(count)
 	return targets
 }
 
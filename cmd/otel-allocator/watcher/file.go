@@ -0,0 +1,185 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-logr/logr"
+	promconfig "github.com/prometheus/prometheus/config"
+
+	allocatorconfig "github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/config"
+)
+
+// FileWatcher loads the scrape configs of the files matching the scrape_config_files of the Prometheus config,
+// and reloads them whenever the directories of these files change. Globs are supported in the directories too, the
+// directories they match being watched as they appear.
+type FileWatcher struct {
+	logger        logr.Logger
+	globalConfig  promconfig.GlobalConfig
+	patterns      []string
+	eventInterval time.Duration
+	watcher       *fsnotify.Watcher
+	closer        chan struct{}
+}
+
+func NewFileWatcher(logger logr.Logger, cfg allocatorconfig.Config) (*FileWatcher, error) {
+	if cfg.PromConfig == nil {
+		return nil, fmt.Errorf("no scrape config files are defined")
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	return &FileWatcher{
+		logger:        logger,
+		globalConfig:  cfg.PromConfig.GlobalConfig,
+		patterns:      cfg.PromConfig.ScrapeConfigFiles,
+		eventInterval: minEventInterval,
+		watcher:       watcher,
+		closer:        make(chan struct{}),
+	}, nil
+}
+
+// Watch the directories of the scrape config files. Kubernetes updates mounted ConfigMaps by swapping a symlink,
+// so any change to a directory triggers a reload, once the directory has stopped changing for a while.
+func (w *FileWatcher) Watch(upstreamEvents chan Event, upstreamErrors chan error) error {
+	if err := w.watchDirectories(); err != nil {
+		return err
+	}
+
+	var reload <-chan time.Time
+	for {
+		select {
+		case <-w.closer:
+			return nil
+		case fsEvent, ok := <-w.watcher.Events:
+			if !ok {
+				return nil
+			}
+			w.logger.V(2).Info("Scrape config files changed", "file", fsEvent.Name, "op", fsEvent.Op.String())
+			if fsEvent.Has(fsnotify.Create) {
+				// a directory matching a glob may have been created
+				if err := w.watchDirectories(); err != nil {
+					w.logger.Error(err, "Unable to watch the new scrape config directories")
+				}
+			}
+			reload = time.After(w.eventInterval)
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return nil
+			}
+			select {
+			case upstreamErrors <- err:
+			case <-w.closer:
+				return nil
+			}
+		case <-reload:
+			reload = nil
+			select {
+			case upstreamEvents <- Event{Source: EventSourceFile, Watcher: w}:
+			case <-w.closer:
+				return nil
+			}
+		}
+	}
+}
+
+// watchDirectories watches the directories of the scrape config files. Directories which are already watched are
+// left as they are.
+func (w *FileWatcher) watchDirectories() error {
+	for _, pattern := range w.patterns {
+		dirs, err := directories(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid scrape config files pattern %s: %w", pattern, err)
+		}
+		for _, dir := range dirs {
+			if err := w.watcher.Add(dir); err != nil {
+				return fmt.Errorf("unable to watch scrape config files in %s: %w", dir, err)
+			}
+		}
+	}
+	return nil
+}
+
+// directories returns the directories to watch for the files matching pattern. When the directory of the pattern
+// contains globs, these are the directories it currently matches, along with the directories the globs are matched
+// in, so that the directories created later are noticed.
+func directories(pattern string) ([]string, error) {
+	dir := filepath.Dir(pattern)
+	if !hasMeta(dir) {
+		return []string{dir}, nil
+	}
+
+	segments := strings.Split(dir, string(filepath.Separator))
+	i := 0
+	for !hasMeta(segments[i]) {
+		i++
+	}
+	base := strings.Join(segments[:i], string(filepath.Separator))
+	if base == "" && filepath.IsAbs(dir) {
+		base = string(filepath.Separator)
+	} else if base == "" {
+		base = "."
+	}
+
+	var dirs []string
+	current := []string{base}
+	for _, segment := range segments[i:] {
+		dirs = append(dirs, current...)
+		var next []string
+		for _, parent := range current {
+			matches, err := filepath.Glob(filepath.Join(parent, segment))
+			if err != nil {
+				return nil, err
+			}
+			for _, match := range matches {
+				if info, err := os.Stat(match); err == nil && info.IsDir() {
+					next = append(next, match)
+				}
+			}
+		}
+		current = next
+	}
+	return append(dirs, current...), nil
+}
+
+// hasMeta reports whether path contains any of the magic characters recognized by filepath.Match.
+func hasMeta(path string) bool {
+	return strings.ContainsAny(path, `*?[\`)
+}
+
+func (w *FileWatcher) LoadConfig(_ context.Context) (*promconfig.Config, error) {
+	filesConfig := &promconfig.Config{
+		GlobalConfig:      w.globalConfig,
+		ScrapeConfigFiles: w.patterns,
+	}
+	scrapeConfigs, err := filesConfig.GetScrapeConfigs()
+	if err != nil {
+		return nil, err
+	}
+	return &promconfig.Config{ScrapeConfigs: scrapeConfigs}, nil
+}
+
+func (w *FileWatcher) Close() error {
+	close(w.closer)
+	return w.watcher.Close()
+}
@@ -0,0 +1,169 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	promconfig "github.com/prometheus/prometheus/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	allocatorconfig "github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/config"
+)
+
+func jobNames(cfg *promconfig.Config) []string {
+	var names []string
+	for _, scrapeConfig := range cfg.ScrapeConfigs {
+		names = append(names, scrapeConfig.JobName)
+	}
+	return names
+}
+
+func TestFileWatcher(t *testing.T) {
+	dir := t.TempDir()
+	writeScrapeConfigs := func(file, jobName string) {
+		content := "scrape_configs:\n- job_name: " + jobName + "\n  static_configs:\n  - targets: [\"localhost:9090\"]\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(content), 0600))
+	}
+	writeScrapeConfigs("a.yaml", "a")
+	writeScrapeConfigs("ignored.txt", "ignored")
+
+	w, err := NewFileWatcher(logr.Discard(), allocatorconfig.Config{
+		PromConfig: &promconfig.Config{
+			GlobalConfig:      promconfig.DefaultGlobalConfig,
+			ScrapeConfigFiles: []string{filepath.Join(dir, "*.yaml")},
+		},
+	})
+	require.NoError(t, err)
+	w.eventInterval = 10 * time.Millisecond
+
+	cfg, err := w.LoadConfig(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, jobNames(cfg))
+	assert.Equal(t, promconfig.DefaultGlobalConfig.ScrapeInterval, cfg.ScrapeConfigs[0].ScrapeInterval)
+
+	events := make(chan Event, 1)
+	errors := make(chan error, 1)
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- w.Watch(events, errors)
+	}()
+	// wait for the watch to start by retrying the change until it's noticed
+	require.Eventually(t, func() bool {
+		writeScrapeConfigs("b.yaml", "b")
+		select {
+		case event := <-events:
+			return event.Source == EventSourceFile
+		default:
+			return false
+		}
+	}, 5*time.Second, 50*time.Millisecond)
+
+	cfg, err = w.LoadConfig(context.Background())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a", "b"}, jobNames(cfg))
+
+	writeScrapeConfigs("c.yaml", "a")
+	_, err = w.LoadConfig(context.Background())
+	assert.ErrorContains(t, err, `found multiple scrape configs with job name "a"`)
+
+	require.NoError(t, w.Close())
+	assert.NoError(t, <-watchErr)
+}
+
+func TestFileWatcherDirectories(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "a", "jobs"), 0700))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "b"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "c"), nil, 0600))
+
+	dirs, err := directories(filepath.Join(dir, "*.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{dir}, dirs)
+
+	dirs, err = directories(filepath.Join(dir, "*", "jobs.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{dir, filepath.Join(dir, "a"), filepath.Join(dir, "b")}, dirs)
+
+	dirs, err = directories(filepath.Join(dir, "*", "jobs", "*.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{dir, filepath.Join(dir, "a"), filepath.Join(dir, "b"), filepath.Join(dir, "a", "jobs")}, dirs)
+
+	_, err = directories(filepath.Join(dir, "[", "jobs.yaml"))
+	assert.Error(t, err)
+}
+
+func TestFileWatcherGlobDirectories(t *testing.T) {
+	dir := t.TempDir()
+	writeScrapeConfigs := func(team string) {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, team), 0700))
+		content := "scrape_configs:\n- job_name: " + team + "\n  static_configs:\n  - targets: [\"localhost:9090\"]\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, team, "jobs.yaml"), []byte(content), 0600))
+	}
+	writeScrapeConfigs("a")
+
+	w, err := NewFileWatcher(logr.Discard(), allocatorconfig.Config{
+		PromConfig: &promconfig.Config{
+			GlobalConfig:      promconfig.DefaultGlobalConfig,
+			ScrapeConfigFiles: []string{filepath.Join(dir, "*", "jobs.yaml")},
+		},
+	})
+	require.NoError(t, err)
+	w.eventInterval = 10 * time.Millisecond
+
+	events := make(chan Event, 1)
+	errors := make(chan error, 1)
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- w.Watch(events, errors)
+	}()
+	require.Eventually(t, func() bool {
+		writeScrapeConfigs("a")
+		select {
+		case <-events:
+			return true
+		default:
+			return false
+		}
+	}, 5*time.Second, 50*time.Millisecond)
+
+	// the directory created after the watch started is watched too
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "b"), 0700))
+	select {
+	case <-events:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the creation of the directory wasn't noticed")
+	}
+	writeScrapeConfigs("b")
+	select {
+	case <-events:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the change in the new directory wasn't noticed")
+	}
+
+	cfg, err := w.LoadConfig(context.Background())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a", "b"}, jobNames(cfg))
+
+	require.NoError(t, w.Close())
+	assert.NoError(t, <-watchErr)
+}
@@ -0,0 +1,200 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watcher
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	promcommconfig "github.com/prometheus/common/config"
+	promconfig "github.com/prometheus/prometheus/config"
+	"gopkg.in/yaml.v2"
+
+	allocatorconfig "github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/config"
+)
+
+// maxHTTPScrapeConfigsSize is the maximum size of the scrape configs served by the HTTP endpoint, so that a faulty
+// endpoint can't exhaust the memory of the target allocator.
+const maxHTTPScrapeConfigsSize = 16 << 20
+
+// HTTPWatcher fetches scrape configs from an HTTP endpoint, which serves them in the format of Prometheus scrape
+// config files, every refresh interval.
+type HTTPWatcher struct {
+	logger          logr.Logger
+	url             string
+	client          *http.Client
+	globalConfig    promconfig.GlobalConfig
+	refreshInterval time.Duration
+	closer          chan struct{}
+
+	mtx           sync.Mutex
+	content       []byte
+	scrapeConfigs []*promconfig.ScrapeConfig
+}
+
+func NewHTTPWatcher(logger logr.Logger, cfg allocatorconfig.Config) (*HTTPWatcher, error) {
+	client, err := promcommconfig.NewClientFromConfig(cfg.HTTPScrapeConfigs.HTTPClientConfig, "target-allocator")
+	if err != nil {
+		return nil, err
+	}
+	globalConfig := promconfig.DefaultGlobalConfig
+	if cfg.PromConfig != nil {
+		globalConfig = cfg.PromConfig.GlobalConfig
+	}
+	return &HTTPWatcher{
+		logger:          logger,
+		url:             cfg.HTTPScrapeConfigs.URL,
+		client:          client,
+		globalConfig:    globalConfig,
+		refreshInterval: cfg.HTTPScrapeConfigs.RefreshInterval,
+		closer:          make(chan struct{}),
+	}, nil
+}
+
+// Watch fetches the scrape configs every refresh interval and sends an event whenever they change.
+func (w *HTTPWatcher) Watch(upstreamEvents chan Event, upstreamErrors chan error) error {
+	ticker := time.NewTicker(w.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.closer:
+			return nil
+		case <-ticker.C:
+			changed, err := w.refresh(context.Background())
+			if err != nil {
+				select {
+				case upstreamErrors <- err:
+				case <-w.closer:
+					return nil
+				}
+				continue
+			}
+			if !changed {
+				continue
+			}
+			select {
+			case upstreamEvents <- Event{Source: EventSourceHTTP, Watcher: w}:
+			case <-w.closer:
+				return nil
+			}
+		}
+	}
+}
+
+// LoadConfig returns the scrape configs fetched last, fetching them first if they haven't been yet.
+func (w *HTTPWatcher) LoadConfig(ctx context.Context) (*promconfig.Config, error) {
+	w.mtx.Lock()
+	fetched := w.content != nil
+	w.mtx.Unlock()
+	if !fetched {
+		if _, err := w.refresh(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return &promconfig.Config{ScrapeConfigs: w.scrapeConfigs}, nil
+}
+
+// refresh fetches the scrape configs and returns whether they changed. Scrape configs which aren't valid are
+// discarded, so that the ones fetched last keep being used.
+func (w *HTTPWatcher) refresh(ctx context.Context) (bool, error) {
+	content, err := w.fetch(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	w.mtx.Lock()
+	unchanged := w.content != nil && bytes.Equal(content, w.content)
+	w.mtx.Unlock()
+	if unchanged {
+		return false, nil
+	}
+
+	scrapeConfigs, err := w.parse(content)
+	if err != nil {
+		return false, fmt.Errorf("invalid scrape configs from %s: %w", w.url, err)
+	}
+
+	w.mtx.Lock()
+	w.content = content
+	w.scrapeConfigs = scrapeConfigs
+	w.mtx.Unlock()
+	w.logger.V(2).Info("Scrape configs changed", "url", w.url, "jobs", len(scrapeConfigs))
+	return true, nil
+}
+
+func (w *HTTPWatcher) fetch(ctx context.Context) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, w.refreshInterval)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, w.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/yaml, application/json")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch scrape configs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to fetch scrape configs from %s: %s", w.url, resp.Status)
+	}
+	// one more byte is read to tell whether the limit is exceeded
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxHTTPScrapeConfigsSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read scrape configs from %s: %w", w.url, err)
+	}
+	if len(content) > maxHTTPScrapeConfigsSize {
+		return nil, fmt.Errorf("scrape configs from %s exceed the maximum size of %d bytes", w.url, maxHTTPScrapeConfigsSize)
+	}
+	return content, nil
+}
+
+// parse parses scrape configs in the format of Prometheus scrape config files. As JSON is valid YAML, they can be
+// served as either.
+func (w *HTTPWatcher) parse(content []byte) ([]*promconfig.ScrapeConfig, error) {
+	cfg := promconfig.ScrapeConfigs{}
+	if err := yaml.UnmarshalStrict(content, &cfg); err != nil {
+		return nil, err
+	}
+
+	jobNames := make(map[string]bool, len(cfg.ScrapeConfigs))
+	for _, scrapeConfig := range cfg.ScrapeConfigs {
+		if err := scrapeConfig.Validate(w.globalConfig); err != nil {
+			return nil, err
+		}
+		if jobNames[scrapeConfig.JobName] {
+			return nil, fmt.Errorf("found multiple scrape configs with job name %q", scrapeConfig.JobName)
+		}
+		jobNames[scrapeConfig.JobName] = true
+	}
+	return cfg.ScrapeConfigs, nil
+}
+
+func (w *HTTPWatcher) Close() error {
+	close(w.closer)
+	return nil
+}
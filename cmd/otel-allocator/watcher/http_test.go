@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watcher
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	allocatorconfig "github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/config"
+)
+
+func TestHTTPWatcher(t *testing.T) {
+	var (
+		mtx     sync.Mutex
+		content = "scrape_configs:\n- job_name: a\n  static_configs:\n  - targets: [\"localhost:9090\"]\n"
+	)
+	setContent := func(c string) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		content = c
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		mtx.Lock()
+		defer mtx.Unlock()
+		_, _ = w.Write([]byte(content))
+	}))
+	defer srv.Close()
+
+	cfg := allocatorconfig.CreateDefaultConfig()
+	cfg.HTTPScrapeConfigs.URL = srv.URL
+	cfg.HTTPScrapeConfigs.RefreshInterval = 10 * time.Millisecond
+	cfg.HTTPScrapeConfigs.HTTPClientConfig.BearerToken = "token"
+	w, err := NewHTTPWatcher(logr.Discard(), cfg)
+	require.NoError(t, err)
+
+	promCfg, err := w.LoadConfig(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, jobNames(promCfg))
+
+	events := make(chan Event, 1)
+	errors := make(chan error, 1)
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- w.Watch(events, errors)
+	}()
+
+	// JSON is served as well as YAML
+	setContent(`{"scrape_configs": [{"job_name": "a"}, {"job_name": "b"}]}`)
+	select {
+	case event := <-events:
+		assert.Equal(t, EventSourceHTTP, event.Source)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event after the scrape configs changed")
+	}
+	promCfg, err = w.LoadConfig(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, jobNames(promCfg))
+
+	// invalid scrape configs are reported and the ones fetched last are kept
+	setContent(`{"scrape_configs": [{"job_name": "a"}, {"job_name": "a"}]}`)
+	select {
+	case err = <-errors:
+		assert.ErrorContains(t, err, `found multiple scrape configs with job name "a"`)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no error after invalid scrape configs were served")
+	}
+	promCfg, err = w.LoadConfig(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, jobNames(promCfg))
+
+	require.NoError(t, w.Close())
+	assert.NoError(t, <-watchErr)
+}
+
+func TestHTTPWatcherSizeLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("scrape_configs: []\n"))
+		_, _ = w.Write(bytes.Repeat([]byte("#"), maxHTTPScrapeConfigsSize))
+	}))
+	defer srv.Close()
+
+	cfg := allocatorconfig.CreateDefaultConfig()
+	cfg.HTTPScrapeConfigs.URL = srv.URL
+	cfg.HTTPScrapeConfigs.RefreshInterval = time.Minute
+	w, err := NewHTTPWatcher(logr.Discard(), cfg)
+	require.NoError(t, err)
+
+	_, err = w.LoadConfig(context.Background())
+	assert.ErrorContains(t, err, "exceed the maximum size")
+}
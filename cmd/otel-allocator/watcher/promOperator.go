@@ -40,8 +40,10 @@ import (
 	"gopkg.in/yaml.v2"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 
 	allocatorconfig "github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/config"
@@ -52,7 +54,7 @@ const (
 	minEventInterval = time.Second * 5
 )
 
-func NewPrometheusCRWatcher(ctx context.Context, logger logr.Logger, cfg allocatorconfig.Config) (*PrometheusCRWatcher, error) {
+func NewPrometheusCRWatcher(ctx context.Context, logger logr.Logger, cfg allocatorconfig.Config, pool string) (*PrometheusCRWatcher, error) {
 	// TODO: Remove this after go 1.23 upgrade
 	promLogger := level.NewFilter(gokitlog.NewLogfmtLogger(os.Stderr), level.AllowWarn())
 	slogger := slog.New(logr.ToSlogHandler(logger))
@@ -106,6 +108,7 @@ func NewPrometheusCRWatcher(ctx context.Context, logger logr.Logger, cfg allocat
 	operatorMetrics := operator.NewMetrics(promRegisterer)
 	eventRecorderFactory := operator.NewEventRecorderFactory(false)
 	eventRecorder := eventRecorderFactory(clientset, "target-allocator")
+	problemRecorder := operator.NewEventRecorderFactory(true)(clientset, "target-allocator")
 
 	var nsMonInf cache.SharedIndexInformer
 	getNamespaceInformerErr := retry.OnError(retry.DefaultRetry,
@@ -142,6 +145,9 @@ func NewPrometheusCRWatcher(ctx context.Context, logger logr.Logger, cfg allocat
 		probeNamespaceSelector:          cfg.PrometheusCR.ProbeNamespaceSelector,
 		resourceSelector:                resourceSelector,
 		store:                           store,
+		problemRecorder:                 problemRecorder,
+		staticJobNames:                  staticJobNames(cfg),
+		pool:                            pool,
 	}, nil
 }
 
@@ -161,6 +167,24 @@ type PrometheusCRWatcher struct {
 	probeNamespaceSelector          *metav1.LabelSelector
 	resourceSelector                *prometheus.ResourceSelector
 	store                           *assets.StoreBuilder
+	problemRecorder                 record.EventRecorder
+	staticJobNames                  map[string]bool
+	problems                        map[string][]Problem
+	problemsHandler                 func(problems map[string][]Problem)
+	// pool is the name of the pool the scrape configs are generated for, which labels the metrics
+	pool string
+}
+
+// staticJobNames returns the job names of the scrape configs of the target allocator config.
+func staticJobNames(cfg allocatorconfig.Config) map[string]bool {
+	jobNames := make(map[string]bool)
+	if cfg.PromConfig == nil {
+		return jobNames
+	}
+	for _, scrapeConfig := range cfg.PromConfig.ScrapeConfigs {
+		jobNames[scrapeConfig.JobName] = true
+	}
+	return jobNames
 }
 
 func getNamespaceInformer(ctx context.Context, allowList map[string]struct{}, promOperatorLogger gokitlog.Logger, clientset kubernetes.Interface, operatorMetrics *operator.Metrics) (cache.SharedIndexInformer, error) {
@@ -346,6 +370,40 @@ func (w *PrometheusCRWatcher) rateLimitedEventSender(upstreamEvents chan Event,
 	}
 }
 
+// SetProblemsHandler sets the function called with the problems found in the scrape configs, by Prometheus CR,
+// every time they're loaded. It must be called before the config is first loaded.
+func (w *PrometheusCRWatcher) SetProblemsHandler(handler func(problems map[string][]Problem)) {
+	w.problemsHandler = handler
+}
+
+// reportProblems records the problems found in the scrape configs and emits an Event on the Prometheus CR of
+// each problem which wasn't there the last time the config was loaded.
+func (w *PrometheusCRWatcher) reportProblems(problems map[string][]Problem, sources map[string]runtime.Object) {
+	// only the series of this pool are reset, the other pools report their own problems
+	scrapeConfigProblems.DeletePartialMatch(prometheusgoclient.Labels{"pool": w.pool})
+	for source, sourceProblems := range problems {
+		reported := make(map[Problem]bool, len(w.problems[source]))
+		for _, problem := range w.problems[source] {
+			reported[problem] = true
+		}
+		for _, problem := range sourceProblems {
+			scrapeConfigProblems.WithLabelValues(problem.Reason, w.pool).Inc()
+			if reported[problem] {
+				continue
+			}
+			w.logger.Warn("Problem found in scrape config", "job", problem.JobName, "reason", problem.Reason, "message", problem.Message)
+			if obj, ok := sources[source]; ok && w.problemRecorder != nil {
+				w.problemRecorder.Eventf(obj, v1.EventTypeWarning, problem.Reason, "Job %s: %s", problem.JobName, problem.Message)
+			}
+		}
+	}
+
+	w.problems = problems
+	if w.problemsHandler != nil {
+		w.problemsHandler(problems)
+	}
+}
+
 func (w *PrometheusCRWatcher) Close() error {
 	close(w.stopChannel)
 	return nil
@@ -401,6 +459,21 @@ func (w *PrometheusCRWatcher) LoadConfig(ctx context.Context) (*promconfig.Confi
 			return nil, unmarshalErr
 		}
 
+		sources := make(map[string]runtime.Object)
+		for key, sm := range serviceMonitorInstances {
+			sources["serviceMonitor/"+key] = sm
+		}
+		for key, pm := range podMonitorInstances {
+			sources["podMonitor/"+key] = pm
+		}
+		for key, probe := range probeInstances {
+			sources["probe/"+key] = probe
+		}
+		for key, sc := range scrapeConfigInstances {
+			sources["scrapeConfig/"+key] = sc
+		}
+		w.reportProblems(validateScrapeConfigs(promCfg.ScrapeConfigs, w.staticJobNames), sources)
+
 		// set kubeconfig path to service discovery configs, else kubernetes_sd will always attempt in-cluster
 		// authentication even if running with a detected kubeconfig
 		for _, scrapeConfig := range promCfg.ScrapeConfigs {
@@ -0,0 +1,227 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watcher
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	promcommconfig "github.com/prometheus/common/config"
+	promconfig "github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/model/relabel"
+)
+
+const (
+	// ProblemJobNameConflict is reported for jobs whose job name or job label is also used by other jobs.
+	ProblemJobNameConflict = "JobNameConflict"
+	// ProblemDropsAllTargets is reported for relabel configs which drop every target or sample.
+	ProblemDropsAllTargets = "DropsAllTargets"
+	// ProblemMissingFile is reported for files which collectors don't have.
+	ProblemMissingFile = "MissingFile"
+
+	// prometheusFilesDir is where the Prometheus operator mounts the files of Prometheus servers, such as the
+	// TLS assets generated for Secrets and ConfigMaps, which collectors don't have.
+	prometheusFilesDir = "/etc/prometheus/"
+)
+
+var (
+	scrapeConfigProblems = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "opentelemetry_allocator_scrape_config_problems",
+		Help: "Number of problems found in the scrape configs generated for Prometheus CRs.",
+	}, []string{"reason", "pool"})
+)
+
+// Problem is an issue found in a scrape config generated for a Prometheus CR, which would otherwise only show
+// up as missing metrics.
+type Problem struct {
+	JobName string `json:"job_name"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// sourceOf returns the Prometheus CR a job was generated for, e.g. serviceMonitor/<namespace>/<name> for the
+// job serviceMonitor/<namespace>/<name>/<endpoint>.
+func sourceOf(jobName string) string {
+	parts := strings.SplitN(jobName, "/", 4)
+	if len(parts) < 3 {
+		return jobName
+	}
+	return strings.Join(parts[:3], "/")
+}
+
+// validateScrapeConfigs returns the problems found in the given scrape configs by Prometheus CR. Static job
+// names are the job names of the scrape configs that aren't generated for Prometheus CRs.
+func validateScrapeConfigs(scrapeConfigs []*promconfig.ScrapeConfig, staticJobNames map[string]bool) map[string][]Problem {
+	problems := make(map[string][]Problem)
+	report := func(jobName, reason, message string) {
+		source := sourceOf(jobName)
+		problems[source] = append(problems[source], Problem{JobName: jobName, Reason: reason, Message: message})
+	}
+
+	jobsByLabel := make(map[string][]string)
+	for _, sc := range scrapeConfigs {
+		if staticJobNames[sc.JobName] {
+			report(sc.JobName, ProblemJobNameConflict, "the job name is also used by a scrape config of the target allocator config, and only one of them is kept")
+		}
+		if label := staticJobLabel(sc.RelabelConfigs); label != "" {
+			jobsByLabel[label] = append(jobsByLabel[label], sc.JobName)
+		}
+		if i, ok := dropsAll(sc.RelabelConfigs); ok {
+			report(sc.JobName, ProblemDropsAllTargets, fmt.Sprintf("relabeling %d drops every target", i))
+		}
+		if i, ok := dropsAll(sc.MetricRelabelConfigs); ok {
+			report(sc.JobName, ProblemDropsAllTargets, fmt.Sprintf("metric relabeling %d drops every sample", i))
+		}
+		for field, file := range httpClientFiles(sc.HTTPClientConfig) {
+			if strings.HasPrefix(file, prometheusFilesDir) {
+				report(sc.JobName, ProblemMissingFile, fmt.Sprintf("%s %s is only mounted into Prometheus servers, not into collectors", field, file))
+			}
+		}
+	}
+
+	for label, jobNames := range jobsByLabel {
+		sources := make(map[string]bool)
+		for _, jobName := range jobNames {
+			sources[sourceOf(jobName)] = true
+		}
+		if len(sources) < 2 {
+			continue
+		}
+		for _, jobName := range jobNames {
+			report(jobName, ProblemJobNameConflict, fmt.Sprintf("the job label %q is also set by %d other jobs", label, len(jobNames)-1))
+		}
+	}
+
+	for _, sourceProblems := range problems {
+		sort.Slice(sourceProblems, func(i, j int) bool {
+			a, b := sourceProblems[i], sourceProblems[j]
+			if a.JobName != b.JobName {
+				return a.JobName < b.JobName
+			}
+			if a.Reason != b.Reason {
+				return a.Reason < b.Reason
+			}
+			return a.Message < b.Message
+		})
+	}
+	return problems
+}
+
+// staticJobLabel returns the job label the given relabel configs always set, if any.
+func staticJobLabel(cfgs []*relabel.Config) string {
+	var label string
+	for _, cfg := range cfgs {
+		if cfg.Action != relabel.Replace || cfg.TargetLabel != "job" {
+			continue
+		}
+		label = ""
+		if len(cfg.SourceLabels) == 0 && !strings.Contains(cfg.Replacement, "$") {
+			label = cfg.Replacement
+		}
+	}
+	return label
+}
+
+// dropsAll returns the index of the first relabel config which drops everything whatever the label values are,
+// such as a keep action whose regex can't match anything or a drop action whose regex matches anything.
+func dropsAll(cfgs []*relabel.Config) (int, bool) {
+	for i, cfg := range cfgs {
+		if cfg.Regex.Regexp == nil {
+			continue
+		}
+		re, err := syntax.Parse(cfg.Regex.String(), syntax.Perl)
+		if err != nil {
+			continue
+		}
+		re = unwrapCaptures(re.Simplify())
+		switch cfg.Action {
+		case relabel.Keep:
+			if matchesNothing(re) {
+				return i, true
+			}
+		case relabel.Drop:
+			if re.Op == syntax.OpStar && len(re.Sub) == 1 && (re.Sub[0].Op == syntax.OpAnyChar || re.Sub[0].Op == syntax.OpAnyCharNotNL) {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func matchesNothing(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpNoMatch:
+		return true
+	case syntax.OpCharClass:
+		return len(re.Rune) == 0
+	case syntax.OpCapture, syntax.OpPlus:
+		return matchesNothing(re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if matchesNothing(sub) {
+				return true
+			}
+		}
+	case syntax.OpAlternate:
+		for _, sub := range re.Sub {
+			if !matchesNothing(sub) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func unwrapCaptures(re *syntax.Regexp) *syntax.Regexp {
+	for re.Op == syntax.OpCapture {
+		re = re.Sub[0]
+	}
+	return re
+}
+
+// httpClientFiles returns the files the given HTTP client config refers to by field.
+func httpClientFiles(cfg promcommconfig.HTTPClientConfig) map[string]string {
+	files := make(map[string]string)
+	add := func(field, file string) {
+		if file != "" {
+			files[field] = file
+		}
+	}
+	addTLS := func(prefix string, tls promcommconfig.TLSConfig) {
+		add(prefix+"ca_file", tls.CAFile)
+		add(prefix+"cert_file", tls.CertFile)
+		add(prefix+"key_file", tls.KeyFile)
+	}
+
+	addTLS("tls_config.", cfg.TLSConfig)
+	add("bearer_token_file", cfg.BearerTokenFile)
+	if cfg.BasicAuth != nil {
+		add("basic_auth.username_file", cfg.BasicAuth.UsernameFile)
+		add("basic_auth.password_file", cfg.BasicAuth.PasswordFile)
+	}
+	if cfg.Authorization != nil {
+		add("authorization.credentials_file", cfg.Authorization.CredentialsFile)
+	}
+	if cfg.OAuth2 != nil {
+		add("oauth2.client_secret_file", cfg.OAuth2.ClientSecretFile)
+		addTLS("oauth2.tls_config.", cfg.OAuth2.TLSConfig)
+	}
+	return files
+}
@@ -0,0 +1,245 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watcher
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	promconfig "github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	allocatorconfig "github.com/open-telemetry/opentelemetry-operator/cmd/otel-allocator/config"
+)
+
+func TestValidateScrapeConfigs(t *testing.T) {
+	setJob := func(job string) *relabel.Config {
+		return &relabel.Config{Action: relabel.Replace, TargetLabel: "job", Replacement: job, Regex: relabel.MustNewRegexp("(.*)")}
+	}
+
+	for _, tc := range []struct {
+		name           string
+		scrapeConfigs  []*promconfig.ScrapeConfig
+		staticJobNames map[string]bool
+		expected       map[string][]Problem
+	}{
+		{
+			name: "no problems",
+			scrapeConfigs: []*promconfig.ScrapeConfig{
+				{
+					JobName: "serviceMonitor/test/simple/0",
+					RelabelConfigs: []*relabel.Config{
+						{Action: relabel.Keep, SourceLabels: model.LabelNames{"__meta_kubernetes_service_label_app"}, Regex: relabel.MustNewRegexp("(app)")},
+						{Action: relabel.Replace, SourceLabels: model.LabelNames{"__meta_kubernetes_service_name"}, TargetLabel: "job", Replacement: "$1", Regex: relabel.MustNewRegexp("(.*)")},
+					},
+					HTTPClientConfig: config.HTTPClientConfig{
+						TLSConfig: config.TLSConfig{CAFile: "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"},
+					},
+				},
+				{
+					JobName:        "podMonitor/test/simple/0",
+					RelabelConfigs: []*relabel.Config{setJob("app"), {Action: relabel.Replace, SourceLabels: model.LabelNames{"__meta_kubernetes_pod_name"}, TargetLabel: "job", Replacement: "$1", Regex: relabel.MustNewRegexp("(.*)")}},
+				},
+				{
+					JobName:        "serviceMonitor/test/simple/1",
+					RelabelConfigs: []*relabel.Config{setJob("app")},
+				},
+			},
+			expected: map[string][]Problem{},
+		},
+		{
+			name: "job name conflicts",
+			scrapeConfigs: []*promconfig.ScrapeConfig{
+				{JobName: "serviceMonitor/test/a/0", RelabelConfigs: []*relabel.Config{setJob("app")}},
+				{JobName: "serviceMonitor/test/a/1", RelabelConfigs: []*relabel.Config{setJob("app")}},
+				{JobName: "podMonitor/test/b/0", RelabelConfigs: []*relabel.Config{setJob("app")}},
+				{JobName: "static"},
+			},
+			staticJobNames: map[string]bool{"static": true},
+			expected: map[string][]Problem{
+				"serviceMonitor/test/a": {
+					{JobName: "serviceMonitor/test/a/0", Reason: ProblemJobNameConflict, Message: `the job label "app" is also set by 2 other jobs`},
+					{JobName: "serviceMonitor/test/a/1", Reason: ProblemJobNameConflict, Message: `the job label "app" is also set by 2 other jobs`},
+				},
+				"podMonitor/test/b": {
+					{JobName: "podMonitor/test/b/0", Reason: ProblemJobNameConflict, Message: `the job label "app" is also set by 2 other jobs`},
+				},
+				"static": {
+					{JobName: "static", Reason: ProblemJobNameConflict, Message: "the job name is also used by a scrape config of the target allocator config, and only one of them is kept"},
+				},
+			},
+		},
+		{
+			name: "relabel configs drop everything",
+			scrapeConfigs: []*promconfig.ScrapeConfig{
+				{
+					JobName: "serviceMonitor/test/keep/0",
+					RelabelConfigs: []*relabel.Config{
+						{Action: relabel.Keep, SourceLabels: model.LabelNames{"__meta_kubernetes_service_label_app"}, Regex: relabel.MustNewRegexp("app|[^\\s\\S]")},
+						{Action: relabel.Keep, SourceLabels: model.LabelNames{"__meta_kubernetes_service_label_app"}, Regex: relabel.MustNewRegexp("app[^\\s\\S]")},
+					},
+				},
+				{
+					JobName: "serviceMonitor/test/drop/0",
+					MetricRelabelConfigs: []*relabel.Config{
+						{Action: relabel.Drop, SourceLabels: model.LabelNames{"__name__"}, Regex: relabel.MustNewRegexp("go_.*")},
+						{Action: relabel.Drop, SourceLabels: model.LabelNames{"__name__"}, Regex: relabel.MustNewRegexp("(.*)")},
+					},
+				},
+			},
+			expected: map[string][]Problem{
+				"serviceMonitor/test/keep": {
+					{JobName: "serviceMonitor/test/keep/0", Reason: ProblemDropsAllTargets, Message: "relabeling 1 drops every target"},
+				},
+				"serviceMonitor/test/drop": {
+					{JobName: "serviceMonitor/test/drop/0", Reason: ProblemDropsAllTargets, Message: "metric relabeling 1 drops every sample"},
+				},
+			},
+		},
+		{
+			name: "files only mounted into Prometheus",
+			scrapeConfigs: []*promconfig.ScrapeConfig{
+				{
+					JobName: "podMonitor/test/tls/0",
+					HTTPClientConfig: config.HTTPClientConfig{
+						TLSConfig: config.TLSConfig{
+							CAFile:   "/etc/prometheus/certs/secret_test_tls_ca",
+							CertFile: "/etc/tls/cert",
+						},
+						BasicAuth: &config.BasicAuth{PasswordFile: "/etc/prometheus/secrets/auth/password"},
+					},
+				},
+			},
+			expected: map[string][]Problem{
+				"podMonitor/test/tls": {
+					{JobName: "podMonitor/test/tls/0", Reason: ProblemMissingFile, Message: "basic_auth.password_file /etc/prometheus/secrets/auth/password is only mounted into Prometheus servers, not into collectors"},
+					{JobName: "podMonitor/test/tls/0", Reason: ProblemMissingFile, Message: "tls_config.ca_file /etc/prometheus/certs/secret_test_tls_ca is only mounted into Prometheus servers, not into collectors"},
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, validateScrapeConfigs(tc.scrapeConfigs, tc.staticJobNames))
+		})
+	}
+}
+
+func TestLoadConfigReportsProblems(t *testing.T) {
+	serviceMonitors := []*monitoringv1.ServiceMonitor{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "tls",
+				Namespace: "test",
+			},
+			Spec: monitoringv1.ServiceMonitorSpec{
+				Endpoints: []monitoringv1.Endpoint{
+					{
+						Port: "web",
+						TLSConfig: &monitoringv1.TLSConfig{
+							SafeTLSConfig: monitoringv1.SafeTLSConfig{
+								CA: monitoringv1.SecretOrConfigMap{
+									Secret: &v1.SecretKeySelector{
+										LocalObjectReference: v1.LocalObjectReference{Name: "tls"},
+										Key:                  "ca.crt",
+									},
+								},
+								InsecureSkipVerify: ptr.To(true),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	cfg := allocatorconfig.Config{
+		PrometheusCR: allocatorconfig.PrometheusCRConfig{
+			ServiceMonitorSelector: &metav1.LabelSelector{},
+		},
+	}
+	w, _ := getTestPrometheusCRWatcher(t, serviceMonitors, nil, nil, nil, cfg)
+	_, err := w.k8sClient.CoreV1().Secrets("test").Create(context.Background(), &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "tls",
+			Namespace: "test",
+		},
+		Data: map[string][]byte{"ca.crt": selfSignedCert(t)},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	recorder := record.NewFakeRecorder(10)
+	w.problemRecorder = recorder
+	var handled map[string][]Problem
+	w.SetProblemsHandler(func(problems map[string][]Problem) {
+		handled = problems
+	})
+
+	go w.nsInformer.Run(w.stopChannel)
+	for !w.nsInformer.HasSynced() {
+		time.Sleep(50 * time.Millisecond)
+	}
+	for _, informer := range w.informers {
+		informer.Start(w.stopChannel)
+	}
+	for _, informer := range w.informers {
+		for !informer.HasSynced() {
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+	defer w.Close()
+
+	_, err = w.LoadConfig(context.Background())
+	require.NoError(t, err)
+	require.Len(t, handled["serviceMonitor/test/tls"], 1)
+	assert.Equal(t, ProblemMissingFile, handled["serviceMonitor/test/tls"][0].Reason)
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Warning MissingFile Job serviceMonitor/test/tls/0: tls_config.ca_file /etc/prometheus/certs/")
+
+	// problems which were already reported don't emit events again
+	_, err = w.LoadConfig(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, handled["serviceMonitor/test/tls"], 1)
+	assert.Empty(t, recorder.Events)
+}
+
+func selfSignedCert(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
@@ -37,12 +37,16 @@ type EventSource int
 const (
 	EventSourceConfigMap EventSource = iota
 	EventSourcePrometheusCR
+	EventSourceFile
+	EventSourceHTTP
 )
 
 var (
 	eventSourceToString = map[EventSource]string{
 		EventSourceConfigMap:    "EventSourceConfigMap",
 		EventSourcePrometheusCR: "EventSourcePrometheusCR",
+		EventSourceFile:         "EventSourceFile",
+		EventSourceHTTP:         "EventSourceHTTP",
 	}
 )
 
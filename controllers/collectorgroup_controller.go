@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1beta1"
+	"github.com/open-telemetry/opentelemetry-operator/internal/manifests/collectorgroup"
+	"github.com/open-telemetry/opentelemetry-operator/internal/naming"
+)
+
+// CollectorGroupReconciler reconciles a CollectorGroup object.
+type CollectorGroupReconciler struct {
+	client.Client
+	recorder record.EventRecorder
+	scheme   *runtime.Scheme
+	log      logr.Logger
+}
+
+// CollectorGroupReconcilerParams is the set of options to build a new CollectorGroupReconciler.
+type CollectorGroupReconcilerParams struct {
+	client.Client
+	Recorder record.EventRecorder
+	Scheme   *runtime.Scheme
+	Log      logr.Logger
+}
+
+// NewCollectorGroupReconciler creates a new reconciler for CollectorGroup objects.
+func NewCollectorGroupReconciler(params CollectorGroupReconcilerParams) *CollectorGroupReconciler {
+	return &CollectorGroupReconciler{
+		Client:   params.Client,
+		recorder: params.Recorder,
+		scheme:   params.Scheme,
+		log:      params.Log,
+	}
+}
+
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=opentelemetry.io,resources=opentelemetrycollectors,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=opentelemetry.io,resources=collectorgroups,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=opentelemetry.io,resources=collectorgroups/status,verbs=get;update;patch
+
+// Reconcile the OpenTelemetryCollectors of a CollectorGroup with the desired state.
+func (r *CollectorGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.log.WithValues("collectorgroup", req.NamespacedName)
+
+	var instance v1alpha1.CollectorGroup
+	if err := r.Client.Get(ctx, req.NamespacedName, &instance); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Error(err, "unable to fetch CollectorGroup")
+		}
+
+		// we'll ignore not-found errors, since they can't be fixed by an immediate
+		// requeue (we'll need to wait for a new notification), and we can get them
+		// on deleted requests.
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// We have a deletion, short circuit and let the deletion happen
+	if deletionTimestamp := instance.GetDeletionTimestamp(); deletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	if instance.Spec.ManagementState == v1beta1.ManagementStateUnmanaged {
+		log.Info("Skipping reconciliation for unmanaged CollectorGroup resource", "name", req.String())
+		// Stop requeueing for unmanaged CollectorGroup custom resources
+		return ctrl.Result{}, nil
+	}
+
+	params := collectorgroup.Params{
+		Log:            log,
+		CollectorGroup: instance,
+	}
+	desiredObjects, buildErr := BuildCollectorGroup(params)
+	if buildErr != nil {
+		r.recorder.Event(&instance, corev1.EventTypeWarning, "Error", buildErr.Error())
+		return ctrl.Result{}, buildErr
+	}
+	if err := reconcileDesiredObjects(ctx, r.Client, log, &instance, r.scheme, desiredObjects, nil); err != nil {
+		r.recorder.Event(&instance, corev1.EventTypeWarning, "Error", err.Error())
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, r.updateStatus(ctx, params)
+}
+
+// updateStatus reports the collectors of the group and the endpoint of the gateway.
+func (r *CollectorGroupReconciler) updateStatus(ctx context.Context, params collectorgroup.Params) error {
+	endpoint, err := collectorgroup.GatewayEndpoint(params)
+	if err != nil {
+		return err
+	}
+	instance := params.CollectorGroup
+	status := v1alpha1.CollectorGroupStatus{
+		Agent:           naming.CollectorGroupAgent(instance.Name),
+		Gateway:         naming.CollectorGroupGateway(instance.Name),
+		GatewayEndpoint: endpoint,
+	}
+	if instance.Status == status {
+		return nil
+	}
+	changed := instance.DeepCopy()
+	changed.Status = status
+	return r.Client.Status().Patch(ctx, changed, client.MergeFrom(&instance))
+}
+
+// SetupWithManager tells the manager what our controller is interested in.
+func (r *CollectorGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.CollectorGroup{}).
+		Owns(&v1beta1.OpenTelemetryCollector{}).
+		Complete(r)
+}
@@ -21,20 +21,26 @@ import (
 
 	"github.com/go-logr/logr"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1beta1"
 	"github.com/open-telemetry/opentelemetry-operator/internal/manifests"
 	"github.com/open-telemetry/opentelemetry-operator/internal/manifests/collector"
+	"github.com/open-telemetry/opentelemetry-operator/internal/manifests/collectorgroup"
 	"github.com/open-telemetry/opentelemetry-operator/internal/manifests/opampbridge"
 	"github.com/open-telemetry/opentelemetry-operator/internal/manifests/targetallocator"
+	collectorStatus "github.com/open-telemetry/opentelemetry-operator/internal/status/collector"
 	"github.com/open-telemetry/opentelemetry-operator/pkg/featuregate"
 )
 
@@ -116,6 +122,96 @@ func BuildTargetAllocator(params targetallocator.Params) ([]client.Object, error
 	return resources, nil
 }
 
+// BuildCollectorGroup returns the generation and collected errors of all manifests for a given instance.
+func BuildCollectorGroup(params collectorgroup.Params) ([]client.Object, error) {
+	builders := []manifests.Builder[collectorgroup.Params]{
+		collectorgroup.Build,
+	}
+	var resources []client.Object
+	for _, builder := range builders {
+		objs, err := builder(params)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, objs...)
+	}
+	return resources, nil
+}
+
+// mergeConfigTemplates merges the config templates referenced by the collector into its config, and validates the
+// pipelines of the resulting config. Errors making the config invalid, such as a missing template, are returned as
+// InvalidConfigError, while transient errors of the API server are returned as they are.
+func mergeConfigTemplates(ctx context.Context, kubeClient client.Client, otelcol *v1beta1.OpenTelemetryCollector) error {
+	refs := otelcol.Spec.Config.Templates
+	if len(refs) == 0 {
+		return nil
+	}
+	if !featuregate.EnableCollectorConfigTemplates.IsEnabled() {
+		return &collectorStatus.InvalidConfigError{Err: fmt.Errorf("the config references templates, which requires the %s feature gate", featuregate.EnableCollectorConfigTemplates.ID())}
+	}
+
+	templates := make([]v1beta1.AnyConfig, 0, len(refs))
+	for _, ref := range refs {
+		if ref.Kind == v1beta1.ConfigTemplateKindCluster {
+			template := v1alpha1.ClusterCollectorConfigTemplate{}
+			if err := kubeClient.Get(ctx, client.ObjectKey{Name: ref.Name}, &template); err != nil {
+				return configTemplateError(ref, err)
+			}
+			templates = append(templates, template.Spec.Config)
+			continue
+		}
+		template := v1alpha1.CollectorConfigTemplate{}
+		if err := kubeClient.Get(ctx, client.ObjectKey{Namespace: otelcol.Namespace, Name: ref.Name}, &template); err != nil {
+			return configTemplateError(ref, err)
+		}
+		templates = append(templates, template.Spec.Config)
+	}
+
+	merged, err := otelcol.Spec.Config.MergeTemplates(templates...)
+	if err != nil {
+		return &collectorStatus.InvalidConfigError{Err: fmt.Errorf("failed to merge the config templates: %w", err)}
+	}
+	if _, errs := merged.ValidatePipelines(field.NewPath("spec", "config")); len(errs) > 0 {
+		return &collectorStatus.InvalidConfigError{Err: fmt.Errorf("the configuration merged with the config templates is incorrect: %w", errs.ToAggregate())}
+	}
+	otelcol.Spec.Config = *merged
+	return nil
+}
+
+// configTemplateError returns the error of getting a config template. A missing template makes the config invalid,
+// while other errors, such as timeouts, are returned as they are so that the reconciliation is retried.
+func configTemplateError(ref v1beta1.ConfigTemplateReference, err error) error {
+	err = fmt.Errorf("failed to get the config template %s: %w", ref.Name, err)
+	if apierrors.IsNotFound(err) {
+		return &collectorStatus.InvalidConfigError{Err: err}
+	}
+	return err
+}
+
+// configTemplateIndexValue returns the value of the config template index for a template of the given kind. Cluster
+// templates have an empty namespace.
+func configTemplateIndexValue(kind v1beta1.ConfigTemplateKind, namespace, name string) string {
+	if kind == "" {
+		kind = v1beta1.ConfigTemplateKindNamespaced
+	}
+	return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+}
+
+// getCollectorsForConfigTemplate lists the collectors referencing a CollectorConfigTemplate or a
+// ClusterCollectorConfigTemplate, through the config template index of the collectors.
+func getCollectorsForConfigTemplate(ctx context.Context, kubeClient client.Client, template client.Object) ([]v1beta1.OpenTelemetryCollector, error) {
+	kind := v1beta1.ConfigTemplateKindNamespaced
+	if _, ok := template.(*v1alpha1.ClusterCollectorConfigTemplate); ok {
+		kind = v1beta1.ConfigTemplateKindCluster
+	}
+	var collectors v1beta1.OpenTelemetryCollectorList
+	indexValue := configTemplateIndexValue(kind, template.GetNamespace(), template.GetName())
+	if err := kubeClient.List(ctx, &collectors, client.MatchingFields{configTemplateKey: indexValue}); err != nil {
+		return nil, err
+	}
+	return collectors.Items, nil
+}
+
 // getList queries the Kubernetes API to list the requested resource, setting the list l of type T.
 func getList[T client.Object](ctx context.Context, cl client.Client, l T, options ...client.ListOption) (map[types.UID]client.Object, error) {
 	ownedObjects := map[types.UID]client.Object{}
@@ -0,0 +1,78 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	colfeaturegate "go.opentelemetry.io/collector/featuregate"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1beta1"
+	collectorStatus "github.com/open-telemetry/opentelemetry-operator/internal/status/collector"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/featuregate"
+)
+
+func TestMergeConfigTemplatesErrors(t *testing.T) {
+	require.NoError(t, colfeaturegate.GlobalRegistry().Set(featuregate.EnableCollectorConfigTemplates.ID(), true))
+	t.Cleanup(func() {
+		require.NoError(t, colfeaturegate.GlobalRegistry().Set(featuregate.EnableCollectorConfigTemplates.ID(), false))
+	})
+
+	otelcol := v1beta1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-collector", Namespace: "default"},
+		Spec: v1beta1.OpenTelemetryCollectorSpec{
+			Config: v1beta1.Config{Templates: []v1beta1.ConfigTemplateReference{{Name: "base"}}},
+		},
+	}
+
+	for _, tc := range []struct {
+		name          string
+		getErr        error
+		invalidConfig bool
+	}{
+		{
+			name:          "missing template",
+			getErr:        apierrors.NewNotFound(schema.GroupResource{Group: "opentelemetry.io", Resource: "collectorconfigtemplates"}, "base"),
+			invalidConfig: true,
+		},
+		{
+			name:   "transient error",
+			getErr: apierrors.NewTimeoutError("request timed out", 1),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := fake.NewClientBuilder().WithScheme(testScheme).WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+					return tc.getErr
+				},
+			}).Build()
+
+			err := mergeConfigTemplates(context.Background(), cl, otelcol.DeepCopy())
+			require.ErrorIs(t, err, tc.getErr)
+			var configErr *collectorStatus.InvalidConfigError
+			assert.Equal(t, tc.invalidConfig, errors.As(err, &configErr))
+		})
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
+	instrumentationStatus "github.com/open-telemetry/opentelemetry-operator/internal/status/instrumentation"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/instrumentation"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/instrumentation/rollout"
+)
+
+// InstrumentationReconciler reconciles the status of Instrumentation objects.
+type InstrumentationReconciler struct {
+	client.Client
+	recorder record.EventRecorder
+	scheme   *runtime.Scheme
+	log      logr.Logger
+}
+
+// InstrumentationReconcilerParams is the set of options to build a new InstrumentationReconciler.
+type InstrumentationReconcilerParams struct {
+	client.Client
+	Recorder record.EventRecorder
+	Scheme   *runtime.Scheme
+	Log      logr.Logger
+}
+
+// NewInstrumentationReconciler creates a new reconciler for Instrumentation objects.
+func NewInstrumentationReconciler(params InstrumentationReconcilerParams) *InstrumentationReconciler {
+	return &InstrumentationReconciler{
+		Client:   params.Client,
+		recorder: params.Recorder,
+		scheme:   params.Scheme,
+		log:      params.Log,
+	}
+}
+
+// +kubebuilder:rbac:groups=opentelemetry.io,resources=instrumentations,verbs=get;list;watch
+// +kubebuilder:rbac:groups=opentelemetry.io,resources=instrumentations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces;secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets;daemonsets,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=apps,resources=replicasets,verbs=get;list;watch
+
+// Reconcile restarts the workloads instrumented with a previous spec of an Instrumentation, when its rollout policy
+// allows it, and updates its status from the pods that reference it.
+func (r *InstrumentationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.log.WithValues("instrumentation", req.NamespacedName)
+
+	var instance v1alpha1.Instrumentation
+	if err := r.Client.Get(ctx, req.NamespacedName, &instance); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Error(err, "unable to fetch Instrumentation")
+		}
+		// we'll ignore not-found errors, since they can't be fixed by an immediate
+		// requeue (we'll need to wait for a new notification), and we can get them
+		// on deleted requests.
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// We have a deletion, short circuit and let the deletion happen
+	if deletionTimestamp := instance.GetDeletionTimestamp(); deletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	if err := rollout.Reconcile(ctx, log, r.Client, r.recorder, instance); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if _, err := instrumentationStatus.HandleReconcileStatus(ctx, log, r.Client, r.recorder, instance); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager tells the manager what our controller is interested in.
+func (r *InstrumentationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// index the pods by the Instrumentations named in their annotations, so that computing the status of an
+	// Instrumentation doesn't list the pods of every namespace
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &corev1.Pod{}, instrumentation.InstrumentationReferenceIndex, instrumentation.InstrumentationReferenceIndexValues); err != nil {
+		return err
+	}
+	// index the pods by the Instrumentations they were instrumented with, so that finding the workloads to restart
+	// doesn't list the pods of every namespace
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &corev1.Pod{}, instrumentation.SpecHashIndex, instrumentation.SpecHashIndexValues); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.Instrumentation{}).
+		Watches(
+			&corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(r.getInstrumentationsForPod),
+		).
+		Complete(r)
+}
+
+// getInstrumentationsForPod returns the Instrumentations referenced by the inject annotations of a pod or its namespace,
+// the ones whose selector matches the pod, and the ones the pod was instrumented with, whose rollout waits for the pod
+// to be replaced.
+func (r *InstrumentationReconciler) getInstrumentationsForPod(ctx context.Context, obj client.Object) []reconcile.Request {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil
+	}
+	ns := corev1.Namespace{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: pod.Namespace}, &ns); err != nil {
+		r.log.V(2).Info("unable to get the namespace of the pod", "namespace", pod.Namespace, "error", err.Error())
+		ns.Name = pod.Namespace
+	}
+
+	requested := map[types.NamespacedName]struct{}{}
+	var values []string
+	for _, value := range instrumentation.InstrumentationReferences(ns.ObjectMeta, pod.ObjectMeta) {
+		values = append(values, value)
+	}
+	if value := instrumentation.AutoInstrumentationReference(ns.ObjectMeta, pod.ObjectMeta); value != "" {
+		values = append(values, value)
+	}
+	for _, value := range values {
+		if strings.EqualFold(value, "true") {
+			insts := v1alpha1.InstrumentationList{}
+			if err := r.Client.List(ctx, &insts, client.InNamespace(pod.Namespace)); err != nil {
+				r.log.V(2).Info("unable to list instrumentations", "namespace", pod.Namespace, "error", err.Error())
+				continue
+			}
+			for _, inst := range insts.Items {
+				requested[types.NamespacedName{Namespace: inst.Namespace, Name: inst.Name}] = struct{}{}
+			}
+		} else if instNamespace, instName, namespaced := strings.Cut(value, "/"); namespaced {
+			requested[types.NamespacedName{Namespace: instNamespace, Name: instName}] = struct{}{}
+		} else {
+			requested[types.NamespacedName{Namespace: pod.Namespace, Name: value}] = struct{}{}
+		}
+	}
+
+	insts := v1alpha1.InstrumentationList{}
+	if err := r.Client.List(ctx, &insts); err != nil {
+		r.log.V(2).Info("unable to list instrumentations", "error", err.Error())
+	}
+	selected, err := instrumentation.SelectedInstrumentations(insts.Items, ns.ObjectMeta, pod.ObjectMeta)
+	if err != nil {
+		r.log.V(2).Info("unable to match the instrumentation selectors", "error", err.Error())
+	}
+	for _, candidates := range selected {
+		for _, inst := range candidates {
+			requested[types.NamespacedName{Namespace: inst.Namespace, Name: inst.Name}] = struct{}{}
+		}
+	}
+
+	for nsn := range instrumentation.ParseSpecHashes(pod.Annotations[instrumentation.AnnotationSpecHash]) {
+		requested[nsn] = struct{}{}
+	}
+
+	var requests []reconcile.Request
+	for nsn := range requested {
+		requests = append(requests, reconcile.Request{NamespacedName: nsn})
+	}
+	return requests
+}
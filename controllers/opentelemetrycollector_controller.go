@@ -38,6 +38,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/cluster"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
 	"github.com/open-telemetry/opentelemetry-operator/apis/v1beta1"
@@ -54,7 +56,10 @@ import (
 	"github.com/open-telemetry/opentelemetry-operator/pkg/featuregate"
 )
 
-const resourceOwnerKey = ".metadata.owner"
+const (
+	resourceOwnerKey  = ".metadata.owner"
+	configTemplateKey = ".spec.config.templates"
+)
 
 var (
 	ownedClusterObjectTypes = []client.Object{
@@ -196,6 +201,29 @@ func (r *OpenTelemetryCollectorReconciler) getTargetAllocator(ctx context.Contex
 	return collector.TargetAllocator(params)
 }
 
+// ApplyConfigTemplates merges the config templates referenced by the collector of the params into its config. Errors
+// making the config invalid are returned as InvalidConfigError.
+func (r *OpenTelemetryCollectorReconciler) ApplyConfigTemplates(ctx context.Context, params *manifests.Params) error {
+	return mergeConfigTemplates(ctx, r.Client, &params.OtelCol)
+}
+
+// findCollectorsForConfigTemplate returns the collectors referencing a CollectorConfigTemplate or a
+// ClusterCollectorConfigTemplate.
+func (r *OpenTelemetryCollectorReconciler) findCollectorsForConfigTemplate(ctx context.Context, template client.Object) []reconcile.Request {
+	collectors, err := getCollectorsForConfigTemplate(ctx, r.Client, template)
+	if err != nil {
+		r.log.Error(err, "failed to list the collectors referencing a config template", "template", template.GetName())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(collectors))
+	for _, collector := range collectors {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: collector.Namespace, Name: collector.Name},
+		})
+	}
+	return requests
+}
+
 // NewReconciler creates a new reconciler for OpenTelemetryCollector objects.
 func NewReconciler(p Params) *OpenTelemetryCollectorReconciler {
 	r := &OpenTelemetryCollectorReconciler{
@@ -223,6 +251,7 @@ func NewReconciler(p Params) *OpenTelemetryCollectorReconciler {
 // +kubebuilder:rbac:groups=opentelemetry.io,resources=opentelemetrycollectors/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=opentelemetry.io,resources=opentelemetrycollectors/finalizers,verbs=get;update;patch
 // +kubebuilder:rbac:groups=opentelemetry.io,resources=targetallocators,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=opentelemetry.io,resources=collectorconfigtemplates;clustercollectorconfigtemplates,verbs=get;list;watch
 
 // Reconcile the current state of an OpenTelemetry collector resource with the desired state.
 func (r *OpenTelemetryCollectorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -284,9 +313,13 @@ func (r *OpenTelemetryCollectorReconciler) Reconcile(ctx context.Context, req ct
 		}
 	}
 
+	if err = r.ApplyConfigTemplates(ctx, &params); err != nil {
+		return collectorStatus.HandleReconcileStatus(ctx, log, params, instance, err)
+	}
+
 	desiredObjects, buildErr := BuildCollector(params)
 	if buildErr != nil {
-		return ctrl.Result{}, buildErr
+		return collectorStatus.HandleReconcileStatus(ctx, log, params, instance, &collectorStatus.InvalidConfigError{Err: buildErr})
 	}
 
 	ownedObjects, err := r.findOtelOwnedObjects(ctx, params)
@@ -313,6 +346,11 @@ func (r *OpenTelemetryCollectorReconciler) SetupWithManager(mgr ctrl.Manager) er
 		builder.Owns(resource)
 	}
 
+	if featuregate.EnableCollectorConfigTemplates.IsEnabled() {
+		builder.Watches(&v1alpha1.CollectorConfigTemplate{}, handler.EnqueueRequestsFromMapFunc(r.findCollectorsForConfigTemplate))
+		builder.Watches(&v1alpha1.ClusterCollectorConfigTemplate{}, handler.EnqueueRequestsFromMapFunc(r.findCollectorsForConfigTemplate))
+	}
+
 	return builder.Complete(r)
 }
 
@@ -335,6 +373,27 @@ func (r *OpenTelemetryCollectorReconciler) SetupCaches(cluster cluster.Cluster)
 			return err
 		}
 	}
+
+	if featuregate.EnableCollectorConfigTemplates.IsEnabled() {
+		// index the collectors by the config templates they reference, to reconcile them when a template changes
+		if err := cluster.GetCache().IndexField(context.Background(), &v1beta1.OpenTelemetryCollector{}, configTemplateKey, func(rawObj client.Object) []string {
+			otelcol, ok := rawObj.(*v1beta1.OpenTelemetryCollector)
+			if !ok {
+				return nil
+			}
+			var values []string
+			for _, ref := range otelcol.Spec.Config.Templates {
+				namespace := otelcol.Namespace
+				if ref.Kind == v1beta1.ConfigTemplateKindCluster {
+					namespace = ""
+				}
+				values = append(values, configTemplateIndexValue(ref.Kind, namespace, ref.Name))
+			}
+			return values
+		}); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -69,6 +69,11 @@ func (r *TargetAllocatorReconciler) getParams(ctx context.Context, instance v1al
 	if err != nil {
 		return targetallocator.Params{}, err
 	}
+	if collector != nil {
+		if err = mergeConfigTemplates(ctx, r.Client, collector); err != nil {
+			return targetallocator.Params{}, err
+		}
+	}
 	p := targetallocator.Params{
 		Config:          r.config,
 		Client:          r.Client,
@@ -151,6 +156,7 @@ func NewTargetAllocatorReconciler(
 // +kubebuilder:rbac:groups=opentelemetry.io,resources=opentelemetrycollectors,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=opentelemetry.io,resources=targetallocators,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=opentelemetry.io,resources=targetallocators/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=opentelemetry.io,resources=collectorconfigtemplates;clustercollectorconfigtemplates,verbs=get;list;watch
 
 // Reconcile the current state of a TargetAllocator resource with the desired state.
 func (r *TargetAllocatorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -239,9 +245,32 @@ func (r *TargetAllocatorReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		builder.WithPredicates(selectorPredicate),
 	)
 
+	// watch the config templates of the collectors, which can hold the scrape configs
+	if featuregate.EnableCollectorConfigTemplates.IsEnabled() {
+		ctrlBuilder.Watches(&v1alpha1.CollectorConfigTemplate{}, handler.EnqueueRequestsFromMapFunc(r.getTargetAllocatorsForConfigTemplate))
+		ctrlBuilder.Watches(&v1alpha1.ClusterCollectorConfigTemplate{}, handler.EnqueueRequestsFromMapFunc(r.getTargetAllocatorsForConfigTemplate))
+	}
+
 	return ctrlBuilder.Complete(r)
 }
 
+// getTargetAllocatorsForConfigTemplate returns the TargetAllocators of the collectors referencing a config template.
+func (r *TargetAllocatorReconciler) getTargetAllocatorsForConfigTemplate(ctx context.Context, template client.Object) []reconcile.Request {
+	collectors, err := getCollectorsForConfigTemplate(ctx, r.Client, template)
+	if err != nil {
+		r.log.Error(err, "failed to list the collectors referencing a config template", "template", template.GetName())
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range collectors {
+		if collectors[i].Spec.TargetAllocator.Enabled {
+			requests = append(requests, getTargetAllocatorForCollector(ctx, &collectors[i])...)
+		}
+		requests = append(requests, getTargetAllocatorRequestsFromLabel(ctx, &collectors[i])...)
+	}
+	return requests
+}
+
 func getTargetAllocatorForCollector(_ context.Context, collector client.Object) []reconcile.Request {
 	return []reconcile.Request{
 		{
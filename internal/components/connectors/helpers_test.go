@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connectors_test
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	"github.com/open-telemetry/opentelemetry-operator/internal/components"
+	"github.com/open-telemetry/opentelemetry-operator/internal/components/connectors"
+)
+
+func TestParserForReturns(t *testing.T) {
+	const testComponentName = "test"
+	parser := connectors.ConnectorFor(testComponentName)
+	assert.Equal(t, "test", parser.ParserType())
+	assert.Equal(t, "__test", parser.ParserName())
+	ports, err := parser.Ports(logr.Discard(), testComponentName, map[string]interface{}{
+		"endpoint": "localhost:9000",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, ports, 0) // Should use the nop parser
+}
+
+func TestCanRegister(t *testing.T) {
+	const testComponentName = "test"
+	rules := []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "watch", "list"}}}
+	connectors.Register(testComponentName, components.NewBuilder[any]().WithName(testComponentName).WithRbacGen(func(_ logr.Logger, _ any) ([]rbacv1.PolicyRule, error) {
+		return rules, nil
+	}).MustBuild())
+	assert.True(t, connectors.IsRegistered(testComponentName))
+	parser := connectors.ConnectorFor(testComponentName + "/custom")
+	assert.Equal(t, "test", parser.ParserType())
+	assert.Equal(t, "__test", parser.ParserName())
+	parsedRules, err := parser.GetRBACRules(logr.Discard(), map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, rules, parsedRules)
+}
+
+func TestDownstreamParsers(t *testing.T) {
+	for _, tt := range []struct {
+		connectorName string
+		parserName    string
+	}{
+		{"count", "__count"},
+		{"failover", "__failover"},
+		{"forward", "__forward"},
+		{"roundrobin", "__roundrobin"},
+		{"routing", "__routing"},
+		{"servicegraph", "__servicegraph"},
+		{"spanmetrics", "__spanmetrics"},
+	} {
+		t.Run(tt.connectorName, func(t *testing.T) {
+			t.Run("is registered", func(t *testing.T) {
+				assert.True(t, connectors.IsRegistered(tt.connectorName))
+			})
+			t.Run("builds successfully", func(t *testing.T) {
+				parser := connectors.ConnectorFor(tt.connectorName)
+				assert.Equal(t, tt.parserName, parser.ParserName())
+			})
+			t.Run("has no ports or rules", func(t *testing.T) {
+				parser := connectors.ConnectorFor(tt.connectorName)
+				ports, err := parser.Ports(logr.Discard(), tt.connectorName, map[string]interface{}{})
+				assert.NoError(t, err)
+				assert.Empty(t, ports)
+				rules, err := parser.GetRBACRules(logr.Discard(), map[string]interface{}{})
+				assert.NoError(t, err)
+				assert.Empty(t, rules)
+			})
+		})
+	}
+}
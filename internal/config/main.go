@@ -56,6 +56,8 @@ type Config struct {
 	enablePythonInstrumentation         bool
 	enableNodeJSInstrumentation         bool
 	enableJavaInstrumentation           bool
+	enableRubyInstrumentation           bool
+	enablePHPInstrumentation            bool
 	autoInstrumentationDotNetImage      string
 	autoInstrumentationGoImage          string
 	autoInstrumentationApacheHttpdImage string
@@ -64,6 +66,8 @@ type Config struct {
 	operatorOpAMPBridgeConfigMapEntry   string
 	autoInstrumentationNodeJSImage      string
 	autoInstrumentationJavaImage        string
+	autoInstrumentationRubyImage        string
+	autoInstrumentationPHPImage         string
 
 	openshiftRoutesAvailability openshift.RoutesAvailability
 	prometheusCRAvailability    prometheus.Availability
@@ -107,6 +111,8 @@ func New(opts ...Option) Config {
 		enablePythonInstrumentation:         o.enablePythonInstrumentation,
 		enableNodeJSInstrumentation:         o.enableNodeJSInstrumentation,
 		enableJavaInstrumentation:           o.enableJavaInstrumentation,
+		enableRubyInstrumentation:           o.enableRubyInstrumentation,
+		enablePHPInstrumentation:            o.enablePHPInstrumentation,
 		targetAllocatorImage:                o.targetAllocatorImage,
 		operatorOpAMPBridgeImage:            o.operatorOpAMPBridgeImage,
 		targetAllocatorConfigMapEntry:       o.targetAllocatorConfigMapEntry,
@@ -123,6 +129,8 @@ func New(opts ...Option) Config {
 		autoInstrumentationGoImage:          o.autoInstrumentationGoImage,
 		autoInstrumentationApacheHttpdImage: o.autoInstrumentationApacheHttpdImage,
 		autoInstrumentationNginxImage:       o.autoInstrumentationNginxImage,
+		autoInstrumentationRubyImage:        o.autoInstrumentationRubyImage,
+		autoInstrumentationPHPImage:         o.autoInstrumentationPHPImage,
 		labelsFilter:                        o.labelsFilter,
 		annotationsFilter:                   o.annotationsFilter,
 		createRBACPermissions:               o.createRBACPermissions,
@@ -216,6 +224,16 @@ func (c *Config) EnableNodeJSAutoInstrumentation() bool {
 	return c.enableNodeJSInstrumentation
 }
 
+// EnableRubyAutoInstrumentation is true when the operator supports ruby auto instrumentation.
+func (c *Config) EnableRubyAutoInstrumentation() bool {
+	return c.enableRubyInstrumentation
+}
+
+// EnablePHPAutoInstrumentation is true when the operator supports php auto instrumentation.
+func (c *Config) EnablePHPAutoInstrumentation() bool {
+	return c.enablePHPInstrumentation
+}
+
 // CollectorConfigMapEntry represents the configuration file name for the collector. Immutable.
 func (c *Config) CollectorConfigMapEntry() string {
 	return c.collectorConfigMapEntry
@@ -301,6 +319,16 @@ func (c *Config) AutoInstrumentationNginxImage() string {
 	return c.autoInstrumentationNginxImage
 }
 
+// AutoInstrumentationRubyImage returns OpenTelemetry Ruby auto-instrumentation container image.
+func (c *Config) AutoInstrumentationRubyImage() string {
+	return c.autoInstrumentationRubyImage
+}
+
+// AutoInstrumentationPHPImage returns OpenTelemetry PHP auto-instrumentation container image.
+func (c *Config) AutoInstrumentationPHPImage() string {
+	return c.autoInstrumentationPHPImage
+}
+
 // LabelsFilter Returns the filters converted to regex strings used to filter out unwanted labels from propagations.
 func (c *Config) LabelsFilter() []string {
 	return c.labelsFilter
@@ -41,6 +41,8 @@ type options struct {
 	autoInstrumentationPythonImage      string
 	autoInstrumentationApacheHttpdImage string
 	autoInstrumentationNginxImage       string
+	autoInstrumentationRubyImage        string
+	autoInstrumentationPHPImage         string
 	collectorImage                      string
 	collectorConfigMapEntry             string
 	createRBACPermissions               autoRBAC.Availability
@@ -52,6 +54,8 @@ type options struct {
 	enablePythonInstrumentation         bool
 	enableNodeJSInstrumentation         bool
 	enableJavaInstrumentation           bool
+	enableRubyInstrumentation           bool
+	enablePHPInstrumentation            bool
 	targetAllocatorConfigMapEntry       string
 	operatorOpAMPBridgeConfigMapEntry   string
 	targetAllocatorImage                string
@@ -129,6 +133,16 @@ func WithEnableNodeJSInstrumentation(s bool) Option {
 		o.enableNodeJSInstrumentation = s
 	}
 }
+func WithEnableRubyInstrumentation(s bool) Option {
+	return func(o *options) {
+		o.enableRubyInstrumentation = s
+	}
+}
+func WithEnablePHPInstrumentation(s bool) Option {
+	return func(o *options) {
+		o.enablePHPInstrumentation = s
+	}
+}
 func WithTargetAllocatorConfigMapEntry(s string) Option {
 	return func(o *options) {
 		o.targetAllocatorConfigMapEntry = s
@@ -192,6 +206,18 @@ func WithAutoInstrumentationNginxImage(s string) Option {
 	}
 }
 
+func WithAutoInstrumentationRubyImage(s string) Option {
+	return func(o *options) {
+		o.autoInstrumentationRubyImage = s
+	}
+}
+
+func WithAutoInstrumentationPHPImage(s string) Option {
+	return func(o *options) {
+		o.autoInstrumentationPHPImage = s
+	}
+}
+
 func WithOpenShiftRoutesAvailability(os openshift.RoutesAvailability) Option {
 	return func(o *options) {
 		o.openshiftRoutesAvailability = os
@@ -0,0 +1,144 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package explanation records the decisions the pod mutators take for a pod that is only being explained, without
+// being admitted.
+package explanation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/go-logr/logr"
+)
+
+// Step is a single decision taken by a pod mutator.
+type Step struct {
+	Mutator string            `json:"mutator"`
+	Message string            `json:"message"`
+	Error   string            `json:"error,omitempty"`
+	Values  map[string]string `json:"values,omitempty"`
+}
+
+// Explanation collects the decisions the pod mutators take for a pod that is only being explained.
+type Explanation struct {
+	mu    sync.Mutex
+	steps []Step
+}
+
+type explanationKey struct{}
+
+// NewContext returns a context telling the pod mutators to record their decisions in explanation, and to not have
+// any side effect such as publishing events.
+func NewContext(ctx context.Context, explanation *Explanation) context.Context {
+	return context.WithValue(ctx, explanationKey{}, explanation)
+}
+
+// FromContext returns the explanation carried by ctx, or nil when the pod is really being mutated.
+func FromContext(ctx context.Context) *Explanation {
+	explanation, _ := ctx.Value(explanationKey{}).(*Explanation)
+	return explanation
+}
+
+// Record adds a step to the explanation. keysAndValues are formatted the same way as the ones of a log entry.
+func (e *Explanation) Record(mutator string, err error, message string, keysAndValues ...any) {
+	step := Step{
+		Mutator: mutator,
+		Message: message,
+	}
+	if err != nil {
+		step.Error = err.Error()
+	}
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		if step.Values == nil {
+			step.Values = map[string]string{}
+		}
+		step.Values[fmt.Sprint(keysAndValues[i])] = fmt.Sprint(keysAndValues[i+1])
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.steps = append(e.steps, step)
+}
+
+// Steps returns the steps recorded so far.
+func (e *Explanation) Steps() []Step {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]Step{}, e.steps...)
+}
+
+// Logger returns a logger that, when ctx carries an explanation, also records every message logged by the given
+// mutator as a step, whatever its verbosity. Otherwise, logger is returned as is.
+func Logger(ctx context.Context, logger logr.Logger, mutator string) logr.Logger {
+	explanation := FromContext(ctx)
+	if explanation == nil {
+		return logger
+	}
+	sink := logger.GetSink()
+	if withCallDepth, ok := sink.(logr.CallDepthLogSink); ok {
+		// account for the explainingSink frame
+		sink = withCallDepth.WithCallDepth(1)
+	}
+	return logr.New(explainingSink{sink: sink, explanation: explanation, mutator: mutator})
+}
+
+// explainingSink records log messages as explanation steps before handing them to the wrapped sink.
+type explainingSink struct {
+	sink        logr.LogSink
+	explanation *Explanation
+	mutator     string
+}
+
+var _ logr.LogSink = explainingSink{}
+
+func (s explainingSink) Init(info logr.RuntimeInfo) {
+	if s.sink != nil {
+		s.sink.Init(info)
+	}
+}
+
+// Enabled always returns true so that the verbose messages are explained too.
+func (s explainingSink) Enabled(int) bool {
+	return true
+}
+
+func (s explainingSink) Info(level int, msg string, keysAndValues ...any) {
+	s.explanation.Record(s.mutator, nil, msg, keysAndValues...)
+	if s.sink != nil && s.sink.Enabled(level) {
+		s.sink.Info(level, msg, keysAndValues...)
+	}
+}
+
+func (s explainingSink) Error(err error, msg string, keysAndValues ...any) {
+	s.explanation.Record(s.mutator, err, msg, keysAndValues...)
+	if s.sink != nil {
+		s.sink.Error(err, msg, keysAndValues...)
+	}
+}
+
+func (s explainingSink) WithValues(keysAndValues ...any) logr.LogSink {
+	if s.sink != nil {
+		s.sink = s.sink.WithValues(keysAndValues...)
+	}
+	return s
+}
+
+func (s explainingSink) WithName(name string) logr.LogSink {
+	if s.sink != nil {
+		s.sink = s.sink.WithName(name)
+	}
+	return s
+}
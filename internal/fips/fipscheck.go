@@ -20,7 +20,7 @@ import (
 
 type FIPSCheck interface {
 	// DisabledComponents checks if a submitted components are denied or not.
-	DisabledComponents(receivers map[string]interface{}, exporters map[string]interface{}, processors map[string]interface{}, extensions map[string]interface{}) []string
+	DisabledComponents(receivers map[string]interface{}, exporters map[string]interface{}, processors map[string]interface{}, extensions map[string]interface{}, connectors map[string]interface{}) []string
 }
 
 // FipsCheck holds configuration for FIPS deny list.
@@ -29,15 +29,17 @@ type fipsCheck struct {
 	exporters  map[string]bool
 	processors map[string]bool
 	extensions map[string]bool
+	connectors map[string]bool
 }
 
 // NewFipsCheck creates new FipsCheck.
-func NewFipsCheck(receivers, exporters, processors, extensions []string) FIPSCheck {
+func NewFipsCheck(receivers, exporters, processors, extensions, connectors []string) FIPSCheck {
 	return &fipsCheck{
 		receivers:  listToMap(receivers),
 		exporters:  listToMap(exporters),
 		processors: listToMap(processors),
 		extensions: listToMap(extensions),
+		connectors: listToMap(connectors),
 	}
 }
 
@@ -49,7 +51,7 @@ func listToMap(list []string) map[string]bool {
 	return m
 }
 
-func (fips fipsCheck) DisabledComponents(receivers map[string]interface{}, exporters map[string]interface{}, processors map[string]interface{}, extensions map[string]interface{}) []string {
+func (fips fipsCheck) DisabledComponents(receivers map[string]interface{}, exporters map[string]interface{}, processors map[string]interface{}, extensions map[string]interface{}, connectors map[string]interface{}) []string {
 	var disabled []string
 	if comp := isDisabled(fips.receivers, receivers); comp != "" {
 		disabled = append(disabled, comp)
@@ -63,6 +65,13 @@ func (fips fipsCheck) DisabledComponents(receivers map[string]interface{}, expor
 	if comp := isDisabled(fips.extensions, extensions); comp != "" {
 		disabled = append(disabled, comp)
 	}
+	// connectors act as both the exporter and the receiver of pipelines, which they may have been denied as
+	for _, denyList := range []map[string]bool{fips.connectors, fips.receivers, fips.exporters} {
+		if comp := isDisabled(denyList, connectors); comp != "" {
+			disabled = append(disabled, comp)
+			break
+		}
+	}
 	return disabled
 }
 
@@ -21,12 +21,18 @@ import (
 )
 
 func TestFipsCheck(t *testing.T) {
-	fipsCheck := NewFipsCheck([]string{"rec1", "rec2"}, []string{"exp1"}, []string{"processor"}, []string{"ext1"})
+	fipsCheck := NewFipsCheck([]string{"rec1", "rec2"}, []string{"exp1"}, []string{"processor"}, []string{"ext1"}, []string{"con1"})
 	blocked := fipsCheck.DisabledComponents(
 		map[string]interface{}{"otlp": true, "rec1/my": true},
 		map[string]interface{}{"exp1": true},
 		map[string]interface{}{"processor": true},
-		map[string]interface{}{"ext1": true})
+		map[string]interface{}{"ext1": true},
+		map[string]interface{}{"con1/my": true})
 
-	assert.Equal(t, []string{"rec1", "exp1", "processor", "ext1"}, blocked)
+	assert.Equal(t, []string{"rec1", "exp1", "processor", "ext1", "con1"}, blocked)
+
+	// connectors are also denied as receivers and exporters
+	blocked = fipsCheck.DisabledComponents(nil, nil, nil, nil, map[string]interface{}{"rec2": true})
+	assert.Equal(t, []string{"rec2"}, blocked)
+	assert.Nil(t, fipsCheck.DisabledComponents(nil, nil, nil, nil, map[string]interface{}{"forward": true}))
 }
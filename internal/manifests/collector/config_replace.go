@@ -45,7 +45,14 @@ type Config struct {
 func ReplaceConfig(otelcol v1beta1.OpenTelemetryCollector, targetAllocator *v1alpha1.TargetAllocator, options ...ta.TAOption) (string, error) {
 	collectorSpec := otelcol.Spec
 	taEnabled := targetAllocator != nil
-	cfgStr, err := collectorSpec.Config.Yaml()
+	// the collector reads the values of the secret references from environment variables
+	collectorConfig, err := collectorSpec.Config.WithSecretReferencesAsEnv()
+	if err != nil {
+		return "", err
+	}
+	// the templates are merged by the operator, the collector doesn't know about them
+	collectorConfig.Templates = nil
+	cfgStr, err := collectorConfig.Yaml()
 	if err != nil {
 		return "", err
 	}
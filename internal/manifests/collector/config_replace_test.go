@@ -22,6 +22,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v2"
 
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1beta1"
 	ta "github.com/open-telemetry/opentelemetry-operator/internal/manifests/targetallocator/adapters"
 )
 
@@ -136,4 +137,31 @@ func TestReplaceConfig(t *testing.T) {
 
 		assert.YAMLEq(t, expectedConfig, actualConfig)
 	})
+
+	t.Run("should replace secret references with environment variables", func(t *testing.T) {
+		otelcol := param.OtelCol.DeepCopy()
+		otelcol.Spec.Config.Exporters.Object["otlp"] = map[string]interface{}{
+			"endpoint": "backend:4317",
+			"headers": map[string]interface{}{
+				"api-key": "${secret:backend/api-key}",
+			},
+		}
+
+		actualConfig, err := ReplaceConfig(*otelcol, nil)
+		require.NoError(t, err)
+
+		assert.Contains(t, actualConfig, "api-key: ${env:OTEL_SECRET_BACKEND_API_KEY}")
+		assert.NotContains(t, actualConfig, "${secret:")
+	})
+
+	t.Run("should not render the config templates", func(t *testing.T) {
+		otelcol := param.OtelCol.DeepCopy()
+		otelcol.Spec.Config.Templates = []v1beta1.ConfigTemplateReference{{Name: "base"}}
+
+		actualConfig, err := ReplaceConfig(*otelcol, nil)
+		require.NoError(t, err)
+
+		assert.NotContains(t, actualConfig, "templates")
+		assert.Equal(t, []v1beta1.ConfigTemplateReference{{Name: "base"}}, otelcol.Spec.Config.Templates)
+	})
 }
@@ -183,6 +183,22 @@ func Container(cfg config.Config, logger logr.Logger, otelcol v1beta1.OpenTeleme
 		envVars = append(envVars, configEnvVars...)
 	}
 
+	if secretRefs, err := otelcol.Spec.Config.SecretReferences(); err != nil {
+		logger.Error(err, "could not get the secret references from the config")
+	} else {
+		for _, ref := range secretRefs {
+			envVars = append(envVars, corev1.EnvVar{
+				Name: ref.EnvVarName(),
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: ref.Name},
+						Key:                  ref.Key,
+					},
+				},
+			})
+		}
+	}
+
 	envVars = append(envVars, proxy.ReadProxyVarsFromEnv()...)
 	return corev1.Container{
 		Name:            naming.Container(),
@@ -567,6 +567,54 @@ func TestContainerProxyEnvVars(t *testing.T) {
 	assert.Equal(t, corev1.EnvVar{Name: "no_proxy", Value: "localhost"}, c.Env[2])
 }
 
+func TestContainerSecretReferenceEnvVars(t *testing.T) {
+	otelcol := v1beta1.OpenTelemetryCollector{
+		Spec: v1beta1.OpenTelemetryCollectorSpec{
+			Config: mustUnmarshalToConfig(t, `receivers:
+  otlp:
+    protocols:
+      grpc:
+exporters:
+  otlp:
+    endpoint: backend:4317
+    headers:
+      api-key: ${secret:backend/api-key}
+      authorization: Bearer ${secret:backend/token}
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [otlp]`),
+		},
+	}
+	cfg := config.New()
+
+	// test
+	c := Container(cfg, logger, otelcol, true)
+
+	// verify
+	require.Len(t, c.Env, 3)
+	assert.Equal(t, "POD_NAME", c.Env[0].Name)
+	assert.Equal(t, corev1.EnvVar{
+		Name: "OTEL_SECRET_BACKEND_API_KEY",
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "backend"},
+				Key:                  "api-key",
+			},
+		},
+	}, c.Env[1])
+	assert.Equal(t, corev1.EnvVar{
+		Name: "OTEL_SECRET_BACKEND_TOKEN",
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "backend"},
+				Key:                  "token",
+			},
+		},
+	}, c.Env[2])
+}
+
 func TestContainerResourceRequirements(t *testing.T) {
 	otelcol := v1beta1.OpenTelemetryCollector{
 		Spec: v1beta1.OpenTelemetryCollectorSpec{
@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collectorgroup
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1beta1"
+	"github.com/open-telemetry/opentelemetry-operator/internal/naming"
+)
+
+const (
+	// otlpExporter is the exporter the agents send to the gateway's Service with.
+	otlpExporter = "otlp/gateway"
+	// loadBalancingExporter is the exporter the agents send to the gateway pods with, when load balancing is enabled.
+	loadBalancingExporter = "loadbalancing/gateway"
+)
+
+// routingKeys are the routing keys the load-balancing exporter supports for each signal. Logs are routed on their
+// trace ID whatever the routing key.
+var routingKeys = map[string][]v1alpha1.CollectorGroupRoutingKey{
+	"traces": {
+		v1alpha1.CollectorGroupRoutingKeyTraceID,
+		v1alpha1.CollectorGroupRoutingKeyService,
+	},
+	"metrics": {
+		v1alpha1.CollectorGroupRoutingKeyService,
+		v1alpha1.CollectorGroupRoutingKeyResource,
+		v1alpha1.CollectorGroupRoutingKeyMetric,
+		v1alpha1.CollectorGroupRoutingKeyStreamID,
+	},
+}
+
+// Agent builds the OpenTelemetryCollector of the agents, running as a DaemonSet. The exporter forwarding to the gateway
+// is added to the agent's configuration and to the forwarded pipelines.
+func Agent(params Params) (*v1beta1.OpenTelemetryCollector, error) {
+	spec := params.CollectorGroup.Spec.Agent.DeepCopy()
+	spec.Mode = v1beta1.ModeDaemonSet
+
+	exporterID, exporter, err := gatewayExporter(params)
+	if err != nil {
+		return nil, err
+	}
+	if spec.Config.Exporters.Object == nil {
+		spec.Config.Exporters.Object = map[string]interface{}{}
+	}
+	spec.Config.Exporters.Object[exporterID] = exporter
+
+	pipelines := params.CollectorGroup.Spec.Forwarding.Pipelines
+	if len(pipelines) == 0 {
+		for name := range spec.Config.Service.Pipelines {
+			pipelines = append(pipelines, name)
+		}
+		slices.Sort(pipelines)
+	}
+	if len(pipelines) == 0 {
+		return nil, fmt.Errorf("the agent has no pipeline to forward to the gateway")
+	}
+	for _, name := range pipelines {
+		pipeline, ok := spec.Config.Service.Pipelines[name]
+		if !ok || pipeline == nil {
+			return nil, fmt.Errorf("the agent has no pipeline %q to forward to the gateway", name)
+		}
+		if err = checkRoutingKey(params, name); err != nil {
+			return nil, err
+		}
+		if !slices.Contains(pipeline.Exporters, exporterID) {
+			pipeline.Exporters = append(pipeline.Exporters, exporterID)
+		}
+	}
+
+	return collector(params, naming.CollectorGroupAgent(params.CollectorGroup.Name), *spec), nil
+}
+
+// gatewayExporter returns the ID and configuration of the exporter the agents forward to the gateway with. The gateway
+// receives the telemetry of the agents without TLS.
+func gatewayExporter(params Params) (string, map[string]interface{}, error) {
+	host, port, err := gatewayAddress(params)
+	if err != nil {
+		return "", nil, err
+	}
+	tls := map[string]interface{}{
+		"insecure": true,
+	}
+
+	loadBalancing := params.CollectorGroup.Spec.Forwarding.LoadBalancing
+	if loadBalancing == nil {
+		return otlpExporter, map[string]interface{}{
+			"endpoint": fmt.Sprintf("%s:%d", host, port),
+			"tls":      tls,
+		}, nil
+	}
+
+	exporter := map[string]interface{}{
+		"protocol": map[string]interface{}{
+			"otlp": map[string]interface{}{
+				"tls": tls,
+			},
+		},
+		"resolver": map[string]interface{}{
+			"dns": map[string]interface{}{
+				"hostname": host,
+				"port":     strconv.Itoa(int(port)),
+			},
+		},
+	}
+	// without a routing key, the exporter routes each signal on its default key
+	if loadBalancing.RoutingKey != "" {
+		exporter["routing_key"] = string(loadBalancing.RoutingKey)
+	}
+	return loadBalancingExporter, exporter, nil
+}
+
+// checkRoutingKey returns an error if the load-balancing exporter can't route the signal of the forwarded pipeline on
+// the routing key, which would make the agents fail to start.
+func checkRoutingKey(params Params, pipeline string) error {
+	loadBalancing := params.CollectorGroup.Spec.Forwarding.LoadBalancing
+	if loadBalancing == nil || loadBalancing.RoutingKey == "" {
+		return nil
+	}
+	signal, _, _ := strings.Cut(pipeline, "/")
+	supported, ok := routingKeys[signal]
+	if !ok || slices.Contains(supported, loadBalancing.RoutingKey) {
+		return nil
+	}
+	return fmt.Errorf("the %s pipeline %q of the agent can't be load balanced on the routing key %s, use one of %v", signal, pipeline, loadBalancing.RoutingKey, supported)
+}
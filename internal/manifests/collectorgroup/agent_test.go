@@ -0,0 +1,165 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collectorgroup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
+)
+
+func TestAgentForwardsToGatewayService(t *testing.T) {
+	agent, err := Agent(groupParams(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"endpoint": "my-group-gateway-collector.observability.svc:4319",
+		"tls": map[string]interface{}{
+			"insecure": true,
+		},
+	}, agent.Spec.Config.Exporters.Object[otlpExporter])
+	assert.Equal(t, []string{"debug", otlpExporter}, agent.Spec.Config.Service.Pipelines["traces"].Exporters)
+	assert.Equal(t, []string{otlpExporter}, agent.Spec.Config.Service.Pipelines["metrics"].Exporters)
+}
+
+func TestAgentLoadBalancesToGatewayPods(t *testing.T) {
+	params := groupParams(t)
+	params.CollectorGroup.Spec.Forwarding.LoadBalancing = &v1alpha1.CollectorGroupLoadBalancing{}
+
+	agent, err := Agent(params)
+	require.NoError(t, err)
+
+	assert.NotContains(t, agent.Spec.Config.Exporters.Object, otlpExporter)
+	assert.Equal(t, map[string]interface{}{
+		"protocol": map[string]interface{}{
+			"otlp": map[string]interface{}{
+				"tls": map[string]interface{}{
+					"insecure": true,
+				},
+			},
+		},
+		"resolver": map[string]interface{}{
+			"dns": map[string]interface{}{
+				"hostname": "my-group-gateway-collector-headless.observability.svc",
+				"port":     "4319",
+			},
+		},
+	}, agent.Spec.Config.Exporters.Object[loadBalancingExporter])
+	assert.Equal(t, []string{"debug", loadBalancingExporter}, agent.Spec.Config.Service.Pipelines["traces"].Exporters)
+	assert.Equal(t, []string{loadBalancingExporter}, agent.Spec.Config.Service.Pipelines["metrics"].Exporters)
+}
+
+func TestAgentRoutingKey(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		routingKey v1alpha1.CollectorGroupRoutingKey
+		pipelines  []string
+		expectErr  string
+	}{
+		{
+			name:       "traces and metrics on service",
+			routingKey: v1alpha1.CollectorGroupRoutingKeyService,
+		},
+		{
+			name:       "traces on traceID",
+			routingKey: v1alpha1.CollectorGroupRoutingKeyTraceID,
+			pipelines:  []string{"traces"},
+		},
+		{
+			name:       "metrics on streamID",
+			routingKey: v1alpha1.CollectorGroupRoutingKeyStreamID,
+			pipelines:  []string{"metrics"},
+		},
+		{
+			name:       "metrics on traceID",
+			routingKey: v1alpha1.CollectorGroupRoutingKeyTraceID,
+			expectErr:  `the metrics pipeline "metrics" of the agent can't be load balanced on the routing key traceID, use one of [service resource metric streamID]`,
+		},
+		{
+			name:       "traces on metric",
+			routingKey: v1alpha1.CollectorGroupRoutingKeyMetric,
+			pipelines:  []string{"traces"},
+			expectErr:  `the traces pipeline "traces" of the agent can't be load balanced on the routing key metric, use one of [traceID service]`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			params := groupParams(t)
+			params.CollectorGroup.Spec.Forwarding.Pipelines = tt.pipelines
+			params.CollectorGroup.Spec.Forwarding.LoadBalancing = &v1alpha1.CollectorGroupLoadBalancing{RoutingKey: tt.routingKey}
+
+			agent, err := Agent(params)
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			exporter, ok := agent.Spec.Config.Exporters.Object[loadBalancingExporter].(map[string]interface{})
+			require.True(t, ok)
+			assert.Equal(t, string(tt.routingKey), exporter["routing_key"])
+		})
+	}
+}
+
+func TestAgentForwardedPipelines(t *testing.T) {
+	for _, tt := range []struct {
+		name             string
+		pipelines        []string
+		metricsExporters []string
+		expected         map[string][]string
+		expectErr        string
+	}{
+		{
+			name:      "selected pipeline",
+			pipelines: []string{"traces"},
+			expected: map[string][]string{
+				"traces":  {"debug", otlpExporter},
+				"metrics": nil,
+			},
+		},
+		{
+			name:             "pipeline already forwarding",
+			pipelines:        []string{"metrics"},
+			metricsExporters: []string{otlpExporter},
+			expected: map[string][]string{
+				"traces":  {"debug"},
+				"metrics": {otlpExporter},
+			},
+		},
+		{
+			name:      "undefined pipeline",
+			pipelines: []string{"logs"},
+			expectErr: `the agent has no pipeline "logs" to forward to the gateway`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			params := groupParams(t)
+			params.CollectorGroup.Spec.Forwarding.Pipelines = tt.pipelines
+			params.CollectorGroup.Spec.Agent.Config.Service.Pipelines["metrics"].Exporters = tt.metricsExporters
+
+			agent, err := Agent(params)
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			for name, exporters := range tt.expected {
+				assert.ElementsMatch(t, exporters, agent.Spec.Config.Service.Pipelines[name].Exporters, name)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collectorgroup
+
+import (
+	"maps"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1beta1"
+	"github.com/open-telemetry/opentelemetry-operator/internal/manifests"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/constants"
+)
+
+// Params holds the parameters to build the collectors of a CollectorGroup.
+type Params struct {
+	Log            logr.Logger
+	CollectorGroup v1alpha1.CollectorGroup
+}
+
+// Build creates the OpenTelemetryCollectors of the CollectorGroup.
+func Build(params Params) ([]client.Object, error) {
+	var resourceManifests []client.Object
+	resourceFactories := []manifests.K8sManifestFactory[Params]{
+		manifests.Factory(Agent),
+		manifests.FactoryWithoutError(Gateway),
+	}
+	for _, factory := range resourceFactories {
+		res, err := factory(params)
+		if err != nil {
+			return nil, err
+		} else if manifests.ObjectIsNotNil(res) {
+			resourceManifests = append(resourceManifests, res)
+		}
+	}
+	return resourceManifests, nil
+}
+
+// collector builds an OpenTelemetryCollector of the group with the given name and spec.
+func collector(params Params, name string, spec v1beta1.OpenTelemetryCollectorSpec) *v1beta1.OpenTelemetryCollector {
+	labels := maps.Clone(params.CollectorGroup.Labels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[constants.LabelCollectorGroup] = params.CollectorGroup.Name
+
+	return &v1beta1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: params.CollectorGroup.Namespace,
+			Labels:    labels,
+		},
+		Spec: spec,
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collectorgroup
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1beta1"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/constants"
+)
+
+const (
+	agentConfig = `
+receivers:
+  otlp:
+    protocols:
+      grpc: {}
+  hostmetrics:
+    scrapers:
+      cpu: {}
+exporters:
+  debug: {}
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [debug]
+    metrics:
+      receivers: [hostmetrics]
+      exporters: []
+`
+	gatewayConfig = `
+receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4319
+      http: {}
+exporters:
+  debug: {}
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [debug]
+`
+)
+
+func collectorConfig(t *testing.T, cfg string) v1beta1.Config {
+	t.Helper()
+	var config v1beta1.Config
+	require.NoError(t, yaml.Unmarshal([]byte(cfg), &config))
+	return config
+}
+
+func groupParams(t *testing.T) Params {
+	t.Helper()
+	return Params{
+		Log: logr.Discard(),
+		CollectorGroup: v1alpha1.CollectorGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-group",
+				Namespace: "observability",
+				Labels: map[string]string{
+					"team": "infra",
+				},
+			},
+			Spec: v1alpha1.CollectorGroupSpec{
+				Agent: v1beta1.OpenTelemetryCollectorSpec{
+					Mode:   v1beta1.ModeDeployment,
+					Config: collectorConfig(t, agentConfig),
+				},
+				Gateway: v1beta1.OpenTelemetryCollectorSpec{
+					Config: collectorConfig(t, gatewayConfig),
+					TargetAllocator: v1beta1.TargetAllocatorEmbedded{
+						Enabled: true,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	params := groupParams(t)
+
+	objects, err := Build(params)
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+
+	agent, ok := objects[0].(*v1beta1.OpenTelemetryCollector)
+	require.True(t, ok)
+	assert.Equal(t, "my-group-agent", agent.Name)
+	assert.Equal(t, "observability", agent.Namespace)
+	assert.Equal(t, v1beta1.ModeDaemonSet, agent.Spec.Mode)
+
+	gateway, ok := objects[1].(*v1beta1.OpenTelemetryCollector)
+	require.True(t, ok)
+	assert.Equal(t, "my-group-gateway", gateway.Name)
+	assert.Equal(t, "observability", gateway.Namespace)
+	assert.Equal(t, v1beta1.ModeStatefulSet, gateway.Spec.Mode)
+	assert.True(t, gateway.Spec.TargetAllocator.Enabled)
+
+	for _, collector := range []*v1beta1.OpenTelemetryCollector{agent, gateway} {
+		assert.Equal(t, map[string]string{
+			"team":                        "infra",
+			constants.LabelCollectorGroup: "my-group",
+		}, collector.Labels)
+	}
+
+	// the specs of the group are left untouched
+	assert.Equal(t, v1beta1.ModeDeployment, params.CollectorGroup.Spec.Agent.Mode)
+	assert.NotContains(t, params.CollectorGroup.Spec.Agent.Config.Exporters.Object, otlpExporter)
+	assert.Equal(t, []string{"debug"}, params.CollectorGroup.Spec.Agent.Config.Service.Pipelines["traces"].Exporters)
+}
+
+func TestBuildWithoutGatewayReceiver(t *testing.T) {
+	params := groupParams(t)
+	params.CollectorGroup.Spec.Gateway.Config = collectorConfig(t, agentConfig)
+	delete(params.CollectorGroup.Spec.Gateway.Config.Service.Pipelines, "traces")
+
+	_, err := Build(params)
+	assert.ErrorContains(t, err, "the gateway must have an otlp receiver with the grpc protocol enabled in a pipeline")
+}
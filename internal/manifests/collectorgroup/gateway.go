@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collectorgroup
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1beta1"
+	"github.com/open-telemetry/opentelemetry-operator/internal/naming"
+)
+
+// Gateway builds the OpenTelemetryCollector of the gateway, running as a StatefulSet.
+func Gateway(params Params) *v1beta1.OpenTelemetryCollector {
+	spec := params.CollectorGroup.Spec.Gateway.DeepCopy()
+	spec.Mode = v1beta1.ModeStatefulSet
+	return collector(params, naming.CollectorGroupGateway(params.CollectorGroup.Name), *spec)
+}
+
+// GatewayEndpoint returns the endpoint the agents export to: the gateway's Service, or its headless Service when the
+// agents load balance between the gateway pods.
+func GatewayEndpoint(params Params) (string, error) {
+	host, port, err := gatewayAddress(params)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s:%d", host, port), nil
+}
+
+// gatewayAddress returns the host and port of the Service the agents export to.
+func gatewayAddress(params Params) (string, int32, error) {
+	port, err := gatewayPort(params)
+	if err != nil {
+		return "", 0, err
+	}
+	gateway := naming.CollectorGroupGateway(params.CollectorGroup.Name)
+	service := naming.Service(gateway)
+	if params.CollectorGroup.Spec.Forwarding.LoadBalancing != nil {
+		service = naming.HeadlessService(gateway)
+	}
+	return fmt.Sprintf("%s.%s.svc", service, params.CollectorGroup.Namespace), port, nil
+}
+
+// gatewayPort returns the port of the gRPC protocol of the gateway's otlp receiver. The otlp receiver without a name is
+// preferred when the gateway has several of them.
+func gatewayPort(params Params) (int32, error) {
+	ports, err := params.CollectorGroup.Spec.Gateway.Config.GetReceiverPorts(params.Log)
+	if err != nil {
+		return 0, err
+	}
+	ports = slices.DeleteFunc(ports, func(port corev1.ServicePort) bool {
+		return !strings.HasPrefix(port.Name, "otlp-") || !strings.HasSuffix(port.Name, "-grpc")
+	})
+	if len(ports) == 0 {
+		return 0, errors.New("the gateway must have an otlp receiver with the grpc protocol enabled in a pipeline")
+	}
+	slices.SortStableFunc(ports, func(a, b corev1.ServicePort) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	for _, port := range ports {
+		if port.Name == "otlp-grpc" {
+			return port.Port, nil
+		}
+	}
+	return ports[0].Port, nil
+}
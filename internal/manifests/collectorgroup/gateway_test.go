@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collectorgroup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
+)
+
+func TestGatewayEndpoint(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		gatewayConfig string
+		loadBalancing bool
+		expected      string
+	}{
+		{
+			name:          "gateway service",
+			gatewayConfig: gatewayConfig,
+			expected:      "my-group-gateway-collector.observability.svc:4319",
+		},
+		{
+			name:          "gateway headless service",
+			gatewayConfig: gatewayConfig,
+			loadBalancing: true,
+			expected:      "my-group-gateway-collector-headless.observability.svc:4319",
+		},
+		{
+			name: "named otlp receivers",
+			gatewayConfig: `
+receivers:
+  otlp/b:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:5000
+  otlp/a:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:6000
+exporters:
+  debug: {}
+service:
+  pipelines:
+    traces:
+      receivers: [otlp/b, otlp/a]
+      exporters: [debug]
+`,
+			expected: "my-group-gateway-collector.observability.svc:6000",
+		},
+		{
+			name: "default otlp receiver is preferred",
+			gatewayConfig: `
+receivers:
+  otlp/a:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:6000
+  otlp:
+    protocols:
+      grpc: {}
+exporters:
+  debug: {}
+service:
+  pipelines:
+    traces:
+      receivers: [otlp, otlp/a]
+      exporters: [debug]
+`,
+			expected: "my-group-gateway-collector.observability.svc:4317",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			params := groupParams(t)
+			params.CollectorGroup.Spec.Gateway.Config = collectorConfig(t, tt.gatewayConfig)
+			if tt.loadBalancing {
+				params.CollectorGroup.Spec.Forwarding.LoadBalancing = &v1alpha1.CollectorGroupLoadBalancing{}
+			}
+
+			endpoint, err := GatewayEndpoint(params)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, endpoint)
+		})
+	}
+}
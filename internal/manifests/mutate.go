@@ -33,6 +33,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1beta1"
 )
 
 type ImmutableFieldChangeErr struct {
@@ -66,6 +67,7 @@ var (
 // - Route
 // - Secret
 // - TargetAllocator
+// - OpenTelemetryCollector
 // In order for the operator to reconcile other types, they must be added here.
 // The function returned takes no arguments but instead uses the existing and desired inputs here. Existing is expected
 // to be set by the controller-runtime package through a client get call.
@@ -192,6 +194,11 @@ func MutateFuncFor(existing, desired client.Object) controllerutil.MutateFn {
 			wantTa := desired.(*v1alpha1.TargetAllocator)
 			mutateTargetAllocator(ta, wantTa)
 
+		case *v1beta1.OpenTelemetryCollector:
+			otelcol := existing.(*v1beta1.OpenTelemetryCollector)
+			wantOtelcol := desired.(*v1beta1.OpenTelemetryCollector)
+			mutateOpenTelemetryCollector(otelcol, wantOtelcol)
+
 		default:
 			t := reflect.TypeOf(existing).String()
 			return fmt.Errorf("missing mutate implementation for resource type: %s", t)
@@ -287,6 +294,12 @@ func mutateTargetAllocator(existing, desired *v1alpha1.TargetAllocator) {
 	existing.Spec = desired.Spec
 }
 
+func mutateOpenTelemetryCollector(existing, desired *v1beta1.OpenTelemetryCollector) {
+	existing.Annotations = desired.Annotations
+	existing.Labels = desired.Labels
+	existing.Spec = desired.Spec
+}
+
 func mutateService(existing, desired *corev1.Service) {
 	existing.Spec.Ports = desired.Spec.Ports
 	existing.Spec.Selector = desired.Spec.Selector
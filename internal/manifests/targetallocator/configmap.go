@@ -126,6 +126,14 @@ func ConfigMap(params Params) (*corev1.ConfigMap, error) {
 		}
 	}
 
+	if featuregate.EnableTargetAllocatorLeaderElection.IsEnabled() && taSpec.Replicas != nil && *taSpec.Replicas > 1 {
+		taConfig["leader_election"] = map[string]interface{}{
+			"enabled":         true,
+			"lease_name":      naming.TargetAllocator(instance.Name),
+			"lease_namespace": instance.Namespace,
+		}
+	}
+
 	taConfigYAML, err := yaml.Marshal(taConfig)
 	if err != nil {
 		return &corev1.ConfigMap{}, err
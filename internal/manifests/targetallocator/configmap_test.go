@@ -25,6 +25,7 @@ import (
 	"github.com/stretchr/testify/require"
 	colfg "go.opentelemetry.io/collector/featuregate"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	"github.com/open-telemetry/opentelemetry-operator/apis/v1beta1"
 	"github.com/open-telemetry/opentelemetry-operator/internal/autodetect/certmanager"
@@ -376,6 +377,36 @@ prometheus_cr:
 		assert.Equal(t, expectedLabels, actual.Labels)
 		assert.Equal(t, expectedData, actual.Data)
 	})
+
+	t.Run("should return expected target allocator config map with leader election", func(t *testing.T) {
+		flgs := featuregate.Flags(colfg.GlobalRegistry())
+		require.NoError(t, flgs.Parse([]string{"--feature-gates=operator.targetallocator.leaderelection"}))
+		defer func() {
+			require.NoError(t, flgs.Parse([]string{"--feature-gates=-operator.targetallocator.leaderelection"}))
+		}()
+
+		replicatedTargetAllocator := targetAllocator.DeepCopy()
+		replicatedTargetAllocator.Spec.Replicas = ptr.To(int32(2))
+		testParams := Params{
+			Collector:       collector,
+			TargetAllocator: *replicatedTargetAllocator,
+			Config:          config.New(config.WithCertManagerAvailability(certmanager.Available)),
+		}
+
+		actual, err := ConfigMap(testParams)
+		require.NoError(t, err)
+		assert.Contains(t, actual.Data[targetAllocatorFilename], `leader_election:
+  enabled: true
+  lease_name: my-instance-targetallocator
+  lease_namespace: default
+`)
+
+		// a single replica doesn't need it
+		testParams.TargetAllocator = targetAllocator
+		actual, err = ConfigMap(testParams)
+		require.NoError(t, err)
+		assert.NotContains(t, actual.Data[targetAllocatorFilename], "leader_election")
+	})
 }
 
 func TestGetScrapeConfigsFromOtelConfig(t *testing.T) {
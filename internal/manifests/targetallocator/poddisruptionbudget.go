@@ -32,15 +32,17 @@ func PodDisruptionBudget(params Params) (*policyV1.PodDisruptionBudget, error) {
 	// if PodDisruptionBudget != nil and stategy isn't correct, users have set
 	// it wrongly
 	if pdbSpec != nil && params.TargetAllocator.Spec.AllocationStrategy != v1beta1.TargetAllocatorAllocationStrategyConsistentHashing &&
-		params.TargetAllocator.Spec.AllocationStrategy != v1beta1.TargetAllocatorAllocationStrategyPerNode {
+		params.TargetAllocator.Spec.AllocationStrategy != v1beta1.TargetAllocatorAllocationStrategyPerNode &&
+		params.TargetAllocator.Spec.AllocationStrategy != v1beta1.TargetAllocatorAllocationStrategyZoneAware {
 		params.Log.V(4).Info("current allocation strategy not compatible, skipping podDisruptionBudget creation")
 		return nil, fmt.Errorf("target allocator pdb has been configured but the allocation strategy isn't not compatible")
-	} else if pdbSpec == nil && params.TargetAllocator.Spec.AllocationStrategy == v1beta1.TargetAllocatorAllocationStrategyLeastWeighted {
+	} else if pdbSpec == nil && (params.TargetAllocator.Spec.AllocationStrategy == v1beta1.TargetAllocatorAllocationStrategyLeastWeighted ||
+		params.TargetAllocator.Spec.AllocationStrategy == v1beta1.TargetAllocatorAllocationStrategyCostWeighted) {
 		params.Log.V(4).Info("current allocation strategy not compatible, skipping podDisruptionBudget creation")
 		return nil, nil
 	}
 	// if pdb isn't provided for target allocator and it's enabled
-	// using a valid strategy (consistent-hashing, per-node),
+	// using a valid strategy (consistent-hashing, per-node, zone-aware),
 	// we set MaxUnavailable 1, which will work even if there is
 	// just one replica, not blocking node drains but preventing
 	// out-of-the-box from disruption generated by them with replicas > 1
@@ -177,19 +177,26 @@ func TestPDBWithNotValidStrategy(t *testing.T) {
 }
 
 func TestNoPDB(t *testing.T) {
-	targetAllocator := v1alpha1.TargetAllocator{
-		Spec: v1alpha1.TargetAllocatorSpec{
-			AllocationStrategy: v1beta1.TargetAllocatorAllocationStrategyLeastWeighted,
-		},
-	}
-	configuration := config.New()
-	pdb, err := PodDisruptionBudget(Params{
-		Log:             logger,
-		Config:          configuration,
-		TargetAllocator: targetAllocator,
-	})
+	for _, strategy := range []v1beta1.TargetAllocatorAllocationStrategy{
+		v1beta1.TargetAllocatorAllocationStrategyLeastWeighted,
+		v1beta1.TargetAllocatorAllocationStrategyCostWeighted,
+	} {
+		t.Run(string(strategy), func(t *testing.T) {
+			targetAllocator := v1alpha1.TargetAllocator{
+				Spec: v1alpha1.TargetAllocatorSpec{
+					AllocationStrategy: strategy,
+				},
+			}
+			configuration := config.New()
+			pdb, err := PodDisruptionBudget(Params{
+				Log:             logger,
+				Config:          configuration,
+				TargetAllocator: targetAllocator,
+			})
 
-	// verify
-	assert.NoError(t, err)
-	assert.Nil(t, pdb)
+			// verify
+			assert.NoError(t, err)
+			assert.Nil(t, pdb)
+		})
+	}
 }
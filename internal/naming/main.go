@@ -106,6 +106,16 @@ func OpAMPBridge(opampBridge string) string {
 	return DNSName(Truncate("%s-opamp-bridge", 63, opampBridge))
 }
 
+// CollectorGroupAgent returns the name of the agent OpenTelemetryCollector of a CollectorGroup.
+func CollectorGroupAgent(collectorGroup string) string {
+	return DNSName(Truncate("%s-agent", 63, collectorGroup))
+}
+
+// CollectorGroupGateway returns the name of the gateway OpenTelemetryCollector of a CollectorGroup.
+func CollectorGroupGateway(collectorGroup string) string {
+	return DNSName(Truncate("%s-gateway", 63, collectorGroup))
+}
+
 // HeadlessService builds the name for the headless service based on the instance.
 func HeadlessService(otelcol string) string {
 	return DNSName(Truncate("%s-headless", 63, Service(otelcol)))
@@ -20,6 +20,9 @@ import (
 	"strconv"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -41,6 +44,8 @@ func UpdateCollectorStatus(ctx context.Context, cli client.Client, changed *v1be
 	if mode == v1beta1.ModeSidecar {
 		changed.Status.Scale.Replicas = 0
 		changed.Status.Scale.Selector = ""
+		setCondition(changed, v1beta1.ConditionTypeReady, metav1.ConditionTrue, v1beta1.ReasonSidecarMode, "sidecar collectors are injected into the pods that request them")
+		setCondition(changed, v1beta1.ConditionTypeDegraded, metav1.ConditionFalse, v1beta1.ReasonAsExpected, "")
 		return nil
 	}
 
@@ -64,39 +69,152 @@ func UpdateCollectorStatus(ctx context.Context, cli client.Client, changed *v1be
 	var readyReplicas int32
 	var statusReplicas string
 	var statusImage string
+	var rollout rolloutState
 
 	switch mode { // nolint:exhaustive
 	case v1beta1.ModeDeployment:
 		obj := &appsv1.Deployment{}
 		if err := cli.Get(ctx, objKey, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				setWorkloadNotFound(changed, "Deployment")
+				return nil
+			}
 			return fmt.Errorf("failed to get deployment status.replicas: %w", err)
 		}
 		replicas = obj.Status.Replicas
 		readyReplicas = obj.Status.ReadyReplicas
 		statusReplicas = strconv.Itoa(int(readyReplicas)) + "/" + strconv.Itoa(int(replicas))
 		statusImage = obj.Spec.Template.Spec.Containers[0].Image
+		rollout = deploymentRolloutState(obj)
 
 	case v1beta1.ModeStatefulSet:
 		obj := &appsv1.StatefulSet{}
 		if err := cli.Get(ctx, objKey, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				setWorkloadNotFound(changed, "StatefulSet")
+				return nil
+			}
 			return fmt.Errorf("failed to get statefulSet status.replicas: %w", err)
 		}
 		replicas = obj.Status.Replicas
 		readyReplicas = obj.Status.ReadyReplicas
 		statusReplicas = strconv.Itoa(int(readyReplicas)) + "/" + strconv.Itoa(int(replicas))
 		statusImage = obj.Spec.Template.Spec.Containers[0].Image
+		rollout = statefulSetRolloutState(obj)
 
 	case v1beta1.ModeDaemonSet:
 		obj := &appsv1.DaemonSet{}
 		if err := cli.Get(ctx, objKey, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				setWorkloadNotFound(changed, "DaemonSet")
+				return nil
+			}
 			return fmt.Errorf("failed to get daemonSet status.replicas: %w", err)
 		}
+		// a daemonset has no replicas of its own, report the scheduled pods instead
+		statusReplicas = strconv.Itoa(int(obj.Status.NumberReady)) + "/" + strconv.Itoa(int(obj.Status.DesiredNumberScheduled))
 		statusImage = obj.Spec.Template.Spec.Containers[0].Image
+		rollout = daemonSetRolloutState(obj)
 	}
 
 	changed.Status.Scale.Replicas = replicas
 	changed.Status.Image = statusImage
 	changed.Status.Scale.StatusReplicas = statusReplicas
 
+	switch {
+	case rollout.failed:
+		setCondition(changed, v1beta1.ConditionTypeReady, metav1.ConditionFalse, v1beta1.ReasonWorkloadFailed, rollout.message)
+		setCondition(changed, v1beta1.ConditionTypeDegraded, metav1.ConditionTrue, v1beta1.ReasonWorkloadFailed, rollout.message)
+	case rollout.ready:
+		setCondition(changed, v1beta1.ConditionTypeReady, metav1.ConditionTrue, v1beta1.ReasonWorkloadReady, rollout.message)
+		setCondition(changed, v1beta1.ConditionTypeDegraded, metav1.ConditionFalse, v1beta1.ReasonAsExpected, "")
+	default:
+		setCondition(changed, v1beta1.ConditionTypeReady, metav1.ConditionFalse, v1beta1.ReasonWorkloadProgressing, rollout.message)
+		setCondition(changed, v1beta1.ConditionTypeDegraded, metav1.ConditionFalse, v1beta1.ReasonAsExpected, "")
+	}
+
 	return nil
 }
+
+// rolloutState summarizes the rollout of a collector workload.
+type rolloutState struct {
+	ready   bool
+	failed  bool
+	message string
+}
+
+func deploymentRolloutState(obj *appsv1.Deployment) rolloutState {
+	for _, cond := range obj.Status.Conditions {
+		if cond.Type == appsv1.DeploymentProgressing && cond.Status == corev1.ConditionFalse && cond.Reason == "ProgressDeadlineExceeded" {
+			return rolloutState{failed: true, message: cond.Message}
+		}
+		if cond.Type == appsv1.DeploymentReplicaFailure && cond.Status == corev1.ConditionTrue {
+			return rolloutState{failed: true, message: cond.Message}
+		}
+	}
+	desired := int32(1)
+	if obj.Spec.Replicas != nil {
+		desired = *obj.Spec.Replicas
+	}
+	if obj.Status.ObservedGeneration < obj.Generation {
+		return rolloutState{message: "waiting for the deployment spec update to be observed"}
+	}
+	if obj.Status.UpdatedReplicas < desired {
+		return rolloutState{message: fmt.Sprintf("%d of %d updated replicas are available", obj.Status.UpdatedReplicas, desired)}
+	}
+	if obj.Status.Replicas > obj.Status.UpdatedReplicas {
+		return rolloutState{message: fmt.Sprintf("%d old replicas are pending termination", obj.Status.Replicas-obj.Status.UpdatedReplicas)}
+	}
+	if obj.Status.ReadyReplicas < desired {
+		return rolloutState{message: fmt.Sprintf("%d of %d replicas are ready", obj.Status.ReadyReplicas, desired)}
+	}
+	return rolloutState{ready: true, message: fmt.Sprintf("%d of %d replicas are ready", obj.Status.ReadyReplicas, desired)}
+}
+
+func statefulSetRolloutState(obj *appsv1.StatefulSet) rolloutState {
+	desired := int32(1)
+	if obj.Spec.Replicas != nil {
+		desired = *obj.Spec.Replicas
+	}
+	if obj.Status.ObservedGeneration < obj.Generation {
+		return rolloutState{message: "waiting for the statefulset spec update to be observed"}
+	}
+	if obj.Status.UpdateRevision != "" && obj.Status.CurrentRevision != obj.Status.UpdateRevision {
+		return rolloutState{message: fmt.Sprintf("%d of %d replicas are updated", obj.Status.UpdatedReplicas, desired)}
+	}
+	if obj.Status.ReadyReplicas < desired {
+		return rolloutState{message: fmt.Sprintf("%d of %d replicas are ready", obj.Status.ReadyReplicas, desired)}
+	}
+	return rolloutState{ready: true, message: fmt.Sprintf("%d of %d replicas are ready", obj.Status.ReadyReplicas, desired)}
+}
+
+func daemonSetRolloutState(obj *appsv1.DaemonSet) rolloutState {
+	desired := obj.Status.DesiredNumberScheduled
+	if obj.Status.ObservedGeneration < obj.Generation {
+		return rolloutState{message: "waiting for the daemonset spec update to be observed"}
+	}
+	if obj.Status.UpdatedNumberScheduled < desired {
+		return rolloutState{message: fmt.Sprintf("%d of %d scheduled pods are updated", obj.Status.UpdatedNumberScheduled, desired)}
+	}
+	if obj.Status.NumberReady < desired {
+		return rolloutState{message: fmt.Sprintf("%d of %d scheduled pods are ready", obj.Status.NumberReady, desired)}
+	}
+	return rolloutState{ready: true, message: fmt.Sprintf("%d of %d scheduled pods are ready", obj.Status.NumberReady, desired)}
+}
+
+func setWorkloadNotFound(changed *v1beta1.OpenTelemetryCollector, kind string) {
+	msg := fmt.Sprintf("%s %s does not exist", kind, naming.Collector(changed.Name))
+	setCondition(changed, v1beta1.ConditionTypeReady, metav1.ConditionFalse, v1beta1.ReasonWorkloadNotFound, msg)
+	setCondition(changed, v1beta1.ConditionTypeDegraded, metav1.ConditionFalse, v1beta1.ReasonAsExpected, "")
+}
+
+// setCondition sets the given condition on the collector status, stamping it with the collector's generation.
+func setCondition(changed *v1beta1.OpenTelemetryCollector, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&changed.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: changed.Generation,
+	})
+}
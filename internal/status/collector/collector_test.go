@@ -16,11 +16,13 @@ package collector
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -190,3 +192,187 @@ func TestUpdateCollectorStatusDaemonsetMode(t *testing.T) {
 	assert.Contains(t, changed.Status.Scale.Selector, "customLabel=customValue", "expected selector to contain customlabel=customValue")
 	assert.Equal(t, "app:latest", changed.Status.Image, "expected image to be app:latest")
 }
+
+func TestUpdateCollectorStatusConditions(t *testing.T) {
+	replicas := int32(2)
+	for _, tt := range []struct {
+		name           string
+		deployment     *appsv1.Deployment
+		expectedReady  metav1.ConditionStatus
+		expectedReason string
+		expectDegraded metav1.ConditionStatus
+	}{
+		{
+			name: "rolled out",
+			deployment: &appsv1.Deployment{
+				Spec: appsv1.DeploymentSpec{Replicas: &replicas},
+				Status: appsv1.DeploymentStatus{
+					Replicas:        2,
+					UpdatedReplicas: 2,
+					ReadyReplicas:   2,
+				},
+			},
+			expectedReady:  metav1.ConditionTrue,
+			expectedReason: v1beta1.ReasonWorkloadReady,
+			expectDegraded: metav1.ConditionFalse,
+		},
+		{
+			name: "rolling out",
+			deployment: &appsv1.Deployment{
+				Spec: appsv1.DeploymentSpec{Replicas: &replicas},
+				Status: appsv1.DeploymentStatus{
+					Replicas:        3,
+					UpdatedReplicas: 1,
+					ReadyReplicas:   2,
+				},
+			},
+			expectedReady:  metav1.ConditionFalse,
+			expectedReason: v1beta1.ReasonWorkloadProgressing,
+			expectDegraded: metav1.ConditionFalse,
+		},
+		{
+			name: "progress deadline exceeded",
+			deployment: &appsv1.Deployment{
+				Spec: appsv1.DeploymentSpec{Replicas: &replicas},
+				Status: appsv1.DeploymentStatus{
+					Replicas:        2,
+					UpdatedReplicas: 1,
+					Conditions: []appsv1.DeploymentCondition{
+						{
+							Type:   appsv1.DeploymentProgressing,
+							Status: corev1.ConditionFalse,
+							Reason: "ProgressDeadlineExceeded",
+						},
+					},
+				},
+			},
+			expectedReady:  metav1.ConditionFalse,
+			expectedReason: v1beta1.ReasonWorkloadFailed,
+			expectDegraded: metav1.ConditionTrue,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.deployment.ObjectMeta = metav1.ObjectMeta{
+				Name:      "test-collector",
+				Namespace: "default",
+			}
+			tt.deployment.Spec.Template.Spec.Containers = []corev1.Container{{Name: "otc-container", Image: "app:latest"}}
+			cli := fake.NewClientBuilder().WithObjects(tt.deployment).Build()
+
+			changed := &v1beta1.OpenTelemetryCollector{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test",
+					Namespace:  "default",
+					Generation: 3,
+				},
+				Spec: v1beta1.OpenTelemetryCollectorSpec{
+					Mode: v1beta1.ModeDeployment,
+				},
+			}
+
+			err := UpdateCollectorStatus(context.TODO(), cli, changed)
+			assert.NoError(t, err)
+
+			ready := meta.FindStatusCondition(changed.Status.Conditions, v1beta1.ConditionTypeReady)
+			assert.NotNil(t, ready)
+			assert.Equal(t, tt.expectedReady, ready.Status)
+			assert.Equal(t, tt.expectedReason, ready.Reason)
+			assert.Equal(t, int64(3), ready.ObservedGeneration)
+			assert.True(t, meta.IsStatusConditionPresentAndEqual(changed.Status.Conditions, v1beta1.ConditionTypeDegraded, tt.expectDegraded))
+		})
+	}
+}
+
+func TestUpdateCollectorStatusDaemonsetReadiness(t *testing.T) {
+	daemonset := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-daemonset-collector",
+			Namespace: "default",
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "app", Image: "app:latest"}},
+				},
+			},
+		},
+		Status: appsv1.DaemonSetStatus{
+			DesiredNumberScheduled: 3,
+			UpdatedNumberScheduled: 3,
+			NumberReady:            2,
+		},
+	}
+	cli := fake.NewClientBuilder().WithObjects(daemonset).Build()
+
+	changed := &v1beta1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-daemonset",
+			Namespace: "default",
+		},
+		Spec: v1beta1.OpenTelemetryCollectorSpec{
+			Mode: v1beta1.ModeDaemonSet,
+		},
+	}
+
+	err := UpdateCollectorStatus(context.TODO(), cli, changed)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "2/3", changed.Status.Scale.StatusReplicas)
+	assert.True(t, meta.IsStatusConditionFalse(changed.Status.Conditions, v1beta1.ConditionTypeReady))
+}
+
+func TestUpdateCollectorStatusWorkloadNotFound(t *testing.T) {
+	changed := &v1beta1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-statefulset",
+			Namespace: "default",
+		},
+		Spec: v1beta1.OpenTelemetryCollectorSpec{
+			Mode: v1beta1.ModeStatefulSet,
+		},
+	}
+
+	err := UpdateCollectorStatus(context.TODO(), fake.NewFakeClient(), changed)
+	assert.NoError(t, err)
+
+	ready := meta.FindStatusCondition(changed.Status.Conditions, v1beta1.ConditionTypeReady)
+	assert.NotNil(t, ready)
+	assert.Equal(t, v1beta1.ReasonWorkloadNotFound, ready.Reason)
+}
+
+func TestSetReconcileFailed(t *testing.T) {
+	for _, tt := range []struct {
+		name              string
+		err               error
+		expectConfigValid metav1.ConditionStatus
+		expectedReason    string
+	}{
+		{
+			name:              "invalid config",
+			err:               &InvalidConfigError{Err: errors.New("no receivers")},
+			expectConfigValid: metav1.ConditionFalse,
+			expectedReason:    v1beta1.ReasonInvalidConfig,
+		},
+		{
+			name:              "apply failure",
+			err:               errors.New("conflict"),
+			expectConfigValid: metav1.ConditionTrue,
+			expectedReason:    v1beta1.ReasonReconcileFailed,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := &v1beta1.OpenTelemetryCollector{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			}
+			setReconcileFailed(changed, tt.err)
+
+			assert.Equal(t, int64(2), changed.Status.ObservedGeneration)
+			assert.True(t, meta.IsStatusConditionPresentAndEqual(changed.Status.Conditions, v1beta1.ConditionTypeConfigValid, tt.expectConfigValid))
+			reconciled := meta.FindStatusCondition(changed.Status.Conditions, v1beta1.ConditionTypeReconciled)
+			assert.NotNil(t, reconciled)
+			assert.Equal(t, metav1.ConditionFalse, reconciled.Status)
+			assert.Equal(t, tt.expectedReason, reconciled.Reason)
+			assert.True(t, meta.IsStatusConditionTrue(changed.Status.Conditions, v1beta1.ConditionTypeDegraded))
+		})
+	}
+}
@@ -16,9 +16,11 @@ package collector
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -37,11 +39,29 @@ const (
 	reasonInfo          = "Info"
 )
 
+// InvalidConfigError is returned when the manifests for a collector cannot be built from its configuration.
+type InvalidConfigError struct {
+	Err error
+}
+
+func (e *InvalidConfigError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *InvalidConfigError) Unwrap() error {
+	return e.Err
+}
+
 // HandleReconcileStatus handles updating the status of the CRDs managed by the operator.
 func HandleReconcileStatus(ctx context.Context, log logr.Logger, params manifests.Params, otelcol v1beta1.OpenTelemetryCollector, err error) (ctrl.Result, error) {
 	log.V(2).Info("updating collector status")
 	if err != nil {
 		params.Recorder.Event(&otelcol, eventTypeWarning, reasonError, err.Error())
+		changed := otelcol.DeepCopy()
+		setReconcileFailed(changed, err)
+		if patchErr := params.Client.Status().Patch(ctx, changed, client.MergeFrom(&otelcol)); patchErr != nil {
+			log.Error(patchErr, "failed to apply status conditions to the OpenTelemetry CR")
+		}
 		return ctrl.Result{}, err
 	}
 	changed := otelcol.DeepCopy()
@@ -58,6 +78,7 @@ func HandleReconcileStatus(ctx context.Context, log logr.Logger, params manifest
 		log.V(2).Error(upgradeErr, "failed to upgrade the OpenTelemetry CR")
 	}
 	changed = &upgraded
+	setReconcileSucceeded(changed)
 	statusErr := UpdateCollectorStatus(ctx, params.Client, changed)
 	if statusErr != nil {
 		params.Recorder.Event(changed, eventTypeWarning, reasonStatusFailure, statusErr.Error())
@@ -70,3 +91,24 @@ func HandleReconcileStatus(ctx context.Context, log logr.Logger, params manifest
 	params.Recorder.Event(changed, eventTypeNormal, reasonInfo, "applied status changes")
 	return ctrl.Result{}, nil
 }
+
+func setReconcileSucceeded(changed *v1beta1.OpenTelemetryCollector) {
+	changed.Status.ObservedGeneration = changed.Generation
+	setCondition(changed, v1beta1.ConditionTypeReconciled, metav1.ConditionTrue, v1beta1.ReasonReconcileSucceeded, "")
+	setCondition(changed, v1beta1.ConditionTypeConfigValid, metav1.ConditionTrue, v1beta1.ReasonConfigValid, "")
+}
+
+func setReconcileFailed(changed *v1beta1.OpenTelemetryCollector, err error) {
+	changed.Status.ObservedGeneration = changed.Generation
+	var configErr *InvalidConfigError
+	if errors.As(err, &configErr) {
+		setCondition(changed, v1beta1.ConditionTypeConfigValid, metav1.ConditionFalse, v1beta1.ReasonInvalidConfig, err.Error())
+		setCondition(changed, v1beta1.ConditionTypeReconciled, metav1.ConditionFalse, v1beta1.ReasonInvalidConfig, err.Error())
+		setCondition(changed, v1beta1.ConditionTypeDegraded, metav1.ConditionTrue, v1beta1.ReasonInvalidConfig, err.Error())
+	} else {
+		setCondition(changed, v1beta1.ConditionTypeConfigValid, metav1.ConditionTrue, v1beta1.ReasonConfigValid, "")
+		setCondition(changed, v1beta1.ConditionTypeReconciled, metav1.ConditionFalse, v1beta1.ReasonReconcileFailed, err.Error())
+		setCondition(changed, v1beta1.ConditionTypeDegraded, metav1.ConditionTrue, v1beta1.ReasonReconcileFailed, err.Error())
+	}
+	setCondition(changed, v1beta1.ConditionTypeReady, metav1.ConditionFalse, v1beta1.ReasonReconcileFailed, err.Error())
+}
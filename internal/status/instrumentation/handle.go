@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instrumentation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
+)
+
+const (
+	eventTypeWarning = "Warning"
+
+	reasonStatusFailure = "StatusFailure"
+)
+
+// HandleReconcileStatus handles updating the status of the Instrumentation CRs.
+func HandleReconcileStatus(ctx context.Context, log logr.Logger, cli client.Client, recorder record.EventRecorder, inst v1alpha1.Instrumentation) (ctrl.Result, error) {
+	log.V(2).Info("updating instrumentation status")
+	changed := inst.DeepCopy()
+
+	if err := UpdateInstrumentationStatus(ctx, cli, changed); err != nil {
+		recorder.Event(changed, eventTypeWarning, reasonStatusFailure, err.Error())
+		return ctrl.Result{}, err
+	}
+	// the status is recomputed on every pod change, skip the patch when nothing moved
+	if equality.Semantic.DeepEqual(inst.Status, changed.Status) {
+		return ctrl.Result{}, nil
+	}
+	statusPatch := client.MergeFrom(&inst)
+	if err := cli.Status().Patch(ctx, changed, statusPatch); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to apply status changes to the Instrumentation CR: %w", err)
+	}
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,358 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instrumentation
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/instrumentation"
+)
+
+const (
+	reasonValid                 = "Valid"
+	reasonInvalidExporterConfig = "InvalidExporterConfig"
+	reasonPodsInjected          = "PodsInjected"
+	reasonNoPodsInjected        = "NoPodsInjected"
+	reasonPodsUpToDate          = "PodsUpToDate"
+	reasonRolloutInProgress     = "RolloutInProgress"
+)
+
+type workloadKey struct {
+	namespace string
+	kind      string
+	name      string
+}
+
+// UpdateInstrumentationStatus computes which workloads reference the Instrumentation, either through an inject
+// annotation, including inject-auto, or through its selector, how many of their pods were
+// instrumented and whether the exporter configuration is usable from the referencing namespaces.
+func UpdateInstrumentationStatus(ctx context.Context, cli client.Client, changed *v1alpha1.Instrumentation) error {
+	namespaces := &corev1.NamespaceList{}
+	if err := cli.List(ctx, namespaces); err != nil {
+		return fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	namespaceMeta := map[string]metav1.ObjectMeta{}
+	for _, ns := range namespaces.Items {
+		namespaceMeta[ns.Name] = ns.ObjectMeta
+	}
+
+	insts := &v1alpha1.InstrumentationList{}
+	if err := cli.List(ctx, insts); err != nil {
+		return fmt.Errorf("failed to list instrumentations: %w", err)
+	}
+	instsPerNamespace := map[string]int{}
+	for _, inst := range insts.Items {
+		instsPerNamespace[inst.Namespace]++
+	}
+
+	instKey := types.NamespacedName{Namespace: changed.Namespace, Name: changed.Name}
+	pods, err := referencingPods(ctx, cli, changed, namespaces.Items)
+	if err != nil {
+		return err
+	}
+
+	rolloutOnChange := instrumentation.RolloutOnChange(*changed)
+	specHash, err := instrumentation.SpecHash(*changed)
+	if err != nil {
+		return fmt.Errorf("failed to compute the spec hash: %w", err)
+	}
+	var outdatedPods, outdatedWorkloads int32
+	workloads := map[workloadKey]*v1alpha1.InstrumentationWorkload{}
+	injectedPods := map[string]int32{}
+	referencingNamespaces := map[string]struct{}{}
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		nsMeta, ok := namespaceMeta[pod.Namespace]
+		if !ok {
+			nsMeta = metav1.ObjectMeta{Name: pod.Namespace}
+		}
+		var languages []v1alpha1.Language
+		refs := instrumentation.InstrumentationReferences(nsMeta, pod.ObjectMeta)
+		for language, value := range refs {
+			if instrumentation.ReferencesInstrumentation(value, pod.Namespace, instKey, instsPerNamespace[pod.Namespace]) {
+				languages = append(languages, language)
+			}
+		}
+		if value := instrumentation.AutoInstrumentationReference(nsMeta, pod.ObjectMeta); value != "" &&
+			instrumentation.ReferencesInstrumentation(value, pod.Namespace, instKey, instsPerNamespace[pod.Namespace]) {
+			detected, err := instrumentation.DetectedLanguages(changed.Spec.LanguageDetection, nsMeta, pod)
+			if err != nil {
+				return err
+			}
+			for _, language := range detected {
+				if _, ok := refs[language]; !ok {
+					languages = append(languages, language)
+				}
+			}
+		}
+		selectorLanguages, err := instrumentation.SelectorReferences(*changed, insts.Items, nsMeta, pod.ObjectMeta)
+		if err != nil {
+			return err
+		}
+		languages = append(languages, selectorLanguages...)
+		if len(languages) == 0 {
+			continue
+		}
+		referencingNamespaces[pod.Namespace] = struct{}{}
+
+		injected := instrumentation.InjectedLanguages(pod)
+		podInjected := false
+		for _, language := range languages {
+			if injected[language] {
+				injectedPods[string(language)]++
+				podInjected = true
+			}
+		}
+
+		key := podWorkload(pod)
+		workload, ok := workloads[key]
+		if !ok {
+			workload = &v1alpha1.InstrumentationWorkload{
+				Namespace: key.namespace,
+				Kind:      key.kind,
+				Name:      key.name,
+			}
+			workloads[key] = workload
+		}
+		workload.Pods++
+		if podInjected {
+			workload.InjectedPods++
+		}
+		if hash, tracked := instrumentation.InjectedSpecHash(pod.ObjectMeta, instKey); rolloutOnChange && tracked && hash != specHash {
+			if workload.OutdatedPods == 0 {
+				outdatedWorkloads++
+			}
+			workload.OutdatedPods++
+			outdatedPods++
+		}
+		for _, language := range languages {
+			if !contains(workload.Languages, language) {
+				workload.Languages = append(workload.Languages, language)
+			}
+		}
+	}
+
+	changed.Status.Workloads = nil
+	for _, workload := range workloads {
+		sort.Slice(workload.Languages, func(i, j int) bool { return workload.Languages[i] < workload.Languages[j] })
+		changed.Status.Workloads = append(changed.Status.Workloads, *workload)
+	}
+	sort.Slice(changed.Status.Workloads, func(i, j int) bool {
+		a, b := changed.Status.Workloads[i], changed.Status.Workloads[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+
+	changed.Status.Namespaces = nil
+	for ns := range referencingNamespaces {
+		changed.Status.Namespaces = append(changed.Status.Namespaces, ns)
+	}
+	sort.Strings(changed.Status.Namespaces)
+
+	changed.Status.InjectedPods = nil
+	var totalInjected int32
+	if len(injectedPods) > 0 {
+		changed.Status.InjectedPods = injectedPods
+		for _, count := range injectedPods {
+			totalInjected += count
+		}
+	}
+
+	var problems []string
+	for _, exporter := range instrumentation.InstrumentationExporters(*changed) {
+		exporterProblems, err := validateExporter(ctx, cli, exporter, changed.Status.Namespaces)
+		if err != nil {
+			return err
+		}
+		// the container overrides can share the endpoint and certificates of spec.exporter
+		for _, problem := range exporterProblems {
+			if !slices.Contains(problems, problem) {
+				problems = append(problems, problem)
+			}
+		}
+	}
+	changed.Status.Problems = problems
+	changed.Status.ObservedGeneration = changed.Generation
+
+	if len(problems) == 0 {
+		setCondition(changed, v1alpha1.InstrumentationConditionValid, metav1.ConditionTrue, reasonValid, "")
+	} else {
+		setCondition(changed, v1alpha1.InstrumentationConditionValid, metav1.ConditionFalse, reasonInvalidExporterConfig, strings.Join(problems, "; "))
+	}
+	if totalInjected > 0 {
+		setCondition(changed, v1alpha1.InstrumentationConditionInUse, metav1.ConditionTrue, reasonPodsInjected, fmt.Sprintf("%d pods are instrumented", totalInjected))
+	} else {
+		setCondition(changed, v1alpha1.InstrumentationConditionInUse, metav1.ConditionFalse, reasonNoPodsInjected, "no running pod is instrumented")
+	}
+	switch {
+	case !rolloutOnChange:
+		meta.RemoveStatusCondition(&changed.Status.Conditions, v1alpha1.InstrumentationConditionUpToDate)
+	case outdatedPods == 0:
+		setCondition(changed, v1alpha1.InstrumentationConditionUpToDate, metav1.ConditionTrue, reasonPodsUpToDate, "")
+	default:
+		setCondition(changed, v1alpha1.InstrumentationConditionUpToDate, metav1.ConditionFalse, reasonRolloutInProgress,
+			fmt.Sprintf("%d pods of %d workloads are instrumented with a previous spec", outdatedPods, outdatedWorkloads))
+	}
+	return nil
+}
+
+// referencingPods returns the pods that can reference inst: the pods naming it in their inject annotations, found
+// through the instrumentation.InstrumentationReferenceIndex, and the pods of the namespaces whose annotations or
+// labels can select it.
+func referencingPods(ctx context.Context, cli client.Client, inst *v1alpha1.Instrumentation, namespaces []corev1.Namespace) ([]corev1.Pod, error) {
+	instKey := types.NamespacedName{Namespace: inst.Namespace, Name: inst.Name}
+	var nsSelector labels.Selector
+	if selector := inst.Spec.Selector; selector != nil && selector.PodSelector != nil && selector.NamespaceSelector != nil {
+		var err error
+		if nsSelector, err = metav1.LabelSelectorAsSelector(selector.NamespaceSelector); err != nil {
+			return nil, fmt.Errorf("invalid namespace selector in Instrumentation %s: %w", instKey.String(), err)
+		}
+	}
+	// "true" and unqualified names select the Instrumentations of the pod namespace, the pods of the Instrumentation
+	// namespace are always listed
+	listedNamespaces := map[string]struct{}{inst.Namespace: {}}
+	for _, ns := range namespaces {
+		if nsSelector != nil && nsSelector.Matches(labels.Set(ns.Labels)) {
+			listedNamespaces[ns.Name] = struct{}{}
+			continue
+		}
+		values := []string{instrumentation.AutoInstrumentationReference(ns.ObjectMeta, metav1.ObjectMeta{})}
+		for _, value := range instrumentation.InstrumentationReferences(ns.ObjectMeta, metav1.ObjectMeta{}) {
+			values = append(values, value)
+		}
+		for _, value := range values {
+			if value != "" && instrumentation.ReferencesInstrumentation(value, ns.Name, instKey, 1) {
+				listedNamespaces[ns.Name] = struct{}{}
+			}
+		}
+	}
+
+	var pods []corev1.Pod
+	seen := map[types.NamespacedName]struct{}{}
+	appendPods := func(list *corev1.PodList) {
+		for _, pod := range list.Items {
+			key := types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				pods = append(pods, pod)
+			}
+		}
+	}
+	indexed := &corev1.PodList{}
+	if err := cli.List(ctx, indexed, client.MatchingFields{instrumentation.InstrumentationReferenceIndex: instKey.String()}); err != nil {
+		return nil, fmt.Errorf("failed to list pods referencing %s: %w", instKey.String(), err)
+	}
+	appendPods(indexed)
+	for ns := range listedNamespaces {
+		list := &corev1.PodList{}
+		if err := cli.List(ctx, list, client.InNamespace(ns)); err != nil {
+			return nil, fmt.Errorf("failed to list pods in namespace %s: %w", ns, err)
+		}
+		appendPods(list)
+	}
+	return pods, nil
+}
+
+// podWorkload returns the workload owning the pod, resolving ReplicaSets to their Deployment through the
+// pod-template-hash the Deployment controller appends to the ReplicaSet name.
+func podWorkload(pod corev1.Pod) workloadKey {
+	owner := metav1.GetControllerOf(&pod)
+	if owner == nil {
+		return workloadKey{namespace: pod.Namespace, kind: "Pod", name: pod.Name}
+	}
+	if owner.Kind == "ReplicaSet" {
+		if hash, ok := pod.Labels["pod-template-hash"]; ok && strings.HasSuffix(owner.Name, "-"+hash) {
+			return workloadKey{namespace: pod.Namespace, kind: "Deployment", name: strings.TrimSuffix(owner.Name, "-"+hash)}
+		}
+	}
+	return workloadKey{namespace: pod.Namespace, kind: owner.Kind, name: owner.Name}
+}
+
+// validateExporter checks that the endpoint of an exporter is usable and that the TLS secrets and config maps, including
+// the ones of its signals, exist in every namespace referencing the Instrumentation, as the pod mutator refuses to
+// inject otherwise.
+func validateExporter(ctx context.Context, cli client.Client, exporter v1alpha1.Exporter, namespaces []string) ([]string, error) {
+	var problems []string
+	if exporter.Endpoint != "" {
+		u, err := url.Parse(exporter.Endpoint)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("exporter endpoint %q is not a valid URL: %v", exporter.Endpoint, err))
+		} else if u.Host == "" {
+			problems = append(problems, fmt.Sprintf("exporter endpoint %q has no host", exporter.Endpoint))
+		}
+	}
+	for _, ns := range namespaces {
+		for _, tls := range instrumentation.ExporterTLS(exporter) {
+			if tls.SecretName != "" {
+				nsn := types.NamespacedName{Namespace: ns, Name: tls.SecretName}
+				if err := cli.Get(ctx, nsn, &corev1.Secret{}); apierrors.IsNotFound(err) {
+					problems = append(problems, fmt.Sprintf("secret %s with certificates does not exist", nsn.String()))
+				} else if err != nil {
+					return nil, fmt.Errorf("failed to get secret %s: %w", nsn.String(), err)
+				}
+			}
+			if tls.ConfigMapName != "" {
+				nsn := types.NamespacedName{Namespace: ns, Name: tls.ConfigMapName}
+				if err := cli.Get(ctx, nsn, &corev1.ConfigMap{}); apierrors.IsNotFound(err) {
+					problems = append(problems, fmt.Sprintf("configmap %s with CA certificate does not exist", nsn.String()))
+				} else if err != nil {
+					return nil, fmt.Errorf("failed to get configmap %s: %w", nsn.String(), err)
+				}
+			}
+		}
+	}
+	return problems, nil
+}
+
+func setCondition(changed *v1alpha1.Instrumentation, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&changed.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: changed.Generation,
+	})
+}
+
+func contains(values []v1alpha1.Language, value v1alpha1.Language) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,438 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instrumentation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/instrumentation"
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	return scheme
+}
+
+func newClientBuilder(t *testing.T) *fake.ClientBuilder {
+	return fake.NewClientBuilder().
+		WithScheme(newScheme(t)).
+		WithIndex(&corev1.Pod{}, instrumentation.InstrumentationReferenceIndex, instrumentation.InstrumentationReferenceIndexValues)
+}
+
+func TestUpdateInstrumentationStatus(t *testing.T) {
+	isController := true
+	inst := &v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-inst", Namespace: "observability", Generation: 4},
+		Spec: v1alpha1.InstrumentationSpec{
+			Exporter: v1alpha1.Exporter{
+				Endpoint: "https://collector:4317",
+				TLS:      &v1alpha1.TLS{SecretName: "otel-certs"},
+			},
+		},
+	}
+	objects := []runtime.Object{
+		inst,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "observability"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "shop",
+			Annotations: map[string]string{"instrumentation.opentelemetry.io/inject-java": "observability/my-inst"},
+		}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "otel-certs", Namespace: "observability"}},
+		// instrumented through the namespace annotation
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cart-5d9f7c-abcde",
+				Namespace: "shop",
+				Labels:    map[string]string{"pod-template-hash": "5d9f7c"},
+				OwnerReferences: []metav1.OwnerReference{
+					{Kind: "ReplicaSet", Name: "cart-5d9f7c", Controller: &isController},
+				},
+			},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "opentelemetry-auto-instrumentation-java"}},
+				Containers:     []corev1.Container{{Name: "app"}},
+			},
+		},
+		// referenced, but created before the Instrumentation existed
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cart-5d9f7c-fghij",
+				Namespace: "shop",
+				Labels:    map[string]string{"pod-template-hash": "5d9f7c"},
+				OwnerReferences: []metav1.OwnerReference{
+					{Kind: "ReplicaSet", Name: "cart-5d9f7c", Controller: &isController},
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "app"}},
+			},
+		},
+		// the only Instrumentation in the namespace, selected with "true"
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "worker",
+				Namespace:   "observability",
+				Annotations: map[string]string{"instrumentation.opentelemetry.io/inject-python": "true"},
+			},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "opentelemetry-auto-instrumentation-python"}},
+				Containers:     []corev1.Container{{Name: "app"}},
+			},
+		},
+		// opted out
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "batch",
+				Namespace:   "shop",
+				Annotations: map[string]string{"instrumentation.opentelemetry.io/inject-java": "false"},
+			},
+		},
+		// completed pods are not counted
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "done",
+				Namespace:   "observability",
+				Annotations: map[string]string{"instrumentation.opentelemetry.io/inject-python": "true"},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodSucceeded},
+		},
+	}
+	cli := newClientBuilder(t).WithRuntimeObjects(objects...).Build()
+
+	changed := inst.DeepCopy()
+	err := UpdateInstrumentationStatus(context.Background(), cli, changed)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(4), changed.Status.ObservedGeneration)
+	assert.Equal(t, []string{"observability", "shop"}, changed.Status.Namespaces)
+	assert.Equal(t, map[string]int32{"java": 1, "python": 1}, changed.Status.InjectedPods)
+	assert.Equal(t, []v1alpha1.InstrumentationWorkload{
+		{Namespace: "observability", Kind: "Pod", Name: "worker", Languages: []v1alpha1.Language{v1alpha1.LanguagePython}, Pods: 1, InjectedPods: 1},
+		{Namespace: "shop", Kind: "Deployment", Name: "cart", Languages: []v1alpha1.Language{v1alpha1.LanguageJava}, Pods: 2, InjectedPods: 1},
+	}, changed.Status.Workloads)
+	assert.Equal(t, []string{"secret shop/otel-certs with certificates does not exist"}, changed.Status.Problems)
+	assert.True(t, meta.IsStatusConditionFalse(changed.Status.Conditions, v1alpha1.InstrumentationConditionValid))
+	assert.True(t, meta.IsStatusConditionTrue(changed.Status.Conditions, v1alpha1.InstrumentationConditionInUse))
+}
+
+func TestUpdateInstrumentationStatusPodReference(t *testing.T) {
+	inst := &v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-inst", Namespace: "observability"},
+	}
+	objects := []runtime.Object{
+		inst,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "observability"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "billing"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		// references the Instrumentation from another namespace
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "invoice",
+				Namespace:   "billing",
+				Annotations: map[string]string{"instrumentation.opentelemetry.io/inject-java": "observability/my-inst"},
+			},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "opentelemetry-auto-instrumentation-java"}},
+				Containers:     []corev1.Container{{Name: "app"}},
+			},
+		},
+		// references an Instrumentation of the same name in its own namespace
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "cart",
+				Namespace:   "shop",
+				Annotations: map[string]string{"instrumentation.opentelemetry.io/inject-java": "my-inst"},
+			},
+		},
+	}
+	cli := newClientBuilder(t).WithRuntimeObjects(objects...).Build()
+
+	changed := inst.DeepCopy()
+	err := UpdateInstrumentationStatus(context.Background(), cli, changed)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"billing"}, changed.Status.Namespaces)
+	assert.Equal(t, []v1alpha1.InstrumentationWorkload{
+		{Namespace: "billing", Kind: "Pod", Name: "invoice", Languages: []v1alpha1.Language{v1alpha1.LanguageJava}, Pods: 1, InjectedPods: 1},
+	}, changed.Status.Workloads)
+}
+
+func TestUpdateInstrumentationStatusUnused(t *testing.T) {
+	inst := &v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-inst", Namespace: "default"},
+		Spec: v1alpha1.InstrumentationSpec{
+			Exporter: v1alpha1.Exporter{Endpoint: "http://collector:4318"},
+		},
+	}
+	second := &v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+	}
+	// "true" is ambiguous with two Instrumentations in the namespace, the pod mutator refuses to inject
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Namespace:   "default",
+			Annotations: map[string]string{"instrumentation.opentelemetry.io/inject-java": "true"},
+		},
+	}
+	cli := newClientBuilder(t).WithRuntimeObjects(inst, second, pod).Build()
+
+	changed := inst.DeepCopy()
+	err := UpdateInstrumentationStatus(context.Background(), cli, changed)
+	require.NoError(t, err)
+
+	assert.Empty(t, changed.Status.Workloads)
+	assert.Empty(t, changed.Status.InjectedPods)
+	assert.Empty(t, changed.Status.Problems)
+	assert.True(t, meta.IsStatusConditionTrue(changed.Status.Conditions, v1alpha1.InstrumentationConditionValid))
+	assert.True(t, meta.IsStatusConditionFalse(changed.Status.Conditions, v1alpha1.InstrumentationConditionInUse))
+}
+
+func TestUpdateInstrumentationStatusSelector(t *testing.T) {
+	inst := &v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-wide", Namespace: "observability"},
+		Spec: v1alpha1.InstrumentationSpec{
+			Exporter: v1alpha1.Exporter{Endpoint: "http://collector:4318"},
+			Selector: &v1alpha1.InstrumentationSelector{
+				PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "backend"}},
+				NamespaceSelector: &metav1.LabelSelector{},
+				Languages:         []v1alpha1.Language{v1alpha1.LanguageJava, v1alpha1.LanguagePython},
+			},
+		},
+	}
+	// takes precedence for the pods of its own namespace
+	local := &v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "local", Namespace: "payments"},
+		Spec: v1alpha1.InstrumentationSpec{
+			Selector: &v1alpha1.InstrumentationSelector{
+				PodSelector: &metav1.LabelSelector{},
+				Languages:   []v1alpha1.Language{v1alpha1.LanguageJava},
+			},
+		},
+	}
+	objects := []runtime.Object{
+		inst,
+		local,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "observability"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments"}},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "cart", Namespace: "shop", Labels: map[string]string{"tier": "backend"}},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "opentelemetry-auto-instrumentation-java"}},
+			},
+		},
+		// opted out of Python
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "search",
+				Namespace:   "shop",
+				Labels:      map[string]string{"tier": "backend"},
+				Annotations: map[string]string{"instrumentation.opentelemetry.io/inject-python": "false"},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "frontend", Namespace: "shop", Labels: map[string]string{"tier": "frontend"}},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "ledger", Namespace: "payments", Labels: map[string]string{"tier": "backend"}},
+		},
+	}
+	cli := newClientBuilder(t).WithRuntimeObjects(objects...).Build()
+
+	changed := inst.DeepCopy()
+	err := UpdateInstrumentationStatus(context.Background(), cli, changed)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"payments", "shop"}, changed.Status.Namespaces)
+	assert.Equal(t, map[string]int32{"java": 1}, changed.Status.InjectedPods)
+	assert.Equal(t, []v1alpha1.InstrumentationWorkload{
+		{Namespace: "payments", Kind: "Pod", Name: "ledger", Languages: []v1alpha1.Language{v1alpha1.LanguagePython}, Pods: 1},
+		{Namespace: "shop", Kind: "Pod", Name: "cart", Languages: []v1alpha1.Language{v1alpha1.LanguageJava, v1alpha1.LanguagePython}, Pods: 1, InjectedPods: 1},
+		{Namespace: "shop", Kind: "Pod", Name: "search", Languages: []v1alpha1.Language{v1alpha1.LanguageJava}, Pods: 1},
+	}, changed.Status.Workloads)
+}
+
+func TestUpdateInstrumentationStatusLanguageDetection(t *testing.T) {
+	inst := &v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-inst", Namespace: "default"},
+		Spec: v1alpha1.InstrumentationSpec{
+			Exporter: v1alpha1.Exporter{Endpoint: "http://collector:4318"},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"instrumentation.opentelemetry.io/inject-auto":   "my-inst",
+				"instrumentation.opentelemetry.io/inject-nodejs": "other",
+			},
+		},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "opentelemetry-auto-instrumentation-python"}},
+			Containers: []corev1.Container{
+				{Name: "web", Image: "node:20"},
+				{Name: "worker", Command: []string{"python", "worker.py"}},
+			},
+		},
+	}
+	cli := newClientBuilder(t).WithRuntimeObjects(inst, pod).Build()
+
+	changed := inst.DeepCopy()
+	err := UpdateInstrumentationStatus(context.Background(), cli, changed)
+	require.NoError(t, err)
+
+	// nodejs is requested from another Instrumentation through its language annotation
+	assert.Equal(t, []v1alpha1.InstrumentationWorkload{
+		{Namespace: "default", Kind: "Pod", Name: "app", Languages: []v1alpha1.Language{v1alpha1.LanguagePython}, Pods: 1, InjectedPods: 1},
+	}, changed.Status.Workloads)
+	assert.Equal(t, map[string]int32{"python": 1}, changed.Status.InjectedPods)
+}
+
+func TestUpdateInstrumentationStatusOutdatedPods(t *testing.T) {
+	inst := &v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-inst", Namespace: "default"},
+		Spec: v1alpha1.InstrumentationSpec{
+			Exporter:      v1alpha1.Exporter{Endpoint: "http://collector:4318"},
+			RolloutPolicy: &v1alpha1.RolloutPolicy{RolloutOnChange: true},
+		},
+	}
+	specHash, err := instrumentation.SpecHash(*inst)
+	require.NoError(t, err)
+	newPod := func(name, hash string) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				Annotations: map[string]string{"instrumentation.opentelemetry.io/inject-java": "my-inst"},
+			},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "opentelemetry-auto-instrumentation-java"}},
+			},
+		}
+		if hash != "" {
+			pod.Annotations[instrumentation.AnnotationSpecHash] = "default/my-inst=" + hash
+		}
+		return pod
+	}
+	cli := newClientBuilder(t).WithRuntimeObjects(
+		inst,
+		newPod("current", specHash),
+		newPod("outdated", "previous"),
+		// instrumented before rolloutOnChange was enabled
+		newPod("untracked", ""),
+	).Build()
+
+	changed := inst.DeepCopy()
+	err = UpdateInstrumentationStatus(context.Background(), cli, changed)
+	require.NoError(t, err)
+
+	assert.Equal(t, []v1alpha1.InstrumentationWorkload{
+		{Namespace: "default", Kind: "Pod", Name: "current", Languages: []v1alpha1.Language{v1alpha1.LanguageJava}, Pods: 1, InjectedPods: 1},
+		{Namespace: "default", Kind: "Pod", Name: "outdated", Languages: []v1alpha1.Language{v1alpha1.LanguageJava}, Pods: 1, InjectedPods: 1, OutdatedPods: 1},
+		{Namespace: "default", Kind: "Pod", Name: "untracked", Languages: []v1alpha1.Language{v1alpha1.LanguageJava}, Pods: 1, InjectedPods: 1},
+	}, changed.Status.Workloads)
+	upToDate := meta.FindStatusCondition(changed.Status.Conditions, v1alpha1.InstrumentationConditionUpToDate)
+	require.NotNil(t, upToDate)
+	assert.Equal(t, metav1.ConditionFalse, upToDate.Status)
+	assert.Equal(t, "1 pods of 1 workloads are instrumented with a previous spec", upToDate.Message)
+
+	// the condition is dropped with the policy
+	changed.Spec.RolloutPolicy = nil
+	err = UpdateInstrumentationStatus(context.Background(), cli, changed)
+	require.NoError(t, err)
+	assert.Nil(t, meta.FindStatusCondition(changed.Status.Conditions, v1alpha1.InstrumentationConditionUpToDate))
+	assert.Zero(t, changed.Status.Workloads[1].OutdatedPods)
+}
+
+func TestValidateExporterEndpoint(t *testing.T) {
+	cli := newClientBuilder(t).Build()
+	problems, err := validateExporter(context.Background(), cli, v1alpha1.Exporter{Endpoint: "collector:4317"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{`exporter endpoint "collector:4317" has no host`}, problems)
+}
+
+func TestUpdateInstrumentationStatusContainerOverrides(t *testing.T) {
+	inst := &v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-inst", Namespace: "observability"},
+		Spec: v1alpha1.InstrumentationSpec{
+			Exporter: v1alpha1.Exporter{
+				Endpoint: "https://collector:4317",
+				TLS:      &v1alpha1.TLS{SecretName: "otel-certs"},
+			},
+			ContainerOverrides: []v1alpha1.ContainerOverride{
+				{
+					ContainerNames: []string{"proxy"},
+					Exporter: &v1alpha1.Exporter{
+						Endpoint: "https://gateway:4317",
+						TLS:      &v1alpha1.TLS{SecretName: "otel-certs", ConfigMapName: "gateway-ca"},
+					},
+				},
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "worker",
+			Namespace:   "observability",
+			Annotations: map[string]string{"instrumentation.opentelemetry.io/inject-java": "true"},
+		},
+	}
+	cli := newClientBuilder(t).WithRuntimeObjects(inst, pod).Build()
+
+	changed := inst.DeepCopy()
+	err := UpdateInstrumentationStatus(context.Background(), cli, changed)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"secret observability/otel-certs with certificates does not exist",
+		"configmap observability/gateway-ca with CA certificate does not exist",
+	}, changed.Status.Problems)
+}
+
+func TestValidateExporterSignalTLS(t *testing.T) {
+	cli := newClientBuilder(t).WithRuntimeObjects(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "otel-certs", Namespace: "shop"}},
+	).Build()
+	exporter := v1alpha1.Exporter{
+		Endpoint: "https://collector:4317",
+		TLS:      &v1alpha1.TLS{SecretName: "otel-certs"},
+		Traces:   &v1alpha1.SignalExporter{Endpoint: "https://traces:4317", TLS: &v1alpha1.TLS{SecretName: "traces-certs"}},
+		Metrics:  &v1alpha1.SignalExporter{Endpoint: "https://metrics:4317", TLS: &v1alpha1.TLS{ConfigMapName: "metrics-ca"}},
+		// disabled signals aren't exported, their certificates aren't mounted
+		Logs: &v1alpha1.SignalExporter{Enabled: ptr.To(false), TLS: &v1alpha1.TLS{SecretName: "logs-certs"}},
+	}
+
+	problems, err := validateExporter(context.Background(), cli, exporter, []string{"shop"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"secret shop/traces-certs with certificates does not exist",
+		"configmap shop/metrics-ca with CA certificate does not exist",
+	}, problems)
+}
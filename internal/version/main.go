@@ -33,6 +33,8 @@ var (
 	autoInstrumentationApacheHttpd string
 	autoInstrumentationNginx       string
 	autoInstrumentationGo          string
+	autoInstrumentationRuby        string
+	autoInstrumentationPHP         string
 )
 
 // Version holds this Operator's version as well as the version of some of the components it uses.
@@ -50,6 +52,8 @@ type Version struct {
 	AutoInstrumentationGo          string `json:"auto-instrumentation-go"`
 	AutoInstrumentationApacheHttpd string `json:"auto-instrumentation-apache-httpd"`
 	AutoInstrumentationNginx       string `json:"auto-instrumentation-nginx"`
+	AutoInstrumentationRuby        string `json:"auto-instrumentation-ruby"`
+	AutoInstrumentationPHP         string `json:"auto-instrumentation-php"`
 }
 
 // Get returns the Version object with the relevant information.
@@ -68,12 +72,14 @@ func Get() Version {
 		AutoInstrumentationGo:          AutoInstrumentationGo(),
 		AutoInstrumentationApacheHttpd: AutoInstrumentationApacheHttpd(),
 		AutoInstrumentationNginx:       AutoInstrumentationNginx(),
+		AutoInstrumentationRuby:        AutoInstrumentationRuby(),
+		AutoInstrumentationPHP:         AutoInstrumentationPHP(),
 	}
 }
 
 func (v Version) String() string {
 	return fmt.Sprintf(
-		"Version(Operator='%v', BuildDate='%v', OpenTelemetryCollector='%v', Go='%v', TargetAllocator='%v', OperatorOpAMPBridge='%v', AutoInstrumentationJava='%v', AutoInstrumentationNodeJS='%v', AutoInstrumentationPython='%v', AutoInstrumentationDotNet='%v', AutoInstrumentationGo='%v', AutoInstrumentationApacheHttpd='%v', AutoInstrumentationNginx='%v')",
+		"Version(Operator='%v', BuildDate='%v', OpenTelemetryCollector='%v', Go='%v', TargetAllocator='%v', OperatorOpAMPBridge='%v', AutoInstrumentationJava='%v', AutoInstrumentationNodeJS='%v', AutoInstrumentationPython='%v', AutoInstrumentationDotNet='%v', AutoInstrumentationGo='%v', AutoInstrumentationApacheHttpd='%v', AutoInstrumentationNginx='%v', AutoInstrumentationRuby='%v', AutoInstrumentationPHP='%v')",
 		v.Operator,
 		v.BuildDate,
 		v.OpenTelemetryCollector,
@@ -87,6 +93,8 @@ func (v Version) String() string {
 		v.AutoInstrumentationGo,
 		v.AutoInstrumentationApacheHttpd,
 		v.AutoInstrumentationNginx,
+		v.AutoInstrumentationRuby,
+		v.AutoInstrumentationPHP,
 	)
 }
 
@@ -171,3 +179,17 @@ func AutoInstrumentationGo() string {
 	}
 	return "0.0.0"
 }
+
+func AutoInstrumentationRuby() string {
+	if len(autoInstrumentationRuby) > 0 {
+		return autoInstrumentationRuby
+	}
+	return "0.0.0"
+}
+
+func AutoInstrumentationPHP() string {
+	if len(autoInstrumentationPHP) > 0 {
+		return autoInstrumentationPHP
+	}
+	return "0.0.0"
+}
@@ -0,0 +1,299 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package podmutation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/open-telemetry/opentelemetry-operator/internal/explanation"
+)
+
+// ExplainPath is the path the explain handler is served on, next to the pod mutation webhook.
+const ExplainPath = "/explain-v1-pod"
+
+// maxExplainRequestSize is the maximum size of the body of an explain request, which is the limit of the API server
+// for the requests it accepts.
+const maxExplainRequestSize = 3 << 20
+
+var errNoPodToExplain = errors.New("either a pod or a workload must be provided")
+
+// ExplainRequest is the body accepted by the explain handler. Either a pod or a reference to a workload, whose pod
+// template is then explained, must be provided.
+type ExplainRequest struct {
+	Pod      *corev1.Pod        `json:"pod,omitempty"`
+	Workload *WorkloadReference `json:"workload,omitempty"`
+}
+
+// WorkloadReference references a workload whose pod template is explained.
+type WorkloadReference struct {
+	// Kind is one of Deployment, StatefulSet, DaemonSet, ReplicaSet or Job.
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// ExplainResponse is the body returned by the explain handler.
+type ExplainResponse struct {
+	// Pod is the pod as it would be admitted by the pod mutation webhook.
+	Pod corev1.Pod `json:"pod"`
+	// Steps are the decisions taken by the pod mutators, in order.
+	Steps []explanation.Step `json:"steps"`
+	// Error is the error that made the webhook admit the pod unmodified, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// explainHandler runs the pod mutators against a pod without admitting it, and returns the mutated pod together
+// with the decisions taken by the mutators.
+type explainHandler struct {
+	client      client.Client
+	clientset   kubernetes.Interface
+	logger      logr.Logger
+	podMutators []PodMutator
+}
+
+// NewExplainHandler creates the HTTP handler explaining what the pod mutation webhook does to a pod. It must be given
+// the same pod mutators as the webhook. The callers are authenticated with their bearer token, and must be allowed to
+// create the pod, or to get the workload, they ask to explain.
+func NewExplainHandler(logger logr.Logger, cl client.Client, clientset kubernetes.Interface, podMutators []PodMutator) http.Handler {
+	return &explainHandler{
+		client:      cl,
+		clientset:   clientset,
+		logger:      logger,
+		podMutators: podMutators,
+	}
+}
+
+func (h *explainHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+	user, err := h.authenticate(ctx, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	req := ExplainRequest{}
+	if err = json.NewDecoder(http.MaxBytesReader(w, r.Body, maxExplainRequestSize)).Decode(&req); err != nil {
+		status := http.StatusBadRequest
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		http.Error(w, fmt.Sprintf("failed to decode the request: %s", err), status)
+		return
+	}
+	attributes, err := requestAttributes(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err = h.authorize(ctx, user, attributes); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	pod, err := h.podToExplain(ctx, req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.explain(ctx, pod)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Error(err, "failed to write the explanation")
+	}
+}
+
+// authenticate returns the user owning the bearer token of the request.
+func (h *explainHandler) authenticate(ctx context.Context, r *http.Request) (authenticationv1.UserInfo, error) {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || token == "" {
+		return authenticationv1.UserInfo{}, errors.New("a bearer token must be provided")
+	}
+	review, err := h.clientset.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		h.logger.Error(err, "failed to review the token of an explain request")
+		return authenticationv1.UserInfo{}, errors.New("the bearer token could not be reviewed")
+	}
+	if !review.Status.Authenticated {
+		return authenticationv1.UserInfo{}, errors.New("the bearer token is not valid")
+	}
+	return review.Status.User, nil
+}
+
+// authorize checks that user is allowed to access the resource the request explains, as the explanation exposes the
+// pod template of a workload and the configuration injected into it.
+func (h *explainHandler) authorize(ctx context.Context, user authenticationv1.UserInfo, attributes *authorizationv1.ResourceAttributes) error {
+	extra := map[string]authorizationv1.ExtraValue{}
+	for key, value := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+	review, err := h.clientset.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: attributes,
+			User:               user.Username,
+			Groups:             user.Groups,
+			UID:                user.UID,
+			Extra:              extra,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		h.logger.Error(err, "failed to review the access of an explain request")
+		return errors.New("the access to the explained resource could not be reviewed")
+	}
+	if !review.Status.Allowed || review.Status.Denied {
+		return fmt.Errorf("user %s is not allowed to %s %s in namespace %s", user.Username, attributes.Verb, attributes.Resource, attributes.Namespace)
+	}
+	return nil
+}
+
+// explain mutates the pod the same way the webhook does. Mutator errors are reported in the response, as the webhook
+// then admits the pod unmodified, only failing to read the namespace is returned as an error.
+func (h *explainHandler) explain(ctx context.Context, pod corev1.Pod) (ExplainResponse, error) {
+	ns := corev1.Namespace{}
+	if err := h.client.Get(ctx, types.NamespacedName{Name: pod.Namespace}, &ns); err != nil {
+		return ExplainResponse{}, fmt.Errorf("failed to get namespace %s: %w", pod.Namespace, err)
+	}
+
+	steps := &explanation.Explanation{}
+	ctx = explanation.NewContext(ctx, steps)
+	mutated := *pod.DeepCopy()
+	for _, m := range h.podMutators {
+		var err error
+		mutated, err = m.Mutate(ctx, ns, mutated)
+		if err != nil {
+			return ExplainResponse{Pod: pod, Steps: steps.Steps(), Error: err.Error()}, nil
+		}
+	}
+	return ExplainResponse{Pod: mutated, Steps: steps.Steps()}, nil
+}
+
+// requestAttributes returns the access the caller must have to explain the request: creating the pod, or getting the
+// workload.
+func requestAttributes(req ExplainRequest) (*authorizationv1.ResourceAttributes, error) {
+	switch {
+	case req.Pod != nil && req.Workload != nil:
+		return nil, errors.New("only one of pod and workload can be provided")
+	case req.Pod != nil:
+		if req.Pod.Namespace == "" {
+			return nil, errors.New("the namespace of the pod must be provided")
+		}
+		return &authorizationv1.ResourceAttributes{Namespace: req.Pod.Namespace, Verb: "create", Resource: "pods"}, nil
+	case req.Workload != nil:
+		kind, ok := workloadKinds[req.Workload.Kind]
+		if !ok {
+			return nil, fmt.Errorf("unsupported workload kind %q", req.Workload.Kind)
+		}
+		return &authorizationv1.ResourceAttributes{
+			Namespace: req.Workload.Namespace,
+			Verb:      "get",
+			Group:     kind.group,
+			Resource:  kind.resource,
+			Name:      req.Workload.Name,
+		}, nil
+	default:
+		return nil, errNoPodToExplain
+	}
+}
+
+// podToExplain returns the pod of the request, or builds one from the pod template of the referenced workload.
+func (h *explainHandler) podToExplain(ctx context.Context, req ExplainRequest) (corev1.Pod, error) {
+	if req.Pod != nil {
+		return *req.Pod, nil
+	}
+	return h.workloadPod(ctx, *req.Workload)
+}
+
+// workloadKind is a kind of workload that can be explained.
+type workloadKind struct {
+	group    string
+	resource string
+	// object returns an empty workload and a function returning its pod template once it has been read
+	object func() (client.Object, func() corev1.PodTemplateSpec)
+}
+
+var workloadKinds = map[string]workloadKind{
+	"Deployment": {"apps", "deployments", func() (client.Object, func() corev1.PodTemplateSpec) {
+		deployment := &appsv1.Deployment{}
+		return deployment, func() corev1.PodTemplateSpec { return deployment.Spec.Template }
+	}},
+	"StatefulSet": {"apps", "statefulsets", func() (client.Object, func() corev1.PodTemplateSpec) {
+		statefulSet := &appsv1.StatefulSet{}
+		return statefulSet, func() corev1.PodTemplateSpec { return statefulSet.Spec.Template }
+	}},
+	"DaemonSet": {"apps", "daemonsets", func() (client.Object, func() corev1.PodTemplateSpec) {
+		daemonSet := &appsv1.DaemonSet{}
+		return daemonSet, func() corev1.PodTemplateSpec { return daemonSet.Spec.Template }
+	}},
+	"ReplicaSet": {"apps", "replicasets", func() (client.Object, func() corev1.PodTemplateSpec) {
+		replicaSet := &appsv1.ReplicaSet{}
+		return replicaSet, func() corev1.PodTemplateSpec { return replicaSet.Spec.Template }
+	}},
+	"Job": {"batch", "jobs", func() (client.Object, func() corev1.PodTemplateSpec) {
+		job := &batchv1.Job{}
+		return job, func() corev1.PodTemplateSpec { return job.Spec.Template }
+	}},
+}
+
+func (h *explainHandler) workloadPod(ctx context.Context, ref WorkloadReference) (corev1.Pod, error) {
+	kind, ok := workloadKinds[ref.Kind]
+	if !ok {
+		return corev1.Pod{}, fmt.Errorf("unsupported workload kind %q", ref.Kind)
+	}
+	obj, template := kind.object()
+	if err := h.client.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, obj); err != nil {
+		return corev1.Pod{}, fmt.Errorf("failed to get %s %s/%s: %w", ref.Kind, ref.Namespace, ref.Name, err)
+	}
+
+	podTemplate := template()
+	pod := corev1.Pod{
+		ObjectMeta: podTemplate.ObjectMeta,
+		Spec:       podTemplate.Spec,
+	}
+	pod.Namespace = ref.Namespace
+	if pod.GenerateName == "" {
+		pod.GenerateName = ref.Name + "-"
+	}
+	return pod, nil
+}
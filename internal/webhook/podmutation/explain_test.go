@@ -0,0 +1,234 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package podmutation_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	kubeTesting "k8s.io/client-go/testing"
+
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1beta1"
+	"github.com/open-telemetry/opentelemetry-operator/internal/config"
+	. "github.com/open-telemetry/opentelemetry-operator/internal/webhook/podmutation"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/sidecar"
+)
+
+type failingMutator struct{}
+
+func (failingMutator) Mutate(context.Context, corev1.Namespace, corev1.Pod) (corev1.Pod, error) {
+	return corev1.Pod{}, errors.New("mutation failed")
+}
+
+// explainClientset authenticates the tokens of the users allowed, and of the users denied, to access everything.
+func explainClientset() *fake.Clientset {
+	users := map[string]string{"allowed-token": "allowed", "denied-token": "denied"}
+	c := fake.NewSimpleClientset()
+	c.PrependReactor("create", "tokenreviews", func(action kubeTesting.Action) (handled bool, ret runtime.Object, err error) {
+		review := action.(kubeTesting.CreateAction).GetObject().DeepCopyObject().(*authenticationv1.TokenReview)
+		if user, ok := users[review.Spec.Token]; ok {
+			review.Status = authenticationv1.TokenReviewStatus{Authenticated: true, User: authenticationv1.UserInfo{Username: user}}
+		}
+		return true, review, nil
+	})
+	c.PrependReactor("create", "subjectaccessreviews", func(action kubeTesting.Action) (handled bool, ret runtime.Object, err error) {
+		review := action.(kubeTesting.CreateAction).GetObject().DeepCopyObject().(*authorizationv1.SubjectAccessReview)
+		review.Status = authorizationv1.SubjectAccessReviewStatus{Allowed: review.Spec.User == "allowed"}
+		return true, review, nil
+	})
+	return c
+}
+
+func explain(t *testing.T, handler http.Handler, req ExplainRequest) (int, ExplainResponse) {
+	return explainAs(t, handler, "allowed-token", req)
+}
+
+func explainAs(t *testing.T, handler http.Handler, token string, req ExplainRequest) (int, ExplainResponse) {
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	httpReq := httptest.NewRequest(http.MethodPost, ExplainPath, bytes.NewReader(body))
+	if token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+	handler.ServeHTTP(rec, httpReq)
+
+	resp := ExplainResponse{}
+	if rec.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	}
+	return rec.Code, resp
+}
+
+func TestExplain(t *testing.T) {
+	ctx := context.Background()
+	ns := corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "explain-namespace"},
+	}
+	otelcol := v1beta1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-sidecar", Namespace: ns.Name},
+		Spec: v1beta1.OpenTelemetryCollectorSpec{
+			Mode: v1beta1.ModeSidecar,
+		},
+	}
+	deployment := appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app", Namespace: ns.Name},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "my-app"}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"app": "my-app"},
+					Annotations: map[string]string{sidecar.Annotation: "true"},
+				},
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "my-app:1"}}},
+			},
+		},
+	}
+	require.NoError(t, k8sClient.Create(ctx, &ns))
+	defer func() {
+		_ = k8sClient.Delete(ctx, &ns)
+	}()
+	require.NoError(t, k8sClient.Create(ctx, &otelcol))
+	defer func() {
+		_ = k8sClient.Delete(ctx, &otelcol)
+	}()
+	require.NoError(t, k8sClient.Create(ctx, &deployment))
+	defer func() {
+		_ = k8sClient.Delete(ctx, &deployment)
+	}()
+
+	cfg := config.New()
+	clientset := explainClientset()
+	handler := NewExplainHandler(logger, k8sClient, clientset, []PodMutator{sidecar.NewMutator(logger, cfg, k8sClient)})
+
+	t.Run("pod", func(t *testing.T) {
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-pod", Namespace: ns.Name},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+		}
+
+		code, resp := explain(t, handler, ExplainRequest{Pod: &pod})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, pod.Spec, resp.Pod.Spec)
+		require.Len(t, resp.Steps, 1)
+		assert.Equal(t, "sidecar", resp.Steps[0].Mutator)
+		assert.Equal(t, "annotation not present in deployment, skipping sidecar injection", resp.Steps[0].Message)
+	})
+
+	t.Run("workload", func(t *testing.T) {
+		code, resp := explain(t, handler, ExplainRequest{Workload: &WorkloadReference{Kind: "Deployment", Namespace: ns.Name, Name: deployment.Name}})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, ns.Name, resp.Pod.Namespace)
+		assert.Equal(t, "my-app-", resp.Pod.GenerateName)
+		require.Len(t, resp.Pod.Spec.Containers, 2)
+		assert.Equal(t, "otc-container", resp.Pod.Spec.Containers[1].Name)
+
+		require.NotEmpty(t, resp.Steps)
+		last := resp.Steps[len(resp.Steps)-1]
+		assert.Equal(t, "injecting sidecar into pod", last.Message)
+		assert.Equal(t, map[string]string{"otelcol-namespace": ns.Name, "otelcol-name": otelcol.Name}, last.Values)
+	})
+
+	t.Run("mutator error", func(t *testing.T) {
+		failing := NewExplainHandler(logger, k8sClient, clientset, []PodMutator{failingMutator{}})
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-pod", Namespace: ns.Name},
+		}
+
+		code, resp := explain(t, failing, ExplainRequest{Pod: &pod})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "mutation failed", resp.Error)
+		assert.Equal(t, pod.Name, resp.Pod.Name)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		for _, req := range []ExplainRequest{
+			{},
+			{Pod: &corev1.Pod{}},
+			{Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: ns.Name}}, Workload: &WorkloadReference{Kind: "Deployment"}},
+			{Workload: &WorkloadReference{Kind: "CronJob", Namespace: ns.Name, Name: "my-job"}},
+			{Workload: &WorkloadReference{Kind: "Deployment", Namespace: ns.Name, Name: "missing"}},
+		} {
+			code, _ := explain(t, handler, req)
+			assert.Equal(t, http.StatusBadRequest, code)
+		}
+	})
+
+	t.Run("request too large", func(t *testing.T) {
+		pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:        "my-pod",
+			Namespace:   ns.Name,
+			Annotations: map[string]string{"large": strings.Repeat("a", 3<<20)},
+		}}
+		code, _ := explain(t, handler, ExplainRequest{Pod: &pod})
+		assert.Equal(t, http.StatusRequestEntityTooLarge, code)
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "my-pod", Namespace: ns.Name}}
+		for _, token := range []string{"", "unknown-token"} {
+			code, _ := explainAs(t, handler, token, ExplainRequest{Pod: &pod})
+			assert.Equal(t, http.StatusUnauthorized, code)
+		}
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "my-pod", Namespace: ns.Name}}
+		code, _ := explainAs(t, handler, "denied-token", ExplainRequest{Pod: &pod})
+		assert.Equal(t, http.StatusForbidden, code)
+
+		// the workload must not be read before the access to it is granted
+		code, _ = explainAs(t, handler, "denied-token", ExplainRequest{Workload: &WorkloadReference{Kind: "Deployment", Namespace: ns.Name, Name: "missing"}})
+		assert.Equal(t, http.StatusForbidden, code)
+	})
+
+	t.Run("access review", func(t *testing.T) {
+		clientset.ClearActions()
+		code, _ := explain(t, handler, ExplainRequest{Workload: &WorkloadReference{Kind: "Deployment", Namespace: ns.Name, Name: deployment.Name}})
+		require.Equal(t, http.StatusOK, code)
+
+		var attributes []authorizationv1.ResourceAttributes
+		for _, action := range clientset.Actions() {
+			if review, ok := action.(kubeTesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview); ok {
+				assert.Equal(t, "allowed", review.Spec.User)
+				attributes = append(attributes, *review.Spec.ResourceAttributes)
+			}
+		}
+		assert.Equal(t, []authorizationv1.ResourceAttributes{
+			{Namespace: ns.Name, Verb: "get", Group: "apps", Resource: "deployments", Name: deployment.Name},
+		}, attributes)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, ExplainPath, nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
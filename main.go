@@ -125,7 +125,10 @@ func main() {
 		enableNginxInstrumentation       bool
 		enableNodeJSInstrumentation      bool
 		enableJavaInstrumentation        bool
+		enableRubyInstrumentation        bool
+		enablePHPInstrumentation         bool
 		enableCRMetrics                  bool
+		enablePodWebhookExplain          bool
 		collectorImage                   string
 		targetAllocatorImage             string
 		operatorOpAMPBridgeImage         string
@@ -136,6 +139,8 @@ func main() {
 		autoInstrumentationApacheHttpd   string
 		autoInstrumentationNginx         string
 		autoInstrumentationGo            string
+		autoInstrumentationRuby          string
+		autoInstrumentationPHP           string
 		labelsFilter                     []string
 		tmplabelsFilter                  []string
 		annotationsFilter                []string
@@ -164,7 +169,10 @@ func main() {
 	pflag.BoolVar(&enableNginxInstrumentation, constants.FlagNginx, false, "Controls whether the operator supports nginx auto-instrumentation")
 	pflag.BoolVar(&enableNodeJSInstrumentation, constants.FlagNodeJS, true, "Controls whether the operator supports nodejs auto-instrumentation")
 	pflag.BoolVar(&enableJavaInstrumentation, constants.FlagJava, true, "Controls whether the operator supports java auto-instrumentation")
+	pflag.BoolVar(&enableRubyInstrumentation, constants.FlagRuby, false, "Controls whether the operator supports ruby auto-instrumentation")
+	pflag.BoolVar(&enablePHPInstrumentation, constants.FlagPHP, false, "Controls whether the operator supports php auto-instrumentation")
 	pflag.BoolVar(&enableCRMetrics, constants.FlagCRMetrics, false, "Controls whether exposing the CR metrics is enabled")
+	pflag.BoolVar(&enablePodWebhookExplain, "enable-pod-webhook-explain", false, "Serve an endpoint, next to the pod mutation webhook, explaining how a pod would be mutated")
 
 	stringFlagOrEnv(&collectorImage, "collector-image", "RELATED_IMAGE_COLLECTOR", fmt.Sprintf("ghcr.io/open-telemetry/opentelemetry-collector-releases/opentelemetry-collector:%s", v.OpenTelemetryCollector), "The default OpenTelemetry collector image. This image is used when no image is specified in the CustomResource.")
 	stringFlagOrEnv(&targetAllocatorImage, "target-allocator-image", "RELATED_IMAGE_TARGET_ALLOCATOR", fmt.Sprintf("ghcr.io/open-telemetry/opentelemetry-operator/target-allocator:%s", v.TargetAllocator), "The default OpenTelemetry target allocator image. This image is used when no image is specified in the CustomResource.")
@@ -176,6 +184,8 @@ func main() {
 	stringFlagOrEnv(&autoInstrumentationGo, "auto-instrumentation-go-image", "RELATED_IMAGE_AUTO_INSTRUMENTATION_GO", fmt.Sprintf("ghcr.io/open-telemetry/opentelemetry-go-instrumentation/autoinstrumentation-go:%s", v.AutoInstrumentationGo), "The default OpenTelemetry Go instrumentation image. This image is used when no image is specified in the CustomResource.")
 	stringFlagOrEnv(&autoInstrumentationApacheHttpd, "auto-instrumentation-apache-httpd-image", "RELATED_IMAGE_AUTO_INSTRUMENTATION_APACHE_HTTPD", fmt.Sprintf("ghcr.io/open-telemetry/opentelemetry-operator/autoinstrumentation-apache-httpd:%s", v.AutoInstrumentationApacheHttpd), "The default OpenTelemetry Apache HTTPD instrumentation image. This image is used when no image is specified in the CustomResource.")
 	stringFlagOrEnv(&autoInstrumentationNginx, "auto-instrumentation-nginx-image", "RELATED_IMAGE_AUTO_INSTRUMENTATION_NGINX", fmt.Sprintf("ghcr.io/open-telemetry/opentelemetry-operator/autoinstrumentation-apache-httpd:%s", v.AutoInstrumentationNginx), "The default OpenTelemetry Nginx instrumentation image. This image is used when no image is specified in the CustomResource.")
+	stringFlagOrEnv(&autoInstrumentationRuby, "auto-instrumentation-ruby-image", "RELATED_IMAGE_AUTO_INSTRUMENTATION_RUBY", fmt.Sprintf("ghcr.io/open-telemetry/opentelemetry-operator/autoinstrumentation-ruby:%s", v.AutoInstrumentationRuby), "The default OpenTelemetry Ruby instrumentation image. This image is used when no image is specified in the CustomResource.")
+	stringFlagOrEnv(&autoInstrumentationPHP, "auto-instrumentation-php-image", "RELATED_IMAGE_AUTO_INSTRUMENTATION_PHP", fmt.Sprintf("ghcr.io/open-telemetry/opentelemetry-operator/autoinstrumentation-php:%s", v.AutoInstrumentationPHP), "The default OpenTelemetry PHP instrumentation image. This image is used when no image is specified in the CustomResource.")
 	pflag.StringArrayVar(&labelsFilter, "labels-filter", []string{}, "Labels to filter away from propagating onto deploys. It should be a string array containing patterns, which are literal strings optionally containing a * wildcard character. Example: --labels-filter=.*filter.out will filter out labels that looks like: label.filter.out: true")
 	pflag.StringArrayVar(&tmplabelsFilter, "label", []string{}, "(Deprecated, please use the labels-filter flag) Labels to filter away from propagating onto deploys. It should be a string array containing patterns, which are literal strings optionally containing a * wildcard character. Example: --label=.*filter.out will filter out labels that looks like: label.filter.out: true")
 	pflag.StringArrayVar(&annotationsFilter, "annotations-filter", []string{}, "Annotations to filter away from propagating onto deploys. It should be a string array containing patterns, which are literal strings optionally containing a * wildcard character. Example: --annotations-filter=.*filter.out will filter out annotations that looks like: annotation.filter.out: true")
@@ -185,7 +195,7 @@ func main() {
 	pflag.StringVar(&encodeLevelKey, "zap-level-key", "level", "The level key to be used in the customized Log Encoder")
 	pflag.StringVar(&encodeTimeKey, "zap-time-key", "timestamp", "The time key to be used in the customized Log Encoder")
 	pflag.StringVar(&encodeLevelFormat, "zap-level-format", "uppercase", "The level format to be used in the customized Log Encoder")
-	pflag.StringVar(&fipsDisabledComponents, "fips-disabled-components", "uppercase", "Disabled collector components when operator runs on FIPS enabled platform. Example flag value =receiver.foo,receiver.bar,exporter.baz")
+	pflag.StringVar(&fipsDisabledComponents, "fips-disabled-components", "uppercase", "Disabled collector components when operator runs on FIPS enabled platform. Example flag value =receiver.foo,receiver.bar,exporter.baz,connector.qux")
 	pflag.IntVar(&webhookPort, "webhook-port", 9443, "The port the webhook endpoint binds to.")
 	pflag.Parse()
 
@@ -214,6 +224,8 @@ func main() {
 		"auto-instrumentation-go", autoInstrumentationGo,
 		"auto-instrumentation-apache-httpd", autoInstrumentationApacheHttpd,
 		"auto-instrumentation-nginx", autoInstrumentationNginx,
+		"auto-instrumentation-ruby", autoInstrumentationRuby,
+		"auto-instrumentation-php", autoInstrumentationPHP,
 		"feature-gates", flagset.Lookup(featuregate.FeatureGatesFlag).Value.String(),
 		"build-date", v.BuildDate,
 		"go-version", v.Go,
@@ -229,7 +241,10 @@ func main() {
 		"enable-nginx-instrumentation", enableNginxInstrumentation,
 		"enable-nodejs-instrumentation", enableNodeJSInstrumentation,
 		"enable-java-instrumentation", enableJavaInstrumentation,
+		"enable-ruby-instrumentation", enableRubyInstrumentation,
+		"enable-php-instrumentation", enablePHPInstrumentation,
 		"create-openshift-dashboard", createOpenShiftDashboard,
+		"enable-pod-webhook-explain", enablePodWebhookExplain,
 		"zap-message-key", encodeMessageKey,
 		"zap-level-key", encodeLevelKey,
 		"zap-time-key", encodeTimeKey,
@@ -322,6 +337,8 @@ func main() {
 		config.WithEnablePythonInstrumentation(enablePythonInstrumentation),
 		config.WithEnableNodeJSInstrumentation(enableNodeJSInstrumentation),
 		config.WithEnableJavaInstrumentation(enableJavaInstrumentation),
+		config.WithEnableRubyInstrumentation(enableRubyInstrumentation),
+		config.WithEnablePHPInstrumentation(enablePHPInstrumentation),
 		config.WithTargetAllocatorImage(targetAllocatorImage),
 		config.WithOperatorOpAMPBridgeImage(operatorOpAMPBridgeImage),
 		config.WithAutoInstrumentationJavaImage(autoInstrumentationJava),
@@ -331,6 +348,8 @@ func main() {
 		config.WithAutoInstrumentationGoImage(autoInstrumentationGo),
 		config.WithAutoInstrumentationApacheHttpdImage(autoInstrumentationApacheHttpd),
 		config.WithAutoInstrumentationNginxImage(autoInstrumentationNginx),
+		config.WithAutoInstrumentationRubyImage(autoInstrumentationRuby),
+		config.WithAutoInstrumentationPHPImage(autoInstrumentationPHP),
 		config.WithAutoDetect(ad),
 		config.WithLabelFilters(labelsFilter),
 		config.WithAnnotationFilters(annotationsFilter),
@@ -424,6 +443,30 @@ func main() {
 		os.Exit(1)
 	}
 
+	if featuregate.EnableInstrumentationStatus.IsEnabled() {
+		if err = controllers.NewInstrumentationReconciler(controllers.InstrumentationReconcilerParams{
+			Client:   mgr.GetClient(),
+			Log:      ctrl.Log.WithName("controllers").WithName("Instrumentation"),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("instrumentation"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Instrumentation")
+			os.Exit(1)
+		}
+	}
+
+	if featuregate.EnableCollectorGroups.IsEnabled() {
+		if err = controllers.NewCollectorGroupReconciler(controllers.CollectorGroupReconcilerParams{
+			Client:   mgr.GetClient(),
+			Log:      ctrl.Log.WithName("controllers").WithName("CollectorGroup"),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("collectorgroup"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "CollectorGroup")
+			os.Exit(1)
+		}
+	}
+
 	if cfg.PrometheusCRAvailability() == prometheus.Available {
 		operatorMetrics, opError := operatormetrics.NewOperatorMetrics(mgr.GetConfig(), scheme, ctrl.Log.WithName("operator-metrics-sm"))
 		if opError != nil {
@@ -458,6 +501,11 @@ func main() {
 				return warnings
 			}
 
+			if newErr = collectorReconciler.ApplyConfigTemplates(ctx, &params); newErr != nil {
+				warnings = append(warnings, newErr.Error())
+				return warnings
+			}
+
 			params.ErrorAsWarning = true
 			_, newErr = collectorManifests.Build(params)
 			if newErr != nil {
@@ -469,9 +517,9 @@ func main() {
 
 		var fipsCheck fips.FIPSCheck
 		if ad.FIPSEnabled(ctx) {
-			receivers, exporters, processors, extensions := parseFipsFlag(fipsDisabledComponents)
-			logger.Info("Fips disabled components", "receivers", receivers, "exporters", exporters, "processors", processors, "extensions", extensions)
-			fipsCheck = fips.NewFipsCheck(receivers, exporters, processors, extensions)
+			receivers, exporters, processors, extensions, connectors := parseFipsFlag(fipsDisabledComponents)
+			logger.Info("Fips disabled components", "receivers", receivers, "exporters", exporters, "processors", processors, "extensions", extensions, "connectors", connectors)
+			fipsCheck = fips.NewFipsCheck(receivers, exporters, processors, extensions, connectors)
 		}
 		if err = otelv1beta1.SetupCollectorWebhook(mgr, cfg, reviewer, crdMetrics, bv, fipsCheck); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "OpenTelemetryCollector")
@@ -487,14 +535,23 @@ func main() {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Instrumentation")
 			os.Exit(1)
 		}
+		// the pod mutator lists the Instrumentations that can select a pod from other namespaces through this index
+		if err = mgr.GetFieldIndexer().IndexField(ctx, &otelv1alpha1.Instrumentation{}, instrumentation.InstrumentationNamespaceSelectorIndex, instrumentation.InstrumentationNamespaceSelectorIndexValues); err != nil {
+			setupLog.Error(err, "unable to index the Instrumentation selectors")
+			os.Exit(1)
+		}
 		decoder := admission.NewDecoder(mgr.GetScheme())
+		podMutators := []podmutation.PodMutator{
+			sidecar.NewMutator(logger, cfg, mgr.GetClient()),
+			instrumentation.NewMutator(logger, mgr.GetClient(), mgr.GetEventRecorderFor("opentelemetry-operator"), cfg),
+		}
 		mgr.GetWebhookServer().Register("/mutate-v1-pod", &webhook.Admission{
-			Handler: podmutation.NewWebhookHandler(cfg, ctrl.Log.WithName("pod-webhook"), decoder, mgr.GetClient(),
-				[]podmutation.PodMutator{
-					sidecar.NewMutator(logger, cfg, mgr.GetClient()),
-					instrumentation.NewMutator(logger, mgr.GetClient(), mgr.GetEventRecorderFor("opentelemetry-operator"), cfg),
-				}),
+			Handler: podmutation.NewWebhookHandler(cfg, ctrl.Log.WithName("pod-webhook"), decoder, mgr.GetClient(), podMutators),
 		})
+		if enablePodWebhookExplain {
+			mgr.GetWebhookServer().Register(podmutation.ExplainPath,
+				podmutation.NewExplainHandler(ctrl.Log.WithName("pod-webhook-explain"), mgr.GetClient(), clientset, podMutators))
+		}
 
 		if err = otelv1alpha1.SetupOpAMPBridgeWebhook(mgr, cfg); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "OpAMPBridge")
@@ -572,12 +629,13 @@ func tlsConfigSetting(cfg *tls.Config, tlsOpt tlsConfig) {
 	cfg.CipherSuites = cipherSuiteIDs
 }
 
-func parseFipsFlag(fipsFlag string) ([]string, []string, []string, []string) {
+func parseFipsFlag(fipsFlag string) ([]string, []string, []string, []string, []string) {
 	split := strings.Split(fipsFlag, ",")
 	var receivers []string
 	var exporters []string
 	var processors []string
 	var extensions []string
+	var connectors []string
 	for _, val := range split {
 		val = strings.TrimSpace(val)
 		typeAndName := strings.Split(val, ".")
@@ -594,8 +652,10 @@ func parseFipsFlag(fipsFlag string) ([]string, []string, []string, []string) {
 				processors = append(processors, name)
 			case "extension":
 				extensions = append(extensions, name)
+			case "connector":
+				connectors = append(connectors, name)
 			}
 		}
 	}
-	return receivers, exporters, processors, extensions
+	return receivers, exporters, processors, extensions, connectors
 }
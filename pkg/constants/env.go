@@ -25,6 +25,10 @@ const (
 	EnvOTELExporterCertificate       = "OTEL_EXPORTER_OTLP_CERTIFICATE"
 	EnvOTELExporterClientCertificate = "OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE"
 	EnvOTELExporterClientKey         = "OTEL_EXPORTER_OTLP_CLIENT_KEY"
+	EnvOTELExporterOTLPProtocol      = "OTEL_EXPORTER_OTLP_PROTOCOL"
+	EnvOTELExporterOTLPCompression   = "OTEL_EXPORTER_OTLP_COMPRESSION"
+	EnvOTELExporterOTLPTimeout       = "OTEL_EXPORTER_OTLP_TIMEOUT"
+	EnvOTELExporterOTLPHeaders       = "OTEL_EXPORTER_OTLP_HEADERS"
 
 	InstrumentationPrefix                           = "instrumentation.opentelemetry.io/"
 	AnnotationDefaultAutoInstrumentationJava        = InstrumentationPrefix + "default-auto-instrumentation-java-image"
@@ -34,12 +38,15 @@ const (
 	AnnotationDefaultAutoInstrumentationGo          = InstrumentationPrefix + "default-auto-instrumentation-go-image"
 	AnnotationDefaultAutoInstrumentationApacheHttpd = InstrumentationPrefix + "default-auto-instrumentation-apache-httpd-image"
 	AnnotationDefaultAutoInstrumentationNginx       = InstrumentationPrefix + "default-auto-instrumentation-nginx-image"
+	AnnotationDefaultAutoInstrumentationRuby        = InstrumentationPrefix + "default-auto-instrumentation-ruby-image"
+	AnnotationDefaultAutoInstrumentationPHP         = InstrumentationPrefix + "default-auto-instrumentation-php-image"
 
 	LabelAppName    = "app.kubernetes.io/name"
 	LabelAppVersion = "app.kubernetes.io/version"
 	LabelAppPartOf  = "app.kubernetes.io/part-of"
 
 	LabelTargetAllocator              = "opentelemetry.io/target-allocator"
+	LabelCollectorGroup               = "opentelemetry.io/collector-group"
 	ResourceAttributeAnnotationPrefix = "resource.opentelemetry.io/"
 
 	EnvPodName  = "OTEL_RESOURCE_ATTRIBUTES_POD_NAME"
@@ -56,6 +63,8 @@ const (
 	FlagNginx       = "enable-nginx-instrumentation"
 	FlagNodeJS      = "enable-nodejs-instrumentation"
 	FlagJava        = "enable-java-instrumentation"
+	FlagRuby        = "enable-ruby-instrumentation"
+	FlagPHP         = "enable-php-instrumentation"
 
 	TACollectorTLSDirPath      = "/tls"
 	TACollectorCAFileName      = "ca.crt"
@@ -76,6 +76,39 @@ var (
 		featuregate.WithRegisterDescription("enables fallback allocation strategy for the target allocator"),
 		featuregate.WithRegisterFromVersion("v0.114.0"),
 	)
+	// EnableTargetAllocatorLeaderElection is the feature gate that enables leader election between the replicas of
+	// a target allocator, so that only one of them allocates targets.
+	EnableTargetAllocatorLeaderElection = featuregate.GlobalRegistry().MustRegister(
+		"operator.targetallocator.leaderelection",
+		featuregate.StageAlpha,
+		featuregate.WithRegisterDescription("enables leader election between the target allocator replicas when there are more than one"),
+		featuregate.WithRegisterFromVersion("v0.118.0"),
+	)
+	// EnableInstrumentationStatus is the feature gate that enables the reconciler populating the Instrumentation status.
+	// The reconciler watches all pods in the watched namespaces, which increases the operator's memory usage.
+	EnableInstrumentationStatus = featuregate.GlobalRegistry().MustRegister(
+		"operator.instrumentation.status",
+		featuregate.StageAlpha,
+		featuregate.WithRegisterDescription("enables the operator to report the workloads and pods using an Instrumentation in its status"),
+		featuregate.WithRegisterFromVersion("v0.118.0"),
+	)
+	// EnableCollectorGroups is the feature gate that enables the reconciler of CollectorGroups, which composes an agent
+	// and a gateway OpenTelemetryCollector. The CollectorGroup CRD must be installed when it is enabled.
+	EnableCollectorGroups = featuregate.GlobalRegistry().MustRegister(
+		"operator.collectorgroups",
+		featuregate.StageAlpha,
+		featuregate.WithRegisterDescription("enables the operator to reconcile CollectorGroups into agent and gateway collectors"),
+		featuregate.WithRegisterFromVersion("v0.118.0"),
+	)
+	// EnableCollectorConfigTemplates is the feature gate that enables collectors to merge the CollectorConfigTemplates
+	// and ClusterCollectorConfigTemplates they reference into their config. The CRDs of the templates must be installed
+	// when it is enabled.
+	EnableCollectorConfigTemplates = featuregate.GlobalRegistry().MustRegister(
+		"operator.collector.configtemplates",
+		featuregate.StageAlpha,
+		featuregate.WithRegisterDescription("enables collectors to reference config templates in spec.config.templates"),
+		featuregate.WithRegisterFromVersion("v0.118.0"),
+	)
 	// EnableConfigDefaulting is the feature gate that enables the operator to default the endpoint for known components.
 	EnableConfigDefaulting = featuregate.GlobalRegistry().MustRegister(
 		"operator.collector.default.config",
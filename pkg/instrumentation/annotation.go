@@ -43,6 +43,11 @@ const (
 	annotationInjectApacheHttpdContainersName = "instrumentation.opentelemetry.io/apache-httpd-container-names"
 	annotationInjectNginx                     = "instrumentation.opentelemetry.io/inject-nginx"
 	annotationInjectNginxContainersName       = "instrumentation.opentelemetry.io/inject-nginx-container-names"
+	annotationInjectRuby                      = "instrumentation.opentelemetry.io/inject-ruby"
+	annotationInjectRubyContainersName        = "instrumentation.opentelemetry.io/ruby-container-names"
+	annotationInjectPHP                       = "instrumentation.opentelemetry.io/inject-php"
+	annotationInjectPHPContainersName         = "instrumentation.opentelemetry.io/php-container-names"
+	annotationInjectAuto                      = "instrumentation.opentelemetry.io/inject-auto"
 )
 
 // annotationValue returns the effective annotationInjectJava value, based on the annotations from the pod and namespace.
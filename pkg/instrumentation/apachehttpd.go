@@ -17,6 +17,7 @@ package instrumentation
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/go-logr/logr"
@@ -59,7 +60,7 @@ const (
 	6) Inject mounting of volumes / files into appropriate directories in application container
 */
 
-func injectApacheHttpdagent(_ logr.Logger, apacheSpec v1alpha1.ApacheHttpd, pod corev1.Pod, useLabelsForResourceAttributes bool, index int, otlpEndpoint string, resourceMap map[string]string) corev1.Pod {
+func injectApacheHttpdagent(_ logr.Logger, apacheSpec v1alpha1.ApacheHttpd, pod corev1.Pod, useLabelsForResourceAttributes bool, index int, exporter v1alpha1.Exporter, resourceMap map[string]string) corev1.Pod {
 
 	volume := instrVolume(apacheSpec.VolumeClaimTemplate, apacheAgentVolume, apacheSpec.VolumeSizeLimit)
 
@@ -156,19 +157,20 @@ func injectApacheHttpdagent(_ logr.Logger, apacheSpec v1alpha1.ApacheHttpd, pod
 					// Include a link to include Apache agent configuration file into httpd.conf
 					"echo -e '\nInclude " + getApacheConfDir(apacheSpec.ConfigPath) + "/" + apacheAgentConfigFile + "' >> " + apacheAgentConfDirFull + "/" + apacheConfigFile,
 			},
-			Env: []corev1.EnvVar{
-				{
+			// The header values read from secrets are expanded in the agent configuration by Kubernetes.
+			Env: append(exporterHeaderSecretEnvVars(exporter),
+				corev1.EnvVar{
 					Name:  apacheAttributesEnvVar,
-					Value: getApacheOtelConfig(pod, useLabelsForResourceAttributes, apacheSpec, index, otlpEndpoint, resourceMap),
+					Value: getApacheOtelConfig(pod, useLabelsForResourceAttributes, apacheSpec, index, exporter, resourceMap),
 				},
-				{Name: apacheServiceInstanceIdEnvVar,
+				corev1.EnvVar{Name: apacheServiceInstanceIdEnvVar,
 					ValueFrom: &corev1.EnvVarSource{
 						FieldRef: &corev1.ObjectFieldSelector{
 							FieldPath: "metadata.name",
 						},
 					},
 				},
-			},
+			),
 			Resources: apacheSpec.Resources,
 			VolumeMounts: []corev1.VolumeMount{
 				{
@@ -198,7 +200,7 @@ func isApacheInitContainerMissing(pod corev1.Pod, containerName string) bool {
 
 // Calculate Apache HTTPD agent configuration file based on attributes provided by the injection rules
 // and by the pod values.
-func getApacheOtelConfig(pod corev1.Pod, useLabelsForResourceAttributes bool, apacheSpec v1alpha1.ApacheHttpd, index int, otelEndpoint string, resourceMap map[string]string) string {
+func getApacheOtelConfig(pod corev1.Pod, useLabelsForResourceAttributes bool, apacheSpec v1alpha1.ApacheHttpd, index int, exporter v1alpha1.Exporter, resourceMap map[string]string) string {
 	template := `
 #Load the Otel Webserver SDK
 LoadFile %[1]s/sdk_lib/lib/libopentelemetry_common.so
@@ -216,6 +218,10 @@ LoadFile %[1]s/sdk_lib/lib/libopentelemetry_webserver_sdk.so
 LoadModule otel_apache_module %[1]s/WebServerModule/Apache/libmod_apache_otel%[2]s.so
 #Attributes
 `
+	otelEndpoint := exporter.Endpoint
+	if exporter.Traces != nil && exporter.Traces.Endpoint != "" {
+		otelEndpoint = exporter.Traces.Endpoint
+	}
 	if otelEndpoint == "" {
 		otelEndpoint = "http://localhost:4317/"
 	}
@@ -251,6 +257,16 @@ LoadModule otel_apache_module %[1]s/WebServerModule/Apache/libmod_apache_otel%[2
 		"ApacheModuleResolveBackends": " ON",
 		"ApacheModuleTraceAsError":    " ON",
 	}
+	// The module only exports traces, always with OTLP over gRPC and without compression.
+	if signalExporterName(exporter.Traces) == "none" {
+		attrMap["ApacheModuleEnabled"] = "OFF"
+	}
+	if len(exporter.Headers) > 0 {
+		attrMap["ApacheModuleOtelExporterHeaders"] = exporterHeaders(exporter)
+	}
+	if exporter.Timeout != nil {
+		attrMap["ApacheModuleOtelExportTimeoutMillis"] = strconv.FormatInt(exporter.Timeout.Milliseconds(), 10)
+	}
 	for _, attr := range apacheSpec.Attrs {
 		attrMap[attr.Name] = attr.Value
 	}
@@ -17,6 +17,8 @@ package instrumentation
 import (
 	"fmt"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -25,66 +27,185 @@ import (
 	"github.com/open-telemetry/opentelemetry-operator/pkg/constants"
 )
 
+// otlpEnvVars holds the names of the env vars configuring an OTLP exporter, either for all signals or for a single one.
+type otlpEnvVars struct {
+	endpoint          string
+	protocol          string
+	certificate       string
+	clientCertificate string
+	clientKey         string
+}
+
+var allSignalsEnvVars = otlpEnvVars{
+	endpoint:          constants.EnvOTELExporterOTLPEndpoint,
+	protocol:          constants.EnvOTELExporterOTLPProtocol,
+	certificate:       constants.EnvOTELExporterCertificate,
+	clientCertificate: constants.EnvOTELExporterClientCertificate,
+	clientKey:         constants.EnvOTELExporterClientKey,
+}
+
+func signalEnvVars(signal string) otlpEnvVars {
+	prefix := "OTEL_EXPORTER_OTLP_" + signal
+	return otlpEnvVars{
+		endpoint:          prefix + "_ENDPOINT",
+		protocol:          prefix + "_PROTOCOL",
+		certificate:       prefix + "_CERTIFICATE",
+		clientCertificate: prefix + "_CLIENT_CERTIFICATE",
+		clientKey:         prefix + "_CLIENT_KEY",
+	}
+}
+
+// signalExporter is the exporter configuration of a signal, along with the env var selecting the exporter of the
+// signal and the env vars configuring its OTLP exporter.
+type signalExporter struct {
+	exporterEnvVar string
+	envVars        otlpEnvVars
+	config         *v1alpha1.SignalExporter
+}
+
+func signalExporters(exporter v1alpha1.Exporter) []signalExporter {
+	return []signalExporter{
+		{envOtelTracesExporter, signalEnvVars("TRACES"), exporter.Traces},
+		{envOtelMetricsExporter, signalEnvVars("METRICS"), exporter.Metrics},
+		{envOtelLogsExporter, signalEnvVars("LOGS"), exporter.Logs},
+	}
+}
+
+// ExporterTLS returns the TLS configurations of an exporter: its own and the ones of its enabled signals.
+func ExporterTLS(exporter v1alpha1.Exporter) []*v1alpha1.TLS {
+	var tlsConfigs []*v1alpha1.TLS
+	if exporter.TLS != nil {
+		tlsConfigs = append(tlsConfigs, exporter.TLS)
+	}
+	for _, signal := range signalExporters(exporter) {
+		if signal.config != nil && signal.config.TLS != nil && signalExporterName(signal.config) != "none" {
+			tlsConfigs = append(tlsConfigs, signal.config.TLS)
+		}
+	}
+	return tlsConfigs
+}
+
+// signalExporterName returns the value of the OTEL_<SIGNAL>_EXPORTER env var for the given signal configuration.
+func signalExporterName(signal *v1alpha1.SignalExporter) string {
+	if signal != nil && signal.Enabled != nil && !*signal.Enabled {
+		return "none"
+	}
+	return "otlp"
+}
+
 func configureExporter(exporter v1alpha1.Exporter, pod *corev1.Pod, container *corev1.Container) {
-	if exporter.Endpoint != "" {
-		if getIndexOfEnv(container.Env, constants.EnvOTELExporterOTLPEndpoint) == -1 {
-			container.Env = append(container.Env, corev1.EnvVar{
-				Name:  constants.EnvOTELExporterOTLPEndpoint,
-				Value: exporter.Endpoint,
-			})
+	configureOTLPExporter(exporter.Endpoint, exporter.Protocol, allSignalsEnvVars, container)
+	if exporter.Compression != "" && getIndexOfEnv(container.Env, constants.EnvOTELExporterOTLPCompression) == -1 {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  constants.EnvOTELExporterOTLPCompression,
+			Value: string(exporter.Compression),
+		})
+	}
+	if exporter.Timeout != nil && getIndexOfEnv(container.Env, constants.EnvOTELExporterOTLPTimeout) == -1 {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  constants.EnvOTELExporterOTLPTimeout,
+			Value: strconv.FormatInt(exporter.Timeout.Milliseconds(), 10),
+		})
+	}
+	if len(exporter.Headers) > 0 && getIndexOfEnv(container.Env, constants.EnvOTELExporterOTLPHeaders) == -1 {
+		// the env vars holding the secret values have to be defined before the one referencing them
+		for _, env := range exporterHeaderSecretEnvVars(exporter) {
+			if getIndexOfEnv(container.Env, env.Name) == -1 {
+				container.Env = append(container.Env, env)
+			}
+		}
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  constants.EnvOTELExporterOTLPHeaders,
+			Value: exporterHeaders(exporter),
+		})
+	}
+	configureExporterTLS(exporter.TLS, allSignalsEnvVars, pod, container)
+
+	for _, signal := range signalExporters(exporter) {
+		if signal.config == nil {
+			continue
+		}
+		if name := signalExporterName(signal.config); name == "none" {
+			if getIndexOfEnv(container.Env, signal.exporterEnvVar) == -1 {
+				container.Env = append(container.Env, corev1.EnvVar{
+					Name:  signal.exporterEnvVar,
+					Value: name,
+				})
+			}
+			continue
 		}
+		configureOTLPExporter(signal.config.Endpoint, signal.config.Protocol, signal.envVars, container)
+		configureExporterTLS(signal.config.TLS, signal.envVars, pod, container)
+	}
+}
+
+func configureOTLPExporter(endpoint string, protocol v1alpha1.ExporterProtocol, envVars otlpEnvVars, container *corev1.Container) {
+	if endpoint != "" && getIndexOfEnv(container.Env, envVars.endpoint) == -1 {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  envVars.endpoint,
+			Value: endpoint,
+		})
+	}
+	if protocol != "" && getIndexOfEnv(container.Env, envVars.protocol) == -1 {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  envVars.protocol,
+			Value: string(protocol),
+		})
 	}
-	if exporter.TLS == nil {
+}
+
+func configureExporterTLS(tls *v1alpha1.TLS, envVars otlpEnvVars, pod *corev1.Pod, container *corev1.Container) {
+	if tls == nil {
 		return
 	}
 	// the name cannot be longer than 63 characters
-	secretVolumeName := naming.Truncate("otel-auto-secret-%s", 63, exporter.TLS.SecretName)
-	secretMountPath := fmt.Sprintf("/otel-auto-instrumentation-secret-%s", exporter.TLS.SecretName)
-	configMapVolumeName := naming.Truncate("otel-auto-configmap-%s", 63, exporter.TLS.ConfigMapName)
-	configMapMountPath := fmt.Sprintf("/otel-auto-instrumentation-configmap-%s", exporter.TLS.ConfigMapName)
+	secretVolumeName := naming.Truncate("otel-auto-secret-%s", 63, tls.SecretName)
+	secretMountPath := fmt.Sprintf("/otel-auto-instrumentation-secret-%s", tls.SecretName)
+	configMapVolumeName := naming.Truncate("otel-auto-configmap-%s", 63, tls.ConfigMapName)
+	configMapMountPath := fmt.Sprintf("/otel-auto-instrumentation-configmap-%s", tls.ConfigMapName)
 
-	if exporter.TLS.CA != "" {
+	if tls.CA != "" {
 		mountPath := secretMountPath
-		if exporter.TLS.ConfigMapName != "" {
+		if tls.ConfigMapName != "" {
 			mountPath = configMapMountPath
 		}
-		envVarVal := fmt.Sprintf("%s/%s", mountPath, exporter.TLS.CA)
-		if filepath.IsAbs(exporter.TLS.CA) {
-			envVarVal = exporter.TLS.CA
+		envVarVal := fmt.Sprintf("%s/%s", mountPath, tls.CA)
+		if filepath.IsAbs(tls.CA) {
+			envVarVal = tls.CA
 		}
-		if getIndexOfEnv(container.Env, constants.EnvOTELExporterCertificate) == -1 {
+		if getIndexOfEnv(container.Env, envVars.certificate) == -1 {
 			container.Env = append(container.Env, corev1.EnvVar{
-				Name:  constants.EnvOTELExporterCertificate,
+				Name:  envVars.certificate,
 				Value: envVarVal,
 			})
 		}
 	}
-	if exporter.TLS.Cert != "" {
-		envVarVal := fmt.Sprintf("%s/%s", secretMountPath, exporter.TLS.Cert)
-		if filepath.IsAbs(exporter.TLS.Cert) {
-			envVarVal = exporter.TLS.Cert
+	if tls.Cert != "" {
+		envVarVal := fmt.Sprintf("%s/%s", secretMountPath, tls.Cert)
+		if filepath.IsAbs(tls.Cert) {
+			envVarVal = tls.Cert
 		}
-		if getIndexOfEnv(container.Env, constants.EnvOTELExporterClientCertificate) == -1 {
+		if getIndexOfEnv(container.Env, envVars.clientCertificate) == -1 {
 			container.Env = append(container.Env, corev1.EnvVar{
-				Name:  constants.EnvOTELExporterClientCertificate,
+				Name:  envVars.clientCertificate,
 				Value: envVarVal,
 			})
 		}
 	}
-	if exporter.TLS.Key != "" {
-		envVarVar := fmt.Sprintf("%s/%s", secretMountPath, exporter.TLS.Key)
-		if filepath.IsAbs(exporter.TLS.Key) {
-			envVarVar = exporter.TLS.Key
+	if tls.Key != "" {
+		envVarVar := fmt.Sprintf("%s/%s", secretMountPath, tls.Key)
+		if filepath.IsAbs(tls.Key) {
+			envVarVar = tls.Key
 		}
-		if getIndexOfEnv(container.Env, constants.EnvOTELExporterClientKey) == -1 {
+		if getIndexOfEnv(container.Env, envVars.clientKey) == -1 {
 			container.Env = append(container.Env, corev1.EnvVar{
-				Name:  constants.EnvOTELExporterClientKey,
+				Name:  envVars.clientKey,
 				Value: envVarVar,
 			})
 		}
 	}
 
-	if exporter.TLS.SecretName != "" {
+	if tls.SecretName != "" {
 		addVolume := true
 		for _, vol := range pod.Spec.Volumes {
 			if vol.Name == secretVolumeName {
@@ -96,7 +217,7 @@ func configureExporter(exporter v1alpha1.Exporter, pod *corev1.Pod, container *c
 				Name: secretVolumeName,
 				VolumeSource: corev1.VolumeSource{
 					Secret: &corev1.SecretVolumeSource{
-						SecretName: exporter.TLS.SecretName,
+						SecretName: tls.SecretName,
 					},
 				}})
 		}
@@ -115,7 +236,7 @@ func configureExporter(exporter v1alpha1.Exporter, pod *corev1.Pod, container *c
 		}
 	}
 
-	if exporter.TLS.ConfigMapName != "" {
+	if tls.ConfigMapName != "" {
 		addVolume := true
 		for _, vol := range pod.Spec.Volumes {
 			if vol.Name == configMapVolumeName {
@@ -128,7 +249,7 @@ func configureExporter(exporter v1alpha1.Exporter, pod *corev1.Pod, container *c
 				VolumeSource: corev1.VolumeSource{
 					ConfigMap: &corev1.ConfigMapVolumeSource{
 						LocalObjectReference: corev1.LocalObjectReference{
-							Name: exporter.TLS.ConfigMapName,
+							Name: tls.ConfigMapName,
 						},
 					},
 				}})
@@ -148,3 +269,39 @@ func configureExporter(exporter v1alpha1.Exporter, pod *corev1.Pod, container *c
 		}
 	}
 }
+
+// exporterHeaderEnvVar returns the name of the env var holding the value of a header read from a secret.
+func exporterHeaderEnvVar(header v1alpha1.ExporterHeader) string {
+	return "OTEL_EXPORTER_OTLP_HEADER_" + strings.ToUpper(strings.ReplaceAll(header.Name, "-", "_"))
+}
+
+// exporterHeaderSecretEnvVars returns the env vars reading the header values stored in secrets.
+func exporterHeaderSecretEnvVars(exporter v1alpha1.Exporter) []corev1.EnvVar {
+	var envs []corev1.EnvVar
+	for _, header := range exporter.Headers {
+		if header.SecretKeyRef == nil {
+			continue
+		}
+		envs = append(envs, corev1.EnvVar{
+			Name: exporterHeaderEnvVar(header),
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: header.SecretKeyRef,
+			},
+		})
+	}
+	return envs
+}
+
+// exporterHeaders returns the headers of the exporter in the key1=value1,key2=value2 format.
+// Values stored in secrets reference the env vars returned by exporterHeaderSecretEnvVars, which Kubernetes expands.
+func exporterHeaders(exporter v1alpha1.Exporter) string {
+	headers := make([]string, 0, len(exporter.Headers))
+	for _, header := range exporter.Headers {
+		value := header.Value
+		if header.SecretKeyRef != nil {
+			value = fmt.Sprintf("$(%s)", exporterHeaderEnvVar(header))
+		}
+		headers = append(headers, fmt.Sprintf("%s=%s", header.Name, value))
+	}
+	return strings.Join(headers, ",")
+}
@@ -16,9 +16,12 @@ package instrumentation
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
 )
@@ -192,6 +195,131 @@ func TestExporter(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "protocol, compression, timeout and headers",
+			exporter: v1alpha1.Exporter{
+				Endpoint:    "https://collector:4318",
+				Protocol:    v1alpha1.ExporterProtocolHTTPProtobuf,
+				Compression: v1alpha1.ExporterCompressionGzip,
+				Timeout:     &metav1.Duration{Duration: 5 * time.Second},
+				Headers: []v1alpha1.ExporterHeader{
+					{Name: "x-tenant", Value: "team-a"},
+					{
+						Name: "api-key",
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "otlp-auth"},
+							Key:                  "api-key",
+						},
+					},
+				},
+			},
+			expected: corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Env: []corev1.EnvVar{
+								{
+									Name:  "OTEL_EXPORTER_OTLP_ENDPOINT",
+									Value: "https://collector:4318",
+								},
+								{
+									Name:  "OTEL_EXPORTER_OTLP_PROTOCOL",
+									Value: "http/protobuf",
+								},
+								{
+									Name:  "OTEL_EXPORTER_OTLP_COMPRESSION",
+									Value: "gzip",
+								},
+								{
+									Name:  "OTEL_EXPORTER_OTLP_TIMEOUT",
+									Value: "5000",
+								},
+								{
+									Name: "OTEL_EXPORTER_OTLP_HEADER_API_KEY",
+									ValueFrom: &corev1.EnvVarSource{
+										SecretKeyRef: &corev1.SecretKeySelector{
+											LocalObjectReference: corev1.LocalObjectReference{Name: "otlp-auth"},
+											Key:                  "api-key",
+										},
+									},
+								},
+								{
+									Name:  "OTEL_EXPORTER_OTLP_HEADERS",
+									Value: "x-tenant=team-a,api-key=$(OTEL_EXPORTER_OTLP_HEADER_API_KEY)",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "per-signal exporters",
+			exporter: v1alpha1.Exporter{
+				Endpoint: "http://collector:4318",
+				Traces: &v1alpha1.SignalExporter{
+					Endpoint: "https://traces-gateway:4317",
+					Protocol: v1alpha1.ExporterProtocolGRPC,
+					TLS: &v1alpha1.TLS{
+						SecretName: "traces-certs",
+						CA:         "ca.crt",
+					},
+				},
+				Metrics: &v1alpha1.SignalExporter{
+					Enabled: ptr.To(true),
+				},
+				Logs: &v1alpha1.SignalExporter{
+					Enabled: ptr.To(false),
+				},
+			},
+			expected: corev1.Pod{
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{
+						{
+							Name: "otel-auto-secret-traces-certs",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{
+									SecretName: "traces-certs",
+								},
+							},
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "otel-auto-secret-traces-certs",
+									ReadOnly:  true,
+									MountPath: "/otel-auto-instrumentation-secret-traces-certs",
+								},
+							},
+							Env: []corev1.EnvVar{
+								{
+									Name:  "OTEL_EXPORTER_OTLP_ENDPOINT",
+									Value: "http://collector:4318",
+								},
+								{
+									Name:  "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT",
+									Value: "https://traces-gateway:4317",
+								},
+								{
+									Name:  "OTEL_EXPORTER_OTLP_TRACES_PROTOCOL",
+									Value: "grpc",
+								},
+								{
+									Name:  "OTEL_EXPORTER_OTLP_TRACES_CERTIFICATE",
+									Value: "/otel-auto-instrumentation-secret-traces-certs/ca.crt",
+								},
+								{
+									Name:  "OTEL_LOGS_EXPORTER",
+									Value: "none",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -207,3 +335,34 @@ func TestExporter(t *testing.T) {
 		})
 	}
 }
+
+func TestExporterKeepsContainerEnv(t *testing.T) {
+	pod := corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Env: []corev1.EnvVar{
+						{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: "grpc"},
+						{Name: "OTEL_EXPORTER_OTLP_HEADERS", Value: "authorization=Basic%20abc"},
+					},
+				},
+			},
+		},
+	}
+	configureExporter(v1alpha1.Exporter{
+		Protocol: v1alpha1.ExporterProtocolHTTPJSON,
+		Headers: []v1alpha1.ExporterHeader{
+			{
+				Name: "authorization",
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "otlp-auth"},
+					Key:                  "authorization",
+				},
+			},
+		},
+	}, &pod, &pod.Spec.Containers[0])
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: "grpc"},
+		{Name: "OTEL_EXPORTER_OTLP_HEADERS", Value: "authorization=Basic%20abc"},
+	}, pod.Spec.Containers[0].Env)
+}
@@ -0,0 +1,189 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instrumentation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
+)
+
+// defaultLanguageDetectionRules are evaluated after the rules of the Instrumentation, unless they are disabled.
+// Environment variables and commands are stronger hints than images, so they are evaluated first.
+var defaultLanguageDetectionRules = []v1alpha1.LanguageDetectionRule{
+	{Language: v1alpha1.LanguageJava, Env: "JAVA_TOOL_OPTIONS"},
+	{Language: v1alpha1.LanguageNodeJS, Env: "NODE_OPTIONS"},
+	{Language: v1alpha1.LanguagePython, Env: "PYTHONPATH"},
+	{Language: v1alpha1.LanguageDotNet, Env: "ASPNETCORE_URLS"},
+	{Language: v1alpha1.LanguageJava, Command: `(^|[\s/])java\s`},
+	{Language: v1alpha1.LanguageNodeJS, Command: `(^|[\s/])(node|nodejs|npm|npx|yarn)(\s|$)`},
+	{Language: v1alpha1.LanguagePython, Command: `(^|[\s/])(python[0-9.]*|gunicorn|uvicorn)(\s|$)`},
+	{Language: v1alpha1.LanguageDotNet, Command: `(^|[\s/])dotnet\s`},
+	{Language: v1alpha1.LanguageRuby, Command: `(^|[\s/])(ruby|bundle|rails|rackup|puma|unicorn|sidekiq)(\s|$)`},
+	{Language: v1alpha1.LanguagePHP, Command: `(^|[\s/])(php|php-fpm[0-9.]*|artisan|frankenphp)(\s|$)`},
+	{Language: v1alpha1.LanguageJava, Image: `(^|/)(openjdk|eclipse-temurin|amazoncorretto|ibm-semeru-runtimes)(:|@|$)`},
+	{Language: v1alpha1.LanguageNodeJS, Image: `(^|/)node(:|@|$)`},
+	{Language: v1alpha1.LanguagePython, Image: `(^|/)python(:|@|$)`},
+	{Language: v1alpha1.LanguageDotNet, Image: `^mcr\.microsoft\.com/dotnet/`},
+	{Language: v1alpha1.LanguageRuby, Image: `(^|/)ruby(:|@|$)`},
+	{Language: v1alpha1.LanguagePHP, Image: `(^|/)php(:|@|$)`},
+}
+
+type languageDetectionRule struct {
+	language v1alpha1.Language
+	image    *regexp.Regexp
+	command  *regexp.Regexp
+	env      string
+}
+
+func (r languageDetectionRule) matches(container corev1.Container) bool {
+	if r.image != nil && !r.image.MatchString(container.Image) {
+		return false
+	}
+	if r.command != nil && !r.command.MatchString(strings.Join(append(append([]string{}, container.Command...), container.Args...), " ")) {
+		return false
+	}
+	if r.env != "" && getIndexOfEnv(container.Env, r.env) == -1 {
+		return false
+	}
+	return true
+}
+
+// String describes the criteria of the rule, for the event explaining the detection.
+func (r languageDetectionRule) String() string {
+	var criteria []string
+	if r.image != nil {
+		criteria = append(criteria, fmt.Sprintf("image matches %q", r.image.String()))
+	}
+	if r.command != nil {
+		criteria = append(criteria, fmt.Sprintf("command matches %q", r.command.String()))
+	}
+	if r.env != "" {
+		criteria = append(criteria, fmt.Sprintf("env %s is set", r.env))
+	}
+	return strings.Join(criteria, ", ")
+}
+
+// compileLanguageDetectionRules returns the rules of the Instrumentation followed by the default rules, unless
+// they are disabled.
+func compileLanguageDetectionRules(detection v1alpha1.LanguageDetection) ([]languageDetectionRule, error) {
+	rules := detection.Rules
+	if !detection.DisableDefaultRules {
+		rules = append(append([]v1alpha1.LanguageDetectionRule{}, rules...), defaultLanguageDetectionRules...)
+	}
+
+	compiled := make([]languageDetectionRule, 0, len(rules))
+	for _, rule := range rules {
+		if rule.Image == "" && rule.Command == "" && rule.Env == "" {
+			continue
+		}
+		switch rule.Language {
+		case v1alpha1.LanguageJava, v1alpha1.LanguageNodeJS, v1alpha1.LanguagePython, v1alpha1.LanguageDotNet, v1alpha1.LanguageRuby, v1alpha1.LanguagePHP:
+		default:
+			return nil, fmt.Errorf("language %s is not supported by the language detection", rule.Language)
+		}
+		r := languageDetectionRule{language: rule.Language, env: rule.Env}
+		if rule.Image != "" {
+			image, err := regexp.Compile(rule.Image)
+			if err != nil {
+				return nil, fmt.Errorf("invalid image expression in language detection rule: %w", err)
+			}
+			r.image = image
+		}
+		if rule.Command != "" {
+			command, err := regexp.Compile(rule.Command)
+			if err != nil {
+				return nil, fmt.Errorf("invalid command expression in language detection rule: %w", err)
+			}
+			r.command = command
+		}
+		compiled = append(compiled, r)
+	}
+	return compiled, nil
+}
+
+// detectContainerLanguage returns the first rule matching the container, if any.
+func detectContainerLanguage(rules []languageDetectionRule, container corev1.Container) (languageDetectionRule, bool) {
+	for _, rule := range rules {
+		if rule.matches(container) {
+			return rule, true
+		}
+	}
+	return languageDetectionRule{}, false
+}
+
+// languageDetectionContainers returns the containers considered by the language detection: the ones listed by the
+// container names annotation, or all the containers of the pod.
+func languageDetectionContainers(ns metav1.ObjectMeta, pod corev1.Pod) []corev1.Container {
+	containersAnnotation := annotationValue(ns, pod.ObjectMeta, annotationInjectContainerName)
+	if containersAnnotation == "" {
+		return pod.Spec.Containers
+	}
+
+	var containers []corev1.Container
+	for _, name := range strings.Split(containersAnnotation, ",") {
+		for _, container := range pod.Spec.Containers {
+			if container.Name == name {
+				containers = append(containers, container)
+			}
+		}
+	}
+	return containers
+}
+
+// AutoInstrumentationReference returns the effective instrumentation.opentelemetry.io/inject-auto value for a pod,
+// or an empty string when the pod does not request language detection.
+func AutoInstrumentationReference(ns metav1.ObjectMeta, pod metav1.ObjectMeta) string {
+	value := annotationValue(ns, pod, annotationInjectAuto)
+	if strings.EqualFold(value, "false") {
+		return ""
+	}
+	return value
+}
+
+// DetectedLanguages returns the languages detected in the containers of a pod with the language detection rules of
+// an Instrumentation.
+func DetectedLanguages(detection v1alpha1.LanguageDetection, ns metav1.ObjectMeta, pod corev1.Pod) ([]v1alpha1.Language, error) {
+	rules, err := compileLanguageDetectionRules(detection)
+	if err != nil {
+		return nil, err
+	}
+
+	var languages []v1alpha1.Language
+	for _, container := range languageDetectionContainers(ns, pod) {
+		rule, ok := detectContainerLanguage(rules, container)
+		if !ok {
+			continue
+		}
+		if !contains(languages, rule.language) {
+			languages = append(languages, rule.language)
+		}
+	}
+	return languages, nil
+}
+
+func contains(languages []v1alpha1.Language, language v1alpha1.Language) bool {
+	for _, l := range languages {
+		if l == language {
+			return true
+		}
+	}
+	return false
+}
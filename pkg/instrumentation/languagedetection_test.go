@@ -0,0 +1,195 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instrumentation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
+)
+
+func TestDetectContainerLanguageDefaultRules(t *testing.T) {
+	rules, err := compileLanguageDetectionRules(v1alpha1.LanguageDetection{})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		container corev1.Container
+		expected  v1alpha1.Language
+	}{
+		{
+			name:      "java command",
+			container: corev1.Container{Image: "registry.example.com/cart:1.0", Command: []string{"java", "-jar", "/app.jar"}},
+			expected:  v1alpha1.LanguageJava,
+		},
+		{
+			name:      "java tool options",
+			container: corev1.Container{Image: "cart:1.0", Env: []corev1.EnvVar{{Name: "JAVA_TOOL_OPTIONS", Value: "-Xmx1g"}}},
+			expected:  v1alpha1.LanguageJava,
+		},
+		{
+			name:      "java image",
+			container: corev1.Container{Image: "docker.io/library/eclipse-temurin:21"},
+			expected:  v1alpha1.LanguageJava,
+		},
+		{
+			name:      "node command",
+			container: corev1.Container{Image: "frontend:1.0", Command: []string{"/usr/local/bin/node"}, Args: []string{"server.js"}},
+			expected:  v1alpha1.LanguageNodeJS,
+		},
+		{
+			name:      "node image",
+			container: corev1.Container{Image: "node:20-alpine"},
+			expected:  v1alpha1.LanguageNodeJS,
+		},
+		{
+			name:      "python command",
+			container: corev1.Container{Image: "api:1.0", Command: []string{"python3", "-m", "api"}},
+			expected:  v1alpha1.LanguagePython,
+		},
+		{
+			name:      "gunicorn command",
+			container: corev1.Container{Image: "api:1.0", Args: []string{"gunicorn", "api:app"}},
+			expected:  v1alpha1.LanguagePython,
+		},
+		{
+			name:      "dotnet command",
+			container: corev1.Container{Image: "orders:1.0", Command: []string{"dotnet", "Orders.dll"}},
+			expected:  v1alpha1.LanguageDotNet,
+		},
+		{
+			name:      "dotnet image",
+			container: corev1.Container{Image: "mcr.microsoft.com/dotnet/aspnet:8.0"},
+			expected:  v1alpha1.LanguageDotNet,
+		},
+		{
+			name:      "ruby command",
+			container: corev1.Container{Image: "storefront:1.0", Command: []string{"bundle", "exec", "puma"}},
+			expected:  v1alpha1.LanguageRuby,
+		},
+		{
+			name:      "ruby image",
+			container: corev1.Container{Image: "docker.io/library/ruby:3.3-slim"},
+			expected:  v1alpha1.LanguageRuby,
+		},
+		{
+			name:      "php command",
+			container: corev1.Container{Image: "blog:1.0", Command: []string{"php-fpm8.2", "-F"}},
+			expected:  v1alpha1.LanguagePHP,
+		},
+		{
+			name:      "php image",
+			container: corev1.Container{Image: "php:8.3-apache"},
+			expected:  v1alpha1.LanguagePHP,
+		},
+		{
+			name:      "env takes precedence over the image",
+			container: corev1.Container{Image: "python:3.12", Env: []corev1.EnvVar{{Name: "NODE_OPTIONS"}}},
+			expected:  v1alpha1.LanguageNodeJS,
+		},
+		{
+			name:      "node-exporter is not a node application",
+			container: corev1.Container{Image: "quay.io/prometheus/node-exporter:v1.8.0"},
+		},
+		{
+			name:      "javascript is not java",
+			container: corev1.Container{Image: "static:1.0", Command: []string{"serve-javascript", "/srv"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule, ok := detectContainerLanguage(rules, test.container)
+			if test.expected == "" {
+				assert.False(t, ok, "unexpected match: %s", rule)
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, test.expected, rule.language)
+		})
+	}
+}
+
+func TestDetectContainerLanguageCustomRules(t *testing.T) {
+	container := corev1.Container{
+		Image:   "registry.example.com/legacy/billing:2.3",
+		Command: []string{"java", "-jar", "billing.jar"},
+		Env:     []corev1.EnvVar{{Name: "BILLING_RUNTIME"}},
+	}
+
+	rules, err := compileLanguageDetectionRules(v1alpha1.LanguageDetection{
+		Rules: []v1alpha1.LanguageDetectionRule{
+			{Language: v1alpha1.LanguagePython, Image: "/legacy/", Env: "BILLING_RUNTIME"},
+		},
+	})
+	require.NoError(t, err)
+	rule, ok := detectContainerLanguage(rules, container)
+	require.True(t, ok)
+	assert.Equal(t, v1alpha1.LanguagePython, rule.language)
+	assert.Equal(t, `image matches "/legacy/", env BILLING_RUNTIME is set`, rule.String())
+
+	rules, err = compileLanguageDetectionRules(v1alpha1.LanguageDetection{
+		Rules: []v1alpha1.LanguageDetectionRule{
+			{Language: v1alpha1.LanguagePython, Image: "/legacy/", Env: "OTHER_RUNTIME"},
+		},
+		DisableDefaultRules: true,
+	})
+	require.NoError(t, err)
+	_, ok = detectContainerLanguage(rules, container)
+	assert.False(t, ok)
+}
+
+func TestCompileLanguageDetectionRulesInvalid(t *testing.T) {
+	_, err := compileLanguageDetectionRules(v1alpha1.LanguageDetection{
+		Rules: []v1alpha1.LanguageDetectionRule{{Language: v1alpha1.LanguageJava, Command: "(java"}},
+	})
+	assert.ErrorContains(t, err, "invalid command expression in language detection rule")
+
+	_, err = compileLanguageDetectionRules(v1alpha1.LanguageDetection{
+		Rules: []v1alpha1.LanguageDetectionRule{{Language: v1alpha1.LanguageGo, Image: "my-go-app"}},
+	})
+	assert.ErrorContains(t, err, "language go is not supported by the language detection")
+}
+
+func TestDetectedLanguages(t *testing.T) {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{annotationInjectContainerName: "web,worker"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "web", Image: "node:20"},
+				{Name: "worker", Command: []string{"python", "worker.py"}},
+				{Name: "batch", Command: []string{"java", "-jar", "batch.jar"}},
+			},
+		},
+	}
+
+	languages, err := DetectedLanguages(v1alpha1.LanguageDetection{}, metav1.ObjectMeta{}, pod)
+	require.NoError(t, err)
+	assert.Equal(t, []v1alpha1.Language{v1alpha1.LanguageNodeJS, v1alpha1.LanguagePython}, languages)
+}
+
+func TestAutoInstrumentationReference(t *testing.T) {
+	ns := metav1.ObjectMeta{Annotations: map[string]string{annotationInjectAuto: "my-inst"}}
+	assert.Equal(t, "my-inst", AutoInstrumentationReference(ns, metav1.ObjectMeta{}))
+
+	pod := metav1.ObjectMeta{Annotations: map[string]string{annotationInjectAuto: "false"}}
+	assert.Equal(t, "", AutoInstrumentationReference(ns, pod))
+}
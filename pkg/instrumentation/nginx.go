@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/go-logr/logr"
@@ -62,7 +63,7 @@ const (
 	6) Inject mounting of volumes / files into appropriate directories in the application container
 */
 
-func injectNginxSDK(_ logr.Logger, nginxSpec v1alpha1.Nginx, pod corev1.Pod, useLabelsForResourceAttributes bool, index int, otlpEndpoint string, resourceMap map[string]string) corev1.Pod {
+func injectNginxSDK(_ logr.Logger, nginxSpec v1alpha1.Nginx, pod corev1.Pod, useLabelsForResourceAttributes bool, index int, exporter v1alpha1.Exporter, resourceMap map[string]string) corev1.Pod {
 
 	// caller checks if there is at least one container
 	container := &pod.Spec.Containers[index]
@@ -214,16 +215,17 @@ mv ${NGINX_AGENT_CONF_DIR_FULL}/opentelemetry_agent.conf  ${NGINX_AGENT_CONF_DIR
 			Image:   nginxSpec.Image,
 			Command: []string{"/bin/sh", "-c"},
 			Args:    []string{nginxAgentI13nCommand},
-			Env: []corev1.EnvVar{
-				{
+			// The header values read from secrets are expanded in the agent configuration by Kubernetes.
+			Env: append(exporterHeaderSecretEnvVars(exporter),
+				corev1.EnvVar{
 					Name:  nginxAttributesEnvVar,
-					Value: getNginxOtelConfig(pod, useLabelsForResourceAttributes, nginxSpec, index, otlpEndpoint, resourceMap),
+					Value: getNginxOtelConfig(pod, useLabelsForResourceAttributes, nginxSpec, index, exporter, resourceMap),
 				},
-				{
+				corev1.EnvVar{
 					Name:  "OTEL_NGINX_I13N_SCRIPT",
 					Value: nginxAgentI13nScript,
 				},
-				{
+				corev1.EnvVar{
 					Name: nginxServiceInstanceIdEnvVar,
 					ValueFrom: &corev1.EnvVarSource{
 						FieldRef: &corev1.ObjectFieldSelector{
@@ -231,7 +233,7 @@ mv ${NGINX_AGENT_CONF_DIR_FULL}/opentelemetry_agent.conf  ${NGINX_AGENT_CONF_DIR
 						},
 					},
 				},
-			},
+			),
 			Resources: nginxSpec.Resources,
 			VolumeMounts: []corev1.VolumeMount{
 				{
@@ -277,8 +279,12 @@ func isNginxInitContainerMissing(pod corev1.Pod, containerName string) bool {
 
 // Calculate Nginx agent configuration file based on attributes provided by the injection rules
 // and by the pod values.
-func getNginxOtelConfig(pod corev1.Pod, useLabelsForResourceAttributes bool, nginxSpec v1alpha1.Nginx, index int, otelEndpoint string, resourceMap map[string]string) string {
+func getNginxOtelConfig(pod corev1.Pod, useLabelsForResourceAttributes bool, nginxSpec v1alpha1.Nginx, index int, exporter v1alpha1.Exporter, resourceMap map[string]string) string {
 
+	otelEndpoint := exporter.Endpoint
+	if exporter.Traces != nil && exporter.Traces.Endpoint != "" {
+		otelEndpoint = exporter.Traces.Endpoint
+	}
 	if otelEndpoint == "" {
 		otelEndpoint = "http://localhost:4317/"
 	}
@@ -303,6 +309,16 @@ func getNginxOtelConfig(pod corev1.Pod, useLabelsForResourceAttributes bool, ngi
 		"NginxModuleResolveBackends":      "ON",
 		"NginxModuleTraceAsError":         "ON",
 	}
+	// The module only exports traces, always with OTLP over gRPC and without compression.
+	if signalExporterName(exporter.Traces) == "none" {
+		attrMap["NginxModuleEnabled"] = "OFF"
+	}
+	if len(exporter.Headers) > 0 {
+		attrMap["NginxModuleOtelExporterOtlpHeaders"] = exporterHeaders(exporter)
+	}
+	if exporter.Timeout != nil {
+		attrMap["NginxModuleOtelExportTimeoutMillis"] = strconv.FormatInt(exporter.Timeout.Milliseconds(), 10)
+	}
 	for _, attr := range nginxSpec.Attrs {
 		attrMap[attr.Name] = attr.Value
 	}
@@ -16,6 +16,7 @@ package instrumentation
 
 import (
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
@@ -477,7 +478,7 @@ func TestInjectNginxSDK(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			pod := injectNginxSDK(logr.Discard(), test.Nginx, test.pod, false, 0, "http://otlp-endpoint:4317", resourceMap)
+			pod := injectNginxSDK(logr.Discard(), test.Nginx, test.pod, false, 0, v1alpha1.Exporter{Endpoint: "http://otlp-endpoint:4317"}, resourceMap)
 			assert.Equal(t, test.expected, pod)
 		})
 	}
@@ -600,7 +601,7 @@ func TestInjectNginxUnknownNamespace(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			pod := injectNginxSDK(logr.Discard(), test.Nginx, test.pod, false, 0, "http://otlp-endpoint:4317", resourceMap)
+			pod := injectNginxSDK(logr.Discard(), test.Nginx, test.pod, false, 0, v1alpha1.Exporter{Endpoint: "http://otlp-endpoint:4317"}, resourceMap)
 			assert.Equal(t, test.expected, pod)
 		})
 	}
@@ -657,3 +658,42 @@ func TestNginxInitContainerMissing(t *testing.T) {
 		})
 	}
 }
+
+func TestInjectNginxSDKExporter(t *testing.T) {
+	exporter := v1alpha1.Exporter{
+		Endpoint: "http://otlp-endpoint:4317",
+		Timeout:  &v1.Duration{Duration: 2 * time.Second},
+		Headers: []v1alpha1.ExporterHeader{
+			{Name: "x-tenant", Value: "team-a"},
+			{
+				Name: "api-key",
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "otlp-auth"},
+					Key:                  "api-key",
+				},
+			},
+		},
+	}
+	pod := corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{},
+			},
+		},
+	}
+
+	pod = injectNginxSDK(logr.Discard(), v1alpha1.Nginx{Image: "foo/bar:1"}, pod, false, 0, exporter, map[string]string{})
+
+	var initContainer corev1.Container
+	for _, container := range pod.Spec.InitContainers {
+		if container.Name == nginxAgentInitContainerName {
+			initContainer = container
+		}
+	}
+	// the secret has to be read before the configuration referencing it is expanded
+	assert.Equal(t, "OTEL_EXPORTER_OTLP_HEADER_API_KEY", initContainer.Env[0].Name)
+	assert.Equal(t, "otlp-auth", initContainer.Env[0].ValueFrom.SecretKeyRef.Name)
+	assert.Equal(t, nginxAttributesEnvVar, initContainer.Env[1].Name)
+	assert.Contains(t, initContainer.Env[1].Value, "NginxModuleOtelExporterOtlpHeaders x-tenant=team-a,api-key=$(OTEL_EXPORTER_OTLP_HEADER_API_KEY);\n")
+	assert.Contains(t, initContainer.Env[1].Value, "NginxModuleOtelExportTimeoutMillis 2000;\n")
+}
@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instrumentation
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
+)
+
+const (
+	envPHPIniScanDir         = "PHP_INI_SCAN_DIR"
+	envOtelPHPAutoloadEnable = "OTEL_PHP_AUTOLOAD_ENABLED"
+	phpInitContainerName     = initContainerName + "-php"
+	phpVolumeName            = volumeName + "-php"
+	phpInstrMountPath        = "/otel-auto-instrumentation-php"
+	phpIniScanDir            = phpInstrMountPath + "/conf.d"
+)
+
+func injectPHPSDK(phpSpec v1alpha1.PHP, pod corev1.Pod, index int) (corev1.Pod, error) {
+	volume := instrVolume(phpSpec.VolumeClaimTemplate, phpVolumeName, phpSpec.VolumeSizeLimit)
+
+	// caller checks if there is at least one container.
+	container := &pod.Spec.Containers[index]
+
+	err := validateContainerEnv(container.Env, envPHPIniScanDir)
+	if err != nil {
+		return pod, err
+	}
+
+	// inject PHP instrumentation spec env vars.
+	for _, env := range phpSpec.Env {
+		idx := getIndexOfEnv(container.Env, env.Name)
+		if idx == -1 {
+			container.Env = append(container.Env, env)
+		}
+	}
+
+	// A leading separator keeps the default scan directory of the PHP build, so that the ini files of the image are
+	// still loaded before the one enabling the extension.
+	idx := getIndexOfEnv(container.Env, envPHPIniScanDir)
+	if idx == -1 {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  envPHPIniScanDir,
+			Value: ":" + phpIniScanDir,
+		})
+	} else if idx > -1 {
+		container.Env[idx].Value = container.Env[idx].Value + ":" + phpIniScanDir
+	}
+
+	// Set OTEL_PHP_AUTOLOAD_ENABLED to true if not set by user, as the SDK is otherwise only configured manually.
+	idx = getIndexOfEnv(container.Env, envOtelPHPAutoloadEnable)
+	if idx == -1 {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  envOtelPHPAutoloadEnable,
+			Value: "true",
+		})
+	}
+
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      volume.Name,
+		MountPath: phpInstrMountPath,
+	})
+
+	// We just inject Volumes and init containers for the first processed container.
+	if isInitContainerMissing(pod, phpInitContainerName) {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, volume)
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, corev1.Container{
+			Name:      phpInitContainerName,
+			Image:     phpSpec.Image,
+			Command:   []string{"cp", "-r", "/autoinstrumentation/.", phpInstrMountPath},
+			Resources: phpSpec.Resources,
+			VolumeMounts: []corev1.VolumeMount{{
+				Name:      volume.Name,
+				MountPath: phpInstrMountPath,
+			}},
+		})
+	}
+	return pod, nil
+}
@@ -30,7 +30,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
 	"github.com/open-telemetry/opentelemetry-operator/internal/config"
-	"github.com/open-telemetry/opentelemetry-operator/internal/webhook/podmutation"
+	"github.com/open-telemetry/opentelemetry-operator/internal/explanation"
 )
 
 var (
@@ -38,6 +38,9 @@ var (
 	errNoInstancesAvailable      = errors.New("no OpenTelemetry Instrumentation instances available")
 )
 
+// mutatorName identifies the instrumentation mutator in the explanations of the pod mutation webhook.
+const mutatorName = "instrumentation"
+
 type instPodMutator struct {
 	Client      client.Client
 	sdkInjector *sdkInjector
@@ -60,6 +63,8 @@ type languageInstrumentations struct {
 	ApacheHttpd instrumentationWithContainers
 	Nginx       instrumentationWithContainers
 	Go          instrumentationWithContainers
+	Ruby        instrumentationWithContainers
+	PHP         instrumentationWithContainers
 	Sdk         instrumentationWithContainers
 }
 
@@ -127,6 +132,22 @@ func (langInsts languageInstrumentations) areInstrumentedContainersCorrect() (bo
 			instrumentationWithNoContainers = true
 		}
 	}
+	if langInsts.Ruby.Instrumentation != nil {
+		instrWithContainers += isInstrWithContainers(langInsts.Ruby)
+		instrWithoutContainers += isInstrWithoutContainers(langInsts.Ruby)
+		allContainers = append(allContainers, langInsts.Ruby.Containers...)
+		if len(langInsts.Ruby.Containers) == 0 {
+			instrumentationWithNoContainers = true
+		}
+	}
+	if langInsts.PHP.Instrumentation != nil {
+		instrWithContainers += isInstrWithContainers(langInsts.PHP)
+		instrWithoutContainers += isInstrWithoutContainers(langInsts.PHP)
+		allContainers = append(allContainers, langInsts.PHP.Containers...)
+		if len(langInsts.PHP.Containers) == 0 {
+			instrumentationWithNoContainers = true
+		}
+	}
 	if langInsts.Sdk.Instrumentation != nil {
 		instrWithContainers += isInstrWithContainers(langInsts.Sdk)
 		instrWithoutContainers += isInstrWithoutContainers(langInsts.Sdk)
@@ -165,6 +186,33 @@ func (langInsts languageInstrumentations) areInstrumentedContainersCorrect() (bo
 	return true, nil
 }
 
+// isEmpty reports whether no instrumentation is configured for any language.
+func (langInsts languageInstrumentations) isEmpty() bool {
+	return langInsts.Java.Instrumentation == nil && langInsts.NodeJS.Instrumentation == nil && langInsts.Python.Instrumentation == nil &&
+		langInsts.DotNet.Instrumentation == nil && langInsts.Go.Instrumentation == nil && langInsts.ApacheHttpd.Instrumentation == nil &&
+		langInsts.Nginx.Instrumentation == nil && langInsts.Ruby.Instrumentation == nil && langInsts.PHP.Instrumentation == nil &&
+		langInsts.Sdk.Instrumentation == nil
+}
+
+// forLanguage returns the instrumentation of one of the languages supported by the language detection.
+func (langInsts *languageInstrumentations) forLanguage(language v1alpha1.Language) *instrumentationWithContainers {
+	switch language {
+	case v1alpha1.LanguageJava:
+		return &langInsts.Java
+	case v1alpha1.LanguageNodeJS:
+		return &langInsts.NodeJS
+	case v1alpha1.LanguagePython:
+		return &langInsts.Python
+	case v1alpha1.LanguageDotNet:
+		return &langInsts.DotNet
+	case v1alpha1.LanguageRuby:
+		return &langInsts.Ruby
+	case v1alpha1.LanguagePHP:
+		return &langInsts.PHP
+	}
+	return nil
+}
+
 // Set containers for configured instrumentation.
 func (langInsts *languageInstrumentations) setCommonInstrumentedContainers(ns corev1.Namespace, pod corev1.Pod) error {
 	containersAnnotation := annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationInjectContainerName)
@@ -200,6 +248,12 @@ func (langInsts *languageInstrumentations) setCommonInstrumentedContainers(ns co
 	if langInsts.Go.Instrumentation != nil {
 		langInsts.Go.Containers = containers
 	}
+	if langInsts.Ruby.Instrumentation != nil {
+		langInsts.Ruby.Containers = containers
+	}
+	if langInsts.PHP.Instrumentation != nil {
+		langInsts.PHP.Containers = containers
+	}
 	if langInsts.Sdk.Instrumentation != nil {
 		langInsts.Sdk.Containers = containers
 	}
@@ -239,6 +293,14 @@ func (langInsts *languageInstrumentations) setLanguageSpecificContainers(ns meta
 			iwc:        &langInsts.Nginx,
 			annotation: annotationInjectNginxContainersName,
 		},
+		{
+			iwc:        &langInsts.Ruby,
+			annotation: annotationInjectRubyContainersName,
+		},
+		{
+			iwc:        &langInsts.PHP,
+			annotation: annotationInjectPHPContainersName,
+		},
 		{
 			iwc:        &langInsts.Sdk,
 			annotation: annotationInjectSdkContainersName,
@@ -254,8 +316,6 @@ func (langInsts *languageInstrumentations) setLanguageSpecificContainers(ns meta
 	return nil
 }
 
-var _ podmutation.PodMutator = (*instPodMutator)(nil)
-
 func NewMutator(logger logr.Logger, client client.Client, recorder record.EventRecorder, cfg config.Config) *instPodMutator {
 	return &instPodMutator{
 		Logger: logger,
@@ -270,7 +330,7 @@ func NewMutator(logger logr.Logger, client client.Client, recorder record.EventR
 }
 
 func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod corev1.Pod) (corev1.Pod, error) {
-	logger := pm.Logger.WithValues("namespace", pod.Namespace)
+	logger := explanation.Logger(ctx, pm.Logger, mutatorName).WithValues("namespace", pod.Namespace)
 	if pod.Name != "" {
 		logger = logger.WithValues("name", pod.Name)
 	} else if pod.GenerateName != "" {
@@ -288,9 +348,16 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 
 	insts := languageInstrumentations{}
 
+	selected, err := pm.selectInstrumentationInstancesFromSelectors(ctx, ns, pod)
+	if err != nil {
+		// we still allow the pod to be created, but we log a message to the operator's logs
+		logger.Error(err, "failed to match the OpenTelemetry Instrumentation selectors against this pod")
+		return pod, err
+	}
+
 	// We bail out if any annotation fails to process.
 
-	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectJava); err != nil {
+	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectJava, selected[v1alpha1.LanguageJava]); err != nil {
 		// we still allow the pod to be created, but we log a message to the operator's logs
 		logger.Error(err, "failed to select an OpenTelemetry Instrumentation instance for this pod")
 		return pod, err
@@ -299,10 +366,10 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 		insts.Java.Instrumentation = inst
 	} else {
 		logger.Error(nil, "support for Java auto instrumentation is not enabled")
-		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for Java auto instrumentation is not enabled")
+		pm.event(ctx, pod, "Warning", "InstrumentationRequestRejected", "support for Java auto instrumentation is not enabled")
 	}
 
-	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectNodeJS); err != nil {
+	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectNodeJS, selected[v1alpha1.LanguageNodeJS]); err != nil {
 		// we still allow the pod to be created, but we log a message to the operator's logs
 		logger.Error(err, "failed to select an OpenTelemetry Instrumentation instance for this pod")
 		return pod, err
@@ -311,10 +378,10 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 		insts.NodeJS.Instrumentation = inst
 	} else {
 		logger.Error(nil, "support for NodeJS auto instrumentation is not enabled")
-		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for NodeJS auto instrumentation is not enabled")
+		pm.event(ctx, pod, "Warning", "InstrumentationRequestRejected", "support for NodeJS auto instrumentation is not enabled")
 	}
 
-	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectPython); err != nil {
+	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectPython, selected[v1alpha1.LanguagePython]); err != nil {
 		// we still allow the pod to be created, but we log a message to the operator's logs
 		logger.Error(err, "failed to select an OpenTelemetry Instrumentation instance for this pod")
 		return pod, err
@@ -324,10 +391,10 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 		insts.Python.AdditionalAnnotations = map[string]string{annotationPythonPlatform: annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationPythonPlatform)}
 	} else {
 		logger.Error(nil, "support for Python auto instrumentation is not enabled")
-		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for Python auto instrumentation is not enabled")
+		pm.event(ctx, pod, "Warning", "InstrumentationRequestRejected", "support for Python auto instrumentation is not enabled")
 	}
 
-	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectDotNet); err != nil {
+	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectDotNet, selected[v1alpha1.LanguageDotNet]); err != nil {
 		// we still allow the pod to be created, but we log a message to the operator's logs
 		logger.Error(err, "failed to select an OpenTelemetry Instrumentation instance for this pod")
 		return pod, err
@@ -337,10 +404,10 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 		insts.DotNet.AdditionalAnnotations = map[string]string{annotationDotNetRuntime: annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationDotNetRuntime)}
 	} else {
 		logger.Error(nil, "support for .NET auto instrumentation is not enabled")
-		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for .NET auto instrumentation is not enabled")
+		pm.event(ctx, pod, "Warning", "InstrumentationRequestRejected", "support for .NET auto instrumentation is not enabled")
 	}
 
-	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectGo); err != nil {
+	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectGo, selected[v1alpha1.LanguageGo]); err != nil {
 		// we still allow the pod to be created, but we log a message to the operator's logs
 		logger.Error(err, "failed to select an OpenTelemetry Instrumentation instance for this pod")
 		return pod, err
@@ -349,10 +416,10 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 		insts.Go.Instrumentation = inst
 	} else {
 		logger.Error(err, "support for Go auto instrumentation is not enabled")
-		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for Go auto instrumentation is not enabled")
+		pm.event(ctx, pod, "Warning", "InstrumentationRequestRejected", "support for Go auto instrumentation is not enabled")
 	}
 
-	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectApacheHttpd); err != nil {
+	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectApacheHttpd, selected[v1alpha1.LanguageApacheHttpd]); err != nil {
 		// we still allow the pod to be created, but we log a message to the operator's logs
 		logger.Error(err, "failed to select an OpenTelemetry Instrumentation instance for this pod")
 		return pod, err
@@ -361,10 +428,10 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 		insts.ApacheHttpd.Instrumentation = inst
 	} else {
 		logger.Error(nil, "support for Apache HTTPD auto instrumentation is not enabled")
-		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for Apache HTTPD auto instrumentation is not enabled")
+		pm.event(ctx, pod, "Warning", "InstrumentationRequestRejected", "support for Apache HTTPD auto instrumentation is not enabled")
 	}
 
-	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectNginx); err != nil {
+	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectNginx, selected[v1alpha1.LanguageNginx]); err != nil {
 		// we still allow the pod to be created, but we log a message to the operator's logs
 		logger.Error(err, "failed to select an OpenTelemetry Instrumentation instance for this pod")
 		return pod, err
@@ -373,21 +440,48 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 		insts.Nginx.Instrumentation = inst
 	} else {
 		logger.Error(nil, "support for Nginx auto instrumentation is not enabled")
-		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for Nginx auto instrumentation is not enabled")
+		pm.event(ctx, pod, "Warning", "InstrumentationRequestRejected", "support for Nginx auto instrumentation is not enabled")
 	}
 
-	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectSdk); err != nil {
+	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectRuby, selected[v1alpha1.LanguageRuby]); err != nil {
+		// we still allow the pod to be created, but we log a message to the operator's logs
+		logger.Error(err, "failed to select an OpenTelemetry Instrumentation instance for this pod")
+		return pod, err
+	}
+	if pm.config.EnableRubyAutoInstrumentation() || inst == nil {
+		insts.Ruby.Instrumentation = inst
+	} else {
+		logger.Error(nil, "support for Ruby auto instrumentation is not enabled")
+		pm.event(ctx, pod, "Warning", "InstrumentationRequestRejected", "support for Ruby auto instrumentation is not enabled")
+	}
+
+	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectPHP, selected[v1alpha1.LanguagePHP]); err != nil {
+		// we still allow the pod to be created, but we log a message to the operator's logs
+		logger.Error(err, "failed to select an OpenTelemetry Instrumentation instance for this pod")
+		return pod, err
+	}
+	if pm.config.EnablePHPAutoInstrumentation() || inst == nil {
+		insts.PHP.Instrumentation = inst
+	} else {
+		logger.Error(nil, "support for PHP auto instrumentation is not enabled")
+		pm.event(ctx, pod, "Warning", "InstrumentationRequestRejected", "support for PHP auto instrumentation is not enabled")
+	}
+
+	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectSdk, selected[v1alpha1.LanguageSdk]); err != nil {
 		// we still allow the pod to be created, but we log a message to the operator's logs
 		logger.Error(err, "failed to select an OpenTelemetry Instrumentation instance for this pod")
 		return pod, err
 	}
 	insts.Sdk.Instrumentation = inst
 
-	if insts.Java.Instrumentation == nil && insts.NodeJS.Instrumentation == nil && insts.Python.Instrumentation == nil &&
-		insts.DotNet.Instrumentation == nil && insts.Go.Instrumentation == nil && insts.ApacheHttpd.Instrumentation == nil &&
-		insts.Nginx.Instrumentation == nil &&
-		insts.Sdk.Instrumentation == nil {
+	var autoInst *v1alpha1.Instrumentation
+	if autoInst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectAuto, nil); err != nil {
+		// we still allow the pod to be created, but we log a message to the operator's logs
+		logger.Error(err, "failed to select an OpenTelemetry Instrumentation instance for this pod")
+		return pod, err
+	}
 
+	if insts.isEmpty() && autoInst == nil {
 		logger.V(1).Info("annotation not present in deployment, skipping instrumentation injection")
 		return pod, nil
 	}
@@ -397,18 +491,31 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 		return pod, err
 	}
 
-	if err = pm.validateInstrumentations(ctx, insts, ns.Name); err != nil {
-		logger.Error(err, "failed to validate instrumentations")
-		return pod, err
-	}
-
 	// We retrieve the annotation for podname
 	if pm.config.EnableMultiInstrumentation() {
 		err = insts.setLanguageSpecificContainers(ns.ObjectMeta, pod.ObjectMeta)
 		if err != nil {
 			return pod, err
 		}
+	}
+
+	if autoInst != nil {
+		if err = pm.setDetectedLanguages(ctx, &insts, autoInst, ns, pod); err != nil {
+			logger.Error(err, "failed to detect the languages of the containers")
+			return pod, err
+		}
+		if insts.isEmpty() {
+			logger.V(1).Info("no language detected, skipping instrumentation injection")
+			return pod, nil
+		}
+	}
+
+	if err = pm.validateInstrumentations(ctx, insts, ns.Name); err != nil {
+		logger.Error(err, "failed to validate instrumentations")
+		return pod, err
+	}
 
+	if pm.config.EnableMultiInstrumentation() {
 		// We check if provided annotations and instrumentations are valid
 		ok, msg := insts.areInstrumentedContainersCorrect()
 		if !ok {
@@ -422,13 +529,33 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 	modifiedPod := pod
 	modifiedPod = pm.sdkInjector.inject(ctx, insts, ns, modifiedPod, pm.config)
 
+	// track the version of the Instrumentations the pod was instrumented with, to restart it when they change
+	if modifiedPod, err = stampSpecHashes(insts, modifiedPod); err != nil {
+		logger.Error(err, "failed to compute the spec hash of the instrumentations")
+		return pod, err
+	}
+
 	return modifiedPod, nil
 }
 
-func (pm *instPodMutator) getInstrumentationInstance(ctx context.Context, ns corev1.Namespace, pod corev1.Pod, instAnnotation string) (*v1alpha1.Instrumentation, error) {
+// getInstrumentationInstance returns the Instrumentation to inject for the language of instAnnotation. An inject
+// annotation on the pod or its namespace always takes precedence, otherwise the first of the Instrumentations whose
+// selector matches the pod is used.
+func (pm *instPodMutator) getInstrumentationInstance(ctx context.Context, ns corev1.Namespace, pod corev1.Pod, instAnnotation string, selected []*v1alpha1.Instrumentation) (*v1alpha1.Instrumentation, error) {
 	instValue := annotationValue(ns.ObjectMeta, pod.ObjectMeta, instAnnotation)
 
-	if len(instValue) == 0 || strings.EqualFold(instValue, "false") {
+	if len(instValue) == 0 {
+		return pm.selectInstrumentationInstanceFromSelectors(ctx, pod, instAnnotation, selected), nil
+	}
+	logger := explanation.Logger(ctx, pm.Logger, mutatorName)
+	logger.V(1).Info("resolved the inject annotation of the pod and its namespace", "annotation", instAnnotation, "value", instValue)
+
+	if len(selected) > 0 {
+		pm.eventf(ctx, pod, "Normal", "InstrumentationSelectorOverridden",
+			"annotation %s=%s takes precedence over the selector of Instrumentation %s/%s", instAnnotation, instValue, selected[0].Namespace, selected[0].Name)
+	}
+
+	if strings.EqualFold(instValue, "false") {
 		return nil, nil
 	}
 
@@ -449,9 +576,141 @@ func (pm *instPodMutator) getInstrumentationInstance(ctx context.Context, ns cor
 		return nil, err
 	}
 
+	logger.V(1).Info("selected the Instrumentation referenced by the inject annotation", "annotation", instAnnotation, "otelinst-namespace", otelInst.Namespace, "otelinst-name", otelInst.Name)
 	return otelInst, nil
 }
 
+// setDetectedLanguages detects the language of the containers of a pod annotated with inject-auto and configures
+// inst for them. Containers already instrumented through a language specific annotation, and languages requested
+// through such an annotation, are left untouched. The outcome is recorded as an event on the pod.
+func (pm *instPodMutator) setDetectedLanguages(ctx context.Context, insts *languageInstrumentations, inst *v1alpha1.Instrumentation, ns corev1.Namespace, pod corev1.Pod) error {
+	rules, err := compileLanguageDetectionRules(inst.Spec.LanguageDetection)
+	if err != nil {
+		return err
+	}
+
+	claimed := map[string]bool{}
+	for _, iwc := range []instrumentationWithContainers{insts.Java, insts.NodeJS, insts.Python, insts.DotNet, insts.Go, insts.ApacheHttpd, insts.Nginx, insts.Ruby, insts.PHP, insts.Sdk} {
+		if iwc.Instrumentation == nil {
+			continue
+		}
+		if len(iwc.Containers) == 0 && len(pod.Spec.Containers) > 0 {
+			claimed[pod.Spec.Containers[0].Name] = true
+		}
+		for _, container := range iwc.Containers {
+			claimed[container] = true
+		}
+	}
+
+	detected := map[v1alpha1.Language][]string{}
+	var decisions []string
+	for _, container := range languageDetectionContainers(ns.ObjectMeta, pod) {
+		if claimed[container.Name] {
+			decisions = append(decisions, fmt.Sprintf("container %s: instrumented through its language annotation", container.Name))
+			continue
+		}
+		rule, ok := detectContainerLanguage(rules, container)
+		switch {
+		case !ok:
+			decisions = append(decisions, fmt.Sprintf("container %s: no language detected", container.Name))
+		case insts.forLanguage(rule.language).Instrumentation != nil:
+			decisions = append(decisions, fmt.Sprintf("container %s: %s detected (%s), but %s is requested through its language annotation", container.Name, rule.language, rule, rule.language))
+		case !pm.isLanguageEnabled(rule.language):
+			decisions = append(decisions, fmt.Sprintf("container %s: %s detected (%s), but support for %s auto instrumentation is not enabled", container.Name, rule.language, rule, rule.language))
+		default:
+			detected[rule.language] = append(detected[rule.language], container.Name)
+			decisions = append(decisions, fmt.Sprintf("container %s: %s detected (%s)", container.Name, rule.language, rule))
+		}
+	}
+
+	for language, containers := range detected {
+		iwc := insts.forLanguage(language)
+		iwc.Instrumentation = inst
+		iwc.Containers = containers
+	}
+	if len(detected[v1alpha1.LanguagePython]) > 0 {
+		insts.Python.AdditionalAnnotations = map[string]string{annotationPythonPlatform: annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationPythonPlatform)}
+	}
+	if len(detected[v1alpha1.LanguageDotNet]) > 0 {
+		insts.DotNet.AdditionalAnnotations = map[string]string{annotationDotNetRuntime: annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationDotNetRuntime)}
+	}
+
+	pm.event(ctx, pod, "Normal", "InstrumentationLanguageDetected", strings.Join(decisions, "; "))
+	return nil
+}
+
+// event publishes an event on the pod, unless the pod is only being explained, in which case the event is recorded
+// in the explanation instead.
+func (pm *instPodMutator) event(ctx context.Context, pod corev1.Pod, eventtype, reason, message string) {
+	if steps := explanation.FromContext(ctx); steps != nil {
+		steps.Record(mutatorName, nil, message, "event", reason)
+		return
+	}
+	pm.Recorder.Event(pod.DeepCopy(), eventtype, reason, message)
+}
+
+func (pm *instPodMutator) eventf(ctx context.Context, pod corev1.Pod, eventtype, reason, messageFmt string, args ...interface{}) {
+	pm.event(ctx, pod, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (pm *instPodMutator) isLanguageEnabled(language v1alpha1.Language) bool {
+	switch language {
+	case v1alpha1.LanguageJava:
+		return pm.config.EnableJavaAutoInstrumentation()
+	case v1alpha1.LanguageNodeJS:
+		return pm.config.EnableNodeJSAutoInstrumentation()
+	case v1alpha1.LanguagePython:
+		return pm.config.EnablePythonAutoInstrumentation()
+	case v1alpha1.LanguageDotNet:
+		return pm.config.EnableDotNetAutoInstrumentation()
+	case v1alpha1.LanguageRuby:
+		return pm.config.EnableRubyAutoInstrumentation()
+	case v1alpha1.LanguagePHP:
+		return pm.config.EnablePHPAutoInstrumentation()
+	}
+	return false
+}
+
+// selectInstrumentationInstancesFromSelectors returns, per language, the Instrumentations whose selector matches the
+// pod, ordered by precedence. Only the Instrumentations of the pod namespace and the ones with a namespace selector,
+// found through the InstrumentationNamespaceSelectorIndex, can match the pod.
+func (pm *instPodMutator) selectInstrumentationInstancesFromSelectors(ctx context.Context, ns corev1.Namespace, pod corev1.Pod) (map[v1alpha1.Language][]*v1alpha1.Instrumentation, error) {
+	var otelInsts v1alpha1.InstrumentationList
+	if err := pm.Client.List(ctx, &otelInsts, client.InNamespace(ns.Name)); err != nil {
+		return nil, err
+	}
+	var otherInsts v1alpha1.InstrumentationList
+	if err := pm.Client.List(ctx, &otherInsts, client.MatchingFields{InstrumentationNamespaceSelectorIndex: "true"}); err != nil {
+		return nil, err
+	}
+	for _, inst := range otherInsts.Items {
+		if inst.Namespace != ns.Name {
+			otelInsts.Items = append(otelInsts.Items, inst)
+		}
+	}
+	return SelectedInstrumentations(otelInsts.Items, ns.ObjectMeta, pod.ObjectMeta)
+}
+
+func (pm *instPodMutator) selectInstrumentationInstanceFromSelectors(ctx context.Context, pod corev1.Pod, instAnnotation string, selected []*v1alpha1.Instrumentation) *v1alpha1.Instrumentation {
+	if len(selected) == 0 {
+		return nil
+	}
+
+	inst := selected[0]
+	if len(selected) > 1 {
+		names := make([]string, 0, len(selected))
+		for _, candidate := range selected {
+			names = append(names, candidate.Namespace+"/"+candidate.Name)
+		}
+		explanation.Logger(ctx, pm.Logger, mutatorName).Info("multiple Instrumentation selectors match the pod", "annotation", instAnnotation, "instrumentations", names)
+		pm.eventf(ctx, pod, "Warning", "InstrumentationSelectorConflict",
+			"selectors of Instrumentations %s match the pod, using %s/%s", strings.Join(names, ", "), inst.Namespace, inst.Name)
+	}
+	pm.eventf(ctx, pod, "Normal", "InstrumentationSelected",
+		"Instrumentation %s/%s selected through its selector for %s", inst.Namespace, inst.Name, instAnnotation)
+	return inst.DeepCopy()
+}
+
 func (pm *instPodMutator) selectInstrumentationInstanceFromNamespace(ctx context.Context, ns corev1.Namespace) (*v1alpha1.Instrumentation, error) {
 	var otelInsts v1alpha1.InstrumentationList
 	if err := pm.Client.List(ctx, &otelInsts, client.InNamespace(ns.Name)); err != nil {
@@ -479,6 +738,8 @@ func (pm *instPodMutator) validateInstrumentations(ctx context.Context, inst lan
 		{inst.Go.Instrumentation},
 		{inst.ApacheHttpd.Instrumentation},
 		{inst.Nginx.Instrumentation},
+		{inst.Ruby.Instrumentation},
+		{inst.PHP.Instrumentation},
 		{inst.Sdk.Instrumentation},
 	}
 	var errs []error
@@ -500,15 +761,22 @@ func (pm *instPodMutator) validateInstrumentation(ctx context.Context, inst *v1a
 	// Check if secret and configmap exists
 	// If they don't exist pod cannot start
 	var errs []error
-	if inst.Spec.Exporter.TLS != nil {
-		if inst.Spec.Exporter.TLS.SecretName != "" {
-			nsn := types.NamespacedName{Name: inst.Spec.Exporter.TLS.SecretName, Namespace: podNamespace}
+	secrets, configMaps := map[string]bool{}, map[string]bool{}
+	var tlsConfigs []*v1alpha1.TLS
+	for _, exporter := range InstrumentationExporters(*inst) {
+		tlsConfigs = append(tlsConfigs, ExporterTLS(exporter)...)
+	}
+	for _, tls := range tlsConfigs {
+		if name := tls.SecretName; name != "" && !secrets[name] {
+			secrets[name] = true
+			nsn := types.NamespacedName{Name: name, Namespace: podNamespace}
 			if err := pm.Client.Get(ctx, nsn, &corev1.Secret{}); apierrors.IsNotFound(err) {
 				errs = append(errs, fmt.Errorf("secret %s with certificates does not exists: %w", nsn.String(), err))
 			}
 		}
-		if inst.Spec.Exporter.TLS.ConfigMapName != "" {
-			nsn := types.NamespacedName{Name: inst.Spec.Exporter.TLS.ConfigMapName, Namespace: podNamespace}
+		if name := tls.ConfigMapName; name != "" && !configMaps[name] {
+			configMaps[name] = true
+			nsn := types.NamespacedName{Name: name, Namespace: podNamespace}
 			if err := pm.Client.Get(ctx, nsn, &corev1.ConfigMap{}); apierrors.IsNotFound(err) {
 				errs = append(errs, fmt.Errorf("configmap %s with CA certificate does not exists: %w", nsn.String(), err))
 			}
@@ -25,9 +25,12 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
 	"github.com/open-telemetry/opentelemetry-operator/internal/config"
+	"github.com/open-telemetry/opentelemetry-operator/internal/explanation"
 )
 
 func TestMutatePod(t *testing.T) {
@@ -3374,6 +3377,542 @@ func TestMutatePod(t *testing.T) {
 			config: config.New(config.WithEnableDotNetInstrumentation(false)),
 		},
 
+		{
+			name: "ruby injection, true",
+			ns: corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "ruby",
+				},
+			},
+			inst: v1alpha1.Instrumentation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "example-inst",
+					Namespace: "ruby",
+				},
+				Spec: v1alpha1.InstrumentationSpec{
+					Ruby: v1alpha1.Ruby{
+						Image: "otel/ruby:1",
+						Env: []corev1.EnvVar{
+							{
+								Name:  "OTEL_RUBY_DEBUG",
+								Value: "true",
+							},
+						},
+					},
+					Env: []corev1.EnvVar{
+						{
+							Name:  "OTEL_TRACES_EXPORTER",
+							Value: "otlp",
+						},
+						{
+							Name:  "OTEL_EXPORTER_OTLP_ENDPOINT",
+							Value: "http://localhost:4317",
+						},
+						{
+							Name:  "OTEL_EXPORTER_OTLP_TIMEOUT",
+							Value: "20",
+						},
+						{
+							Name:  "OTEL_TRACES_SAMPLER",
+							Value: "parentbased_traceidratio",
+						},
+						{
+							Name:  "OTEL_TRACES_SAMPLER_ARG",
+							Value: "0.85",
+						},
+						{
+							Name:  "SPLUNK_TRACE_RESPONSE_HEADER_ENABLED",
+							Value: "true",
+						},
+					},
+					Exporter: v1alpha1.Exporter{
+						Endpoint: "http://collector:12345",
+					},
+				},
+			},
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						annotationInjectRuby: "true",
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "app",
+						},
+					},
+				},
+			},
+			expected: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						annotationInjectRuby: "true",
+					},
+				},
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{
+						{
+							Name: rubyVolumeName,
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{
+									SizeLimit: &defaultVolumeLimitSize,
+								},
+							},
+						},
+					},
+					InitContainers: []corev1.Container{
+						{
+							Name:    rubyInitContainerName,
+							Image:   "otel/ruby:1",
+							Command: []string{"cp", "-r", "/autoinstrumentation/.", rubyInstrMountPath},
+							VolumeMounts: []corev1.VolumeMount{{
+								Name:      rubyVolumeName,
+								MountPath: rubyInstrMountPath,
+							}},
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name: "app",
+							Env: []corev1.EnvVar{
+								{
+									Name: "OTEL_NODE_IP",
+									ValueFrom: &corev1.EnvVarSource{
+										FieldRef: &corev1.ObjectFieldSelector{
+											FieldPath: "status.hostIP",
+										},
+									},
+								},
+								{
+									Name: "OTEL_POD_IP",
+									ValueFrom: &corev1.EnvVarSource{
+										FieldRef: &corev1.ObjectFieldSelector{
+											FieldPath: "status.podIP",
+										},
+									},
+								},
+								{
+									Name:  "OTEL_RUBY_DEBUG",
+									Value: "true",
+								},
+								{
+									Name:  "RUBYOPT",
+									Value: rubyRequireArgument,
+								},
+								{
+									Name:  "OTEL_TRACES_EXPORTER",
+									Value: "otlp",
+								},
+								{
+									Name:  "OTEL_EXPORTER_OTLP_ENDPOINT",
+									Value: "http://localhost:4317",
+								},
+								{
+									Name:  "OTEL_EXPORTER_OTLP_TIMEOUT",
+									Value: "20",
+								},
+								{
+									Name:  "OTEL_TRACES_SAMPLER",
+									Value: "parentbased_traceidratio",
+								},
+								{
+									Name:  "OTEL_TRACES_SAMPLER_ARG",
+									Value: "0.85",
+								},
+								{
+									Name:  "SPLUNK_TRACE_RESPONSE_HEADER_ENABLED",
+									Value: "true",
+								},
+								{
+									Name:  "OTEL_SERVICE_NAME",
+									Value: "app",
+								},
+								{
+									Name: "OTEL_RESOURCE_ATTRIBUTES_POD_NAME",
+									ValueFrom: &corev1.EnvVarSource{
+										FieldRef: &corev1.ObjectFieldSelector{
+											FieldPath: "metadata.name",
+										},
+									},
+								},
+								{
+									Name: "OTEL_RESOURCE_ATTRIBUTES_NODE_NAME",
+									ValueFrom: &corev1.EnvVarSource{
+										FieldRef: &corev1.ObjectFieldSelector{
+											FieldPath: "spec.nodeName",
+										},
+									},
+								},
+								{
+									Name:  "OTEL_RESOURCE_ATTRIBUTES",
+									Value: "k8s.container.name=app,k8s.namespace.name=ruby,k8s.node.name=$(OTEL_RESOURCE_ATTRIBUTES_NODE_NAME),k8s.pod.name=$(OTEL_RESOURCE_ATTRIBUTES_POD_NAME),service.instance.id=ruby.$(OTEL_RESOURCE_ATTRIBUTES_POD_NAME).app",
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      rubyVolumeName,
+									MountPath: rubyInstrMountPath,
+								},
+							},
+						},
+					},
+				},
+			},
+			config: config.New(config.WithEnableRubyInstrumentation(true)),
+		},
+		{
+			name: "ruby injection feature gate disabled",
+			ns: corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "ruby-disabled",
+				},
+			},
+			inst: v1alpha1.Instrumentation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "example-inst",
+					Namespace: "ruby-disabled",
+				},
+				Spec: v1alpha1.InstrumentationSpec{
+					Ruby: v1alpha1.Ruby{
+						Image: "otel/ruby:1",
+						Env: []corev1.EnvVar{
+							{
+								Name:  "OTEL_RUBY_DEBUG",
+								Value: "true",
+							},
+						},
+					},
+					Env: []corev1.EnvVar{
+						{
+							Name:  "OTEL_TRACES_EXPORTER",
+							Value: "otlp",
+						},
+						{
+							Name:  "OTEL_EXPORTER_OTLP_ENDPOINT",
+							Value: "http://localhost:4317",
+						},
+						{
+							Name:  "OTEL_EXPORTER_OTLP_TIMEOUT",
+							Value: "20",
+						},
+						{
+							Name:  "OTEL_TRACES_SAMPLER",
+							Value: "parentbased_traceidratio",
+						},
+						{
+							Name:  "OTEL_TRACES_SAMPLER_ARG",
+							Value: "0.85",
+						},
+						{
+							Name:  "SPLUNK_TRACE_RESPONSE_HEADER_ENABLED",
+							Value: "true",
+						},
+					},
+					Exporter: v1alpha1.Exporter{
+						Endpoint: "http://collector:12345",
+					},
+				},
+			},
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						annotationInjectRuby: "true",
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "app",
+						},
+					},
+				},
+			},
+			expected: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						annotationInjectRuby: "true",
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "app",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "php injection, true",
+			ns: corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "php",
+				},
+			},
+			inst: v1alpha1.Instrumentation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "example-inst",
+					Namespace: "php",
+				},
+				Spec: v1alpha1.InstrumentationSpec{
+					PHP: v1alpha1.PHP{
+						Image: "otel/php:1",
+						Env: []corev1.EnvVar{
+							{
+								Name:  "OTEL_PHP_DEBUG",
+								Value: "true",
+							},
+						},
+					},
+					Env: []corev1.EnvVar{
+						{
+							Name:  "OTEL_TRACES_EXPORTER",
+							Value: "otlp",
+						},
+						{
+							Name:  "OTEL_EXPORTER_OTLP_ENDPOINT",
+							Value: "http://localhost:4317",
+						},
+						{
+							Name:  "OTEL_EXPORTER_OTLP_TIMEOUT",
+							Value: "20",
+						},
+						{
+							Name:  "OTEL_TRACES_SAMPLER",
+							Value: "parentbased_traceidratio",
+						},
+						{
+							Name:  "OTEL_TRACES_SAMPLER_ARG",
+							Value: "0.85",
+						},
+						{
+							Name:  "SPLUNK_TRACE_RESPONSE_HEADER_ENABLED",
+							Value: "true",
+						},
+					},
+					Exporter: v1alpha1.Exporter{
+						Endpoint: "http://collector:12345",
+					},
+				},
+			},
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						annotationInjectPHP: "true",
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "app",
+						},
+					},
+				},
+			},
+			expected: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						annotationInjectPHP: "true",
+					},
+				},
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{
+						{
+							Name: phpVolumeName,
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{
+									SizeLimit: &defaultVolumeLimitSize,
+								},
+							},
+						},
+					},
+					InitContainers: []corev1.Container{
+						{
+							Name:    phpInitContainerName,
+							Image:   "otel/php:1",
+							Command: []string{"cp", "-r", "/autoinstrumentation/.", phpInstrMountPath},
+							VolumeMounts: []corev1.VolumeMount{{
+								Name:      phpVolumeName,
+								MountPath: phpInstrMountPath,
+							}},
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name: "app",
+							Env: []corev1.EnvVar{
+								{
+									Name: "OTEL_NODE_IP",
+									ValueFrom: &corev1.EnvVarSource{
+										FieldRef: &corev1.ObjectFieldSelector{
+											FieldPath: "status.hostIP",
+										},
+									},
+								},
+								{
+									Name: "OTEL_POD_IP",
+									ValueFrom: &corev1.EnvVarSource{
+										FieldRef: &corev1.ObjectFieldSelector{
+											FieldPath: "status.podIP",
+										},
+									},
+								},
+								{
+									Name:  "OTEL_PHP_DEBUG",
+									Value: "true",
+								},
+								{
+									Name:  "PHP_INI_SCAN_DIR",
+									Value: ":" + phpIniScanDir,
+								},
+								{
+									Name:  "OTEL_PHP_AUTOLOAD_ENABLED",
+									Value: "true",
+								},
+								{
+									Name:  "OTEL_TRACES_EXPORTER",
+									Value: "otlp",
+								},
+								{
+									Name:  "OTEL_EXPORTER_OTLP_ENDPOINT",
+									Value: "http://localhost:4317",
+								},
+								{
+									Name:  "OTEL_EXPORTER_OTLP_TIMEOUT",
+									Value: "20",
+								},
+								{
+									Name:  "OTEL_TRACES_SAMPLER",
+									Value: "parentbased_traceidratio",
+								},
+								{
+									Name:  "OTEL_TRACES_SAMPLER_ARG",
+									Value: "0.85",
+								},
+								{
+									Name:  "SPLUNK_TRACE_RESPONSE_HEADER_ENABLED",
+									Value: "true",
+								},
+								{
+									Name:  "OTEL_SERVICE_NAME",
+									Value: "app",
+								},
+								{
+									Name: "OTEL_RESOURCE_ATTRIBUTES_POD_NAME",
+									ValueFrom: &corev1.EnvVarSource{
+										FieldRef: &corev1.ObjectFieldSelector{
+											FieldPath: "metadata.name",
+										},
+									},
+								},
+								{
+									Name: "OTEL_RESOURCE_ATTRIBUTES_NODE_NAME",
+									ValueFrom: &corev1.EnvVarSource{
+										FieldRef: &corev1.ObjectFieldSelector{
+											FieldPath: "spec.nodeName",
+										},
+									},
+								},
+								{
+									Name:  "OTEL_RESOURCE_ATTRIBUTES",
+									Value: "k8s.container.name=app,k8s.namespace.name=php,k8s.node.name=$(OTEL_RESOURCE_ATTRIBUTES_NODE_NAME),k8s.pod.name=$(OTEL_RESOURCE_ATTRIBUTES_POD_NAME),service.instance.id=php.$(OTEL_RESOURCE_ATTRIBUTES_POD_NAME).app",
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      phpVolumeName,
+									MountPath: phpInstrMountPath,
+								},
+							},
+						},
+					},
+				},
+			},
+			config: config.New(config.WithEnablePHPInstrumentation(true)),
+		},
+		{
+			name: "php injection feature gate disabled",
+			ns: corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "php-disabled",
+				},
+			},
+			inst: v1alpha1.Instrumentation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "example-inst",
+					Namespace: "php-disabled",
+				},
+				Spec: v1alpha1.InstrumentationSpec{
+					PHP: v1alpha1.PHP{
+						Image: "otel/php:1",
+						Env: []corev1.EnvVar{
+							{
+								Name:  "OTEL_PHP_DEBUG",
+								Value: "true",
+							},
+						},
+					},
+					Env: []corev1.EnvVar{
+						{
+							Name:  "OTEL_TRACES_EXPORTER",
+							Value: "otlp",
+						},
+						{
+							Name:  "OTEL_EXPORTER_OTLP_ENDPOINT",
+							Value: "http://localhost:4317",
+						},
+						{
+							Name:  "OTEL_EXPORTER_OTLP_TIMEOUT",
+							Value: "20",
+						},
+						{
+							Name:  "OTEL_TRACES_SAMPLER",
+							Value: "parentbased_traceidratio",
+						},
+						{
+							Name:  "OTEL_TRACES_SAMPLER_ARG",
+							Value: "0.85",
+						},
+						{
+							Name:  "SPLUNK_TRACE_RESPONSE_HEADER_ENABLED",
+							Value: "true",
+						},
+					},
+					Exporter: v1alpha1.Exporter{
+						Endpoint: "http://collector:12345",
+					},
+				},
+			},
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						annotationInjectPHP: "true",
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "app",
+						},
+					},
+				},
+			},
+			expected: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						annotationInjectPHP: "true",
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "app",
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "missing annotation",
 			ns: corev1.Namespace{
@@ -5096,3 +5635,280 @@ func TestInstrumentationLanguageContainersSet(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateInstrumentationContainerOverrides(t *testing.T) {
+	ns := "validate-overrides"
+	inst := &v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-inst", Namespace: ns},
+		Spec: v1alpha1.InstrumentationSpec{
+			Exporter: v1alpha1.Exporter{
+				Endpoint: "https://collector:4317",
+				TLS:      &v1alpha1.TLS{SecretName: "my-certs"},
+			},
+			ContainerOverrides: []v1alpha1.ContainerOverride{
+				{
+					ContainerNames: []string{"proxy"},
+					Exporter: &v1alpha1.Exporter{
+						Endpoint: "https://gateway:4317",
+						TLS:      &v1alpha1.TLS{SecretName: "gateway-certs", ConfigMapName: "gateway-ca"},
+					},
+				},
+			},
+		},
+	}
+	cli := fake.NewClientBuilder().
+		WithScheme(testScheme).
+		WithObjects(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "my-certs", Namespace: ns}}).
+		Build()
+	mutator := NewMutator(logr.Discard(), cli, record.NewFakeRecorder(10), config.New())
+
+	err := mutator.validateInstrumentation(context.Background(), inst, ns)
+	require.Error(t, err)
+	assert.Equal(t, "secret validate-overrides/gateway-certs with certificates does not exists: secrets \"gateway-certs\" not found\n"+
+		"configmap validate-overrides/gateway-ca with CA certificate does not exists: configmaps \"gateway-ca\" not found", err.Error())
+}
+
+func TestValidateInstrumentationSignalExporters(t *testing.T) {
+	ns := "validate-signals"
+	inst := &v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-inst", Namespace: ns},
+		Spec: v1alpha1.InstrumentationSpec{
+			Exporter: v1alpha1.Exporter{
+				Endpoint: "http://collector:4318",
+				Traces:   &v1alpha1.SignalExporter{Endpoint: "https://traces:4317", TLS: &v1alpha1.TLS{SecretName: "traces-certs"}},
+				Logs:     &v1alpha1.SignalExporter{Enabled: ptr.To(false), TLS: &v1alpha1.TLS{SecretName: "logs-certs"}},
+			},
+			ContainerOverrides: []v1alpha1.ContainerOverride{
+				{
+					ContainerNames: []string{"proxy"},
+					Exporter: &v1alpha1.Exporter{
+						Endpoint: "http://gateway:4318",
+						Metrics:  &v1alpha1.SignalExporter{Endpoint: "https://metrics:4317", TLS: &v1alpha1.TLS{ConfigMapName: "metrics-ca"}},
+					},
+				},
+			},
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(testScheme).Build()
+	mutator := NewMutator(logr.Discard(), cli, record.NewFakeRecorder(10), config.New())
+
+	err := mutator.validateInstrumentation(context.Background(), inst, ns)
+	require.Error(t, err)
+	assert.Equal(t, "secret validate-signals/traces-certs with certificates does not exists: secrets \"traces-certs\" not found\n"+
+		"configmap validate-signals/metrics-ca with CA certificate does not exists: configmaps \"metrics-ca\" not found", err.Error())
+}
+
+func TestMutatePodSelector(t *testing.T) {
+	ctx := context.Background()
+	ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "selector-shop"}}
+	otherNs := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "selector-observability"}}
+	local := v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "local", Namespace: ns.Name},
+		Spec: v1alpha1.InstrumentationSpec{
+			Exporter: v1alpha1.Exporter{Endpoint: "http://local-collector:4317"},
+			Selector: &v1alpha1.InstrumentationSelector{
+				PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "cart"}},
+				Languages:   []v1alpha1.Language{v1alpha1.LanguageJava},
+			},
+		},
+	}
+	clusterWide := v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-wide", Namespace: otherNs.Name},
+		Spec: v1alpha1.InstrumentationSpec{
+			Exporter: v1alpha1.Exporter{Endpoint: "http://central-collector:4317"},
+			Selector: &v1alpha1.InstrumentationSelector{
+				PodSelector:       &metav1.LabelSelector{},
+				NamespaceSelector: &metav1.LabelSelector{},
+				Languages:         []v1alpha1.Language{v1alpha1.LanguageJava},
+			},
+		},
+	}
+	// the cross-namespace Instrumentations are listed through a field index of the cache
+	selectorClient := fake.NewClientBuilder().
+		WithScheme(testScheme).
+		WithObjects(&ns, &otherNs, &local, &clusterWide).
+		WithIndex(&v1alpha1.Instrumentation{}, InstrumentationNamespaceSelectorIndex, InstrumentationNamespaceSelectorIndexValues).
+		Build()
+
+	endpoint := func(pod corev1.Pod) string {
+		for _, env := range pod.Spec.Containers[0].Env {
+			if env.Name == "OTEL_EXPORTER_OTLP_ENDPOINT" {
+				return env.Value
+			}
+		}
+		return ""
+	}
+
+	t.Run("selected, local instrumentation wins", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		mutator := NewMutator(logr.Discard(), selectorClient, recorder, config.New())
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "cart", Labels: map[string]string{"app": "cart"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+		}
+
+		mutated, err := mutator.Mutate(ctx, ns, pod)
+		require.NoError(t, err)
+		require.Len(t, mutated.Spec.InitContainers, 1)
+		assert.Equal(t, javaInitContainerName, mutated.Spec.InitContainers[0].Name)
+		assert.Equal(t, "http://local-collector:4317", endpoint(mutated))
+
+		require.Len(t, recorder.Events, 2)
+		assert.Contains(t, <-recorder.Events, "Warning InstrumentationSelectorConflict selectors of Instrumentations selector-shop/local, selector-observability/cluster-wide match the pod")
+		assert.Contains(t, <-recorder.Events, "Normal InstrumentationSelected Instrumentation selector-shop/local selected")
+	})
+
+	t.Run("selected by the cluster-wide instrumentation only", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		mutator := NewMutator(logr.Discard(), selectorClient, recorder, config.New())
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "payments", Labels: map[string]string{"app": "payments"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+		}
+
+		mutated, err := mutator.Mutate(ctx, ns, pod)
+		require.NoError(t, err)
+		assert.Equal(t, "http://central-collector:4317", endpoint(mutated))
+	})
+
+	t.Run("annotation takes precedence", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		mutator := NewMutator(logr.Discard(), selectorClient, recorder, config.New())
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "cart",
+				Labels:      map[string]string{"app": "cart"},
+				Annotations: map[string]string{annotationInjectJava: "false"},
+			},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+		}
+
+		mutated, err := mutator.Mutate(ctx, ns, pod)
+		require.NoError(t, err)
+		assert.Equal(t, pod, mutated)
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "Normal InstrumentationSelectorOverridden annotation instrumentation.opentelemetry.io/inject-java=false takes precedence")
+	})
+
+	t.Run("explained without publishing events", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		mutator := NewMutator(logr.Discard(), selectorClient, recorder, config.New())
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "cart", Labels: map[string]string{"app": "cart"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+		}
+		steps := &explanation.Explanation{}
+
+		mutated, err := mutator.Mutate(explanation.NewContext(ctx, steps), ns, pod)
+		require.NoError(t, err)
+		assert.Equal(t, "http://local-collector:4317", endpoint(mutated))
+		assert.Empty(t, recorder.Events)
+
+		var messages []string
+		for _, step := range steps.Steps() {
+			assert.Equal(t, mutatorName, step.Mutator)
+			messages = append(messages, step.Message)
+		}
+		assert.Contains(t, messages, "Instrumentation selector-shop/local selected through its selector for instrumentation.opentelemetry.io/inject-java")
+		assert.Contains(t, messages, "injecting Java instrumentation into pod")
+	})
+}
+
+func TestMutatePodLanguageDetection(t *testing.T) {
+	ctx := context.Background()
+	ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "language-detection"}}
+	inst := v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-inst", Namespace: ns.Name},
+		Spec: v1alpha1.InstrumentationSpec{
+			Exporter: v1alpha1.Exporter{Endpoint: "http://collector:4317"},
+			LanguageDetection: v1alpha1.LanguageDetection{
+				Rules: []v1alpha1.LanguageDetectionRule{
+					{Language: v1alpha1.LanguagePython, Image: "^registry.example.com/ml/"},
+				},
+			},
+		},
+	}
+	require.NoError(t, k8sClient.Create(ctx, &ns))
+	defer func() {
+		_ = k8sClient.Delete(ctx, &ns)
+	}()
+	require.NoError(t, k8sClient.Create(ctx, &inst))
+	defer func() {
+		_ = k8sClient.Delete(ctx, &inst)
+	}()
+
+	cfg := config.New(config.WithEnableNodeJSInstrumentation(true), config.WithEnablePythonInstrumentation(true))
+	initContainers := func(pod corev1.Pod) []string {
+		var names []string
+		for _, container := range pod.Spec.InitContainers {
+			names = append(names, container.Name)
+		}
+		return names
+	}
+
+	t.Run("per container detection", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		mutator := NewMutator(logr.Discard(), k8sClient, recorder, cfg)
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "app",
+				Annotations: map[string]string{annotationInjectAuto: "true"},
+			},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{
+				{Name: "api", Image: "api:1.0", Command: []string{"java", "-jar", "api.jar"}},
+				{Name: "model", Image: "registry.example.com/ml/model:3"},
+				{Name: "proxy", Image: "envoyproxy/envoy:v1.31"},
+			}},
+		}
+
+		mutated, err := mutator.Mutate(ctx, ns, pod)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{javaInitContainerName, pythonInitContainerName}, initContainers(mutated))
+		assert.Equal(t, pod.Spec.Containers[2], mutated.Spec.Containers[2])
+
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, "Normal InstrumentationLanguageDetected "+
+			`container api: java detected (command matches "(^|[\\s/])java\\s"); `+
+			`container model: python detected (image matches "^registry.example.com/ml/"); `+
+			"container proxy: no language detected", <-recorder.Events)
+	})
+
+	t.Run("language annotation takes precedence", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		mutator := NewMutator(logr.Discard(), k8sClient, recorder, cfg)
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "app",
+				Annotations: map[string]string{
+					annotationInjectAuto:   "true",
+					annotationInjectNodeJS: "true",
+				},
+			},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{
+				{Name: "web", Image: "web:1.0"},
+				{Name: "api", Image: "api:1.0", Command: []string{"java", "-jar", "api.jar"}},
+			}},
+		}
+
+		mutated, err := mutator.Mutate(ctx, ns, pod)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{nodejsInitContainerName, javaInitContainerName}, initContainers(mutated))
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "container web: instrumented through its language annotation; container api: java detected")
+	})
+
+	t.Run("nothing detected", func(t *testing.T) {
+		mutator := NewMutator(logr.Discard(), k8sClient, record.NewFakeRecorder(10), cfg)
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "app",
+				Annotations: map[string]string{annotationInjectAuto: "true"},
+			},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "proxy", Image: "envoyproxy/envoy:v1.31"}}},
+		}
+
+		mutated, err := mutator.Mutate(ctx, ns, pod)
+		require.NoError(t, err)
+		assert.Equal(t, pod, mutated)
+	})
+}
@@ -39,7 +39,7 @@ const (
 	muslLinux                        = "musl"
 )
 
-func injectPythonSDK(pythonSpec v1alpha1.Python, pod corev1.Pod, index int, platform string) (corev1.Pod, error) {
+func injectPythonSDK(pythonSpec v1alpha1.Python, exporter v1alpha1.Exporter, pod corev1.Pod, index int, platform string) (corev1.Pod, error) {
 	volume := instrVolume(pythonSpec.VolumeClaimTemplate, pythonVolumeName, pythonSpec.VolumeSizeLimit)
 
 	// caller checks if there is at least one container.
@@ -87,30 +87,33 @@ func injectPythonSDK(pythonSpec v1alpha1.Python, pod corev1.Pod, index int, plat
 		})
 	}
 
-	// Set OTEL_TRACES_EXPORTER to otlp exporter if not set by user because it is what our autoinstrumentation supports.
+	// Set OTEL_TRACES_EXPORTER to otlp exporter if not set by user because it is what our autoinstrumentation supports,
+	// unless the signal is disabled in the exporter.
 	idx = getIndexOfEnv(container.Env, envOtelTracesExporter)
 	if idx == -1 {
 		container.Env = append(container.Env, corev1.EnvVar{
 			Name:  envOtelTracesExporter,
-			Value: "otlp",
+			Value: signalExporterName(exporter.Traces),
 		})
 	}
 
-	// Set OTEL_METRICS_EXPORTER to otlp exporter if not set by user because it is what our autoinstrumentation supports.
+	// Set OTEL_METRICS_EXPORTER to otlp exporter if not set by user because it is what our autoinstrumentation supports,
+	// unless the signal is disabled in the exporter.
 	idx = getIndexOfEnv(container.Env, envOtelMetricsExporter)
 	if idx == -1 {
 		container.Env = append(container.Env, corev1.EnvVar{
 			Name:  envOtelMetricsExporter,
-			Value: "otlp",
+			Value: signalExporterName(exporter.Metrics),
 		})
 	}
 
-	// Set OTEL_LOGS_EXPORTER to otlp exporter if not set by user because it is what our autoinstrumentation supports.
+	// Set OTEL_LOGS_EXPORTER to otlp exporter if not set by user because it is what our autoinstrumentation supports,
+	// unless the signal is disabled in the exporter.
 	idx = getIndexOfEnv(container.Env, envOtelLogsExporter)
 	if idx == -1 {
 		container.Env = append(container.Env, corev1.EnvVar{
 			Name:  envOtelLogsExporter,
-			Value: "otlp",
+			Value: signalExporterName(exporter.Logs),
 		})
 	}
 
@@ -20,6 +20,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
 
 	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
 )
@@ -28,6 +29,7 @@ func TestInjectPythonSDK(t *testing.T) {
 	tests := []struct {
 		name string
 		v1alpha1.Python
+		exporter v1alpha1.Exporter
 		pod      corev1.Pod
 		platform string
 		expected corev1.Pod
@@ -258,6 +260,80 @@ func TestInjectPythonSDK(t *testing.T) {
 			},
 			err: nil,
 		},
+		{
+			name:   "metrics and logs disabled in the exporter",
+			Python: v1alpha1.Python{Image: "foo/bar:1"},
+			exporter: v1alpha1.Exporter{
+				Metrics: &v1alpha1.SignalExporter{Enabled: ptr.To(false)},
+				Logs:    &v1alpha1.SignalExporter{Enabled: ptr.To(false)},
+			},
+			pod: corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{},
+					},
+				},
+			},
+			platform: "glibc",
+			expected: corev1.Pod{
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{
+						{
+							Name: pythonVolumeName,
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{
+									SizeLimit: &defaultVolumeLimitSize,
+								},
+							},
+						},
+					},
+					InitContainers: []corev1.Container{
+						{
+							Name:    "opentelemetry-auto-instrumentation-python",
+							Image:   "foo/bar:1",
+							Command: []string{"cp", "-r", "/autoinstrumentation/.", "/otel-auto-instrumentation-python"},
+							VolumeMounts: []corev1.VolumeMount{{
+								Name:      "opentelemetry-auto-instrumentation-python",
+								MountPath: "/otel-auto-instrumentation-python",
+							}},
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "opentelemetry-auto-instrumentation-python",
+									MountPath: "/otel-auto-instrumentation-python",
+								},
+							},
+							Env: []corev1.EnvVar{
+								{
+									Name:  "PYTHONPATH",
+									Value: fmt.Sprintf("%s:%s", "/otel-auto-instrumentation-python/opentelemetry/instrumentation/auto_instrumentation", "/otel-auto-instrumentation-python"),
+								},
+								{
+									Name:  "OTEL_EXPORTER_OTLP_PROTOCOL",
+									Value: "http/protobuf",
+								},
+								{
+									Name:  "OTEL_TRACES_EXPORTER",
+									Value: "otlp",
+								},
+								{
+									Name:  "OTEL_METRICS_EXPORTER",
+									Value: "none",
+								},
+								{
+									Name:  "OTEL_LOGS_EXPORTER",
+									Value: "none",
+								},
+							},
+						},
+					},
+				},
+			},
+			err: nil,
+		},
 		{
 			name:   "OTEL_METRICS_EXPORTER defined",
 			Python: v1alpha1.Python{Image: "foo/bar:1"},
@@ -686,7 +762,7 @@ func TestInjectPythonSDK(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			pod, err := injectPythonSDK(test.Python, test.pod, 0, test.platform)
+			pod, err := injectPythonSDK(test.Python, test.exporter, test.pod, 0, test.platform)
 			assert.Equal(t, test.expected, pod)
 			assert.Equal(t, test.err, err)
 		})
@@ -0,0 +1,137 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instrumentation
+
+import (
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
+)
+
+var languageInjectAnnotations = []struct {
+	language   v1alpha1.Language
+	annotation string
+}{
+	{v1alpha1.LanguageJava, annotationInjectJava},
+	{v1alpha1.LanguageNodeJS, annotationInjectNodeJS},
+	{v1alpha1.LanguagePython, annotationInjectPython},
+	{v1alpha1.LanguageDotNet, annotationInjectDotNet},
+	{v1alpha1.LanguageGo, annotationInjectGo},
+	{v1alpha1.LanguageApacheHttpd, annotationInjectApacheHttpd},
+	{v1alpha1.LanguageNginx, annotationInjectNginx},
+	{v1alpha1.LanguageRuby, annotationInjectRuby},
+	{v1alpha1.LanguagePHP, annotationInjectPHP},
+	{v1alpha1.LanguageSdk, annotationInjectSdk},
+}
+
+// InstrumentationReferenceIndex is the name of the pod field index holding the Instrumentations named by the inject
+// annotations of the pods, in the namespace/name form.
+const InstrumentationReferenceIndex = ".metadata.annotations.instrumentation"
+
+// InstrumentationReferenceIndexValues returns the InstrumentationReferenceIndex values of a pod. Only the annotations of
+// the pod are considered, and the ones set to "true" are left out, as they select the Instrumentation of the pod
+// namespace.
+func InstrumentationReferenceIndexValues(obj client.Object) []string {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil
+	}
+	values := []string{AutoInstrumentationReference(metav1.ObjectMeta{}, pod.ObjectMeta)}
+	for _, value := range InstrumentationReferences(metav1.ObjectMeta{}, pod.ObjectMeta) {
+		values = append(values, value)
+	}
+
+	var refs []string
+	for _, value := range values {
+		if len(value) == 0 || strings.EqualFold(value, "true") {
+			continue
+		}
+		if !strings.Contains(value, "/") {
+			value = pod.Namespace + "/" + value
+		}
+		if !slices.Contains(refs, value) {
+			refs = append(refs, value)
+		}
+	}
+	return refs
+}
+
+// InstrumentationReferences returns the effective inject annotation value per language for a pod, resolved against
+// the namespace annotations the same way the pod mutator does. Languages that are not requested are omitted.
+func InstrumentationReferences(ns metav1.ObjectMeta, pod metav1.ObjectMeta) map[v1alpha1.Language]string {
+	refs := map[v1alpha1.Language]string{}
+	for _, l := range languageInjectAnnotations {
+		value := annotationValue(ns, pod, l.annotation)
+		if len(value) == 0 || strings.EqualFold(value, "false") {
+			continue
+		}
+		refs[l.language] = value
+	}
+	return refs
+}
+
+// ReferencesInstrumentation reports whether an inject annotation value found on a pod in podNamespace selects inst.
+// A "true" value selects the only Instrumentation of the pod namespace, so namespaceInstrumentations must be the
+// number of Instrumentation objects in that namespace.
+func ReferencesInstrumentation(value string, podNamespace string, inst types.NamespacedName, namespaceInstrumentations int) bool {
+	if strings.EqualFold(value, "true") {
+		return podNamespace == inst.Namespace && namespaceInstrumentations == 1
+	}
+	if instNamespace, instName, namespaced := strings.Cut(value, "/"); namespaced {
+		return instNamespace == inst.Namespace && instName == inst.Name
+	}
+	return podNamespace == inst.Namespace && value == inst.Name
+}
+
+// InjectedLanguages returns the languages whose auto-instrumentation has been injected into the pod.
+func InjectedLanguages(pod corev1.Pod) map[v1alpha1.Language]bool {
+	injected := map[v1alpha1.Language]bool{}
+	for _, container := range pod.Spec.InitContainers {
+		switch container.Name {
+		case javaInitContainerName:
+			injected[v1alpha1.LanguageJava] = true
+		case nodejsInitContainerName:
+			injected[v1alpha1.LanguageNodeJS] = true
+		case pythonInitContainerName:
+			injected[v1alpha1.LanguagePython] = true
+		case dotnetInitContainerName:
+			injected[v1alpha1.LanguageDotNet] = true
+		case apacheAgentInitContainerName:
+			injected[v1alpha1.LanguageApacheHttpd] = true
+		case nginxAgentInitContainerName:
+			injected[v1alpha1.LanguageNginx] = true
+		case rubyInitContainerName:
+			injected[v1alpha1.LanguageRuby] = true
+		case phpInitContainerName:
+			injected[v1alpha1.LanguagePHP] = true
+		}
+	}
+	for _, container := range pod.Spec.Containers {
+		if container.Name == sideCarName {
+			injected[v1alpha1.LanguageGo] = true
+		}
+	}
+	// the SDK-only injection leaves no container behind, only the common env vars
+	if isAutoInstrumentationInjected(pod) {
+		injected[v1alpha1.LanguageSdk] = true
+	}
+	return injected
+}
@@ -0,0 +1,114 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instrumentation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
+)
+
+func TestInstrumentationReferences(t *testing.T) {
+	ns := metav1.ObjectMeta{
+		Annotations: map[string]string{
+			annotationInjectJava:   "my-inst",
+			annotationInjectPython: "true",
+		},
+	}
+	pod := metav1.ObjectMeta{
+		Annotations: map[string]string{
+			annotationInjectPython: "false",
+			annotationInjectGo:     "other/go-inst",
+		},
+	}
+
+	refs := InstrumentationReferences(ns, pod)
+	assert.Equal(t, map[v1alpha1.Language]string{
+		v1alpha1.LanguageJava: "my-inst",
+		v1alpha1.LanguageGo:   "other/go-inst",
+	}, refs)
+}
+
+func TestReferencesInstrumentation(t *testing.T) {
+	inst := types.NamespacedName{Namespace: "observability", Name: "my-inst"}
+	for _, tt := range []struct {
+		name      string
+		value     string
+		podNs     string
+		instCount int
+		expected  bool
+	}{
+		{"true with a single instance", "true", "observability", 1, true},
+		{"true with several instances", "true", "observability", 2, false},
+		{"true in another namespace", "true", "default", 1, false},
+		{"name in the same namespace", "my-inst", "observability", 3, true},
+		{"name in another namespace", "my-inst", "default", 1, false},
+		{"namespaced name", "observability/my-inst", "default", 0, true},
+		{"other namespaced name", "default/my-inst", "default", 1, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ReferencesInstrumentation(tt.value, tt.podNs, inst, tt.instCount))
+		})
+	}
+}
+
+func TestInstrumentationReferenceIndexValues(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "shop",
+			Annotations: map[string]string{
+				annotationInjectJava:   "my-inst",
+				annotationInjectPython: "true",
+				annotationInjectNodeJS: "false",
+				annotationInjectGo:     "observability/my-inst",
+				annotationInjectAuto:   "observability/auto",
+			},
+		},
+	}
+
+	values := InstrumentationReferenceIndexValues(pod)
+	assert.ElementsMatch(t, []string{"shop/my-inst", "observability/my-inst", "observability/auto"}, values)
+	assert.Empty(t, InstrumentationReferenceIndexValues(&corev1.Namespace{}))
+}
+
+func TestInjectedLanguages(t *testing.T) {
+	pod := corev1.Pod{
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{
+				{Name: javaInitContainerName},
+				{Name: nodejsInitContainerName},
+				{Name: rubyInitContainerName},
+			},
+			Containers: []corev1.Container{
+				{Name: "app"},
+				{Name: sideCarName},
+			},
+		},
+	}
+
+	assert.Equal(t, map[v1alpha1.Language]bool{
+		v1alpha1.LanguageJava:   true,
+		v1alpha1.LanguageNodeJS: true,
+		v1alpha1.LanguageRuby:   true,
+		v1alpha1.LanguageGo:     true,
+		v1alpha1.LanguageSdk:    true,
+	}, InjectedLanguages(pod))
+	assert.Empty(t, InjectedLanguages(corev1.Pod{}))
+}
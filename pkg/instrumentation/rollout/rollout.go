@@ -0,0 +1,218 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rollout restarts the workloads of pods instrumented with a previous version of an Instrumentation.
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/instrumentation"
+)
+
+// AnnotationRestartedFor is set on the pod template of the workloads restarted to pick up the changes of an
+// Instrumentation. Updating it triggers the rolling restart. It lists the spec hash each Instrumentation restarted the
+// workload for, in the instrumentation.AnnotationSpecHash format.
+const AnnotationRestartedFor = "instrumentation.opentelemetry.io/restarted-for"
+
+const (
+	eventTypeNormal = "Normal"
+
+	reasonRolloutRestarted = "RolloutRestarted"
+)
+
+type workloadKey struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+func (k workloadKey) String() string {
+	return fmt.Sprintf("%s %s/%s", k.kind, k.namespace, k.name)
+}
+
+// Reconcile restarts the Deployments, StatefulSets and DaemonSets owning pods instrumented with a previous spec of
+// inst, when its rollout policy enables rolloutOnChange. At most maxConcurrent workloads are restarting at the same
+// time, a workload being restarting until none of its pods is outdated anymore. The pods of the workloads are expected
+// to be watched, for the Instrumentation to be reconciled again when its outdated pods are replaced, and to be indexed
+// with instrumentation.SpecHashIndex.
+func Reconcile(ctx context.Context, log logr.Logger, cli client.Client, recorder record.EventRecorder, inst v1alpha1.Instrumentation) error {
+	if !instrumentation.RolloutOnChange(inst) {
+		return nil
+	}
+	specHash, err := instrumentation.SpecHash(inst)
+	if err != nil {
+		return fmt.Errorf("failed to compute the spec hash: %w", err)
+	}
+	instKey := types.NamespacedName{Namespace: inst.Namespace, Name: inst.Name}
+
+	outdated, err := outdatedWorkloads(ctx, log, cli, inst, instKey, specHash)
+	if err != nil {
+		return err
+	}
+
+	var restarting int32
+	var pending []workloadKey
+	for _, key := range outdated {
+		obj, template := newWorkload(key.kind)
+		if err = cli.Get(ctx, types.NamespacedName{Namespace: key.namespace, Name: key.name}, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to get %s: %w", key, err)
+		}
+		if instrumentation.ParseSpecHashes(template().Annotations[AnnotationRestartedFor])[instKey] == specHash {
+			restarting++
+		} else {
+			pending = append(pending, key)
+		}
+	}
+
+	maxConcurrent := int32(1)
+	if inst.Spec.RolloutPolicy.MaxConcurrent != nil {
+		maxConcurrent = *inst.Spec.RolloutPolicy.MaxConcurrent
+	}
+	for i := 0; i < len(pending) && restarting < maxConcurrent; i++ {
+		if err = restart(ctx, cli, pending[i], instKey, specHash); err != nil {
+			return err
+		}
+		restarting++
+		log.V(1).Info("restarted workload to pick up the Instrumentation changes", "workload", pending[i].String())
+		recorder.Eventf(&inst, eventTypeNormal, reasonRolloutRestarted, "restarted %s to pick up the changes of the Instrumentation", pending[i])
+	}
+	return nil
+}
+
+// outdatedWorkloads returns the workloads of the allowed namespaces owning running pods instrumented with a previous
+// spec of the Instrumentation, sorted for the restarts to happen in a stable order. Only the pods whose spec hash
+// annotation lists the Instrumentation are read.
+func outdatedWorkloads(ctx context.Context, log logr.Logger, cli client.Client, inst v1alpha1.Instrumentation, instKey types.NamespacedName, specHash string) ([]workloadKey, error) {
+	tracked := client.MatchingFields{instrumentation.SpecHashIndex: instKey.String()}
+	var pods []corev1.Pod
+	if namespaces := inst.Spec.RolloutPolicy.Namespaces; len(namespaces) > 0 {
+		for _, ns := range namespaces {
+			list := &corev1.PodList{}
+			if err := cli.List(ctx, list, client.InNamespace(ns), tracked); err != nil {
+				return nil, fmt.Errorf("failed to list pods of namespace %s: %w", ns, err)
+			}
+			pods = append(pods, list.Items...)
+		}
+	} else {
+		list := &corev1.PodList{}
+		if err := cli.List(ctx, list, tracked); err != nil {
+			return nil, fmt.Errorf("failed to list pods: %w", err)
+		}
+		pods = list.Items
+	}
+
+	seen := map[workloadKey]struct{}{}
+	var outdated []workloadKey
+	for i := range pods {
+		pod := pods[i]
+		if pod.DeletionTimestamp != nil || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if hash, tracked := instrumentation.InjectedSpecHash(pod.ObjectMeta, instKey); !tracked || hash == specHash {
+			continue
+		}
+		key, ok, err := podWorkload(ctx, cli, pod)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			log.V(2).Info("skipping outdated pod without a workload that can be restarted", "namespace", pod.Namespace, "name", pod.Name)
+			continue
+		}
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			outdated = append(outdated, key)
+		}
+	}
+	sort.Slice(outdated, func(i, j int) bool { return outdated[i].String() < outdated[j].String() })
+	return outdated, nil
+}
+
+// podWorkload returns the Deployment, StatefulSet or DaemonSet controlling the pod, if any.
+func podWorkload(ctx context.Context, cli client.Client, pod corev1.Pod) (workloadKey, bool, error) {
+	owner := metav1.GetControllerOf(&pod)
+	if owner == nil {
+		return workloadKey{}, false, nil
+	}
+	switch owner.Kind {
+	case "StatefulSet", "DaemonSet":
+		return workloadKey{kind: owner.Kind, namespace: pod.Namespace, name: owner.Name}, true, nil
+	case "ReplicaSet":
+		replicaSet := &appsv1.ReplicaSet{}
+		if err := cli.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: owner.Name}, replicaSet); err != nil {
+			if apierrors.IsNotFound(err) {
+				return workloadKey{}, false, nil
+			}
+			return workloadKey{}, false, fmt.Errorf("failed to get replicaset %s/%s: %w", pod.Namespace, owner.Name, err)
+		}
+		if deployment := metav1.GetControllerOf(replicaSet); deployment != nil && deployment.Kind == "Deployment" {
+			return workloadKey{kind: deployment.Kind, namespace: pod.Namespace, name: deployment.Name}, true, nil
+		}
+	}
+	return workloadKey{}, false, nil
+}
+
+// newWorkload returns an empty workload of the given kind, and a function returning its pod template.
+func newWorkload(kind string) (client.Object, func() *corev1.PodTemplateSpec) {
+	switch kind {
+	case "StatefulSet":
+		statefulSet := &appsv1.StatefulSet{}
+		return statefulSet, func() *corev1.PodTemplateSpec { return &statefulSet.Spec.Template }
+	case "DaemonSet":
+		daemonSet := &appsv1.DaemonSet{}
+		return daemonSet, func() *corev1.PodTemplateSpec { return &daemonSet.Spec.Template }
+	default:
+		deployment := &appsv1.Deployment{}
+		return deployment, func() *corev1.PodTemplateSpec { return &deployment.Spec.Template }
+	}
+}
+
+// restart triggers a rolling restart of the workload by recording on its pod template the spec hash it is restarted
+// for, the same way `kubectl rollout restart` records a timestamp.
+func restart(ctx context.Context, cli client.Client, key workloadKey, instKey types.NamespacedName, specHash string) error {
+	obj, template := newWorkload(key.kind)
+	if err := cli.Get(ctx, types.NamespacedName{Namespace: key.namespace, Name: key.name}, obj); err != nil {
+		return fmt.Errorf("failed to get %s: %w", key, err)
+	}
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+
+	podTemplate := template()
+	restartedFor := instrumentation.ParseSpecHashes(podTemplate.Annotations[AnnotationRestartedFor])
+	restartedFor[instKey] = specHash
+	if podTemplate.Annotations == nil {
+		podTemplate.Annotations = map[string]string{}
+	}
+	podTemplate.Annotations[AnnotationRestartedFor] = instrumentation.FormatSpecHashes(restartedFor)
+
+	if err := cli.Patch(ctx, obj, patch); err != nil {
+		return fmt.Errorf("failed to restart %s: %w", key, err)
+	}
+	return nil
+}
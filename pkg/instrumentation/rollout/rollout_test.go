@@ -0,0 +1,156 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rollout
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/instrumentation"
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	return scheme
+}
+
+// newClientBuilder returns a fake client builder indexing the pods the same way the Instrumentation controller does.
+func newClientBuilder(t *testing.T) *fake.ClientBuilder {
+	return fake.NewClientBuilder().WithScheme(newScheme(t)).WithIndex(&corev1.Pod{}, instrumentation.SpecHashIndex, instrumentation.SpecHashIndexValues)
+}
+
+func newPod(namespace, name, ownerKind, ownerName, specHash string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Annotations: map[string]string{instrumentation.AnnotationSpecHash: "observability/my-inst=" + specHash},
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: ownerKind, Name: ownerName, Controller: ptr.To(true)},
+			},
+		},
+	}
+}
+
+func restartedFor(t *testing.T, cli client.Client, obj client.Object, template func() *corev1.PodTemplateSpec) string {
+	require.NoError(t, cli.Get(context.Background(), types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, obj))
+	return template().Annotations[AnnotationRestartedFor]
+}
+
+func TestReconcile(t *testing.T) {
+	inst := v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-inst", Namespace: "observability"},
+		Spec: v1alpha1.InstrumentationSpec{
+			Exporter: v1alpha1.Exporter{Endpoint: "http://collector:4318"},
+			RolloutPolicy: &v1alpha1.RolloutPolicy{
+				RolloutOnChange: true,
+				MaxConcurrent:   ptr.To(int32(2)),
+				Namespaces:      []string{"shop"},
+			},
+		},
+	}
+	specHash, err := instrumentation.SpecHash(inst)
+	require.NoError(t, err)
+
+	cart := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "cart", Namespace: "shop"}}
+	cartReplicaSet := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Name:            "cart-5d9f7c",
+		Namespace:       "shop",
+		OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "cart", Controller: ptr.To(true)}},
+	}}
+	orders := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "shop"}}
+	agent := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "shop"}}
+	ledger := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "ledger", Namespace: "payments"}}
+	ledgerReplicaSet := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Name:            "ledger-7f8b9c",
+		Namespace:       "payments",
+		OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "ledger", Controller: ptr.To(true)}},
+	}}
+	upToDate := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "search", Namespace: "shop"}}
+
+	cli := newClientBuilder(t).WithRuntimeObjects(
+		&inst, cart, cartReplicaSet, orders, agent, ledger, ledgerReplicaSet, upToDate,
+		newPod("shop", "cart-5d9f7c-abcde", "ReplicaSet", "cart-5d9f7c", "previous"),
+		newPod("shop", "cart-5d9f7c-fghij", "ReplicaSet", "cart-5d9f7c", "previous"),
+		newPod("shop", "orders-0", "StatefulSet", "orders", "previous"),
+		newPod("shop", "agent-xyz", "DaemonSet", "agent", "previous"),
+		newPod("shop", "search-0", "StatefulSet", "search", specHash),
+		// instrumented by another Instrumentation
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:            "search-1",
+			Namespace:       "shop",
+			Annotations:     map[string]string{instrumentation.AnnotationSpecHash: "observability/other-inst=previous"},
+			OwnerReferences: []metav1.OwnerReference{{Kind: "StatefulSet", Name: "search", Controller: ptr.To(true)}},
+		}},
+		// not in the allowed namespaces
+		newPod("payments", "ledger-7f8b9c-abcde", "ReplicaSet", "ledger-7f8b9c", "previous"),
+	).Build()
+	recorder := record.NewFakeRecorder(10)
+
+	require.NoError(t, Reconcile(context.Background(), logr.Discard(), cli, recorder, inst))
+
+	// workloads are restarted in order, at most two at a time
+	expected := "observability/my-inst=" + specHash
+	assert.Equal(t, expected, restartedFor(t, cli, agent, func() *corev1.PodTemplateSpec { return &agent.Spec.Template }))
+	assert.Equal(t, expected, restartedFor(t, cli, cart, func() *corev1.PodTemplateSpec { return &cart.Spec.Template }))
+	assert.Empty(t, restartedFor(t, cli, orders, func() *corev1.PodTemplateSpec { return &orders.Spec.Template }))
+	assert.Empty(t, restartedFor(t, cli, ledger, func() *corev1.PodTemplateSpec { return &ledger.Spec.Template }))
+	assert.Empty(t, restartedFor(t, cli, upToDate, func() *corev1.PodTemplateSpec { return &upToDate.Spec.Template }))
+	require.Len(t, recorder.Events, 2)
+	assert.Contains(t, <-recorder.Events, "Normal RolloutRestarted restarted DaemonSet shop/agent to pick up the changes of the Instrumentation")
+	assert.Contains(t, <-recorder.Events, "Normal RolloutRestarted restarted Deployment shop/cart to pick up the changes of the Instrumentation")
+
+	// the restarted workloads still have outdated pods, the statefulset waits for them
+	require.NoError(t, Reconcile(context.Background(), logr.Discard(), cli, recorder, inst))
+	assert.Empty(t, restartedFor(t, cli, orders, func() *corev1.PodTemplateSpec { return &orders.Spec.Template }))
+	assert.Empty(t, recorder.Events)
+
+	// once the daemonset pods are replaced, the statefulset is restarted
+	require.NoError(t, cli.Delete(context.Background(), &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "agent-xyz", Namespace: "shop"}}))
+	require.NoError(t, Reconcile(context.Background(), logr.Discard(), cli, recorder, inst))
+	assert.Equal(t, expected, restartedFor(t, cli, orders, func() *corev1.PodTemplateSpec { return &orders.Spec.Template }))
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "restarted StatefulSet shop/orders")
+}
+
+func TestReconcileDisabled(t *testing.T) {
+	inst := v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-inst", Namespace: "observability"},
+	}
+	orders := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "shop"}}
+	cli := newClientBuilder(t).WithRuntimeObjects(
+		&inst, orders, newPod("shop", "orders-0", "StatefulSet", "orders", "previous"),
+	).Build()
+
+	require.NoError(t, Reconcile(context.Background(), logr.Discard(), cli, record.NewFakeRecorder(10), inst))
+	assert.Empty(t, restartedFor(t, cli, orders, func() *corev1.PodTemplateSpec { return &orders.Spec.Template }))
+}
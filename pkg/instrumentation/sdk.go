@@ -17,6 +17,7 @@ package instrumentation
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -37,6 +38,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
 	"github.com/open-telemetry/opentelemetry-operator/internal/config"
+	"github.com/open-telemetry/opentelemetry-operator/internal/explanation"
 	"github.com/open-telemetry/opentelemetry-operator/pkg/constants"
 )
 
@@ -57,10 +59,11 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 	if len(pod.Spec.Containers) < 1 {
 		return pod
 	}
+	logger := explanation.Logger(ctx, i.logger, mutatorName)
 	if insts.Java.Instrumentation != nil {
 		otelinst := *insts.Java.Instrumentation
 		var err error
-		i.logger.V(1).Info("injecting Java instrumentation into pod", "otelinst-namespace", otelinst.Namespace, "otelinst-name", otelinst.Name)
+		logger.V(1).Info("injecting Java instrumentation into pod", "otelinst-namespace", otelinst.Namespace, "otelinst-name", otelinst.Name)
 
 		if len(insts.Java.Containers) == 0 {
 			insts.Java.Containers = []string{pod.Spec.Containers[0].Name}
@@ -70,7 +73,7 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 			index := getContainerIndex(container, pod)
 			pod, err = injectJavaagent(otelinst.Spec.Java, pod, index)
 			if err != nil {
-				i.logger.Info("Skipping javaagent injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
+				logger.Info("Skipping javaagent injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
 			} else {
 				pod = i.injectCommonEnvVar(otelinst, pod, index)
 				pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
@@ -81,7 +84,7 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 	if insts.NodeJS.Instrumentation != nil {
 		otelinst := *insts.NodeJS.Instrumentation
 		var err error
-		i.logger.V(1).Info("injecting NodeJS instrumentation into pod", "otelinst-namespace", otelinst.Namespace, "otelinst-name", otelinst.Name)
+		logger.V(1).Info("injecting NodeJS instrumentation into pod", "otelinst-namespace", otelinst.Namespace, "otelinst-name", otelinst.Name)
 
 		if len(insts.NodeJS.Containers) == 0 {
 			insts.NodeJS.Containers = []string{pod.Spec.Containers[0].Name}
@@ -91,7 +94,7 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 			index := getContainerIndex(container, pod)
 			pod, err = injectNodeJSSDK(otelinst.Spec.NodeJS, pod, index)
 			if err != nil {
-				i.logger.Info("Skipping NodeJS SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
+				logger.Info("Skipping NodeJS SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
 			} else {
 				pod = i.injectCommonEnvVar(otelinst, pod, index)
 				pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
@@ -102,7 +105,7 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 	if insts.Python.Instrumentation != nil {
 		otelinst := *insts.Python.Instrumentation
 		var err error
-		i.logger.V(1).Info("injecting Python instrumentation into pod", "otelinst-namespace", otelinst.Namespace, "otelinst-name", otelinst.Name)
+		logger.V(1).Info("injecting Python instrumentation into pod", "otelinst-namespace", otelinst.Namespace, "otelinst-name", otelinst.Name)
 
 		if len(insts.Python.Containers) == 0 {
 			insts.Python.Containers = []string{pod.Spec.Containers[0].Name}
@@ -110,9 +113,10 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 
 		for _, container := range insts.Python.Containers {
 			index := getContainerIndex(container, pod)
-			pod, err = injectPythonSDK(otelinst.Spec.Python, pod, index, insts.Python.AdditionalAnnotations[annotationPythonPlatform])
+			exporter, _ := containerExporterAndSampler(otelinst, pod.Spec.Containers[index].Name)
+			pod, err = injectPythonSDK(otelinst.Spec.Python, exporter, pod, index, insts.Python.AdditionalAnnotations[annotationPythonPlatform])
 			if err != nil {
-				i.logger.Info("Skipping Python SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
+				logger.Info("Skipping Python SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
 			} else {
 				pod = i.injectCommonEnvVar(otelinst, pod, index)
 				pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
@@ -123,7 +127,7 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 	if insts.DotNet.Instrumentation != nil {
 		otelinst := *insts.DotNet.Instrumentation
 		var err error
-		i.logger.V(1).Info("injecting DotNet instrumentation into pod", "otelinst-namespace", otelinst.Namespace, "otelinst-name", otelinst.Name)
+		logger.V(1).Info("injecting DotNet instrumentation into pod", "otelinst-namespace", otelinst.Namespace, "otelinst-name", otelinst.Name)
 
 		if len(insts.DotNet.Containers) == 0 {
 			insts.DotNet.Containers = []string{pod.Spec.Containers[0].Name}
@@ -133,7 +137,7 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 			index := getContainerIndex(container, pod)
 			pod, err = injectDotNetSDK(otelinst.Spec.DotNet, pod, index, insts.DotNet.AdditionalAnnotations[annotationDotNetRuntime])
 			if err != nil {
-				i.logger.Info("Skipping DotNet SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
+				logger.Info("Skipping DotNet SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
 			} else {
 				pod = i.injectCommonEnvVar(otelinst, pod, index)
 				pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
@@ -141,11 +145,53 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 			}
 		}
 	}
+	if insts.Ruby.Instrumentation != nil {
+		otelinst := *insts.Ruby.Instrumentation
+		var err error
+		logger.V(1).Info("injecting Ruby instrumentation into pod", "otelinst-namespace", otelinst.Namespace, "otelinst-name", otelinst.Name)
+
+		if len(insts.Ruby.Containers) == 0 {
+			insts.Ruby.Containers = []string{pod.Spec.Containers[0].Name}
+		}
+
+		for _, container := range insts.Ruby.Containers {
+			index := getContainerIndex(container, pod)
+			pod, err = injectRubySDK(otelinst.Spec.Ruby, pod, index)
+			if err != nil {
+				logger.Info("Skipping Ruby SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
+			} else {
+				pod = i.injectCommonEnvVar(otelinst, pod, index)
+				pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
+				pod = i.setInitContainerSecurityContext(pod, pod.Spec.Containers[index].SecurityContext, rubyInitContainerName)
+			}
+		}
+	}
+	if insts.PHP.Instrumentation != nil {
+		otelinst := *insts.PHP.Instrumentation
+		var err error
+		logger.V(1).Info("injecting PHP instrumentation into pod", "otelinst-namespace", otelinst.Namespace, "otelinst-name", otelinst.Name)
+
+		if len(insts.PHP.Containers) == 0 {
+			insts.PHP.Containers = []string{pod.Spec.Containers[0].Name}
+		}
+
+		for _, container := range insts.PHP.Containers {
+			index := getContainerIndex(container, pod)
+			pod, err = injectPHPSDK(otelinst.Spec.PHP, pod, index)
+			if err != nil {
+				logger.Info("Skipping PHP SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
+			} else {
+				pod = i.injectCommonEnvVar(otelinst, pod, index)
+				pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
+				pod = i.setInitContainerSecurityContext(pod, pod.Spec.Containers[index].SecurityContext, phpInitContainerName)
+			}
+		}
+	}
 	if insts.Go.Instrumentation != nil {
 		origPod := pod
 		otelinst := *insts.Go.Instrumentation
 		var err error
-		i.logger.V(1).Info("injecting Go instrumentation into pod", "otelinst-namespace", otelinst.Namespace, "otelinst-name", otelinst.Name)
+		logger.V(1).Info("injecting Go instrumentation into pod", "otelinst-namespace", otelinst.Namespace, "otelinst-name", otelinst.Name)
 
 		if len(insts.Go.Containers) == 0 {
 			insts.Go.Containers = []string{pod.Spec.Containers[0].Name}
@@ -155,7 +201,7 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 		index := getContainerIndex(insts.Go.Containers[0], pod)
 		pod, err = injectGoSDK(otelinst.Spec.Go, pod, cfg)
 		if err != nil {
-			i.logger.Info("Skipping Go SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
+			logger.Info("Skipping Go SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
 		} else {
 			// Common env vars and config need to be applied to the agent contain.
 			pod = i.injectCommonEnvVar(otelinst, pod, len(pod.Spec.Containers)-1)
@@ -164,14 +210,14 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 			// Ensure that after all the env var coalescing we have a value for OTEL_GO_AUTO_TARGET_EXE
 			idx := getIndexOfEnv(pod.Spec.Containers[len(pod.Spec.Containers)-1].Env, envOtelTargetExe)
 			if idx == -1 {
-				i.logger.Info("Skipping Go SDK injection", "reason", "OTEL_GO_AUTO_TARGET_EXE not set", "container", pod.Spec.Containers[index].Name)
+				logger.Info("Skipping Go SDK injection", "reason", "OTEL_GO_AUTO_TARGET_EXE not set", "container", pod.Spec.Containers[index].Name)
 				pod = origPod
 			}
 		}
 	}
 	if insts.ApacheHttpd.Instrumentation != nil {
 		otelinst := *insts.ApacheHttpd.Instrumentation
-		i.logger.V(1).Info("injecting Apache Httpd instrumentation into pod", "otelinst-namespace", otelinst.Namespace, "otelinst-name", otelinst.Name)
+		logger.V(1).Info("injecting Apache Httpd instrumentation into pod", "otelinst-namespace", otelinst.Namespace, "otelinst-name", otelinst.Name)
 
 		if len(insts.ApacheHttpd.Containers) == 0 {
 			insts.ApacheHttpd.Containers = []string{pod.Spec.Containers[0].Name}
@@ -182,7 +228,8 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 			// Apache agent is configured via config files rather than env vars.
 			// Therefore, service name, otlp endpoint and other attributes are passed to the agent injection method
 			useLabelsForResourceAttributes := otelinst.Spec.Defaults.UseLabelsForResourceAttributes
-			pod = injectApacheHttpdagent(i.logger, otelinst.Spec.ApacheHttpd, pod, useLabelsForResourceAttributes, index, otelinst.Spec.Endpoint, i.createResourceMap(ctx, otelinst, ns, pod, index))
+			exporter, _ := containerExporterAndSampler(otelinst, pod.Spec.Containers[index].Name)
+			pod = injectApacheHttpdagent(i.logger, otelinst.Spec.ApacheHttpd, pod, useLabelsForResourceAttributes, index, exporter, i.createResourceMap(ctx, otelinst, ns, pod, index))
 			pod = i.injectCommonEnvVar(otelinst, pod, index)
 			pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
 			pod = i.setInitContainerSecurityContext(pod, pod.Spec.Containers[index].SecurityContext, apacheAgentInitContainerName)
@@ -192,7 +239,7 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 
 	if insts.Nginx.Instrumentation != nil {
 		otelinst := *insts.Nginx.Instrumentation
-		i.logger.V(1).Info("injecting Nginx instrumentation into pod", "otelinst-namespace", otelinst.Namespace, "otelinst-name", otelinst.Name)
+		logger.V(1).Info("injecting Nginx instrumentation into pod", "otelinst-namespace", otelinst.Namespace, "otelinst-name", otelinst.Name)
 
 		if len(insts.Nginx.Containers) == 0 {
 			insts.Nginx.Containers = []string{pod.Spec.Containers[0].Name}
@@ -203,7 +250,8 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 			// Nginx agent is configured via config files rather than env vars.
 			// Therefore, service name, otlp endpoint and other attributes are passed to the agent injection method
 			useLabelsForResourceAttributes := otelinst.Spec.Defaults.UseLabelsForResourceAttributes
-			pod = injectNginxSDK(i.logger, otelinst.Spec.Nginx, pod, useLabelsForResourceAttributes, index, otelinst.Spec.Endpoint, i.createResourceMap(ctx, otelinst, ns, pod, index))
+			exporter, _ := containerExporterAndSampler(otelinst, pod.Spec.Containers[index].Name)
+			pod = injectNginxSDK(i.logger, otelinst.Spec.Nginx, pod, useLabelsForResourceAttributes, index, exporter, i.createResourceMap(ctx, otelinst, ns, pod, index))
 			pod = i.injectCommonEnvVar(otelinst, pod, index)
 			pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
 		}
@@ -211,7 +259,7 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 
 	if insts.Sdk.Instrumentation != nil {
 		otelinst := *insts.Sdk.Instrumentation
-		i.logger.V(1).Info("injecting sdk-only instrumentation into pod", "otelinst-namespace", otelinst.Namespace, "otelinst-name", otelinst.Name)
+		logger.V(1).Info("injecting sdk-only instrumentation into pod", "otelinst-namespace", otelinst.Namespace, "otelinst-name", otelinst.Name)
 
 		if len(insts.Sdk.Containers) == 0 {
 			insts.Sdk.Containers = []string{pod.Spec.Containers[0].Name}
@@ -250,6 +298,37 @@ func getContainerIndex(containerName string, pod corev1.Pod) int {
 	return index
 }
 
+// InstrumentationExporters returns the exporters of an Instrumentation: spec.exporter and the exporters of its
+// container overrides.
+func InstrumentationExporters(otelinst v1alpha1.Instrumentation) []v1alpha1.Exporter {
+	exporters := []v1alpha1.Exporter{otelinst.Spec.Exporter}
+	for _, override := range otelinst.Spec.ContainerOverrides {
+		if override.Exporter != nil {
+			exporters = append(exporters, *override.Exporter)
+		}
+	}
+	return exporters
+}
+
+// containerExporterAndSampler returns the exporter and sampler of the named container, taking the container
+// overrides of the instrumentation into account.
+func containerExporterAndSampler(otelinst v1alpha1.Instrumentation, containerName string) (v1alpha1.Exporter, v1alpha1.Sampler) {
+	exporter, sampler := otelinst.Spec.Exporter, otelinst.Spec.Sampler
+	for _, override := range otelinst.Spec.ContainerOverrides {
+		if !slices.Contains(override.ContainerNames, containerName) {
+			continue
+		}
+		if override.Exporter != nil {
+			exporter = *override.Exporter
+		}
+		if override.Sampler != nil {
+			sampler = *override.Sampler
+		}
+		break
+	}
+	return exporter, sampler
+}
+
 func (i *sdkInjector) injectCommonEnvVar(otelinst v1alpha1.Instrumentation, pod corev1.Pod, index int) corev1.Pod {
 	container := &pod.Spec.Containers[index]
 
@@ -290,7 +369,7 @@ func (i *sdkInjector) injectCommonEnvVar(otelinst v1alpha1.Instrumentation, pod
 // agentIndex represents the index of the pod the needs the env vars to instrument the application.
 // appIndex represents the index of the pod the will produce the telemetry.
 // When the pod handling the instrumentation is the same as the pod producing the telemetry agentIndex
-// and appIndex should be the same value.  This is true for dotnet, java, nodejs, and python instrumentations.
+// and appIndex should be the same value.  This is true for dotnet, java, nodejs, python, ruby and php instrumentations.
 // Go requires the agent to be a different container in the pod, so the agentIndex should represent this new sidecar
 // and appIndex should represent the application being instrumented.
 func (i *sdkInjector) injectCommonSDKConfig(ctx context.Context, otelinst v1alpha1.Instrumentation, ns corev1.Namespace, pod corev1.Pod, agentIndex int, appIndex int) corev1.Pod {
@@ -304,7 +383,8 @@ func (i *sdkInjector) injectCommonSDKConfig(ctx context.Context, otelinst v1alph
 			Value: chooseServiceName(pod, useLabelsForResourceAttributes, resourceMap, appIndex),
 		})
 	}
-	configureExporter(otelinst.Spec.Exporter, &pod, container)
+	exporter, sampler := containerExporterAndSampler(otelinst, pod.Spec.Containers[appIndex].Name)
+	configureExporter(exporter, &pod, container)
 
 	// Always retrieve the pod name from the Downward API. Ensure that the OTEL_RESOURCE_ATTRIBUTES_POD_NAME env exists.
 	container.Env = append(container.Env, corev1.EnvVar{
@@ -377,17 +457,17 @@ func (i *sdkInjector) injectCommonSDKConfig(ctx context.Context, otelinst v1alph
 
 	idx = getIndexOfEnv(container.Env, constants.EnvOTELTracesSampler)
 	// configure sampler only if it is configured in the CR
-	if idx == -1 && otelinst.Spec.Sampler.Type != "" {
+	if idx == -1 && sampler.Type != "" {
 		idxSamplerArg := getIndexOfEnv(container.Env, constants.EnvOTELTracesSamplerArg)
 		if idxSamplerArg == -1 {
 			container.Env = append(container.Env, corev1.EnvVar{
 				Name:  constants.EnvOTELTracesSampler,
-				Value: string(otelinst.Spec.Sampler.Type),
+				Value: string(sampler.Type),
 			})
-			if otelinst.Spec.Sampler.Argument != "" {
+			if sampler.Argument != "" {
 				container.Env = append(container.Env, corev1.EnvVar{
 					Name:  constants.EnvOTELTracesSamplerArg,
-					Value: otelinst.Spec.Sampler.Argument,
+					Value: sampler.Argument,
 				})
 			}
 		}
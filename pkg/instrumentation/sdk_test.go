@@ -2210,6 +2210,91 @@ func TestInjectSdkOnly(t *testing.T) {
 	}, pod)
 }
 
+func TestInjectContainerOverrides(t *testing.T) {
+	inst := v1alpha1.Instrumentation{
+		Spec: v1alpha1.InstrumentationSpec{
+			Exporter: v1alpha1.Exporter{
+				Endpoint: "http://collector:4318",
+			},
+			Sampler: v1alpha1.Sampler{
+				Type:     v1alpha1.ParentBasedTraceIDRatio,
+				Argument: "0.1",
+			},
+			ContainerOverrides: []v1alpha1.ContainerOverride{
+				{
+					ContainerNames: []string{"proxy", "worker"},
+					Sampler: &v1alpha1.Sampler{
+						Type:     v1alpha1.ParentBasedTraceIDRatio,
+						Argument: "0.01",
+					},
+				},
+				{
+					ContainerNames: []string{"batch"},
+					Exporter: &v1alpha1.Exporter{
+						Endpoint: "https://batch-collector:4318",
+						TLS:      &v1alpha1.TLS{SecretName: "batch-certs", CA: "ca.crt"},
+					},
+				},
+			},
+		},
+	}
+	insts := languageInstrumentations{
+		Sdk: instrumentationWithContainers{Instrumentation: &inst, Containers: []string{"app", "proxy", "worker", "batch"}},
+	}
+
+	inj := sdkInjector{
+		logger: logr.Discard(),
+	}
+	pod := inj.inject(context.Background(), insts,
+		testNamespace,
+		corev1.Pod{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "app"},
+					{Name: "proxy"},
+					{
+						Name: "worker",
+						Env: []corev1.EnvVar{
+							{Name: "OTEL_TRACES_SAMPLER", Value: "always_on"},
+						},
+					},
+					{Name: "batch"},
+				},
+			},
+		}, config.New())
+
+	envValue := func(container corev1.Container, name string) string {
+		idx := getIndexOfEnv(container.Env, name)
+		if idx == -1 {
+			return ""
+		}
+		return container.Env[idx].Value
+	}
+	expected := []struct {
+		endpoint   string
+		sampler    string
+		samplerArg string
+	}{
+		{"http://collector:4318", "parentbased_traceidratio", "0.1"},
+		{"http://collector:4318", "parentbased_traceidratio", "0.01"},
+		// the sampler defined by the container is kept
+		{"http://collector:4318", "always_on", ""},
+		{"https://batch-collector:4318", "parentbased_traceidratio", "0.1"},
+	}
+	for i, container := range pod.Spec.Containers {
+		assert.Equal(t, expected[i].endpoint, envValue(container, "OTEL_EXPORTER_OTLP_ENDPOINT"), container.Name)
+		assert.Equal(t, expected[i].sampler, envValue(container, "OTEL_TRACES_SAMPLER"), container.Name)
+		assert.Equal(t, expected[i].samplerArg, envValue(container, "OTEL_TRACES_SAMPLER_ARG"), container.Name)
+	}
+	assert.Equal(t, "/otel-auto-instrumentation-secret-batch-certs/ca.crt", envValue(pod.Spec.Containers[3], "OTEL_EXPORTER_OTLP_CERTIFICATE"))
+	assert.Equal(t, []corev1.VolumeMount{{
+		Name:      "otel-auto-secret-batch-certs",
+		MountPath: "/otel-auto-instrumentation-secret-batch-certs",
+		ReadOnly:  true,
+	}}, pod.Spec.Containers[3].VolumeMounts)
+	assert.Empty(t, pod.Spec.Containers[0].VolumeMounts)
+}
+
 func TestParentResourceLabels(t *testing.T) {
 	ns := corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,127 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instrumentation
+
+import (
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
+)
+
+// InstrumentationNamespaceSelectorIndex is the name of the Instrumentation field index holding whether the selector of
+// an Instrumentation has a namespace selector, and so can select pods outside of its namespace.
+const InstrumentationNamespaceSelectorIndex = ".spec.selector.namespaceSelector"
+
+// InstrumentationNamespaceSelectorIndexValues returns the InstrumentationNamespaceSelectorIndex values of an
+// Instrumentation.
+func InstrumentationNamespaceSelectorIndexValues(obj client.Object) []string {
+	inst, ok := obj.(*v1alpha1.Instrumentation)
+	if !ok || inst.Spec.Selector == nil || inst.Spec.Selector.PodSelector == nil || inst.Spec.Selector.NamespaceSelector == nil {
+		return nil
+	}
+	return []string{"true"}
+}
+
+// selectorMatches reports whether the spec.selector of inst matches a pod in the namespace ns.
+func selectorMatches(inst v1alpha1.Instrumentation, ns metav1.ObjectMeta, pod metav1.ObjectMeta) (bool, error) {
+	selector := inst.Spec.Selector
+	if selector == nil || selector.PodSelector == nil {
+		return false, nil
+	}
+
+	if selector.NamespaceSelector == nil {
+		if ns.Name != inst.Namespace {
+			return false, nil
+		}
+	} else {
+		nsSelector, err := metav1.LabelSelectorAsSelector(selector.NamespaceSelector)
+		if err != nil {
+			return false, fmt.Errorf("invalid namespace selector in Instrumentation %s/%s: %w", inst.Namespace, inst.Name, err)
+		}
+		if !nsSelector.Matches(labels.Set(ns.Labels)) {
+			return false, nil
+		}
+	}
+
+	podSelector, err := metav1.LabelSelectorAsSelector(selector.PodSelector)
+	if err != nil {
+		return false, fmt.Errorf("invalid pod selector in Instrumentation %s/%s: %w", inst.Namespace, inst.Name, err)
+	}
+	return podSelector.Matches(labels.Set(pod.Labels)), nil
+}
+
+// SelectedInstrumentations returns, per language, the Instrumentations whose selector matches a pod in the namespace
+// ns. The Instrumentations are ordered by precedence: the ones in the pod namespace come first, then the others
+// ordered by namespace and name. The first one is the Instrumentation injected when the pod has no inject annotation.
+func SelectedInstrumentations(insts []v1alpha1.Instrumentation, ns metav1.ObjectMeta, pod metav1.ObjectMeta) (map[v1alpha1.Language][]*v1alpha1.Instrumentation, error) {
+	selected := map[v1alpha1.Language][]*v1alpha1.Instrumentation{}
+	for i := range insts {
+		inst := &insts[i]
+		matches, err := selectorMatches(*inst, ns, pod)
+		if err != nil {
+			return nil, err
+		}
+		if !matches {
+			continue
+		}
+		for _, language := range inst.Spec.Selector.Languages {
+			selected[language] = append(selected[language], inst)
+		}
+	}
+
+	for _, candidates := range selected {
+		sort.SliceStable(candidates, func(i, j int) bool {
+			iLocal, jLocal := candidates[i].Namespace == ns.Name, candidates[j].Namespace == ns.Name
+			if iLocal != jLocal {
+				return iLocal
+			}
+			if candidates[i].Namespace != candidates[j].Namespace {
+				return candidates[i].Namespace < candidates[j].Namespace
+			}
+			return candidates[i].Name < candidates[j].Name
+		})
+	}
+	return selected, nil
+}
+
+// SelectorReferences returns the languages for which a pod is instrumented by inst through its selector. These are
+// the languages without an inject annotation on the pod or its namespace, for which inst takes precedence over the
+// other Instrumentations matching the pod.
+func SelectorReferences(inst v1alpha1.Instrumentation, insts []v1alpha1.Instrumentation, ns metav1.ObjectMeta, pod metav1.ObjectMeta) ([]v1alpha1.Language, error) {
+	if inst.Spec.Selector == nil {
+		return nil, nil
+	}
+	selected, err := SelectedInstrumentations(insts, ns, pod)
+	if err != nil {
+		return nil, err
+	}
+
+	var languages []v1alpha1.Language
+	for _, l := range languageInjectAnnotations {
+		if annotationValue(ns, pod, l.annotation) != "" {
+			continue
+		}
+		candidates := selected[l.language]
+		if len(candidates) > 0 && candidates[0].Namespace == inst.Namespace && candidates[0].Name == inst.Name {
+			languages = append(languages, l.language)
+		}
+	}
+	return languages, nil
+}
@@ -0,0 +1,127 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instrumentation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
+)
+
+func selectorInstrumentation(namespace, name string, selector *v1alpha1.InstrumentationSelector) v1alpha1.Instrumentation {
+	return v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       v1alpha1.InstrumentationSpec{Selector: selector},
+	}
+}
+
+func TestSelectedInstrumentations(t *testing.T) {
+	appSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "cart"}}
+	insts := []v1alpha1.Instrumentation{
+		// no selector, only usable through annotations
+		selectorInstrumentation("shop", "annotated", nil),
+		// same namespace, takes precedence
+		selectorInstrumentation("shop", "local", &v1alpha1.InstrumentationSelector{
+			PodSelector: appSelector,
+			Languages:   []v1alpha1.Language{v1alpha1.LanguageJava},
+		}),
+		// nil namespace selector only matches its own namespace
+		selectorInstrumentation("observability", "own-namespace", &v1alpha1.InstrumentationSelector{
+			PodSelector: appSelector,
+			Languages:   []v1alpha1.Language{v1alpha1.LanguageJava},
+		}),
+		// empty namespace selector matches all namespaces
+		selectorInstrumentation("observability", "cluster-wide", &v1alpha1.InstrumentationSelector{
+			PodSelector:       &metav1.LabelSelector{},
+			NamespaceSelector: &metav1.LabelSelector{},
+			Languages:         []v1alpha1.Language{v1alpha1.LanguageJava, v1alpha1.LanguagePython},
+		}),
+		selectorInstrumentation("monitoring", "by-team", &v1alpha1.InstrumentationSelector{
+			PodSelector:       appSelector,
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "shop"}},
+			Languages:         []v1alpha1.Language{v1alpha1.LanguageJava},
+		}),
+		selectorInstrumentation("monitoring", "other-team", &v1alpha1.InstrumentationSelector{
+			PodSelector:       appSelector,
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}},
+			Languages:         []v1alpha1.Language{v1alpha1.LanguageJava},
+		}),
+		selectorInstrumentation("shop", "no-pod-selector", &v1alpha1.InstrumentationSelector{
+			Languages: []v1alpha1.Language{v1alpha1.LanguageJava},
+		}),
+	}
+	ns := metav1.ObjectMeta{Name: "shop", Labels: map[string]string{"team": "shop"}}
+	pod := metav1.ObjectMeta{Labels: map[string]string{"app": "cart"}}
+
+	selected, err := SelectedInstrumentations(insts, ns, pod)
+	require.NoError(t, err)
+
+	names := map[v1alpha1.Language][]string{}
+	for language, candidates := range selected {
+		for _, candidate := range candidates {
+			names[language] = append(names[language], candidate.Namespace+"/"+candidate.Name)
+		}
+	}
+	assert.Equal(t, map[v1alpha1.Language][]string{
+		v1alpha1.LanguageJava:   {"shop/local", "monitoring/by-team", "observability/cluster-wide"},
+		v1alpha1.LanguagePython: {"observability/cluster-wide"},
+	}, names)
+}
+
+func TestSelectedInstrumentationsInvalidSelector(t *testing.T) {
+	insts := []v1alpha1.Instrumentation{
+		selectorInstrumentation("shop", "invalid", &v1alpha1.InstrumentationSelector{
+			PodSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "app", Operator: "Near"},
+			}},
+			Languages: []v1alpha1.Language{v1alpha1.LanguageJava},
+		}),
+	}
+
+	_, err := SelectedInstrumentations(insts, metav1.ObjectMeta{Name: "shop"}, metav1.ObjectMeta{})
+	assert.ErrorContains(t, err, "invalid pod selector in Instrumentation shop/invalid")
+}
+
+func TestSelectorReferences(t *testing.T) {
+	local := selectorInstrumentation("shop", "local", &v1alpha1.InstrumentationSelector{
+		PodSelector: &metav1.LabelSelector{},
+		Languages:   []v1alpha1.Language{v1alpha1.LanguageJava, v1alpha1.LanguagePython, v1alpha1.LanguageNodeJS},
+	})
+	clusterWide := selectorInstrumentation("observability", "cluster-wide", &v1alpha1.InstrumentationSelector{
+		PodSelector:       &metav1.LabelSelector{},
+		NamespaceSelector: &metav1.LabelSelector{},
+		Languages:         []v1alpha1.Language{v1alpha1.LanguageJava, v1alpha1.LanguageGo},
+	})
+	insts := []v1alpha1.Instrumentation{local, clusterWide}
+	ns := metav1.ObjectMeta{Name: "shop"}
+	pod := metav1.ObjectMeta{
+		Annotations: map[string]string{
+			// annotations take precedence over selectors, including opt-outs
+			annotationInjectPython: "false",
+		},
+	}
+
+	languages, err := SelectorReferences(local, insts, ns, pod)
+	require.NoError(t, err)
+	assert.Equal(t, []v1alpha1.Language{v1alpha1.LanguageJava, v1alpha1.LanguageNodeJS}, languages)
+
+	languages, err = SelectorReferences(clusterWide, insts, ns, pod)
+	require.NoError(t, err)
+	assert.Equal(t, []v1alpha1.Language{v1alpha1.LanguageGo}, languages)
+}
@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instrumentation
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
+)
+
+// AnnotationSpecHash is stamped on the pods instrumented by Instrumentations whose rollout policy enables
+// rolloutOnChange. It lists the hash of the spec each of them was instrumented with, as comma separated
+// "<namespace>/<name>=<hash>" entries.
+const AnnotationSpecHash = "instrumentation.opentelemetry.io/spec-hash"
+
+// SpecHash returns the hash of the spec of an Instrumentation. The rollout policy is left out, as changing it does
+// not change how pods are instrumented.
+func SpecHash(inst v1alpha1.Instrumentation) (string, error) {
+	spec := inst.Spec.DeepCopy()
+	spec.RolloutPolicy = nil
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.Sum256(b)
+	return fmt.Sprintf("%x", h), nil
+}
+
+// RolloutOnChange reports whether the rollout policy of inst enables rolloutOnChange.
+func RolloutOnChange(inst v1alpha1.Instrumentation) bool {
+	return inst.Spec.RolloutPolicy != nil && inst.Spec.RolloutPolicy.RolloutOnChange
+}
+
+// ParseSpecHashes parses the value of an annotation in the AnnotationSpecHash format. Malformed entries are ignored.
+func ParseSpecHashes(value string) map[types.NamespacedName]string {
+	hashes := map[types.NamespacedName]string{}
+	for _, entry := range strings.Split(value, ",") {
+		nsn, hash, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			continue
+		}
+		namespace, name, ok := strings.Cut(nsn, "/")
+		if !ok {
+			continue
+		}
+		hashes[types.NamespacedName{Namespace: namespace, Name: name}] = hash
+	}
+	return hashes
+}
+
+// FormatSpecHashes formats hashes in the AnnotationSpecHash format, sorted by Instrumentation.
+func FormatSpecHashes(hashes map[types.NamespacedName]string) string {
+	entries := make([]string, 0, len(hashes))
+	for nsn, hash := range hashes {
+		entries = append(entries, nsn.String()+"="+hash)
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+// SpecHashIndex is the name of the pod field index holding the Instrumentations listed in the AnnotationSpecHash
+// annotation of the pods, in the namespace/name form.
+const SpecHashIndex = ".metadata.annotations.spec-hash"
+
+// SpecHashIndexValues returns the SpecHashIndex values of a pod.
+func SpecHashIndexValues(obj client.Object) []string {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil
+	}
+	value, ok := pod.Annotations[AnnotationSpecHash]
+	if !ok {
+		return nil
+	}
+	hashes := ParseSpecHashes(value)
+	values := make([]string, 0, len(hashes))
+	for nsn := range hashes {
+		values = append(values, nsn.String())
+	}
+	sort.Strings(values)
+	return values
+}
+
+// InjectedSpecHash returns the hash of the spec of inst that the pod was instrumented with, if it is tracked.
+func InjectedSpecHash(pod metav1.ObjectMeta, inst types.NamespacedName) (string, bool) {
+	value, ok := pod.Annotations[AnnotationSpecHash]
+	if !ok {
+		return "", false
+	}
+	hash, ok := ParseSpecHashes(value)[inst]
+	return hash, ok
+}
+
+// stampSpecHashes records on the pod the spec hash of the Instrumentations injected into it whose rollout policy
+// enables rolloutOnChange.
+func stampSpecHashes(insts languageInstrumentations, pod corev1.Pod) (corev1.Pod, error) {
+	hashes := map[types.NamespacedName]string{}
+	for _, inst := range []*v1alpha1.Instrumentation{insts.Java.Instrumentation, insts.NodeJS.Instrumentation, insts.Python.Instrumentation,
+		insts.DotNet.Instrumentation, insts.Go.Instrumentation, insts.ApacheHttpd.Instrumentation, insts.Nginx.Instrumentation,
+		insts.Ruby.Instrumentation, insts.PHP.Instrumentation, insts.Sdk.Instrumentation} {
+		if inst == nil || !RolloutOnChange(*inst) {
+			continue
+		}
+		hash, err := SpecHash(*inst)
+		if err != nil {
+			return pod, err
+		}
+		hashes[types.NamespacedName{Namespace: inst.Namespace, Name: inst.Name}] = hash
+	}
+	if len(hashes) == 0 {
+		return pod, nil
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[AnnotationSpecHash] = FormatSpecHashes(hashes)
+	return pod, nil
+}
@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instrumentation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/open-telemetry/opentelemetry-operator/apis/v1alpha1"
+)
+
+func TestSpecHash(t *testing.T) {
+	inst := v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-inst", Namespace: "default"},
+		Spec: v1alpha1.InstrumentationSpec{
+			Exporter: v1alpha1.Exporter{Endpoint: "http://collector:4318"},
+		},
+	}
+	hash, err := SpecHash(inst)
+	require.NoError(t, err)
+
+	withPolicy := inst.DeepCopy()
+	withPolicy.Spec.RolloutPolicy = &v1alpha1.RolloutPolicy{RolloutOnChange: true}
+	withPolicyHash, err := SpecHash(*withPolicy)
+	require.NoError(t, err)
+	assert.Equal(t, hash, withPolicyHash)
+
+	changed := inst.DeepCopy()
+	changed.Spec.Exporter.Endpoint = "http://other-collector:4318"
+	changedHash, err := SpecHash(*changed)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, changedHash)
+}
+
+func TestSpecHashes(t *testing.T) {
+	hashes := map[types.NamespacedName]string{
+		{Namespace: "shop", Name: "java"}:            "abc",
+		{Namespace: "observability", Name: "python"}: "def",
+	}
+	value := FormatSpecHashes(hashes)
+	assert.Equal(t, "observability/python=def,shop/java=abc", value)
+	assert.Equal(t, hashes, ParseSpecHashes(value))
+	assert.Empty(t, ParseSpecHashes(""))
+	assert.Equal(t, map[types.NamespacedName]string{{Namespace: "shop", Name: "java"}: "abc"}, ParseSpecHashes("malformed,no-namespace=1,shop/java=abc"))
+
+	pod := metav1.ObjectMeta{Annotations: map[string]string{AnnotationSpecHash: value}}
+	hash, tracked := InjectedSpecHash(pod, types.NamespacedName{Namespace: "shop", Name: "java"})
+	assert.True(t, tracked)
+	assert.Equal(t, "abc", hash)
+	_, tracked = InjectedSpecHash(pod, types.NamespacedName{Namespace: "shop", Name: "other"})
+	assert.False(t, tracked)
+}
+
+func TestSpecHashIndexValues(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{AnnotationSpecHash: "shop/java=abc,observability/python=def"},
+		},
+	}
+	assert.Equal(t, []string{"observability/python", "shop/java"}, SpecHashIndexValues(pod))
+	assert.Empty(t, SpecHashIndexValues(&corev1.Pod{}))
+	assert.Empty(t, SpecHashIndexValues(&corev1.Namespace{}))
+}
+
+func TestStampSpecHashes(t *testing.T) {
+	tracked := &v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "tracked", Namespace: "default"},
+		Spec: v1alpha1.InstrumentationSpec{
+			RolloutPolicy: &v1alpha1.RolloutPolicy{RolloutOnChange: true},
+		},
+	}
+	untracked := &v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "untracked", Namespace: "default"},
+	}
+	hash, err := SpecHash(*tracked)
+	require.NoError(t, err)
+
+	insts := languageInstrumentations{}
+	insts.Java.Instrumentation = tracked
+	insts.Python.Instrumentation = tracked
+	insts.NodeJS.Instrumentation = untracked
+	pod, err := stampSpecHashes(insts, corev1.Pod{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{AnnotationSpecHash: "default/tracked=" + hash}, pod.Annotations)
+
+	insts = languageInstrumentations{}
+	insts.NodeJS.Instrumentation = untracked
+	pod, err = stampSpecHashes(insts, corev1.Pod{})
+	require.NoError(t, err)
+	assert.Nil(t, pod.Annotations)
+}
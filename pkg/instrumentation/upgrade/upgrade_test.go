@@ -64,6 +64,8 @@ func TestUpgrade(t *testing.T) {
 			config.WithAutoInstrumentationGoImage("go:1"),
 			config.WithAutoInstrumentationApacheHttpdImage("apache-httpd:1"),
 			config.WithAutoInstrumentationNginxImage("nginx:1"),
+			config.WithAutoInstrumentationRubyImage("ruby:1"),
+			config.WithAutoInstrumentationPHPImage("php:1"),
 			config.WithEnableApacheHttpdInstrumentation(true),
 			config.WithEnableDotNetInstrumentation(true),
 			config.WithEnableGoInstrumentation(true),
@@ -71,6 +73,8 @@ func TestUpgrade(t *testing.T) {
 			config.WithEnablePythonInstrumentation(true),
 			config.WithEnableNodeJSInstrumentation(true),
 			config.WithEnableJavaInstrumentation(true),
+			config.WithEnableRubyInstrumentation(true),
+			config.WithEnablePHPInstrumentation(true),
 		),
 	).Default(context.Background(), inst)
 	assert.Nil(t, err)
@@ -81,6 +85,8 @@ func TestUpgrade(t *testing.T) {
 	assert.Equal(t, "go:1", inst.Spec.Go.Image)
 	assert.Equal(t, "apache-httpd:1", inst.Spec.ApacheHttpd.Image)
 	assert.Equal(t, "nginx:1", inst.Spec.Nginx.Image)
+	assert.Equal(t, "ruby:1", inst.Spec.Ruby.Image)
+	assert.Equal(t, "php:1", inst.Spec.PHP.Image)
 	err = k8sClient.Create(context.Background(), inst)
 	require.NoError(t, err)
 
@@ -92,6 +98,8 @@ func TestUpgrade(t *testing.T) {
 		config.WithAutoInstrumentationGoImage("go:2"),
 		config.WithAutoInstrumentationApacheHttpdImage("apache-httpd:2"),
 		config.WithAutoInstrumentationNginxImage("nginx:2"),
+		config.WithAutoInstrumentationRubyImage("ruby:2"),
+		config.WithAutoInstrumentationPHPImage("php:2"),
 		config.WithEnableApacheHttpdInstrumentation(true),
 		config.WithEnableDotNetInstrumentation(true),
 		config.WithEnableGoInstrumentation(true),
@@ -99,6 +107,8 @@ func TestUpgrade(t *testing.T) {
 		config.WithEnablePythonInstrumentation(true),
 		config.WithEnableNodeJSInstrumentation(true),
 		config.WithEnableJavaInstrumentation(true),
+		config.WithEnableRubyInstrumentation(true),
+		config.WithEnablePHPInstrumentation(true),
 	)
 	up := NewInstrumentationUpgrade(k8sClient, ctrl.Log.WithName("instrumentation-upgrade"), &record.FakeRecorder{}, cfg)
 
@@ -125,4 +135,8 @@ func TestUpgrade(t *testing.T) {
 	assert.Equal(t, "apache-httpd:2", updated.Spec.ApacheHttpd.Image)
 	assert.Equal(t, "nginx:2", updated.Annotations[constants.AnnotationDefaultAutoInstrumentationNginx])
 	assert.Equal(t, "nginx:2", updated.Spec.Nginx.Image)
+	assert.Equal(t, "ruby:2", updated.Annotations[constants.AnnotationDefaultAutoInstrumentationRuby])
+	assert.Equal(t, "ruby:2", updated.Spec.Ruby.Image)
+	assert.Equal(t, "php:2", updated.Annotations[constants.AnnotationDefaultAutoInstrumentationPHP])
+	assert.Equal(t, "php:2", updated.Spec.PHP.Image)
 }
@@ -28,7 +28,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-operator/apis/v1beta1"
 	"github.com/open-telemetry/opentelemetry-operator/internal/config"
-	"github.com/open-telemetry/opentelemetry-operator/internal/webhook/podmutation"
+	"github.com/open-telemetry/opentelemetry-operator/internal/explanation"
 )
 
 var (
@@ -37,14 +37,15 @@ var (
 	errInstanceNotSidecar        = errors.New("the OpenTelemetry Collector's mode is not set to sidecar")
 )
 
+// mutatorName identifies the sidecar mutator in the explanations of the pod mutation webhook.
+const mutatorName = "sidecar"
+
 type sidecarPodMutator struct {
 	client client.Client
 	logger logr.Logger
 	config config.Config
 }
 
-var _ podmutation.PodMutator = (*sidecarPodMutator)(nil)
-
 func NewMutator(logger logr.Logger, config config.Config, client client.Client) *sidecarPodMutator {
 	return &sidecarPodMutator{
 		config: config,
@@ -54,7 +55,7 @@ func NewMutator(logger logr.Logger, config config.Config, client client.Client)
 }
 
 func (p *sidecarPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod corev1.Pod) (corev1.Pod, error) {
-	logger := p.logger.WithValues("namespace", pod.Namespace, "name", pod.Name)
+	logger := explanation.Logger(ctx, p.logger, mutatorName).WithValues("namespace", pod.Namespace, "name", pod.Name)
 
 	// if no annotations are found at all, just return the same pod
 	annValue := annotationValue(ns, pod)
@@ -62,6 +63,7 @@ func (p *sidecarPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod
 		logger.V(1).Info("annotation not present in deployment, skipping sidecar injection")
 		return pod, nil
 	}
+	logger.V(1).Info("resolved the sidecar annotation of the pod and its namespace", "annotation", Annotation, "value", annValue)
 
 	// is the annotation value 'false'? if so, we need a pod without the sidecar (ie, remove if exists)
 	if strings.EqualFold(annValue, "false") {